@@ -3,7 +3,6 @@ package admin
 import (
 	"fmt"
 
-	"k8s.io/kubectl/pkg/cmd/certificates"
 	"k8s.io/kubectl/pkg/cmd/taint"
 
 	"github.com/spf13/cobra"
@@ -15,6 +14,7 @@ import (
 
 	"github.com/openshift/oc/pkg/cli/admin/buildchain"
 	"github.com/openshift/oc/pkg/cli/admin/catalog"
+	"github.com/openshift/oc/pkg/cli/admin/certificates"
 	"github.com/openshift/oc/pkg/cli/admin/createbootstrapprojecttemplate"
 	"github.com/openshift/oc/pkg/cli/admin/createerrortemplate"
 	"github.com/openshift/oc/pkg/cli/admin/createkubeconfig"
@@ -23,6 +23,7 @@ import (
 	"github.com/openshift/oc/pkg/cli/admin/groups"
 	"github.com/openshift/oc/pkg/cli/admin/inspect"
 	"github.com/openshift/oc/pkg/cli/admin/migrate"
+	migratestorage "github.com/openshift/oc/pkg/cli/admin/migrate/storage"
 	migratetemplateinstances "github.com/openshift/oc/pkg/cli/admin/migrate/templateinstances"
 	"github.com/openshift/oc/pkg/cli/admin/mustgather"
 	"github.com/openshift/oc/pkg/cli/admin/network"
@@ -87,6 +88,7 @@ func NewCommandAdmin(f kcmdutil.Factory, streams genericclioptions.IOStreams) *c
 				prune.NewCommandPrune(f, streams),
 				migrate.NewCommandMigrate(f, streams,
 					// Migration commands
+					migratestorage.NewCmdMigrateAPIStorage(f, streams),
 					migratetemplateinstances.NewCmdMigrateTemplateInstances(f, streams),
 				),
 			},
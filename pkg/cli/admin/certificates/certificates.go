@@ -0,0 +1,168 @@
+package certificates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	certificatesv1client "k8s.io/client-go/kubernetes/typed/certificates/v1"
+	kcertificates "k8s.io/kubectl/pkg/cmd/certificates"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+	kterm "k8s.io/kubectl/pkg/util/term"
+
+	"github.com/openshift/oc/pkg/helpers/term"
+)
+
+const approvePendingExample = `
+  # Approve every pending CSR, for example while new nodes join the cluster
+  oc adm certificate approve --all-pending --confirm
+
+  # Approve the pending CSRs that match a label selector, asking for confirmation first
+  oc adm certificate approve --selector=app=bootstrap`
+
+// NewCmdCertificate returns the certificate command, whose approve subcommand can also approve
+// every pending certificate signing request at once.
+func NewCmdCertificate(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := kcertificates.NewCmdCertificate(f, streams)
+	if approve, _, err := cmd.Find([]string{"approve"}); err == nil {
+		withApprovePending(approve, f, streams)
+	}
+	return cmd
+}
+
+// ApprovePendingOptions approves the pending certificate signing requests that match a label
+// selector. Requests that are already approved or denied are skipped.
+type ApprovePendingOptions struct {
+	Selector   string
+	AllPending bool
+	Confirm    bool
+
+	Client   certificatesv1client.CertificateSigningRequestsGetter
+	PrintObj func(obj runtime.Object) error
+
+	genericclioptions.IOStreams
+}
+
+// withApprovePending adds the --selector, --all-pending and --confirm flags to the approve
+// command.
+func withApprovePending(cmd *cobra.Command, f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ApprovePendingOptions{IOStreams: streams}
+	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Approve the pending CSRs that match this label selector instead of the named ones.")
+	cmd.Flags().BoolVar(&o.AllPending, "all-pending", o.AllPending, "If true, approve every CSR that is neither approved nor denied yet.")
+	cmd.Flags().BoolVar(&o.Confirm, "confirm", o.Confirm, "With --selector or --all-pending, approve the CSRs without asking for confirmation.")
+	cmd.Example += "\n" + approvePendingExample
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if len(o.Selector) == 0 && !o.AllPending {
+			run(c, args)
+			return
+		}
+		kcmdutil.CheckErr(o.Complete(f, c, args))
+		kcmdutil.CheckErr(o.Run())
+	}
+	return cmd
+}
+
+func (o *ApprovePendingOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 || len(kcmdutil.GetFlagStringSlice(cmd, "filename")) > 0 {
+		return kcmdutil.UsageErrorf(cmd, "CSR names and --filename may not be used with --selector or --all-pending")
+	}
+	if kcmdutil.GetFlagBool(cmd, "force") {
+		return kcmdutil.UsageErrorf(cmd, "--force may not be used with --selector or --all-pending")
+	}
+
+	client, err := f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.Client = client.CertificatesV1()
+
+	printFlags := genericclioptions.NewPrintFlags("approved").WithTypeSetter(scheme.Scheme)
+	output := kcmdutil.GetFlagString(cmd, "output")
+	printFlags.OutputFormat = &output
+	printer, err := printFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	o.PrintObj = func(obj runtime.Object) error {
+		return printer.PrintObj(obj, o.Out)
+	}
+	return nil
+}
+
+// Run approves the pending CSRs matching the selector, once confirmed.
+func (o *ApprovePendingOptions) Run() error {
+	list, err := o.Client.CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{LabelSelector: o.Selector})
+	if err != nil {
+		return err
+	}
+	var pending []certificatesv1.CertificateSigningRequest
+	for _, csr := range list.Items {
+		if decision := csrDecision(&csr); len(decision) > 0 {
+			fmt.Fprintf(o.ErrOut, "Skipping certificatesigningrequest/%s, it is already %s\n", csr.Name, strings.ToLower(string(decision)))
+			continue
+		}
+		pending = append(pending, csr)
+	}
+	if len(pending) == 0 {
+		fmt.Fprintf(o.ErrOut, "No pending certificate signing requests found\n")
+		return nil
+	}
+
+	if !o.Confirm {
+		if !kterm.IsTerminal(o.In) {
+			return fmt.Errorf("approving %d certificate signing requests requires --confirm when not run from a terminal", len(pending))
+		}
+		for _, csr := range pending {
+			fmt.Fprintf(o.Out, "  %s (requested by %s)\n", csr.Name, csr.Spec.Username)
+		}
+		if !term.PromptForBool(o.In, o.Out, "Approve these %d certificate signing requests? (y/n): ", len(pending)) {
+			return fmt.Errorf("no certificate signing requests were approved")
+		}
+	}
+
+	var errs []error
+	for i := range pending {
+		csr := &pending[i]
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+			Type:           certificatesv1.CertificateApproved,
+			Status:         corev1.ConditionTrue,
+			Reason:         "KubectlApprove",
+			Message:        "This CSR was approved by oc adm certificate approve.",
+			LastUpdateTime: metav1.Now(),
+		})
+		approved, err := o.Client.CertificateSigningRequests().UpdateApproval(context.TODO(), csr.Name, csr, metav1.UpdateOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to approve %s: %v", csr.Name, err))
+			continue
+		}
+		if err := o.PrintObj(approved); err != nil {
+			return err
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(kcmdutil.MultipleErrors("error: ", errs))
+	}
+	return nil
+}
+
+// csrDecision returns the approved, denied or failed condition type of csr, or an empty string
+// while it is pending.
+func csrDecision(csr *certificatesv1.CertificateSigningRequest) certificatesv1.RequestConditionType {
+	for _, condition := range csr.Status.Conditions {
+		switch condition.Type {
+		case certificatesv1.CertificateApproved, certificatesv1.CertificateDenied, certificatesv1.CertificateFailed:
+			return condition.Type
+		}
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package certificates
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func csr(name string, labels map[string]string, conditions ...certificatesv1.RequestConditionType) *certificatesv1.CertificateSigningRequest {
+	csr := &certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	for _, condition := range conditions {
+		csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{Type: condition, Status: corev1.ConditionTrue})
+	}
+	return csr
+}
+
+func TestApprovePendingRun(t *testing.T) {
+	bootstrap := map[string]string{"app": "bootstrap"}
+	tests := []struct {
+		name     string
+		selector string
+		confirm  bool
+		approved []string
+		skipped  []string
+		err      string
+	}{
+		{name: "all pending", confirm: true, approved: []string{"csr-a", "csr-b"}, skipped: []string{"csr-approved", "csr-denied"}},
+		{name: "selector", selector: "app=bootstrap", confirm: true, approved: []string{"csr-a"}, skipped: []string{"csr-approved"}},
+		{name: "no match", selector: "app=other", confirm: true},
+		{name: "not confirmed", err: "requires --confirm"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(
+				csr("csr-a", bootstrap),
+				csr("csr-b", nil),
+				csr("csr-approved", bootstrap, certificatesv1.CertificateApproved),
+				csr("csr-denied", nil, certificatesv1.CertificateDenied),
+			)
+			streams, _, out, errOut := genericclioptions.NewTestIOStreams()
+			var printed []string
+			o := &ApprovePendingOptions{
+				Selector: test.selector,
+				Confirm:  test.confirm,
+				Client:   client.CertificatesV1(),
+				PrintObj: func(obj runtime.Object) error {
+					printed = append(printed, obj.(*certificatesv1.CertificateSigningRequest).Name)
+					return nil
+				},
+				IOStreams: streams,
+			}
+			err := o.Run()
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(printed, ",") != strings.Join(test.approved, ",") {
+				t.Errorf("expected %v to be approved, got %v", test.approved, printed)
+			}
+			for _, name := range test.approved {
+				approved, err := client.CertificatesV1().CertificateSigningRequests().Get(context.TODO(), name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if csrDecision(approved) != certificatesv1.CertificateApproved {
+					t.Errorf("expected %s to be approved, got %v", name, approved.Status.Conditions)
+				}
+			}
+			for _, name := range test.skipped {
+				if !strings.Contains(errOut.String(), "Skipping certificatesigningrequest/"+name) {
+					t.Errorf("expected %s to be skipped, got %q", name, errOut.String())
+				}
+			}
+			if out.Len() > 0 {
+				t.Errorf("unexpected output: %q", out.String())
+			}
+		})
+	}
+}
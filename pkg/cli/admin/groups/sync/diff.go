@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	userv1 "github.com/openshift/api/user/v1"
+	userv1typedclient "github.com/openshift/client-go/user/clientset/versioned/typed/user/v1"
+)
+
+// printGroupDiff prints the members that a sync would add to and remove from each of the
+// given groups, compared to the groups currently stored in OpenShift, and a summary of the
+// groups that would be created and updated.
+func printGroupDiff(out io.Writer, groups []*userv1.Group, client userv1typedclient.GroupInterface) error {
+	created, updated, unchanged := 0, 0, 0
+	for _, group := range groups {
+		current := sets.NewString()
+		existing, err := client.Get(context.TODO(), group.Name, metav1.GetOptions{})
+		switch {
+		case kapierrors.IsNotFound(err):
+			existing = nil
+		case err != nil:
+			return err
+		default:
+			current.Insert(existing.Users...)
+		}
+
+		desired := sets.NewString(group.Users...)
+		added := desired.Difference(current).List()
+		removed := current.Difference(desired).List()
+
+		switch {
+		case existing == nil:
+			created++
+			fmt.Fprintf(out, "group/%s (create)\n", group.Name)
+		case len(added) > 0 || len(removed) > 0:
+			updated++
+			fmt.Fprintf(out, "group/%s (update)\n", group.Name)
+		default:
+			unchanged++
+			fmt.Fprintf(out, "group/%s (unchanged)\n", group.Name)
+		}
+		for _, user := range added {
+			fmt.Fprintf(out, "  + %s\n", user)
+		}
+		for _, user := range removed {
+			fmt.Fprintf(out, "  - %s\n", user)
+		}
+	}
+	fmt.Fprintf(out, "\n%d group(s) to create, %d to update, %d unchanged (dry run)\n", created, updated, unchanged)
+	return nil
+}
@@ -0,0 +1,42 @@
+package sync
+
+import (
+	"bytes"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	userv1 "github.com/openshift/api/user/v1"
+	fakeuserclient "github.com/openshift/client-go/user/clientset/versioned/fake"
+)
+
+func TestPrintGroupDiff(t *testing.T) {
+	client := fakeuserclient.NewSimpleClientset(
+		&userv1.Group{ObjectMeta: metav1.ObjectMeta{Name: "admins"}, Users: []string{"alice", "dave"}},
+		&userv1.Group{ObjectMeta: metav1.ObjectMeta{Name: "viewers"}, Users: []string{"erin"}},
+	)
+	groups := []*userv1.Group{
+		{ObjectMeta: metav1.ObjectMeta{Name: "admins"}, Users: []string{"alice", "carol"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "developers"}, Users: []string{"bob", "alice"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "viewers"}, Users: []string{"erin"}},
+	}
+
+	out := &bytes.Buffer{}
+	if err := printGroupDiff(out, groups, client.UserV1().Groups()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `group/admins (update)
+  + carol
+  - dave
+group/developers (create)
+  + alice
+  + bob
+group/viewers (unchanged)
+
+1 group(s) to create, 1 to update, 1 unchanged (dry run)
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
@@ -44,6 +44,10 @@ var (
 		requested from the external record store and migrated to OpenShift records. Default behavior is to do a dry-run
 		without changing OpenShift records. Passing '--confirm' will sync all groups from the LDAP server returned by the
 		LDAP query templates.
+
+		Passing '--output=diff' without '--confirm' prints, for every group, the members that
+		would be added and removed compared to the groups currently stored in OpenShift,
+		followed by the number of groups that would be created and updated.
 	`)
 
 	syncExamples = templates.Examples(`
@@ -53,6 +57,9 @@ var (
 		# Sync all groups except the ones from the blacklist file with an LDAP server
 		oc adm groups sync --blacklist=/path/to/blacklist.txt --sync-config=/path/to/ldap-sync-config.yaml --confirm
 
+		# Review the membership changes a sync with an LDAP server would make
+		oc adm groups sync --sync-config=/path/to/ldap-sync-config.yaml --output=diff
+
 		# Sync specific groups specified in a whitelist file with an LDAP server
 		oc adm groups sync --whitelist=/path/to/whitelist.txt --sync-config=/path/to/sync-config.yaml --confirm
 
@@ -100,6 +107,9 @@ type SyncOptions struct {
 	// Confirm determines whether or not to write to OpenShift
 	Confirm bool
 
+	// Diff prints the membership changes of a dry-run instead of the resulting groups
+	Diff bool
+
 	// GroupClient is the interface used to interact with OpenShift Group objects
 	GroupClient userv1typedclient.GroupsGetter
 
@@ -189,6 +199,10 @@ func (o *SyncOptions) Complete(f kcmdutil.Factory, args []string) error {
 	if err != nil {
 		return err
 	}
+	if o.PrintFlags.OutputFormat != nil && *o.PrintFlags.OutputFormat == "diff" {
+		o.Diff = true
+		return nil
+	}
 	if !o.Confirm {
 		o.PrintFlags.Complete("%s (dry run)")
 	}
@@ -316,6 +330,10 @@ func (o *SyncOptions) Validate() error {
 		return fmt.Errorf("sync source must be one of the following: %v", strings.Join(AllowedSourceTypes, ","))
 	}
 
+	if o.Diff && o.Confirm {
+		return fmt.Errorf("--output=diff shows the changes of a dry-run and cannot be combined with --confirm")
+	}
+
 	results := ldap.ValidateLDAPSyncConfig(o.Config)
 	if o.GroupClient == nil {
 		results.Errors = append(results.Errors, field.Required(field.NewPath("groupInterface"), ""))
@@ -408,7 +426,11 @@ func (o *SyncOptions) Run() error {
 
 	// Now we run the Syncer and report any errors
 	openshiftGroups, syncErrors := syncer.Sync()
-	if !o.Confirm {
+	if o.Diff {
+		if err := printGroupDiff(o.Out, openshiftGroups, o.GroupClient.Groups()); err != nil {
+			return err
+		}
+	} else if !o.Confirm {
 		list := &unstructured.UnstructuredList{
 			Object: map[string]interface{}{
 				"kind":       "List",
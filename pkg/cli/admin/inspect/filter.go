@@ -0,0 +1,53 @@
+package inspect
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var namespacesGroupResource = schema.GroupResource{Resource: "namespaces"}
+
+// resourceFilter decides which resource types are gathered when inspect follows the contents
+// of a namespace or the related objects of a resource. Entries are plural resource names,
+// optionally qualified by their group (e.g. "secrets" or "deployments.apps").
+type resourceFilter struct {
+	include sets.String
+	exclude sets.String
+}
+
+func newResourceFilter(include, exclude []string) (*resourceFilter, error) {
+	f := &resourceFilter{include: sets.NewString(), exclude: sets.NewString()}
+	for _, s := range include {
+		if s = strings.ToLower(strings.TrimSpace(s)); len(s) > 0 {
+			f.include.Insert(s)
+		}
+	}
+	for _, s := range exclude {
+		if s = strings.ToLower(strings.TrimSpace(s)); len(s) > 0 {
+			f.exclude.Insert(s)
+		}
+	}
+	if both := f.include.Intersection(f.exclude); both.Len() > 0 {
+		return nil, fmt.Errorf("resource types may not be both included and excluded: %s", strings.Join(both.List(), ", "))
+	}
+	return f, nil
+}
+
+func (f *resourceFilter) matches(names sets.String, gr schema.GroupResource) bool {
+	return names.Has(gr.Resource) || (len(gr.Group) > 0 && names.Has(gr.String()))
+}
+
+// Allows returns true if resources of the given type should be gathered. Namespaces are
+// only skipped when excluded explicitly, so that --include can select types inside them.
+func (f *resourceFilter) Allows(gr schema.GroupResource) bool {
+	if f == nil {
+		return true
+	}
+	if f.include.Len() > 0 && !f.matches(f.include, gr) && gr != namespacesGroupResource {
+		return false
+	}
+	return !f.matches(f.exclude, gr)
+}
@@ -55,6 +55,12 @@ var (
 
 		# Collect debugging data for all clusteroperators and clusterversions
 		oc adm inspect clusteroperators,clusterversions
+
+		# Collect debugging data for a namespace without its secrets and config maps
+		oc adm inspect ns/my-project --exclude=secrets,configmaps
+
+		# Collect debugging data for a namespace, scrubbing all secret data values
+		oc adm inspect ns/my-project --redact
 	`)
 )
 
@@ -77,6 +83,10 @@ type InspectOptions struct {
 	rotatedPodLogs bool
 	sinceInt       int64
 	sinceTimestamp metav1.Time
+	include        []string
+	exclude        []string
+	redact         bool
+	resourceFilter *resourceFilter
 
 	// directory where all gathered data will be stored
 	DestDir string
@@ -119,6 +129,9 @@ func NewCmdInspect(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", o.allNamespaces, "If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
 	cmd.Flags().StringVar(&o.sinceTime, "since-time", o.sinceTime, "Only return logs after a specific date (RFC3339). Defaults to all logs. Only one of since-time / since may be used.")
 	cmd.Flags().DurationVar(&o.since, "since", o.since, "Only return logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all logs. Only one of since-time / since may be used.")
+	cmd.Flags().StringSliceVar(&o.include, "include", o.include, "If set, only gather these resource types (e.g. pods,events,deployments.apps) from namespaces and related objects. Pod logs are gathered with pods.")
+	cmd.Flags().StringSliceVar(&o.exclude, "exclude", o.exclude, "Resource types (e.g. secrets,configmaps) not to gather from namespaces and related objects. Excluding pods also skips their logs.")
+	cmd.Flags().BoolVar(&o.redact, "redact", o.redact, "If true, replace the value of every key in gathered secrets, including certificates, keeping only their metadata.")
 	cmd.Flags().BoolVar(&o.rotatedPodLogs, "rotated-pod-logs", o.rotatedPodLogs, "Experimental: If present, retrieve rotated log files that are available for selected pods. This can significantly increase the collected logs size. since/since-time is ignored for rotated logs.")
 
 	// The rotated-pod-logs option should be removed once support for retrieving rotated logs is added to kubelet
@@ -137,6 +150,11 @@ func (o *InspectOptions) Complete(args []string) error {
 	}
 
 	var err error
+	o.resourceFilter, err = newResourceFilter(o.include, o.exclude)
+	if err != nil {
+		return err
+	}
+
 	o.RESTConfig, err = o.configFlags.ToRESTConfig()
 	if err != nil {
 		return err
@@ -281,6 +299,9 @@ func (o *InspectOptions) gatherConfigResourceData(destDir string, ctx *resourceC
 
 	errs := []error{}
 	for _, resource := range resources {
+		if !o.resourceFilter.Allows(resource.GroupResource()) {
+			continue
+		}
 		resourceList, err := o.dynamicClient.Resource(resource).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
 			errs = append(errs, err)
@@ -322,6 +343,9 @@ func (o *InspectOptions) gatherOperatorResourceData(destDir string, ctx *resourc
 
 	errs := []error{}
 	for _, resource := range resources {
+		if !o.resourceFilter.Allows(resource.GroupResource()) {
+			continue
+		}
 		resourceList, err := o.dynamicClient.Resource(resource).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
 			errs = append(errs, err)
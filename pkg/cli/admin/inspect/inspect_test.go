@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -236,3 +237,85 @@ func errorFuzzyEquals(a, b error) bool {
 	}
 	return strings.Contains(a.Error(), b.Error())
 }
+
+func TestResourceFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		allowed  []schema.GroupResource
+		filtered []schema.GroupResource
+	}{
+		{
+			name:    "no filters",
+			allowed: []schema.GroupResource{{Resource: "secrets"}, {Group: "apps", Resource: "deployments"}},
+		},
+		{
+			name:     "exclude",
+			exclude:  []string{"secrets", " ConfigMaps"},
+			allowed:  []schema.GroupResource{{Resource: "pods"}, {Resource: "events"}},
+			filtered: []schema.GroupResource{{Resource: "secrets"}, {Resource: "configmaps"}},
+		},
+		{
+			name:     "include by group",
+			include:  []string{"deployments.apps", "pods"},
+			allowed:  []schema.GroupResource{{Group: "apps", Resource: "deployments"}, {Resource: "pods"}, {Resource: "namespaces"}},
+			filtered: []schema.GroupResource{{Resource: "events"}, {Group: "apps", Resource: "replicasets"}, {Group: "example.com", Resource: "pods.apps"}},
+		},
+		{
+			name:     "exclude namespaces",
+			include:  []string{"pods"},
+			exclude:  []string{"namespaces"},
+			filtered: []schema.GroupResource{{Resource: "namespaces"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := newResourceFilter(test.include, test.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, gr := range test.allowed {
+				if !f.Allows(gr) {
+					t.Errorf("expected %s to be allowed", gr)
+				}
+			}
+			for _, gr := range test.filtered {
+				if f.Allows(gr) {
+					t.Errorf("expected %s to be filtered", gr)
+				}
+			}
+		})
+	}
+
+	if _, err := newResourceFilter([]string{"secrets"}, []string{"secrets"}); err == nil || !strings.Contains(err.Error(), "secrets") {
+		t.Errorf("expected an error for a type both included and excluded, got %v", err)
+	}
+}
+
+func TestElideSecret(t *testing.T) {
+	newSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "serving-cert", Labels: map[string]string{"app": "foo"}},
+			Data: map[string][]byte{
+				"tls.crt": []byte("certificate"),
+				"tls.key": []byte("private"),
+			},
+		}
+	}
+
+	secret := newSecret()
+	elideSecret(secret, false)
+	if string(secret.Data["tls.crt"]) != "certificate" || string(secret.Data["tls.key"]) != "7 bytes long" {
+		t.Errorf("unexpected data: %v", secret.Data)
+	}
+
+	secret = newSecret()
+	elideSecret(secret, true)
+	if string(secret.Data["tls.crt"]) != "11 bytes long" || string(secret.Data["tls.key"]) != "7 bytes long" {
+		t.Errorf("unexpected redacted data: %v", secret.Data)
+	}
+	if secret.Name != "serving-cert" || secret.Labels["app"] != "foo" {
+		t.Errorf("expected metadata to be kept: %#v", secret.ObjectMeta)
+	}
+}
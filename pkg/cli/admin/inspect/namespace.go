@@ -64,6 +64,9 @@ func (o *InspectOptions) gatherNamespaceData(baseDir, namespace string) error {
 
 	// collect specific resource information for namespace
 	for gvr := range resourcesTypesToStore {
+		if !o.resourceFilter.Allows(gvr.GroupResource()) {
+			continue
+		}
 		list, err := o.dynamicClient.Resource(gvr).Namespace(namespace).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
 			errs = append(errs, err)
@@ -73,12 +73,20 @@ func InspectResource(info *resource.Info, context *resourceContext, o *InspectOp
 			if context.visited.Has(resourceToContextKey(resource, info.Name)) {
 				continue
 			}
+			// "all" is expanded by the server, its contents are filtered below
+			if resource.Resource != "all" && !o.resourceFilter.Allows(resource) {
+				continue
+			}
 			resourceInfos, err := groupResourceToInfos(o.configFlags, resource, info.Name)
 			if err != nil {
 				errs = append(errs, err)
 				continue
 			}
 			for _, resourceInfo := range resourceInfos {
+				if !o.resourceFilter.Allows(resourceInfo.Mapping.Resource.GroupResource()) {
+					klog.V(1).Infof("Skipping filtered resource: %q ...", infoToContextKey(resourceInfo))
+					continue
+				}
 				if err := InspectResource(resourceInfo, context, o); err != nil {
 					errs = append(errs, err)
 					continue
@@ -131,6 +139,10 @@ func gatherRelatedObjects(context *resourceContext, unstr *unstructured.Unstruct
 		if context.visited.Has(objectRefToContextKey(relatedRef)) {
 			continue
 		}
+		if !o.resourceFilter.Allows(schema.GroupResource{Group: relatedRef.Group, Resource: relatedRef.Resource}) {
+			klog.V(1).Infof("Skipping filtered related object %q ...", objectReferenceToString(relatedRef))
+			continue
+		}
 
 		relatedInfos, err := objectReferenceToResourceInfos(o.configFlags, relatedRef)
 		if err != nil {
@@ -43,11 +43,11 @@ func inspectSecretInfo(info *resource.Info, o *InspectOptions) error {
 
 	switch castObj := obj.(type) {
 	case *corev1.Secret:
-		elideSecret(castObj)
+		elideSecret(castObj, o.redact)
 
 	case *corev1.SecretList:
 		for i := range castObj.Items {
-			elideSecret(&castObj.Items[i])
+			elideSecret(&castObj.Items[i], o.redact)
 		}
 
 	case *unstructured.UnstructuredList:
@@ -73,10 +73,12 @@ var publicSecretKeys = sets.NewString(
 	"service-ca.crt",
 )
 
-func elideSecret(secret *corev1.Secret) {
+// elideSecret replaces the secret data values with their length. Keys known to hold public
+// certificates are kept unless redact is set.
+func elideSecret(secret *corev1.Secret, redact bool) {
 	for k, v := range secret.Data {
 		// some secrets keys are safe to include because know their content.
-		if publicSecretKeys.Has(k) {
+		if !redact && publicSecretKeys.Has(k) {
 			continue
 		}
 		secret.Data[k] = []byte(fmt.Sprintf("%d bytes long", len(v)))
@@ -122,15 +122,14 @@ type ResourceOptions struct {
 	FilterFn  MigrateFilterFunc
 	DryRun    bool
 	Summarize bool
+	// If true, every migrated or unchanged object is reported regardless of the log level.
+	Verbose bool
 
 	// Number of parallel workers to use.
 	// Any migrate command that sets this must make sure that
 	// its SaveFn, PrintFn and FilterFn are goroutine safe.
 	// If multiple workers may attempt to write to Out or ErrOut
 	// at the same time, SyncOut must also be set to true.
-	// This should not be exposed as a CLI flag.  Instead it
-	// should have a fixed value that is high enough to saturate
-	// the desired bandwidth when parallel processing is desired.
 	Workers int
 	// If true, Out and ErrOut will be wrapped to make them goroutine safe.
 	SyncOut bool
@@ -384,6 +383,7 @@ func (o *ResourceOptions) Visitor() *ResourceVisitor {
 		PrintFn:  o.PrintFn,
 		FilterFn: o.FilterFn,
 		DryRun:   o.DryRun,
+		Verbose:  o.Verbose,
 		Workers:  o.Workers,
 	}
 }
@@ -412,7 +412,8 @@ type ResourceVisitor struct {
 	PrintFn  MigrateActionFunc
 	FilterFn MigrateFilterFunc
 
-	DryRun bool
+	DryRun  bool
+	Verbose bool
 
 	Workers int
 }
@@ -446,6 +447,7 @@ func (o *ResourceVisitor) Visit(fn MigrateVisitFunc) error {
 	t := &migrateTracker{
 		out:                 out,
 		dryRun:              dryRun,
+		verbose:             o.Verbose,
 		resourcesWithErrors: sets.NewString(),
 		results:             results,
 	}
@@ -581,7 +583,8 @@ type resultData struct {
 type migrateTracker struct {
 	out io.Writer
 
-	dryRun bool
+	dryRun  bool
+	verbose bool
 
 	found, ignored, unchanged, errors int
 
@@ -631,11 +634,11 @@ func (t *migrateTracker) run() {
 			}
 		case attemptResultUnchanged:
 			t.unchanged++
-			if klog.V(2).Enabled() {
+			if t.verbose || klog.V(2).Enabled() {
 				t.report("unchanged:", r.data.info, nil)
 			}
 		case attemptResultSuccess:
-			if klog.V(1).Enabled() {
+			if t.verbose || klog.V(1).Enabled() {
 				if t.dryRun {
 					t.report("migrated (dry run):", r.data.info, nil)
 				} else {
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/oc/pkg/cli/admin/migrate"
+)
+
+var (
+	internalMigrateStorageLong = templates.LongDesc(`
+		Migrate internal object storage via update
+
+		This command rewrites every object of the given resource types by reading it and writing it
+		back unchanged. The API server encodes the objects it stores at the current storage version,
+		so this moves objects that were stored at an older version, for instance after the storage
+		version of a custom resource definition changed, to the current one.
+
+		Objects that were changed by someone else while being rewritten are read again and retried a
+		bounded number of times before they are reported as failed. Objects that were deleted in the
+		meantime are skipped. Every object is reported as migrated, unchanged or failed, followed by a
+		summary. Objects that are already stored at the current version are reported as unchanged.
+
+		By default this command performs a dry run that only lists the objects that would be
+		rewritten. Pass --confirm to rewrite them.`)
+
+	internalMigrateStorageExample = templates.Examples(`
+		# List the routes that would be rewritten
+		oc adm migrate storage --resource=routes.route.openshift.io
+
+		# Rewrite every route in the cluster
+		oc adm migrate storage --resource=routes.route.openshift.io --confirm
+
+		# Rewrite the objects of a custom resource with 8 concurrent updates
+		oc adm migrate storage --resource=widgets.example.com --confirm --workers=8
+	`)
+)
+
+type MigrateAPIStorageOptions struct {
+	migrate.ResourceOptions
+}
+
+func NewMigrateAPIStorageOptions(streams genericclioptions.IOStreams) *MigrateAPIStorageOptions {
+	o := &MigrateAPIStorageOptions{
+		ResourceOptions: *migrate.NewResourceOptions(streams).WithUnstructured().WithAllNamespaces(),
+	}
+	o.Workers = 4
+	o.Verbose = true
+	return o
+}
+
+// NewCmdMigrateAPIStorage implements a MigrateStorage command
+func NewCmdMigrateAPIStorage(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewMigrateAPIStorageOptions(streams)
+	cmd := &cobra.Command{
+		Use:     "storage --resource=RESOURCE.GROUP",
+		Short:   "Update the stored version of API objects",
+		Long:    internalMigrateStorageLong,
+		Example: internalMigrateStorageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Validate())
+			kcmdutil.CheckErr(o.Run())
+		},
+	}
+	cmd.Flags().StringSliceVar(&o.Include, "resource", o.Include, "Resource types to migrate, such as routes.route.openshift.io. May be specified multiple times.")
+	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", o.AllNamespaces, "Migrate objects in all namespaces. Defaults to true.")
+	cmd.Flags().BoolVar(&o.Confirm, "confirm", o.Confirm, "If true, all requested objects will be migrated. Defaults to false.")
+	cmd.Flags().IntVar(&o.Workers, "workers", o.Workers, "The number of objects to update concurrently.")
+	o.PrintFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+func (o *MigrateAPIStorageOptions) Complete(f kcmdutil.Factory, c *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("oc adm migrate storage takes no positional arguments, use --resource to select the resources to migrate")
+	}
+
+	// the migration reports objects from all workers
+	o.SyncOut = true
+	o.ResourceOptions.SaveFn = o.save
+	return o.ResourceOptions.Complete(f, c)
+}
+
+func (o MigrateAPIStorageOptions) Validate() error {
+	if len(o.Include) == 0 {
+		return fmt.Errorf("you must specify at least one resource type to migrate with --resource")
+	}
+	return o.ResourceOptions.Validate()
+}
+
+func (o MigrateAPIStorageOptions) Run() error {
+	return o.ResourceOptions.Visitor().Visit(migrate.AlwaysRequiresMigration)
+}
+
+// save writes the object back unchanged, which causes the API server to store it at the current
+// storage version. It returns migrate.ErrUnchanged if the server did not store a new version of
+// the object, and a retriable error if the object was changed since it was read.
+func (o *MigrateAPIStorageOptions) save(info *resource.Info, reporter migrate.Reporter) error {
+	oldObject, err := meta.Accessor(info.Object)
+	if err != nil {
+		return err
+	}
+	oldResourceVersion := oldObject.GetResourceVersion()
+
+	obj, err := resource.NewHelper(info.Client, info.Mapping).Replace(info.Namespace, info.Name, false, info.Object)
+	if err != nil {
+		return migrate.DefaultRetriable(info, err)
+	}
+	newObject, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if newObject.GetResourceVersion() == oldResourceVersion {
+		return migrate.ErrUnchanged
+	}
+	info.Refresh(obj, true)
+	return nil
+}
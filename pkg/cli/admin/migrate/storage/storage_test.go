@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/openshift/oc/pkg/cli/admin/migrate"
+)
+
+func TestSave(t *testing.T) {
+	configMap := func(resourceVersion string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns", ResourceVersion: resourceVersion},
+		}
+	}
+	tests := []struct {
+		name string
+		// responses are returned by method
+		responses   map[string][]runtime.Object
+		expectErr   func(error) bool
+		expectedRV  string
+		expectedPut int
+	}{
+		{
+			name:        "rewritten",
+			responses:   map[string][]runtime.Object{"PUT": {configMap("2")}},
+			expectErr:   func(err error) bool { return err == nil },
+			expectedRV:  "2",
+			expectedPut: 1,
+		},
+		{
+			name:        "already at the storage version",
+			responses:   map[string][]runtime.Object{"PUT": {configMap("1")}},
+			expectErr:   func(err error) bool { return err == migrate.ErrUnchanged },
+			expectedRV:  "1",
+			expectedPut: 1,
+		},
+		{
+			name: "conflict is retried with the current object",
+			responses: map[string][]runtime.Object{
+				"PUT": {&metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonConflict, Code: http.StatusConflict}},
+				"GET": {configMap("3")},
+			},
+			expectErr: func(err error) bool {
+				_, ok := err.(migrate.ErrRetriable)
+				return ok
+			},
+			expectedRV:  "3",
+			expectedPut: 1,
+		},
+		{
+			name: "deleted while migrating",
+			responses: map[string][]runtime.Object{
+				"PUT": {&metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonNotFound, Code: http.StatusNotFound}},
+			},
+			expectErr:   func(err error) bool { return err == migrate.ErrUnchanged },
+			expectedRV:  "1",
+			expectedPut: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			puts := 0
+			client := &fake.RESTClient{
+				NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+				Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+					if req.Method == "PUT" {
+						puts++
+					}
+					responses := test.responses[req.Method]
+					if len(responses) == 0 {
+						t.Fatalf("unexpected request: %s %s", req.Method, req.URL)
+					}
+					obj := responses[0]
+					test.responses[req.Method] = responses[1:]
+					code := http.StatusOK
+					if status, ok := obj.(*metav1.Status); ok {
+						code = int(status.Code)
+					}
+					body, err := runtime.Encode(scheme.Codecs.LegacyCodec(corev1.SchemeGroupVersion, metav1.SchemeGroupVersion), obj)
+					if err != nil {
+						t.Fatal(err)
+					}
+					header := http.Header{}
+					header.Set("Content-Type", runtime.ContentTypeJSON)
+					return &http.Response{StatusCode: code, Header: header, Body: ioutil.NopCloser(bytes.NewReader(body))}, nil
+				}),
+			}
+			info := &resource.Info{
+				Client:    client,
+				Mapping:   &meta.RESTMapping{Resource: corev1.SchemeGroupVersion.WithResource("configmaps"), Scope: meta.RESTScopeNamespace},
+				Namespace: "ns",
+				Name:      "test",
+				Object:    configMap("1"),
+			}
+
+			o := NewMigrateAPIStorageOptions(genericclioptions.NewTestIOStreamsDiscard())
+			err := o.save(info, migrate.ReporterBool(true))
+			if !test.expectErr(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if puts != test.expectedPut {
+				t.Errorf("expected %d updates, got %d", test.expectedPut, puts)
+			}
+			if rv := info.Object.(metav1.Object).GetResourceVersion(); rv != test.expectedRV {
+				t.Errorf("expected object at resource version %s, got %s", test.expectedRV, rv)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package mustgather
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"path"
@@ -49,6 +51,12 @@ var (
 		This command will launch a pod in a temporary namespace on your cluster that gathers
 		debugging information and then downloads the gathered information.
 
+		When several plug-in images are specified they are gathered concurrently, up to
+		--max-parallel images at a time. Each image is subject to --timeout; gather pods for an
+		image that does not finish in time are removed and the image is recorded as timed out
+		while the remaining images continue. The outcome for every image is written to
+		gather-status.json at the top of the destination directory.
+
 		Experimental: This command is under active development and may change without notice.
 	`)
 
@@ -65,6 +73,12 @@ var (
 		# Gather information using multiple plug-in images
 		  oc adm must-gather --image=quay.io/kubevirt/must-gather --image=quay.io/openshift/origin-must-gather
 
+		# Gather information without streaming the logs of the gather containers
+		  oc adm must-gather --stream=false
+
+		# Gather from multiple plug-in images one at a time, giving each at most 5 minutes
+		  oc adm must-gather --image=quay.io/kubevirt/must-gather --image=quay.io/openshift/origin-must-gather --max-parallel=1 --timeout=5m
+
 		# Gather information using a specific image stream plug-in
 		  oc adm must-gather --image-stream=openshift/must-gather:latest
 
@@ -94,7 +108,9 @@ func NewMustGatherCommand(f kcmdutil.Factory, streams genericclioptions.IOStream
 	cmd.Flags().StringSliceVar(&o.ImageStreams, "image-stream", o.ImageStreams, "Specify an image stream (namespace/name:tag) containing a must-gather plugin image to run.")
 	cmd.Flags().StringVar(&o.DestDir, "dest-dir", o.DestDir, "Set a specific directory on the local machine to write gathered data to.")
 	cmd.Flags().StringVar(&o.SourceDir, "source-dir", o.SourceDir, "Set the specific directory on the pod copy the gathered data from.")
-	cmd.Flags().StringVar(&o.timeoutStr, "timeout", "10m", "The length of time to gather data, like 5s, 2m, or 3h, higher than zero. Defaults to 10 minutes.")
+	cmd.Flags().StringVar(&o.timeoutStr, "timeout", "10m", "The length of time to gather data for each plug-in image, like 5s, 2m, or 3h, higher than zero. Defaults to 10 minutes.")
+	cmd.Flags().IntVar(&o.MaxParallel, "max-parallel", o.MaxParallel, "The maximum number of plug-in images to gather from concurrently.")
+	cmd.Flags().BoolVar(&o.Stream, "stream", o.Stream, "Stream the logs of the gather containers while collecting. Lines are prefixed with the plug-in image when multiple images are used. Pass --stream=false to only report progress.")
 	cmd.Flags().StringVar(&o.RunNamespace, "run-namespace", o.RunNamespace, "An existing namespace where must-gather pods should run. If not specified a temporary namespace will be generated.")
 	cmd.Flags().MarkHidden("run-namespace")
 	cmd.Flags().BoolVar(&o.Keep, "keep", o.Keep, "Do not delete temporary resources when command completes.")
@@ -110,6 +126,9 @@ func NewMustGatherOptions(streams genericclioptions.IOStreams) *MustGatherOption
 		LogOut:    newPrefixWriter(streams.Out, "[must-gather      ] OUT"),
 		RawOut:    streams.Out,
 		Timeout:   10 * time.Minute,
+
+		MaxParallel: 4,
+		Stream:      true,
 	}
 }
 
@@ -243,6 +262,8 @@ type MustGatherOptions struct {
 	Command      []string
 	Timeout      time.Duration
 	timeoutStr   string
+	MaxParallel  int
+	Stream       bool
 	RunNamespace string
 	Keep         bool
 
@@ -262,6 +283,9 @@ func (o *MustGatherOptions) Validate() error {
 	if o.NodeName != "" && o.NodeSelector != "" {
 		return fmt.Errorf("--node-name and --node-selector are mutually exclusive: please specify one or the other")
 	}
+	if o.MaxParallel < 1 {
+		return fmt.Errorf("--max-parallel must be greater than zero")
+	}
 	return nil
 }
 
@@ -298,37 +322,11 @@ func (o *MustGatherOptions) Run() error {
 		defer cleanupNamespace()
 	}
 
-	// ... and create must-gather pod(s)
-	var pods []*corev1.Pod
 	for _, image := range o.Images {
-		_, err := imagereference.Parse(image)
-		if err != nil {
+		if _, err := imagereference.Parse(image); err != nil {
 			o.log("unable to parse image reference %s: %v", image, err)
 			return err
 		}
-		if o.NodeSelector != "" {
-			nodes, err := o.Client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
-				LabelSelector: o.NodeSelector,
-			})
-			if err != nil {
-				return err
-			}
-			for _, node := range nodes.Items {
-				pod, err := o.Client.CoreV1().Pods(ns.Name).Create(context.TODO(), o.newPod(node.Name, image), metav1.CreateOptions{})
-				if err != nil {
-					return err
-				}
-				o.log("pod: %s on node: %s for plug-in image %s created", pod.Name, node.Name, image)
-				pods = append(pods, pod)
-			}
-		} else {
-			pod, err := o.Client.CoreV1().Pods(ns.Name).Create(context.TODO(), o.newPod(o.NodeName, image), metav1.CreateOptions{})
-			if err != nil {
-				return err
-			}
-			o.log("pod for plug-in image %s created", image)
-			pods = append(pods, pod)
-		}
 	}
 
 	// log timestamps...
@@ -340,55 +338,28 @@ func (o *MustGatherOptions) Run() error {
 	}
 	defer o.logTimestamp()
 
+	// ... and gather from each plug-in image, at most MaxParallel at a time
+	statuses := make([]gatherStatus, len(o.Images))
+	limit := make(chan struct{}, o.MaxParallel)
 	var wg sync.WaitGroup
-	wg.Add(len(pods))
-	errCh := make(chan error, len(pods))
-	for _, pod := range pods {
-		go func(pod *corev1.Pod) {
+	wg.Add(len(o.Images))
+	for i, image := range o.Images {
+		go func(i int, image string) {
 			defer wg.Done()
-
-			log := newPodOutLogger(o.Out, pod.Name)
-
-			// wait for gather container to be running (gather is running)
-			if err := o.waitForGatherContainerRunning(pod); err != nil {
-				log("gather did not start: %s", err)
-				errCh <- fmt.Errorf("gather did not start for pod %s: %s", pod.Name, err)
-				return
-			}
-			// stream gather container logs
-			if err := o.getGatherContainerLogs(pod); err != nil {
-				log("gather logs unavailable: %v", err)
-			}
-
-			// wait for pod to be running (gather has completed)
-			log("waiting for gather to complete")
-			if err := o.waitForGatherToComplete(pod); err != nil {
-				log("gather never finished: %v", err)
-				errCh <- fmt.Errorf("gather never finished for pod %s: %s", pod.Name, err)
-				return
-			}
-
-			// copy the gathered files into the local destination dir
-			log("downloading gather output")
-			pod, err = o.Client.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
-			if err != nil {
-				log("gather output not downloaded: %v\n", err)
-				errCh <- fmt.Errorf("unable to download output from pod %s: %s", pod.Name, err)
-				return
-			}
-			if err := o.copyFilesFromPod(pod); err != nil {
-				log("gather output not downloaded: %v\n", err)
-				errCh <- fmt.Errorf("unable to download output from pod %s: %s", pod.Name, err)
-				return
-			}
-		}(pod)
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			statuses[i] = o.gatherImage(ns.Name, image)
+		}(i, image)
 	}
 	wg.Wait()
-	close(errCh)
 
-	for i := range errCh {
-		errs = append(errs, i)
+	for _, status := range statuses {
+		errs = append(errs, status.errs...)
+	}
+	if err := writeGatherStatus(path.Join(o.DestDir, gatherStatusFile), statuses); err != nil {
+		errs = append(errs, err)
 	}
+
 	if len(errs) == 0 {
 		// If we didn't have an error during collection, then we don't need to do our backup collection.
 		runBackCollection = false
@@ -407,6 +378,198 @@ func (o *MustGatherOptions) Run() error {
 	return errors.NewAggregate(errs)
 }
 
+const (
+	gatherStatusFile = "gather-status.json"
+
+	gatherSucceeded = "Succeeded"
+	gatherFailed    = "Failed"
+	gatherTimedOut  = "TimedOut"
+)
+
+// gatherStatus records the outcome of gathering from a single plug-in image.
+type gatherStatus struct {
+	Image  string   `json:"image"`
+	Status string   `json:"status"`
+	Pods   []string `json:"pods,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+
+	errs []error
+}
+
+// gatherStatusList is the content of the status file written to the top of the destination directory.
+type gatherStatusList struct {
+	Images []gatherStatus `json:"images"`
+}
+
+// errGatherTimedOut is returned when a gather pod did not finish within the image timeout.
+type errGatherTimedOut struct {
+	pod     string
+	timeout time.Duration
+}
+
+func (e errGatherTimedOut) Error() string {
+	return fmt.Sprintf("gather for pod %s did not finish within %s", e.pod, e.timeout)
+}
+
+// newGatherStatus summarizes the errors from the gather pods of an image. An image that had any pod
+// time out is reported as timed out, otherwise any error marks the image as failed.
+func newGatherStatus(image string, pods []string, errs []error) gatherStatus {
+	status := gatherStatus{Image: image, Status: gatherSucceeded, Pods: pods, errs: errs}
+	for _, err := range errs {
+		status.Errors = append(status.Errors, err.Error())
+		if _, ok := err.(errGatherTimedOut); ok {
+			status.Status = gatherTimedOut
+		} else if status.Status != gatherTimedOut {
+			status.Status = gatherFailed
+		}
+	}
+	return status
+}
+
+func writeGatherStatus(filename string, statuses []gatherStatus) error {
+	data, err := json.MarshalIndent(gatherStatusList{Images: statuses}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// gatherImage creates the must-gather pods for an image and waits for all of them to finish,
+// copying their output locally. Pods that do not finish within the timeout are removed.
+func (o *MustGatherOptions) gatherImage(namespace, image string) gatherStatus {
+	pods, err := o.createGatherPods(namespace, image)
+	var podNames []string
+	for _, pod := range pods {
+		podNames = append(podNames, pod.Name)
+	}
+	if err != nil {
+		o.log("unable to create pods for plug-in image %s: %v", image, err)
+		return newGatherStatus(image, podNames, []error{err})
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), o.Timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(len(pods))
+	errCh := make(chan error, len(pods))
+	for _, pod := range pods {
+		go func(pod *corev1.Pod) {
+			defer wg.Done()
+			if err := o.gatherPod(ctx, pod); err != nil {
+				errCh <- err
+			}
+		}(pod)
+	}
+	wg.Wait()
+	close(errCh)
+
+	var errs []error
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+	return newGatherStatus(image, podNames, errs)
+}
+
+func (o *MustGatherOptions) createGatherPods(namespace, image string) ([]*corev1.Pod, error) {
+	var pods []*corev1.Pod
+	if o.NodeSelector != "" {
+		nodes, err := o.Client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
+			LabelSelector: o.NodeSelector,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes.Items {
+			pod, err := o.Client.CoreV1().Pods(namespace).Create(context.TODO(), o.newPod(node.Name, image), metav1.CreateOptions{})
+			if err != nil {
+				return pods, err
+			}
+			o.log("pod: %s on node: %s for plug-in image %s created", pod.Name, node.Name, image)
+			pods = append(pods, pod)
+		}
+		return pods, nil
+	}
+	pod, err := o.Client.CoreV1().Pods(namespace).Create(context.TODO(), o.newPod(o.NodeName, image), metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	o.log("pod for plug-in image %s created", image)
+	return append(pods, pod), nil
+}
+
+// gatherPod waits for the gather container of a pod to complete and downloads its output. If ctx
+// expires first the pod is removed and an errGatherTimedOut is returned.
+func (o *MustGatherOptions) gatherPod(ctx context.Context, pod *corev1.Pod) error {
+	log := newPodOutLogger(o.Out, pod.Name)
+
+	timedOut := func() error {
+		log("gather timed out after %s", o.Timeout)
+		o.deletePod(pod)
+		return errGatherTimedOut{pod: pod.Name, timeout: o.Timeout}
+	}
+
+	// wait for gather container to be running (gather is running)
+	if err := o.waitForGatherContainerRunning(ctx, pod); err != nil {
+		if ctx.Err() != nil {
+			return timedOut()
+		}
+		log("gather did not start: %s", err)
+		return fmt.Errorf("gather did not start for pod %s: %s", pod.Name, err)
+	}
+	// stream gather container logs, giving up on them once the timeout is reached
+	if o.Stream {
+		logsCh := make(chan error, 1)
+		go func() {
+			logsCh <- o.getGatherContainerLogs(pod)
+		}()
+		select {
+		case err := <-logsCh:
+			if err != nil {
+				log("gather logs unavailable, continuing without them: %v", err)
+			}
+		case <-ctx.Done():
+			return timedOut()
+		}
+	}
+
+	// wait for pod to be running (gather has completed)
+	log("waiting for gather to complete")
+	if err := o.waitForGatherToComplete(ctx, pod); err != nil {
+		if ctx.Err() != nil {
+			return timedOut()
+		}
+		log("gather never finished: %v", err)
+		return fmt.Errorf("gather never finished for pod %s: %s", pod.Name, err)
+	}
+
+	// copy the gathered files into the local destination dir
+	log("downloading gather output")
+	gathered, err := o.Client.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	if err != nil {
+		log("gather output not downloaded: %v\n", err)
+		return fmt.Errorf("unable to download output from pod %s: %s", pod.Name, err)
+	}
+	if err := o.copyFilesFromPod(gathered); err != nil {
+		log("gather output not downloaded: %v\n", err)
+		return fmt.Errorf("unable to download output from pod %s: %s", pod.Name, err)
+	}
+	return nil
+}
+
+// deletePod removes a gather pod that is no longer needed, unless temporary resources are kept.
+func (o *MustGatherOptions) deletePod(pod *corev1.Pod) {
+	if o.Keep {
+		return
+	}
+	zero := int64(0)
+	if err := o.Client.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &zero}); err != nil && !kerrors.IsNotFound(err) {
+		klog.V(4).Infof("unable to delete pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return
+	}
+	o.PrinterDeleted.PrintObj(pod, o.LogOut)
+}
+
 func newPodOutLogger(out io.Writer, podName string) func(string, ...interface{}) {
 	writer := newPrefixWriter(out, fmt.Sprintf("[%s] OUT", podName))
 	return func(format string, a ...interface{}) {
@@ -475,7 +638,7 @@ func (o *MustGatherOptions) getGatherContainerLogs(pod *corev1.Pod) error {
 		Object:           pod,
 		ConsumeRequestFn: logs.DefaultConsumeRequest,
 		LogsForObject:    polymorphichelpers.LogsForObjectFn,
-		IOStreams:        genericclioptions.IOStreams{Out: newPrefixWriter(o.Out, fmt.Sprintf("[%s] POD", pod.Name))},
+		IOStreams:        genericclioptions.IOStreams{Out: newPrefixWriter(o.Out, o.gatherLogPrefix(pod))},
 	}
 
 	for {
@@ -495,6 +658,15 @@ func (o *MustGatherOptions) getGatherContainerLogs(pod *corev1.Pod) error {
 	}
 }
 
+// gatherLogPrefix returns the prefix for the streamed logs of a gather pod, which includes
+// the plug-in image when more than one image is gathered.
+func (o *MustGatherOptions) gatherLogPrefix(pod *corev1.Pod) string {
+	if len(o.Images) > 1 {
+		return fmt.Sprintf("[%s] POD [%s]", pod.Name, pod.Spec.Containers[0].Image)
+	}
+	return fmt.Sprintf("[%s] POD", pod.Name)
+}
+
 func newPrefixWriter(out io.Writer, prefix string) io.Writer {
 	reader, writer := io.Pipe()
 	scanner := bufio.NewScanner(reader)
@@ -506,8 +678,8 @@ func newPrefixWriter(out io.Writer, prefix string) io.Writer {
 	return writer
 }
 
-func (o *MustGatherOptions) waitForGatherToComplete(pod *corev1.Pod) error {
-	return wait.PollImmediate(10*time.Second, o.Timeout, func() (bool, error) {
+func (o *MustGatherOptions) waitForGatherToComplete(ctx context.Context, pod *corev1.Pod) error {
+	return wait.PollImmediateUntilWithContext(ctx, 10*time.Second, func(ctx context.Context) (bool, error) {
 		return o.isGatherDone(pod)
 	})
 }
@@ -543,8 +715,8 @@ func (o *MustGatherOptions) isGatherDone(pod *corev1.Pod) (bool, error) {
 	return false, nil
 }
 
-func (o *MustGatherOptions) waitForGatherContainerRunning(pod *corev1.Pod) error {
-	return wait.PollImmediate(10*time.Second, o.Timeout, func() (bool, error) {
+func (o *MustGatherOptions) waitForGatherContainerRunning(ctx context.Context, pod *corev1.Pod) error {
+	return wait.PollImmediateUntilWithContext(ctx, 10*time.Second, func(ctx context.Context) (bool, error) {
 		var err error
 		if pod, err = o.Client.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{}); err == nil {
 			if len(pod.Status.ContainerStatuses) == 0 {
@@ -2,8 +2,13 @@ package mustgather
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -179,3 +184,102 @@ func TestGetNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestGatherImageTimeout(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	options := MustGatherOptions{
+		IOStreams:      genericclioptions.NewTestIOStreamsDiscard(),
+		Client:         client,
+		LogOut:         genericclioptions.NewTestIOStreamsDiscard().Out,
+		PrinterDeleted: printers.NewDiscardingPrinter(),
+		SourceDir:      "/must-gather/",
+		Timeout:        10 * time.Millisecond,
+	}
+
+	status := options.gatherImage("test", "registry.test/must-gather:stuck")
+	if status.Status != gatherTimedOut {
+		t.Fatalf("expected image to time out, got %#v", status)
+	}
+	if len(status.errs) != 1 || len(status.Errors) != 1 {
+		t.Fatalf("expected a single timeout error, got %#v", status)
+	}
+	pods, err := client.CoreV1().Pods("test").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("expected timed out gather pod to be removed, found %d pods", len(pods.Items))
+	}
+}
+
+func TestNewGatherStatus(t *testing.T) {
+	timeout := errGatherTimedOut{pod: "a", timeout: time.Minute}
+	failure := fmt.Errorf("gather never finished")
+	for name, tc := range map[string]struct {
+		errs     []error
+		expected string
+	}{
+		"succeeded":           {expected: gatherSucceeded},
+		"failed":              {errs: []error{failure}, expected: gatherFailed},
+		"timed out":           {errs: []error{timeout}, expected: gatherTimedOut},
+		"failed then timeout": {errs: []error{failure, timeout}, expected: gatherTimedOut},
+		"timeout then failed": {errs: []error{timeout, failure}, expected: gatherTimedOut},
+	} {
+		t.Run(name, func(t *testing.T) {
+			status := newGatherStatus("image", []string{"a", "b"}, tc.errs)
+			if status.Status != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, status.Status)
+			}
+			if len(status.Errors) != len(tc.errs) {
+				t.Errorf("expected %d errors, got %v", len(tc.errs), status.Errors)
+			}
+		})
+	}
+}
+
+func TestWriteGatherStatus(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), gatherStatusFile)
+	statuses := []gatherStatus{
+		newGatherStatus("one", []string{"must-gather-a"}, nil),
+		newGatherStatus("two", []string{"must-gather-b"}, []error{errGatherTimedOut{pod: "must-gather-b", timeout: time.Minute}}),
+	}
+	if err := writeGatherStatus(filename, statuses); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list gatherStatusList
+	if err := json.Unmarshal(data, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Images) != 2 {
+		t.Fatalf("unexpected status file: %s", data)
+	}
+	if list.Images[0].Image != "one" || list.Images[0].Status != gatherSucceeded {
+		t.Errorf("unexpected status for first image: %#v", list.Images[0])
+	}
+	if list.Images[1].Image != "two" || list.Images[1].Status != gatherTimedOut || len(list.Images[1].Errors) != 1 {
+		t.Errorf("unexpected status for second image: %#v", list.Images[1])
+	}
+}
+
+func TestGatherLogPrefix(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "must-gather-abcde"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "gather", Image: "quay.io/kubevirt/must-gather"}},
+		},
+	}
+
+	options := MustGatherOptions{Images: []string{"quay.io/kubevirt/must-gather"}}
+	if prefix := options.gatherLogPrefix(pod); prefix != "[must-gather-abcde] POD" {
+		t.Errorf("unexpected prefix for a single image: %s", prefix)
+	}
+
+	options.Images = append(options.Images, "quay.io/openshift/origin-must-gather")
+	if prefix := options.gatherLogPrefix(pod); prefix != "[must-gather-abcde] POD [quay.io/kubevirt/must-gather]" {
+		t.Errorf("unexpected prefix for multiple images: %s", prefix)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -54,6 +55,15 @@ var (
 
 		# Display cron log file from all masters
 		oc adm node-logs --role master --path=cron
+
+		# Show kubelet logs from the last two hours matching a pattern
+		oc adm node-logs node-1 -u kubelet --since=2h --grep=error
+
+		# Show the CRI-O and kubelet logs of a node from the previous boot
+		oc adm node-logs node-1 --unit=crio --unit=kubelet --boot=-1
+
+		# Show kubelet logs from a node without compressing the logs in transit
+		oc adm node-logs node-1 -u kubelet --compress=false
 	`)
 )
 
@@ -81,6 +91,9 @@ type LogsOptions struct {
 	Raw   bool
 	Unify bool
 
+	// Compress requests gzip encoded responses from the node logs endpoint
+	Compress bool
+
 	RESTClientGetter func(mapping *meta.RESTMapping) (resource.RESTClient, error)
 	Builder          *resource.Builder
 
@@ -92,6 +105,7 @@ func NewLogsOptions(streams genericclioptions.IOStreams) *LogsOptions {
 		Path:              "journal",
 		IOStreams:         streams,
 		GrepCaseSensitive: true,
+		Compress:          true,
 	}
 }
 
@@ -114,11 +128,11 @@ func NewCmdLogs(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.
 
 	cmd.Flags().StringVar(&o.Path, "path", o.Path, "Retrieve the specified path within the node's /var/logs/ folder. The 'journal' value will allow querying the journal on supported operating systems.")
 
-	cmd.Flags().StringSliceVarP(&o.Units, "unit", "u", o.Units, "Return log entries from the specified unit(s). Only applies to node journal logs.")
+	cmd.Flags().StringSliceVarP(&o.Units, "unit", "u", o.Units, "Return log entries from the specified unit(s). Each unit is requested separately and units without entries are reported. Only applies to node journal logs.")
 	cmd.Flags().StringVarP(&o.Grep, "grep", "g", o.Grep, "Filter log entries by the provided regex pattern. Only applies to node journal logs.")
 	cmd.Flags().BoolVar(&o.GrepCaseSensitive, "case-sensitive", o.GrepCaseSensitive, "Filters are case sensitive by default. Pass --case-sensitive=false to do a case insensitive filter.")
-	cmd.Flags().StringVar(&o.SinceTime, "since", o.SinceTime, "Return logs after a specific ISO timestamp or relative date. Only applies to node journal logs.")
-	cmd.Flags().StringVar(&o.UntilTime, "until", o.UntilTime, "Return logs before a specific ISO timestamp or relative date. Only applies to node journal logs.")
+	cmd.Flags().StringVar(&o.SinceTime, "since", o.SinceTime, "Return logs after a specific ISO or RFC3339 timestamp, a duration before now (e.g. 2h), or relative date. Only applies to node journal logs.")
+	cmd.Flags().StringVar(&o.UntilTime, "until", o.UntilTime, "Return logs before a specific ISO or RFC3339 timestamp, a duration before now (e.g. 30m), or relative date. Only applies to node journal logs.")
 	cmd.Flags().IntVar(&o.Boot, "boot", o.Boot, " Show messages from a specific boot. Use negative numbers, allowed [-100, 0], passing invalid boot offset will fail retrieving logs. Only applies to node journal logs.")
 	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Display journal logs in an alternate format (short, cat, json, short-unix). Only applies to node journal logs.")
 	cmd.Flags().IntVar(&o.Tail, "tail", o.Tail, "Return up to this many lines (not more than 100k) from the end of the log. Only applies to node journal logs.")
@@ -127,6 +141,7 @@ func NewCmdLogs(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.
 	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Selector (label query) to filter on.")
 	cmd.Flags().BoolVar(&o.Raw, "raw", o.Raw, "Perform no transformation of the returned data.")
 	cmd.Flags().BoolVar(&o.Unify, "unify", o.Unify, "Interleave logs by sorting the output. Defaults on when viewing node journal logs.")
+	cmd.Flags().BoolVar(&o.Compress, "compress", o.Compress, "If true, request gzip compressed logs from the node to reduce bandwidth. The output is decompressed locally, and uncompressed responses are used as is.")
 
 	return cmd
 }
@@ -138,6 +153,12 @@ func (o *LogsOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []st
 
 	o.Resources = args
 
+	var err error
+	o.SinceTime, o.UntilTime, err = journalTimeRange(o.SinceTime, o.UntilTime, time.Now())
+	if err != nil {
+		return err
+	}
+
 	o.RESTClientGetter = f.UnstructuredClientForMapping
 
 	builder := f.NewBuilder().
@@ -176,20 +197,58 @@ func (o LogsOptions) Validate() error {
 	return nil
 }
 
+// journalTimeRange converts --since and --until values given as a duration before now or as
+// an RFC3339 timestamp into a form journalctl accepts, and returns an error when both bound
+// the range and since is after until. Other values are passed to journalctl unchanged.
+func journalTimeRange(since, until string, now time.Time) (string, string, error) {
+	sinceParam, sinceTime := journalTime(since, now)
+	untilParam, untilTime := journalTime(until, now)
+	if sinceTime != nil && untilTime != nil && sinceTime.After(*untilTime) {
+		return "", "", fmt.Errorf("--since=%s is after --until=%s, no logs would be returned", since, until)
+	}
+	return sinceParam, untilParam, nil
+}
+
+// journalTime returns the journalctl time for value and, when value names a specific
+// time, that time.
+func journalTime(value string, now time.Time) (string, *time.Time) {
+	const journalFormat = "2006-01-02 15:04:05"
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		t := now.Add(-d)
+		return fmt.Sprintf("-%ds", int64(d.Round(time.Second)/time.Second)), &t
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC().Format(journalFormat) + " UTC", &t
+	}
+	for _, layout := range []string{journalFormat, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return value, &t
+		}
+	}
+	return value, nil
+}
+
 // logRequest abstracts retrieving the content of the node logs endpoint which is normally
 // either directory content or a file. It supports raw retrieval for use with the journal
 // endpoint, and formats the HTML returned by a directory listing into a more user friendly
 // output.
 type logRequest struct {
 	node string
+	// unit is the journal unit the request is limited to, if any
+	unit string
 	req  *rest.Request
 	err  error
+	// empty is set once the request completed without returning any journal entries
+	empty bool
 
 	// raw is set to true when we are viewing the journal and wish to skip prefixing
 	raw bool
 	// skipPrefix bypasses prefixing if the user knows that a unique identifier is already
 	// in the file
 	skipPrefix bool
+	// compressed is set to true when the response was requested with gzip encoding and
+	// must be decompressed before use
+	compressed bool
 }
 
 // WriteTo prefixes the error message with the current node if necessary
@@ -205,15 +264,30 @@ func (req *logRequest) WriteRequest(out io.Writer) error {
 }
 
 func (req *logRequest) writeTo(out io.Writer) error {
-	in, err := req.req.Stream(context.TODO())
+	stream, err := req.req.Stream(context.TODO())
 	if err != nil {
 		return err
 	}
-	defer in.Close()
+	defer stream.Close()
+
+	// the server may ignore the requested encoding, in which case the
+	// content is used as is
+	var in io.Reader = stream
+	if req.compressed {
+		if in, err = optionallyDecompressReader(stream); err != nil {
+			return err
+		}
+	}
 
 	// raw output implies we may be getting binary content directly
 	// from the remote and so we want to perform no translation
 	if req.raw {
+		// record whether the unit had any entries so it can be reported
+		if len(req.unit) > 0 {
+			w := &entryWriter{w: out}
+			defer func() { req.empty = w.empty() }()
+			out = w
+		}
 		// TODO: optionallyDecompress should be implemented by checking
 		// the content-encoding of the response, but we perform optional
 		// decompression here in case the content of the logs on the server
@@ -255,41 +329,20 @@ func (o LogsOptions) RunLogs() error {
 			path += "/"
 		}
 
-		req := client.Get().RequestURI(path).
-			SetHeader("Accept", "text/plain, */*").
-			SetHeader("Accept-Encoding", "gzip")
-		if o.Path == "journal" {
-			if len(o.UntilTime) > 0 {
-				req.Param("until", o.UntilTime)
-			}
-			if len(o.SinceTime) > 0 {
-				req.Param("since", o.SinceTime)
-			}
-			if len(o.Output) > 0 {
-				req.Param("output", o.Output)
-			}
-			if o.BootChanaged {
-				req.Param("boot", fmt.Sprintf("%d", o.Boot))
-			}
-			if len(o.Units) > 0 {
-				for _, unit := range o.Units {
-					req.Param("unit", unit)
-				}
-			}
-			if len(o.Grep) > 0 {
-				req.Param("grep", o.Grep)
-				req.Param("case-sensitive", fmt.Sprintf("%t", o.GrepCaseSensitive))
-			}
-			if o.Tail > 0 {
-				req.Param("tail", strconv.Itoa(o.Tail))
-			}
+		// request each unit separately so that a unit without entries can be reported
+		units := []string{""}
+		if o.Path == "journal" && len(o.Units) > 0 {
+			units = o.Units
+		}
+		for _, unit := range units {
+			requests = append(requests, &logRequest{
+				node:       info.Name,
+				unit:       unit,
+				req:        o.newRequest(client, path, unit),
+				raw:        o.Raw || o.Path == "journal",
+				compressed: o.Compress,
+			})
 		}
-
-		requests = append(requests, &logRequest{
-			node: info.Name,
-			req:  req,
-			raw:  o.Raw || o.Path == "journal",
-		})
 		return nil
 	})
 	if err != nil {
@@ -339,6 +392,12 @@ func (o LogsOptions) RunLogs() error {
 		}
 	}
 
+	for _, req := range requests {
+		if req.empty && req.err == nil {
+			fmt.Fprintf(o.ErrOut, "info: No log entries for unit %s on node %s\n", req.unit, req.node)
+		}
+	}
+
 	if len(errs) > 0 {
 		for _, err := range errs {
 			fmt.Fprintf(o.ErrOut, "error: %v\n", err)
@@ -354,24 +413,95 @@ func (o LogsOptions) RunLogs() error {
 	return nil
 }
 
+// newRequest builds the request for the node logs endpoint at path, including the journal
+// query parameters limited to unit if it is set, and the requested transport encoding.
+func (o LogsOptions) newRequest(client resource.RESTClient, path, unit string) *rest.Request {
+	encoding := "identity"
+	if o.Compress {
+		encoding = "gzip"
+	}
+	req := client.Get().RequestURI(path).
+		SetHeader("Accept", "text/plain, */*").
+		SetHeader("Accept-Encoding", encoding)
+	if o.Path == "journal" {
+		if len(o.UntilTime) > 0 {
+			req.Param("until", o.UntilTime)
+		}
+		if len(o.SinceTime) > 0 {
+			req.Param("since", o.SinceTime)
+		}
+		if len(o.Output) > 0 {
+			req.Param("output", o.Output)
+		}
+		if o.BootChanaged {
+			req.Param("boot", fmt.Sprintf("%d", o.Boot))
+		}
+		if len(unit) > 0 {
+			req.Param("unit", unit)
+		}
+		if len(o.Grep) > 0 {
+			req.Param("grep", o.Grep)
+			req.Param("case-sensitive", fmt.Sprintf("%t", o.GrepCaseSensitive))
+		}
+		if o.Tail > 0 {
+			req.Param("tail", strconv.Itoa(o.Tail))
+		}
+	}
+	return req
+}
+
+// journalNoEntries is printed by journalctl when no entries match the query.
+const journalNoEntries = "-- No entries --"
+
+// entryWriter records whether anything other than the journalctl notice that no entries
+// matched was written to w.
+type entryWriter struct {
+	w    io.Writer
+	head []byte
+	n    int
+}
+
+func (w *entryWriter) Write(p []byte) (int, error) {
+	if remaining := len(journalNoEntries) + 2 - len(w.head); remaining > 0 {
+		if remaining > len(p) {
+			remaining = len(p)
+		}
+		w.head = append(w.head, p[:remaining]...)
+	}
+	w.n += len(p)
+	return w.w.Write(p)
+}
+
+func (w *entryWriter) empty() bool {
+	if w.n == 0 {
+		return true
+	}
+	return w.n == len(w.head) && strings.TrimSpace(string(w.head)) == journalNoEntries
+}
+
 func optionallyDecompress(out io.Writer, in io.Reader) error {
+	r, err := optionallyDecompressReader(in)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, r)
+	return err
+}
+
+// optionallyDecompressReader returns a reader for the decompressed content of in if it
+// is gzipped, or a reader for the unmodified content otherwise.
+func optionallyDecompressReader(in io.Reader) (io.Reader, error) {
 	bufferSize := 4096
 	buf := bufio.NewReaderSize(in, bufferSize)
 	head, err := buf.Peek(1024)
 	if err != nil && err != io.EOF {
-		return err
+		return nil, err
 	}
 	if _, err := gzip.NewReader(bytes.NewBuffer(head)); err != nil {
 		// not a gzipped stream
-		_, err = io.Copy(out, buf)
-		return err
-	}
-	r, err := gzip.NewReader(buf)
-	if err != nil {
-		return err
+		return buf, nil
 	}
-	_, err = io.Copy(out, r)
-	return err
+	return gzip.NewReader(buf)
 }
 
 func outputDirectoryEntriesOrContent(out io.Writer, in io.Reader, prefix []byte) error {
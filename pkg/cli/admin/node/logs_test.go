@@ -4,8 +4,15 @@ import (
 	"bytes"
 	"compress/gzip"
 	"io"
+	"io/ioutil"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubectl/pkg/scheme"
 )
 
 func Test_optionallyDecompress(t *testing.T) {
@@ -43,6 +50,104 @@ func gzipped(s string) io.Reader {
 	return out
 }
 
+func TestCompressDefault(t *testing.T) {
+	cmd := NewCmdLogs(nil, genericclioptions.NewTestIOStreamsDiscard())
+	if compress, err := cmd.Flags().GetBool("compress"); err != nil || !compress {
+		t.Errorf("expected logs to be compressed by default, got %t (%v)", compress, err)
+	}
+	if err := cmd.Flags().Parse([]string{"--compress=false"}); err != nil {
+		t.Fatal(err)
+	}
+	if compress, _ := cmd.Flags().GetBool("compress"); compress {
+		t.Errorf("expected --compress=false to disable compression")
+	}
+}
+
+func Test_logRequest_compress(t *testing.T) {
+	content := strings.Repeat("Jan 01 00:00:00 node-1 kubelet[1234]: some journal content\n", 1000)
+	tests := []struct {
+		name           string
+		compress       bool
+		serverCompress bool
+		raw            bool
+		wantEncoding   string
+	}{
+		{name: "compression disabled", wantEncoding: "identity"},
+		{name: "compressed", compress: true, serverCompress: true, wantEncoding: "gzip"},
+		{name: "compressed raw", compress: true, serverCompress: true, raw: true, wantEncoding: "gzip"},
+		{name: "compression unsupported by server", compress: true, wantEncoding: "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotEncoding string
+			client := &fake.RESTClient{
+				NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+				Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+					gotEncoding = req.Header.Get("Accept-Encoding")
+					header := http.Header{}
+					var body io.Reader = bytes.NewBufferString(content)
+					if tt.serverCompress && gotEncoding == "gzip" {
+						header.Set("Content-Encoding", "gzip")
+						body = gzipped(content)
+					}
+					return &http.Response{StatusCode: http.StatusOK, Header: header, Body: ioutil.NopCloser(body)}, nil
+				}),
+			}
+			o := LogsOptions{Path: "journal", Compress: tt.compress}
+			req := &logRequest{
+				node:       "node-1",
+				req:        o.newRequest(client, "/api/v1/nodes/node-1/proxy/logs/journal", ""),
+				raw:        tt.raw,
+				skipPrefix: true,
+				compressed: o.Compress,
+			}
+			out := &bytes.Buffer{}
+			if err := req.WriteRequest(out); err != nil {
+				t.Fatal(err)
+			}
+			if gotEncoding != tt.wantEncoding {
+				t.Errorf("expected Accept-Encoding %q, got %q", tt.wantEncoding, gotEncoding)
+			}
+			if out.String() != content {
+				t.Errorf("output differs from the uncompressed content: %d bytes, expected %d", out.Len(), len(content))
+			}
+		})
+	}
+}
+
+func Test_logRequest_unit(t *testing.T) {
+	client := &fake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			content := "-- No entries --\n"
+			if units := req.URL.Query()["unit"]; len(units) != 1 {
+				t.Errorf("expected a single unit, got %v", units)
+			} else if units[0] == "kubelet" {
+				content = "Jan 01 00:00:00 node-1 kubelet[1234]: started\n"
+			}
+			if boot := req.URL.Query().Get("boot"); boot != "-1" {
+				t.Errorf("expected boot -1, got %q", boot)
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(bytes.NewBufferString(content))}, nil
+		}),
+	}
+	o := LogsOptions{Path: "journal", Boot: -1, BootChanaged: true}
+	for unit, empty := range map[string]bool{"crio": true, "kubelet": false} {
+		req := &logRequest{
+			node: "node-1",
+			unit: unit,
+			req:  o.newRequest(client, "/api/v1/nodes/node-1/proxy/logs/journal", unit),
+			raw:  true,
+		}
+		if err := req.WriteRequest(&bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+		if req.empty != empty {
+			t.Errorf("expected unit %s to be empty=%t", unit, empty)
+		}
+	}
+}
+
 func Test_outputDirectoryEntriesOrContent(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -123,3 +228,36 @@ func readers(all ...string) []Reader {
 	}
 	return out
 }
+
+func Test_journalTimeRange(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		since     string
+		until     string
+		wantSince string
+		wantUntil string
+		wantErr   bool
+	}{
+		{name: "empty"},
+		{name: "relative duration", since: "2h", wantSince: "-7200s"},
+		{name: "relative range", since: "2h", until: "90m", wantSince: "-7200s", wantUntil: "-5400s"},
+		{name: "rfc3339", since: "2021-06-01T10:00:00+02:00", until: "2021-06-01T11:00:00Z", wantSince: "2021-06-01 08:00:00 UTC", wantUntil: "2021-06-01 11:00:00 UTC"},
+		{name: "journal format passed through", since: "2021-06-01 10:00:00", wantSince: "2021-06-01 10:00:00"},
+		{name: "journal keywords passed through", since: "yesterday", until: "-1h", wantSince: "yesterday", wantUntil: "-1h"},
+		{name: "since after until", since: "30m", until: "1h", wantErr: true},
+		{name: "rfc3339 since after until", since: "2021-06-01T11:00:00Z", until: "2021-06-01T10:00:00Z", wantErr: true},
+		{name: "since after relative until", since: "2021-06-01T11:30:00Z", until: "1h", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since, until, err := journalTimeRange(tt.since, tt.until, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("journalTimeRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if since != tt.wantSince || until != tt.wantUntil {
+				t.Errorf("journalTimeRange() = %q, %q, want %q, %q", since, until, tt.wantSince, tt.wantUntil)
+			}
+		})
+	}
+}
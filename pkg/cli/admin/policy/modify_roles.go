@@ -242,8 +242,8 @@ func NewCmdAddClusterRoleToUser(f kcmdutil.Factory, streams genericclioptions.IO
 		},
 	}
 
-	cmd.Flags().StringVar(&o.RoleBindingName, "rolebinding-name", o.RoleBindingName, "Name of the rolebinding to modify or create. If left empty creates a new rolebindo.RoleBindingNameg with a default name")
-	cmd.Flags().StringSliceVarP(&o.SANames, "serviceaccount", "z", o.SANames, "service account in the current namespace to use o.SANamess a user")
+	cmd.Flags().StringVar(&o.RoleBindingName, "rolebinding-name", o.RoleBindingName, "Name of the rolebinding to modify or create. If left empty creates a new rolebinding with a default name")
+	cmd.Flags().StringSliceVarP(&o.SANames, "serviceaccount", "z", o.SANames, "service account in the current namespace to use as a user")
 
 	kcmdutil.AddDryRunFlag(cmd)
 	o.PrintFlags.AddFlags(cmd)
@@ -302,8 +302,8 @@ func (o *RoleModificationOptions) checkRoleBindingNamespace(f kcmdutil.Factory)
 	}
 	if len(o.RoleNamespace) > 0 {
 		if o.RoleBindingNamespace != o.RoleNamespace {
-			return fmt.Errorf("role binding in namespace %q can't reference role in different namespace %q",
-				o.RoleBindingNamespace, o.RoleNamespace)
+			return fmt.Errorf("role binding in namespace %q can't reference role in different namespace %q: use -n %s, or a cluster role to share the role between namespaces",
+				o.RoleBindingNamespace, o.RoleNamespace, o.RoleNamespace)
 		}
 		o.RoleKind = "Role"
 	} else {
@@ -427,11 +427,11 @@ func (o *RoleModificationOptions) getRoleBinding() (*roleBindingAbstraction, boo
 
 	// Check that we update the rolebinding for the intended role.
 	if roleBinding.RoleName() != o.RoleName {
-		return nil, false, fmt.Errorf("rolebinding %s found for role %s, not %s",
+		return nil, false, fmt.Errorf("rolebinding %s already exists for role %s, not %s: choose a different --rolebinding-name",
 			o.RoleBindingName, roleBinding.RoleName(), o.RoleName)
 	}
 	if roleBinding.RoleKind() != o.RoleKind {
-		return nil, false, fmt.Errorf("rolebinding %s found for %q, not %q",
+		return nil, false, fmt.Errorf("rolebinding %s already exists for a %s, not a %s: choose a different --rolebinding-name",
 			o.RoleBindingName, roleBinding.RoleKind(), o.RoleKind)
 	}
 
@@ -16,6 +16,7 @@ import (
 	"k8s.io/cli-runtime/pkg/printers"
 	fakeclient "k8s.io/client-go/kubernetes/fake"
 	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	kcmdtesting "k8s.io/kubectl/pkg/cmd/testing"
 
 	userv1 "github.com/openshift/api/user/v1"
 	fakeuserclient "github.com/openshift/client-go/user/clientset/versioned/fake"
@@ -589,6 +590,98 @@ func TestModifyNamedLocalRoleBinding(t *testing.T) {
 		modifyRoleAndCheck(t, o, tcName, tc.action, tc.expectedRoleBindingName, tc.expectedSubjects, tc.expectedRoleBindingList)
 	}
 }
+
+func TestAddRoleNamedRoleBindingConflict(t *testing.T) {
+	existing := &rbacv1.RoleBindingList{
+		Items: []rbacv1.RoleBinding{{
+			ObjectMeta: metav1.ObjectMeta{Name: "custom", Namespace: "ns"},
+			Subjects:   []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Name: "bar", Kind: rbacv1.UserKind}},
+			RoleRef:    rbacv1.RoleRef{Name: "view", Kind: "ClusterRole"},
+		}},
+	}
+	tests := map[string]struct {
+		roleName    string
+		roleKind    string
+		expectedErr string
+	}{
+		"different role": {
+			roleName:    "edit",
+			roleKind:    "ClusterRole",
+			expectedErr: "rolebinding custom already exists for role view, not edit: choose a different --rolebinding-name",
+		},
+		"different role kind": {
+			roleName:    "view",
+			roleKind:    "Role",
+			expectedErr: "rolebinding custom already exists for a ClusterRole, not a Role: choose a different --rolebinding-name",
+		},
+	}
+	for tcName, tc := range tests {
+		o := &RoleModificationOptions{
+			RoleBindingNamespace: "ns",
+			RoleBindingName:      "custom",
+			RoleKind:             tc.roleKind,
+			RoleName:             tc.roleName,
+			RbacClient:           fakeclient.NewSimpleClientset(existing.DeepCopy()).RbacV1(),
+			Users:                []string{"foo"},
+			PrintFlags:           genericclioptions.NewPrintFlags(""),
+			ToPrinter:            func(string) (printers.ResourcePrinter, error) { return printers.NewDiscardingPrinter(), nil },
+		}
+
+		err := o.AddRole()
+		if err == nil || err.Error() != tc.expectedErr {
+			t.Errorf("%s: expected error %q, got %v", tcName, tc.expectedErr, err)
+		}
+
+		roleBinding, err := getRoleBindingAbstraction(o.RbacClient, "custom", "ns")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tcName, err)
+		}
+		if subjects := roleBinding.Subjects(); len(subjects) != 1 || subjects[0].Name != "bar" {
+			t.Errorf("%s: expected existing rolebinding to be left unchanged, got subjects %v", tcName, subjects)
+		}
+	}
+}
+
+func TestCheckRoleBindingNamespace(t *testing.T) {
+	tests := map[string]struct {
+		roleNamespace    string
+		expectedRoleKind string
+		expectedErr      string
+	}{
+		"cluster role": {
+			expectedRoleKind: "ClusterRole",
+		},
+		"role in the same namespace": {
+			roleNamespace:    "ns",
+			expectedRoleKind: "Role",
+		},
+		"role in another namespace": {
+			roleNamespace: "shared",
+			expectedErr:   `role binding in namespace "ns" can't reference role in different namespace "shared": use -n shared, or a cluster role to share the role between namespaces`,
+		},
+	}
+	for tcName, tc := range tests {
+		tf := kcmdtesting.NewTestFactory().WithNamespace("ns")
+		defer tf.Cleanup()
+
+		o := &RoleModificationOptions{RoleNamespace: tc.roleNamespace}
+		err := o.checkRoleBindingNamespace(tf)
+		if len(tc.expectedErr) > 0 {
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("%s: expected error %q, got %v", tcName, tc.expectedErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tcName, err)
+			continue
+		}
+		if o.RoleBindingNamespace != "ns" || o.RoleKind != tc.expectedRoleKind {
+			t.Errorf("%s: unexpected role binding namespace %q and role kind %q", tcName, o.RoleBindingNamespace, o.RoleKind)
+		}
+	}
+}
+
 func TestModifyRoleBindingWarnings(t *testing.T) {
 	type clusterState struct {
 		roles               *rbacv1.RoleList
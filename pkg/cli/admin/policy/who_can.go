@@ -3,21 +3,27 @@ package policy
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
+	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 
 	authorizationv1 "github.com/openshift/api/authorization/v1"
 	authorizationv1typedclient "github.com/openshift/client-go/authorization/clientset/versioned/typed/authorization/v1"
@@ -25,6 +31,27 @@ import (
 
 const WhoCanRecommendedName = "who-can"
 
+// serviceAccountUserPrefix is the prefix of the user names of service accounts.
+const serviceAccountUserPrefix = "system:serviceaccount:"
+
+var (
+	whoCanLong = templates.LongDesc(`
+		List who can perform the specified action on a resource
+
+		With -o json or -o yaml, the users, groups and service accounts that can perform the
+		action are printed as separate sorted lists, followed by the cluster role bindings and
+		role bindings that grant the access. Role bindings are only looked up in the current
+		namespace, or in all namespaces with --all-namespaces.`)
+
+	whoCanExample = templates.Examples(`
+		# List who can delete pods in the current namespace
+		oc adm policy who-can delete pods
+
+		# Record who can read secrets in any namespace, and the bindings that grant it, as JSON
+		oc adm policy who-can get secrets --all-namespaces -o json
+	`)
+)
+
 type WhoCanOptions struct {
 	PrintFlags *genericclioptions.PrintFlags
 
@@ -33,6 +60,7 @@ type WhoCanOptions struct {
 	allNamespaces    bool
 	bindingNamespace string
 	client           authorizationv1typedclient.AuthorizationV1Interface
+	rbacClient       rbacv1client.RbacV1Interface
 
 	verb         string
 	resource     schema.GroupVersionResource
@@ -53,9 +81,10 @@ func NewWhoCanOptions(streams genericclioptions.IOStreams) *WhoCanOptions {
 func NewCmdWhoCan(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
 	o := NewWhoCanOptions(streams)
 	cmd := &cobra.Command{
-		Use:   "who-can VERB RESOURCE [NAME]",
-		Short: "List who can perform the specified action on a resource",
-		Long:  "List who can perform the specified action on a resource",
+		Use:     "who-can VERB RESOURCE [NAME]",
+		Short:   "List who can perform the specified action on a resource",
+		Long:    whoCanLong,
+		Example: whoCanExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.complete(f, cmd, args))
 			kcmdutil.CheckErr(o.run())
@@ -93,6 +122,10 @@ func (o *WhoCanOptions) complete(f kcmdutil.Factory, cmd *cobra.Command, args []
 	if err != nil {
 		return err
 	}
+	o.rbacClient, err = rbacv1client.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
 
 	o.bindingNamespace, _, err = f.ToRawKubeConfigLoader().Namespace()
 	if err != nil {
@@ -149,6 +182,10 @@ func (o *WhoCanOptions) run() error {
 		return err
 	}
 
+	if output := *o.PrintFlags.OutputFormat; output == "json" || output == "yaml" {
+		return o.printResult(resourceAccessReviewResponse, output)
+	}
+
 	message := bytes.NewBuffer([]byte{})
 	fmt.Fprintln(message)
 
@@ -190,3 +227,147 @@ func (o *WhoCanOptions) run() error {
 
 	return p.PrintObj(resourceAccessReviewResponse, o.Out)
 }
+
+// WhoCanResult is the output of who-can with -o json or -o yaml.
+type WhoCanResult struct {
+	Namespace       string   `json:"namespace"`
+	Verb            string   `json:"verb"`
+	Resource        string   `json:"resource"`
+	ResourceName    string   `json:"resourceName,omitempty"`
+	Users           []string `json:"users"`
+	Groups          []string `json:"groups"`
+	ServiceAccounts []string `json:"serviceAccounts"`
+	// Bindings are the cluster role bindings and role bindings that grant the access.
+	Bindings        []WhoCanBinding `json:"bindings"`
+	EvaluationError string          `json:"evaluationError,omitempty"`
+}
+
+// WhoCanBinding is a cluster role binding or role binding that grants the access.
+type WhoCanBinding struct {
+	Kind      string           `json:"kind"`
+	Namespace string           `json:"namespace,omitempty"`
+	Name      string           `json:"name"`
+	RoleRef   rbacv1.RoleRef   `json:"roleRef"`
+	Subjects  []rbacv1.Subject `json:"subjects"`
+}
+
+func (o *WhoCanOptions) printResult(response *authorizationv1.ResourceAccessReviewResponse, output string) error {
+	result := WhoCanResult{
+		Namespace:       response.Namespace,
+		Verb:            o.verb,
+		Resource:        o.resource.GroupResource().String(),
+		ResourceName:    o.resourceName,
+		Users:           []string{},
+		Groups:          sets.NewString(response.GroupsSlice...).List(),
+		ServiceAccounts: []string{},
+		EvaluationError: response.EvaluationError,
+	}
+	for _, user := range sets.NewString(response.UsersSlice...).List() {
+		if strings.HasPrefix(user, serviceAccountUserPrefix) {
+			result.ServiceAccounts = append(result.ServiceAccounts, user)
+			continue
+		}
+		result.Users = append(result.Users, user)
+	}
+
+	bindings, err := o.grantingBindings()
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "warning: unable to list the bindings that grant the access: %v\n", err)
+	}
+	result.Bindings = bindings
+
+	var data []byte
+	if output == "yaml" {
+		data, err = yaml.Marshal(result)
+	} else {
+		data, err = json.MarshalIndent(result, "", "    ")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "%s\n", data)
+	return nil
+}
+
+// grantingBindings returns the cluster role bindings, and the role bindings in the binding
+// namespace or all namespaces, whose role allows the action.
+func (o *WhoCanOptions) grantingBindings() ([]WhoCanBinding, error) {
+	namespace := o.bindingNamespace
+	if o.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	clusterRoles, err := o.rbacClient.ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return []WhoCanBinding{}, err
+	}
+	clusterRoleRules := map[string][]rbacv1.PolicyRule{}
+	for _, role := range clusterRoles.Items {
+		clusterRoleRules[role.Name] = role.Rules
+	}
+	roles, err := o.rbacClient.Roles(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return []WhoCanBinding{}, err
+	}
+	roleRules := map[string][]rbacv1.PolicyRule{}
+	for _, role := range roles.Items {
+		roleRules[role.Namespace+"/"+role.Name] = role.Rules
+	}
+	rulesFor := func(namespace string, ref rbacv1.RoleRef) []rbacv1.PolicyRule {
+		if ref.Kind == "ClusterRole" {
+			return clusterRoleRules[ref.Name]
+		}
+		return roleRules[namespace+"/"+ref.Name]
+	}
+
+	bindings := []WhoCanBinding{}
+	clusterRoleBindings, err := o.rbacClient.ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return bindings, err
+	}
+	for _, binding := range clusterRoleBindings.Items {
+		if len(binding.Subjects) > 0 && o.rulesAllow(rulesFor("", binding.RoleRef)) {
+			bindings = append(bindings, WhoCanBinding{Kind: "ClusterRoleBinding", Name: binding.Name, RoleRef: binding.RoleRef, Subjects: binding.Subjects})
+		}
+	}
+	roleBindings, err := o.rbacClient.RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return bindings, err
+	}
+	for _, binding := range roleBindings.Items {
+		if len(binding.Subjects) > 0 && o.rulesAllow(rulesFor(binding.Namespace, binding.RoleRef)) {
+			bindings = append(bindings, WhoCanBinding{Kind: "RoleBinding", Namespace: binding.Namespace, Name: binding.Name, RoleRef: binding.RoleRef, Subjects: binding.Subjects})
+		}
+	}
+	sort.SliceStable(bindings, func(i, j int) bool {
+		if bindings[i].Kind != bindings[j].Kind {
+			return bindings[i].Kind < bindings[j].Kind
+		}
+		if bindings[i].Namespace != bindings[j].Namespace {
+			return bindings[i].Namespace < bindings[j].Namespace
+		}
+		return bindings[i].Name < bindings[j].Name
+	})
+	return bindings, nil
+}
+
+// rulesAllow returns true if any of rules allows the verb on the resource.
+func (o *WhoCanOptions) rulesAllow(rules []rbacv1.PolicyRule) bool {
+	for _, rule := range rules {
+		if matchesRule(rule.Verbs, o.verb) &&
+			matchesRule(rule.APIGroups, o.resource.Group) &&
+			matchesRule(rule.Resources, o.resource.Resource) &&
+			(len(rule.ResourceNames) == 0 || matchesRule(rule.ResourceNames, o.resourceName)) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRule(values []string, value string) bool {
+	for _, v := range values {
+		if v == rbacv1.VerbAll || v == value {
+			return true
+		}
+	}
+	return false
+}
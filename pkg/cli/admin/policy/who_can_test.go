@@ -0,0 +1,97 @@
+package policy
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	authorizationv1 "github.com/openshift/api/authorization/v1"
+)
+
+func TestWhoCanPrintResult(t *testing.T) {
+	subjects := []rbacv1.Subject{{Kind: "User", Name: "alice"}}
+	client := fake.NewSimpleClientset(
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+		},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "view"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+		},
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-deleter", Namespace: "test"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+		},
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "one-pod-deleter", Namespace: "test"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"pods"}, ResourceNames: []string{"other"}}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-admins"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: "Group", Name: "system:cluster-admins"}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "viewers"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+			Subjects:   subjects,
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleters", Namespace: "test"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "pod-deleter"},
+			Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Namespace: "test", Name: "cleaner"}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "one-deleter", Namespace: "test"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "one-pod-deleter"},
+			Subjects:   subjects,
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "deleters", Namespace: "other"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   subjects,
+		},
+	)
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	o := NewWhoCanOptions(streams)
+	o.bindingNamespace = "test"
+	o.rbacClient = client.RbacV1()
+	o.verb = "delete"
+	o.resource = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	response := &authorizationv1.ResourceAccessReviewResponse{
+		Namespace:   "test",
+		UsersSlice:  []string{"system:serviceaccount:test:cleaner", "system:admin"},
+		GroupsSlice: []string{"system:cluster-admins"},
+	}
+	if err := o.printResult(response, "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	result := WhoCanResult{}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("%v: %s", err, out.String())
+	}
+	expected := WhoCanResult{
+		Namespace:       "test",
+		Verb:            "delete",
+		Resource:        "pods",
+		Users:           []string{"system:admin"},
+		Groups:          []string{"system:cluster-admins"},
+		ServiceAccounts: []string{"system:serviceaccount:test:cleaner"},
+		Bindings: []WhoCanBinding{
+			{Kind: "ClusterRoleBinding", Name: "cluster-admins", RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"}, Subjects: []rbacv1.Subject{{Kind: "Group", Name: "system:cluster-admins"}}},
+			{Kind: "RoleBinding", Namespace: "test", Name: "deleters", RoleRef: rbacv1.RoleRef{Kind: "Role", Name: "pod-deleter"}, Subjects: []rbacv1.Subject{{Kind: "ServiceAccount", Namespace: "test", Name: "cleaner"}}},
+		},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected result:\n%s", out.String())
+	}
+}
@@ -130,10 +130,31 @@ func (o PruneBuildsOptions) Validate() error {
 
 // Run contains all the necessary functionality for the OpenShift cli prune builds command.
 func (o PruneBuildsOptions) Run() error {
-	buildConfigList, err := o.BuildClient.BuildConfigs(o.Namespace).List(context.TODO(), metav1.ListOptions{})
+	pruner, err := o.LoadPruner()
 	if err != nil {
 		return err
 	}
+
+	w := tabwriter.NewWriter(o.Out, 10, 4, 3, ' ', 0)
+	defer w.Flush()
+
+	buildDeleter := &describingBuildDeleter{w: w}
+
+	if o.Confirm {
+		buildDeleter.delegate = NewBuildDeleter(o.BuildClient)
+	} else {
+		fmt.Fprintln(os.Stderr, "Dry run enabled - no modifications will be made. Add --confirm to remove builds")
+	}
+
+	return pruner.Prune(buildDeleter)
+}
+
+// LoadPruner lists the builds and build configs in the namespace and returns a pruner for them.
+func (o PruneBuildsOptions) LoadPruner() (Pruner, error) {
+	buildConfigList, err := o.BuildClient.BuildConfigs(o.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
 	buildConfigs := []*buildv1.BuildConfig{}
 	for i := range buildConfigList.Items {
 		buildConfigs = append(buildConfigs, &buildConfigList.Items[i])
@@ -141,7 +162,7 @@ func (o PruneBuildsOptions) Run() error {
 
 	buildList, err := o.BuildClient.Builds(o.Namespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	builds := []*buildv1.Build{}
 	for i := range buildList.Items {
@@ -156,20 +177,7 @@ func (o PruneBuildsOptions) Run() error {
 		BuildConfigs:    buildConfigs,
 		Builds:          builds,
 	}
-	pruner := NewPruner(options)
-
-	w := tabwriter.NewWriter(o.Out, 10, 4, 3, ' ', 0)
-	defer w.Flush()
-
-	buildDeleter := &describingBuildDeleter{w: w}
-
-	if o.Confirm {
-		buildDeleter.delegate = NewBuildDeleter(o.BuildClient)
-	} else {
-		fmt.Fprintln(os.Stderr, "Dry run enabled - no modifications will be made. Add --confirm to remove builds")
-	}
-
-	return pruner.Prune(buildDeleter)
+	return NewPruner(options), nil
 }
 
 // describingBuildDeleter prints information about each build it removes.
@@ -141,10 +141,32 @@ func (o PruneDeploymentsOptions) Validate() error {
 
 // Run contains all the necessary functionality for the OpenShift cli prune deployments command.
 func (o PruneDeploymentsOptions) Run() error {
-	deploymentConfigList, err := o.AppsClient.DeploymentConfigs(o.Namespace).List(context.TODO(), metav1.ListOptions{})
+	pruner, err := o.LoadPruner()
 	if err != nil {
 		return err
 	}
+
+	w := tabwriter.NewWriter(o.Out, 10, 4, 3, ' ', 0)
+	defer w.Flush()
+
+	replicaDeleter := &describingReplicaDeleter{w: w}
+
+	if o.Confirm {
+		replicaDeleter.delegate = NewReplicaDeleter(o.KubeClient, o.KAppsClient)
+	} else {
+		fmt.Fprintln(os.Stderr, "Dry run enabled - no modifications will be made. Add --confirm to remove deployments")
+	}
+
+	return pruner.Prune(replicaDeleter)
+}
+
+// LoadPruner lists the deployment configs and replication controllers in the namespace, and the
+// deployments and replica sets as well with ReplicaSets, and returns a pruner for them.
+func (o PruneDeploymentsOptions) LoadPruner() (Pruner, error) {
+	deploymentConfigList, err := o.AppsClient.DeploymentConfigs(o.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
 	deployments := []metav1.Object{}
 	for i := range deploymentConfigList.Items {
 		deployments = append(deployments, &deploymentConfigList.Items[i])
@@ -153,7 +175,7 @@ func (o PruneDeploymentsOptions) Run() error {
 	if o.ReplicaSets {
 		deploymentList, err := o.KAppsClient.Deployments(o.Namespace).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for i := range deploymentList.Items {
@@ -163,7 +185,7 @@ func (o PruneDeploymentsOptions) Run() error {
 
 	replicationControllerList, err := o.KubeClient.ReplicationControllers(o.Namespace).List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	replicas := []metav1.Object{}
 	for i := range replicationControllerList.Items {
@@ -173,7 +195,7 @@ func (o PruneDeploymentsOptions) Run() error {
 	if o.ReplicaSets {
 		replicaSetList, err := o.KAppsClient.ReplicaSets(o.Namespace).List(context.TODO(), metav1.ListOptions{})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for i := range replicaSetList.Items {
@@ -190,20 +212,7 @@ func (o PruneDeploymentsOptions) Run() error {
 		Deployments:     deployments,
 		Replicas:        replicas,
 	}
-	pruner := NewPruner(options)
-
-	w := tabwriter.NewWriter(o.Out, 10, 4, 3, ' ', 0)
-	defer w.Flush()
-
-	replicaDeleter := &describingReplicaDeleter{w: w}
-
-	if o.Confirm {
-		replicaDeleter.delegate = NewReplicaDeleter(o.KubeClient, o.KAppsClient)
-	} else {
-		fmt.Fprintln(os.Stderr, "Dry run enabled - no modifications will be made. Add --confirm to remove deployments")
-	}
-
-	return pruner.Prune(replicaDeleter)
+	return NewPruner(options), nil
 }
 
 // describingReplicaDeleter prints information about each replication controller or replicaset it removes.
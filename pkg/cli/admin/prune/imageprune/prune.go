@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -162,6 +163,7 @@ func (s *PruneStats) String() string {
 type pruneAlgorithm struct {
 	keepYoungerThan    time.Time
 	keepTagRevisions   int
+	keepTagRegex       *regexp.Regexp
 	pruneOverSizeLimit bool
 	namespace          string
 	allImages          bool
@@ -205,6 +207,33 @@ type ManifestDeleter interface {
 	DeleteManifest(repo, manifest string) error
 }
 
+// KeepReason is the rule that caused an image stream tag revision to be kept.
+type KeepReason string
+
+const (
+	KeepReasonTagRegex     KeepReason = "--keep-tag-regex"
+	KeepReasonYoungerThan  KeepReason = "--keep-younger-than"
+	KeepReasonTagRevisions KeepReason = "--keep-tag-revisions"
+	KeepReasonSizeLimit    KeepReason = "--prune-over-size-limit"
+	KeepReasonTagInUse     KeepReason = "tag in use"
+	KeepReasonImageInUse   KeepReason = "image in use"
+)
+
+// KeptRevision identifies a revision of an image stream tag that was not pruned.
+type KeptRevision struct {
+	Tag string
+	// Revision is the 1-based position of the revision in the tag history, 1 being the newest.
+	Revision int
+	Image    string
+	Reason   KeepReason
+}
+
+// PlanReporter is informed of the revisions that are kept in each examined image stream.
+// It may be invoked concurrently for different image streams.
+type PlanReporter interface {
+	ReportKeptRevisions(stream *imagev1.ImageStream, kept []KeptRevision)
+}
+
 // PrunerOptions contains the fields used to initialize a new Pruner.
 type PrunerOptions struct {
 	// KeepYoungerThan indicates the minimum age an Image must be to be a
@@ -213,6 +242,11 @@ type PrunerOptions struct {
 	// KeepTagRevisions is the minimum number of tag revisions to preserve;
 	// revisions older than this value are candidates for pruning.
 	KeepTagRevisions *int
+	// KeepTagRegex preserves every revision of the image stream tags whose name matches it,
+	// regardless of the other rules.
+	KeepTagRegex *regexp.Regexp
+	// PlanReporter, if set, is informed of the revisions kept in each image stream and why.
+	PlanReporter PlanReporter
 	// PruneOverSizeLimit indicates that images exceeding defined limits (openshift.io/Image)
 	// will be considered as candidates for pruning.
 	PruneOverSizeLimit *bool
@@ -292,6 +326,7 @@ type pruner struct {
 	ignoreInvalidRefs bool
 	imageStreamLimits map[string][]*corev1.LimitRange
 	numWorkers        int
+	planReporter      PlanReporter
 }
 
 var _ Pruner = &pruner{}
@@ -308,6 +343,9 @@ var _ Pruner = &pruner{}
 // status.tags that are preserved and ineligible for pruning. Any revision older
 // than keepTagRevisions is eligible for pruning.
 //
+// keepTagRegex preserves all revisions of the tags whose name it matches, no
+// matter their age or position in the tag history.
+//
 // pruneOverSizeLimit is a boolean flag speyfing that all images exceeding limits
 // defined in their namespace will be considered for pruning. Important to note is
 // the fact that this flag does not work in any combination with the keep* flags.
@@ -354,6 +392,7 @@ func NewPruner(options PrunerOptions) (Pruner, kerrors.Aggregate) {
 	if options.KeepTagRevisions != nil {
 		algorithm.keepTagRevisions = *options.KeepTagRevisions
 	}
+	algorithm.keepTagRegex = options.KeepTagRegex
 	if options.PruneOverSizeLimit != nil {
 		algorithm.pruneOverSizeLimit = *options.PruneOverSizeLimit
 	}
@@ -374,6 +413,7 @@ func NewPruner(options PrunerOptions) (Pruner, kerrors.Aggregate) {
 		ignoreInvalidRefs: options.IgnoreInvalidRefs,
 		imageStreamLimits: options.LimitRanges,
 		numWorkers:        options.NumWorkers,
+		planReporter:      options.PlanReporter,
 	}
 
 	if p.numWorkers < 1 {
@@ -884,14 +924,25 @@ func (p *pruner) getGlobalReferenceCounts(images map[string]*imagev1.Image) (ref
 	return counts, nil
 }
 
-func (p *pruner) pruneImageStreamTag(is *imagev1.ImageStream, tagEventList imagev1.NamedTagEventList, counts referenceCounts, layerLinkDeleter LayerLinkDeleter) (imagev1.NamedTagEventList, int, []string, []error) {
+func (p *pruner) pruneImageStreamTag(is *imagev1.ImageStream, tagEventList imagev1.NamedTagEventList, counts referenceCounts, layerLinkDeleter LayerLinkDeleter) (imagev1.NamedTagEventList, int, []KeptRevision, []string, []error) {
 	filteredItems := tagEventList.Items[:0]
+	var kept []KeptRevision
 	var manifestsToDelete []string
 	var errs []error
+	keep := func(rev int, item imagev1.TagEvent, reason KeepReason) {
+		filteredItems = append(filteredItems, item)
+		kept = append(kept, KeptRevision{Tag: tagEventList.Tag, Revision: rev + 1, Image: item.Image, Reason: reason})
+	}
 	for rev, item := range tagEventList.Items {
+		if p.algorithm.keepTagRegex != nil && p.algorithm.keepTagRegex.MatchString(tagEventList.Tag) {
+			klog.V(4).Infof("imagestream %s/%s: tag %s: revision %d: keeping %s because of --keep-tag-regex", is.Namespace, is.Name, tagEventList.Tag, rev+1, item.Image)
+			keep(rev, item, KeepReasonTagRegex)
+			continue
+		}
+
 		if !p.algorithm.pruneOverSizeLimit && item.Created.After(p.algorithm.keepYoungerThan) {
 			klog.V(4).Infof("imagestream %s/%s: tag %s: revision %d: keeping %s because of --keep-younger-than", is.Namespace, is.Name, tagEventList.Tag, rev+1, item.Image)
-			filteredItems = append(filteredItems, item)
+			keep(rev, item, KeepReasonYoungerThan)
 			continue
 		}
 
@@ -903,7 +954,7 @@ func (p *pruner) pruneImageStreamTag(is *imagev1.ImageStream, tagEventList image
 			}
 			if usedBy := p.usedTags[istag]; len(usedBy) > 0 {
 				klog.V(4).Infof("imagestream %s/%s: tag %s: revision %d: keeping %s because tag is used by %s", is.Namespace, is.Name, tagEventList.Tag, rev+1, item.Image, referencesSample(usedBy))
-				filteredItems = append(filteredItems, item)
+				keep(rev, item, KeepReasonTagInUse)
 				continue
 			}
 		}
@@ -922,13 +973,13 @@ func (p *pruner) pruneImageStreamTag(is *imagev1.ImageStream, tagEventList image
 		if p.algorithm.pruneOverSizeLimit {
 			if !exceedsLimits(is, image, p.imageStreamLimits) {
 				klog.V(4).Infof("imagestream %s/%s: tag %s: revision %d: keeping %s because --prune-over-size-limit is used and image does not exceed limits", is.Namespace, is.Name, tagEventList.Tag, rev+1, item.Image)
-				filteredItems = append(filteredItems, item)
+				keep(rev, item, KeepReasonSizeLimit)
 				continue
 			}
 		} else {
 			if rev < p.algorithm.keepTagRevisions {
 				klog.V(4).Infof("imagestream %s/%s: tag %s: revision %d: keeping %s because of --keep-tag-revisions", is.Namespace, is.Name, tagEventList.Tag, rev+1, item.Image)
-				filteredItems = append(filteredItems, item)
+				keep(rev, item, KeepReasonTagRevisions)
 				continue
 			}
 		}
@@ -940,7 +991,7 @@ func (p *pruner) pruneImageStreamTag(is *imagev1.ImageStream, tagEventList image
 		}
 		if usedBy := p.usedImages[isimage]; len(usedBy) > 0 {
 			klog.V(4).Infof("imagestream %s/%s: tag %s: revision %d: keeping %s because image is used by %s", is.Namespace, is.Name, tagEventList.Tag, rev+1, item.Image, referencesSample(usedBy))
-			filteredItems = append(filteredItems, item)
+			keep(rev, item, KeepReasonImageInUse)
 			continue
 		}
 
@@ -970,7 +1021,7 @@ func (p *pruner) pruneImageStreamTag(is *imagev1.ImageStream, tagEventList image
 
 	tagEventList.Items = filteredItems
 
-	return tagEventList, deletedItems, manifestsToDelete, errs
+	return tagEventList, deletedItems, kept, manifestsToDelete, errs
 }
 
 func (p *pruner) pruneImageStream(stream *imagev1.ImageStream, imageStreamDeleter ImageStreamDeleter, layerLinkDeleter LayerLinkDeleter, manifestDeleter ManifestDeleter) (*imagev1.ImageStream, *PruneStats, []error) {
@@ -978,16 +1029,27 @@ func (p *pruner) pruneImageStream(stream *imagev1.ImageStream, imageStreamDelete
 
 	if !p.algorithm.pruneOverSizeLimit && stream.CreationTimestamp.Time.After(p.algorithm.keepYoungerThan) {
 		klog.V(4).Infof("imagestream %s/%s: keeping all images because of --keep-younger-than", stream.Namespace, stream.Name)
+		if p.planReporter != nil {
+			var kept []KeptRevision
+			for _, tagEventList := range stream.Status.Tags {
+				for rev, item := range tagEventList.Items {
+					kept = append(kept, KeptRevision{Tag: tagEventList.Tag, Revision: rev + 1, Image: item.Image, Reason: KeepReasonYoungerThan})
+				}
+			}
+			p.planReporter.ReportKeptRevisions(stream, kept)
+		}
 		return stream, &PruneStats{}, nil
 	}
 
 	collectingLayerLinkDeleter := newCollectingLayerLinkDeleter(layerLinkDeleter)
 
 	var manifestsToDelete []string
+	var kept []KeptRevision
 	var errs []error
 	var deletedItems int
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		manifestsToDelete = nil
+		kept = nil
 
 		is, err := imageStreamDeleter.GetImageStream(stream)
 		if err != nil {
@@ -1008,9 +1070,10 @@ func (p *pruner) pruneImageStream(stream *imagev1.ImageStream, imageStreamDelete
 
 		deletedItems = 0
 		for i, tagEventList := range is.Status.Tags {
-			updatedTagEventList, deletedTagItems, tagManifestsToDelete, tagErrs := p.pruneImageStreamTag(is, tagEventList, counts, collectingLayerLinkDeleter)
+			updatedTagEventList, deletedTagItems, tagKept, tagManifestsToDelete, tagErrs := p.pruneImageStreamTag(is, tagEventList, counts, collectingLayerLinkDeleter)
 			is.Status.Tags[i] = updatedTagEventList
 			deletedItems += deletedTagItems
+			kept = append(kept, tagKept...)
 			manifestsToDelete = append(manifestsToDelete, tagManifestsToDelete...)
 			errs = append(errs, tagErrs...)
 		}
@@ -1046,6 +1109,10 @@ func (p *pruner) pruneImageStream(stream *imagev1.ImageStream, imageStreamDelete
 		return stream, stats, errs
 	}
 
+	if p.planReporter != nil && stream != nil {
+		p.planReporter.ReportKeptRevisions(stream, kept)
+	}
+
 	if deletedItems > 0 {
 		stats.DeletedImageStreamTagItems = deletedItems
 		stats.UpdatedImageStreams = 1
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
 	"sync"
 	"testing"
@@ -2281,6 +2282,106 @@ func TestImageWithStrongAndWeakRefsIsNotPruned(t *testing.T) {
 	}
 }
 
+type fakePlanReporter struct {
+	mutex sync.Mutex
+	kept  map[string][]KeptRevision
+}
+
+func (r *fakePlanReporter) ReportKeptRevisions(stream *imagev1.ImageStream, kept []KeptRevision) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.kept[stream.Namespace+"/"+stream.Name] = kept
+}
+
+func TestImagePruningKeepTagRegex(t *testing.T) {
+	var level klog.Level
+	level.Set(fmt.Sprint(*logLevel))
+
+	images := Images(
+		imagetest.AgedImage("0000000000000000000000000000000000000000000000000000000000000001", "registry1.io/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000001", 1540),
+		imagetest.AgedImage("0000000000000000000000000000000000000000000000000000000000000002", "registry1.io/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000002", 1540),
+		imagetest.AgedImage("0000000000000000000000000000000000000000000000000000000000000003", "registry1.io/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000003", 1540),
+		imagetest.AgedImage("0000000000000000000000000000000000000000000000000000000000000004", "registry1.io/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000004", 1540),
+	)
+	streams := Streams(
+		imagetest.Stream("registry1", "foo", "bar", []imagev1.NamedTagEventList{
+			imagetest.Tag("latest",
+				imagetest.TagEvent("0000000000000000000000000000000000000000000000000000000000000004", "registry1.io/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000004"),
+				imagetest.TagEvent("0000000000000000000000000000000000000000000000000000000000000003", "registry1.io/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000003"),
+			),
+			imagetest.Tag("release-1.0",
+				imagetest.TagEvent("0000000000000000000000000000000000000000000000000000000000000002", "registry1.io/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000002"),
+				imagetest.TagEvent("0000000000000000000000000000000000000000000000000000000000000001", "registry1.io/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000001"),
+			),
+		}),
+	)
+	pods := imagetest.PodList()
+	rcs := imagetest.RCList()
+	bcs := imagetest.BCList()
+	builds := imagetest.BuildList()
+	dss := imagetest.DSList()
+	deployments := imagetest.DeploymentList()
+	dcs := imagetest.DCList()
+	rss := imagetest.RSList()
+	ssets := imagetest.SSetList()
+	jobs := imagetest.JobList()
+	cjs := imagetest.CronJobList()
+
+	reporter := &fakePlanReporter{kept: make(map[string][]KeptRevision)}
+	options := PrunerOptions{
+		Images:       images,
+		Streams:      streams,
+		Pods:         &pods,
+		RCs:          &rcs,
+		BCs:          &bcs,
+		Builds:       &builds,
+		DSs:          &dss,
+		Deployments:  &deployments,
+		DCs:          &dcs,
+		RSs:          &rss,
+		SSets:        &ssets,
+		Jobs:         &jobs,
+		CronJobs:     &cjs,
+		KeepTagRegex: regexp.MustCompile(`^release-`),
+		PlanReporter: reporter,
+	}
+	keepYoungerThan := 24 * time.Hour
+	keepTagRevisions := 1
+	options.KeepYoungerThan = &keepYoungerThan
+	options.KeepTagRevisions = &keepTagRevisions
+	p, err := NewPruner(options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	streamDeleter := &fakeImageStreamDeleter{invocations: sets.NewString()}
+	layerLinkDeleter := &fakeLayerLinkDeleter{invocations: sets.NewString()}
+	manifestDeleter := &fakeManifestDeleter{invocations: sets.NewString()}
+	blobDeleter := &fakeBlobDeleter{invocations: sets.NewString()}
+	imageDeleter := newFakeImageDeleter(nil)
+
+	_, errs := p.Prune(streamDeleter, layerLinkDeleter, manifestDeleter, blobDeleter, imageDeleter)
+	if errs != nil {
+		t.Errorf("got unexpected errors: %#+v", errs)
+	}
+
+	if !imageDeleter.invocations.Equal(sets.NewString("0000000000000000000000000000000000000000000000000000000000000003")) {
+		t.Errorf("unexpected imageDeleter invocations: %v", imageDeleter.invocations)
+	}
+	if !streamDeleter.invocations.Equal(sets.NewString("foo/bar|latest|1|0000000000000000000000000000000000000000000000000000000000000003")) {
+		t.Errorf("unexpected streamDeleter invocations: %v", streamDeleter.invocations)
+	}
+
+	expectedKept := []KeptRevision{
+		{Tag: "latest", Revision: 1, Image: "0000000000000000000000000000000000000000000000000000000000000004", Reason: KeepReasonTagRevisions},
+		{Tag: "release-1.0", Revision: 1, Image: "0000000000000000000000000000000000000000000000000000000000000002", Reason: KeepReasonTagRegex},
+		{Tag: "release-1.0", Revision: 2, Image: "0000000000000000000000000000000000000000000000000000000000000001", Reason: KeepReasonTagRegex},
+	}
+	if a, e := reporter.kept["foo/bar"], expectedKept; !reflect.DeepEqual(a, e) {
+		t.Errorf("unexpected kept revisions (-actual, +expected): %s", diff.ObjectDiff(a, e))
+	}
+}
+
 func keepTagRevisions(n int) *int {
 	return &n
 }
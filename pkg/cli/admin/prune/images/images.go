@@ -12,7 +12,9 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -88,6 +90,10 @@ var (
 	  # To actually perform the prune operation, the confirm flag must be appended
 	  oc adm prune images --keep-tag-revisions=3 --keep-younger-than=60m --confirm
 
+	  # See what the prune command would delete if all revisions of release tags like v1.2.3
+	  # were always kept, and which rule keeps each remaining revision
+	  oc adm prune images --keep-tag-revisions=3 --keep-younger-than=60m --keep-tag-regex='^v[0-9]+\.[0-9]+\.[0-9]+$' --dry-run
+
 	  # See what the prune command would delete if we are interested in removing images
 	  # exceeding currently set limit ranges ('openshift.io/Image')
 	  oc adm prune images --prune-over-size-limit
@@ -113,8 +119,10 @@ var (
 // PruneImagesOptions holds all the required options for pruning images.
 type PruneImagesOptions struct {
 	Confirm             bool
+	DryRun              bool
 	KeepYoungerThan     *time.Duration
 	KeepTagRevisions    *int
+	KeepTagRegex        string
 	PruneOverSizeLimit  *bool
 	AllImages           *bool
 	CABundle            string
@@ -166,7 +174,9 @@ func NewCmdPruneImages(f kcmdutil.Factory, streams genericclioptions.IOStreams)
 	cmd.Flags().BoolVar(opts.AllImages, "all", *opts.AllImages, "Include images that were imported from external registries as candidates for pruning.  If pruned, all the mirrored objects associated with them will also be removed from the integrated registry.")
 	cmd.Flags().DurationVar(opts.KeepYoungerThan, "keep-younger-than", *opts.KeepYoungerThan, "Specify the minimum age of an image and its referrers for it to be considered a candidate for pruning.")
 	cmd.Flags().IntVar(opts.KeepTagRevisions, "keep-tag-revisions", *opts.KeepTagRevisions, "Specify the number of image revisions for a tag in an image stream that will be preserved.")
-	cmd.Flags().BoolVar(opts.PruneOverSizeLimit, "prune-over-size-limit", *opts.PruneOverSizeLimit, "Specify if images which are exceeding LimitRanges (see 'openshift.io/Image'), specified in the same namespace, should be considered for pruning. This flag cannot be combined with --keep-younger-than nor --keep-tag-revisions.")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", opts.DryRun, "If true, print the pruning plan, with the rule that keeps each revision of every image stream, without deleting anything. Cannot be combined with --confirm.")
+	cmd.Flags().StringVar(&opts.KeepTagRegex, "keep-tag-regex", opts.KeepTagRegex, "Specify a regular expression matching image stream tag names whose revisions will all be preserved, regardless of their age and of --keep-tag-revisions.")
+	cmd.Flags().BoolVar(opts.PruneOverSizeLimit, "prune-over-size-limit", *opts.PruneOverSizeLimit, "Specify if images which are exceeding LimitRanges (see 'openshift.io/Image'), specified in the same namespace, should be considered for pruning. This flag cannot be combined with --keep-younger-than, --keep-tag-revisions nor --keep-tag-regex.")
 	cmd.Flags().StringVar(&opts.CABundle, "certificate-authority", opts.CABundle, "The path to a certificate authority bundle to use when communicating with the managed container image registries. Defaults to the certificate authority data from the current user's config file. It cannot be used together with --force-insecure.")
 	cmd.Flags().StringVar(&opts.RegistryUrlOverride, "registry-url", opts.RegistryUrlOverride, "The address to use when contacting the registry, instead of using the default value. This is useful if you can't resolve or reach the registry (e.g.; the default is a cluster-internal URL) but you do have an alternative route that works. Particular transport protocol can be enforced using '<scheme>://' prefix.")
 	cmd.Flags().BoolVar(&opts.ForceInsecure, "force-insecure", opts.ForceInsecure, "If true, allow an insecure connection to the container image registry that is hosted via HTTP or has an invalid HTTPS certificate. Whenever possible, use --certificate-authority instead of this dangerous option.")
@@ -249,8 +259,8 @@ func (o *PruneImagesOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, ar
 
 // Validate ensures that a PruneImagesOptions is valid and can be used to execute pruning.
 func (o PruneImagesOptions) Validate() error {
-	if o.PruneOverSizeLimit != nil && (o.KeepYoungerThan != nil || o.KeepTagRevisions != nil) {
-		return fmt.Errorf("--prune-over-size-limit cannot be specified with --keep-tag-revisions nor --keep-younger-than")
+	if o.PruneOverSizeLimit != nil && (o.KeepYoungerThan != nil || o.KeepTagRevisions != nil || len(o.KeepTagRegex) > 0) {
+		return fmt.Errorf("--prune-over-size-limit cannot be specified with --keep-tag-revisions, --keep-younger-than nor --keep-tag-regex")
 	}
 	if o.KeepYoungerThan != nil && *o.KeepYoungerThan < 0 {
 		return fmt.Errorf("--keep-younger-than must be greater than or equal to 0")
@@ -258,6 +268,12 @@ func (o PruneImagesOptions) Validate() error {
 	if o.KeepTagRevisions != nil && *o.KeepTagRevisions < 0 {
 		return fmt.Errorf("--keep-tag-revisions must be greater than or equal to 0")
 	}
+	if _, err := regexp.Compile(o.KeepTagRegex); len(o.KeepTagRegex) > 0 && err != nil {
+		return fmt.Errorf("invalid --keep-tag-regex: %v", err)
+	}
+	if o.DryRun && o.Confirm {
+		return fmt.Errorf("--dry-run and --confirm may not be specified together")
+	}
 	if err := validateRegistryURL(o.RegistryUrlOverride); len(o.RegistryUrlOverride) > 0 && err != nil {
 		return fmt.Errorf("invalid --registry-url flag: %v", err)
 	}
@@ -492,6 +508,15 @@ func (o PruneImagesOptions) Run() error {
 	if o.NumWorkers != nil {
 		options.NumWorkers = *o.NumWorkers
 	}
+	if o.DryRun {
+		options.PlanReporter = &describingPlanReporter{w: o.Out}
+	}
+	if len(o.KeepTagRegex) > 0 {
+		options.KeepTagRegex, err = regexp.Compile(o.KeepTagRegex)
+		if err != nil {
+			return fmt.Errorf("invalid --keep-tag-regex: %v", err)
+		}
+	}
 	pruner, errs := imageprune.NewPruner(options)
 	if errs != nil {
 		o.printGraphBuildErrors(errs)
@@ -592,6 +617,37 @@ func (p *describingImageStreamDeleter) UpdateImageStream(stream *imagev1.ImageSt
 	return updatedStream, err
 }
 
+// describingPlanReporter prints, for each image stream, the tag revisions that are kept
+// grouped by the rule that caused them to be kept.
+type describingPlanReporter struct {
+	w     io.Writer
+	mutex sync.Mutex
+}
+
+var _ imageprune.PlanReporter = &describingPlanReporter{}
+
+func (p *describingPlanReporter) ReportKeptRevisions(stream *imagev1.ImageStream, kept []imageprune.KeptRevision) {
+	if len(kept) == 0 {
+		return
+	}
+	byReason := make(map[imageprune.KeepReason][]string)
+	for _, rev := range kept {
+		byReason[rev.Reason] = append(byReason[rev.Reason], fmt.Sprintf("%s#%d (%s)", rev.Tag, rev.Revision, rev.Image))
+	}
+	var reasons []string
+	for reason := range byReason {
+		reasons = append(reasons, string(reason))
+	}
+	sort.Strings(reasons)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	fmt.Fprintf(p.w, "Keeping %d items in image stream %s/%s\n", len(kept), stream.Namespace, stream.Name)
+	for _, reason := range reasons {
+		fmt.Fprintf(p.w, "  because of %s: %s\n", reason, strings.Join(byReason[imageprune.KeepReason(reason)], ", "))
+	}
+}
+
 // describingImageDeleter prints information about each image being deleted.
 // If a delegate exists, its DeleteImage function is invoked prior to returning.
 type describingImageDeleter struct {
@@ -317,3 +317,55 @@ func TestValidateRegistryURL(t *testing.T) {
 		}
 	}
 }
+
+func TestValidate(t *testing.T) {
+	keepTagRevisions := 3
+	pruneOverSizeLimit := true
+	for _, tc := range []struct {
+		name          string
+		opts          PruneImagesOptions
+		expectedError string
+	}{
+		{
+			name: "dry run",
+			opts: PruneImagesOptions{DryRun: true},
+		},
+		{
+			name:          "dry run with confirm",
+			opts:          PruneImagesOptions{DryRun: true, Confirm: true},
+			expectedError: "--dry-run and --confirm may not be specified together",
+		},
+		{
+			name: "keep tag regex",
+			opts: PruneImagesOptions{KeepTagRegex: "^v[0-9]+$"},
+		},
+		{
+			name:          "invalid keep tag regex",
+			opts:          PruneImagesOptions{KeepTagRegex: "v["},
+			expectedError: "invalid --keep-tag-regex",
+		},
+		{
+			name:          "prune over size limit with keep tag revisions",
+			opts:          PruneImagesOptions{PruneOverSizeLimit: &pruneOverSizeLimit, KeepTagRevisions: &keepTagRevisions},
+			expectedError: "--prune-over-size-limit cannot be specified with",
+		},
+		{
+			name:          "prune over size limit with keep tag regex",
+			opts:          PruneImagesOptions{PruneOverSizeLimit: &pruneOverSizeLimit, KeepTagRegex: "^v[0-9]+$"},
+			expectedError: "--prune-over-size-limit cannot be specified with",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.Validate()
+			if len(tc.expectedError) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
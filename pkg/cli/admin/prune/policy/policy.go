@@ -0,0 +1,378 @@
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	"github.com/openshift/oc/pkg/cli/admin/prune/builds"
+	"github.com/openshift/oc/pkg/cli/admin/prune/deployments"
+)
+
+var (
+	policyLongDesc = templates.LongDesc(`
+		Prune old builds and deployments as described by a policy file.
+
+		The policy file sets, separately for builds and for deployments, the minimum age of the
+		objects that are considered and how many complete and failed ones are kept for every
+		BuildConfig, DeploymentConfig or Deployment. A kind without a section in the policy is not
+		pruned, and a setting that is left out of a section takes the default of the matching
+		'oc adm prune builds' or 'oc adm prune deployments' flag.
+
+		    builds:
+		      keepYoungerThan: 24h
+		      keepComplete: 3
+		      keepFailed: 1
+		      orphans: true
+		    deployments:
+		      keepYoungerThan: 1h
+		      keepComplete: 2
+		      keepFailed: 0
+		      replicaSets: true
+
+		Everything the policy selects is listed in a single report, grouped by the object that owns
+		it. By default, the prune operation performs a dry run making no changes. A --confirm flag
+		is needed for changes to be effective.
+	`)
+
+	policyExample = templates.Examples(`
+		# Dry run pruning the builds and deployments selected by a policy file
+		oc adm prune policy -f prune-policy.yaml
+
+		# To actually perform the prune operation, the confirm flag must be appended
+		oc adm prune policy -f prune-policy.yaml --confirm
+	`)
+)
+
+// Policy selects the builds and deployments that are pruned. A nil section is not pruned.
+type Policy struct {
+	Builds      *RetentionPolicy   `json:"builds,omitempty"`
+	Deployments *DeploymentsPolicy `json:"deployments,omitempty"`
+}
+
+// RetentionPolicy holds the settings shared by builds and deployments. Unset fields keep the
+// defaults of the prune builds and prune deployments commands.
+type RetentionPolicy struct {
+	KeepYoungerThan *metav1.Duration `json:"keepYoungerThan,omitempty"`
+	KeepComplete    *int             `json:"keepComplete,omitempty"`
+	KeepFailed      *int             `json:"keepFailed,omitempty"`
+	Orphans         bool             `json:"orphans,omitempty"`
+}
+
+// DeploymentsPolicy is the retention policy of deployments.
+type DeploymentsPolicy struct {
+	RetentionPolicy `json:",inline"`
+	ReplicaSets     bool `json:"replicaSets,omitempty"`
+}
+
+// PrunePolicyOptions holds all the required options for pruning by policy.
+type PrunePolicyOptions struct {
+	Filename string
+	Confirm  bool
+
+	Policy      *Policy
+	Builds      *builds.PruneBuildsOptions
+	Deployments *deployments.PruneDeploymentsOptions
+
+	genericclioptions.IOStreams
+}
+
+func NewPrunePolicyOptions(streams genericclioptions.IOStreams) *PrunePolicyOptions {
+	return &PrunePolicyOptions{
+		IOStreams: streams,
+	}
+}
+
+// NewCmdPrunePolicy implements the OpenShift cli prune policy command.
+func NewCmdPrunePolicy(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewPrunePolicyOptions(streams)
+	cmd := &cobra.Command{
+		Use:     "policy -f FILENAME",
+		Short:   "Remove old builds and deployments as described by a policy file",
+		Long:    policyLongDesc,
+		Example: policyExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Validate())
+			kcmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "The policy file to prune by, or - to read it from standard input.")
+	cmd.Flags().BoolVar(&o.Confirm, "confirm", o.Confirm, "If true, specify that pruning should proceed. Defaults to false, displaying what would be deleted but not actually deleting anything.")
+
+	return cmd
+}
+
+// Complete reads the policy file and prepares the prune builds and prune deployments options for
+// the sections it contains.
+func (o *PrunePolicyOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return kcmdutil.UsageErrorf(cmd, "no arguments are allowed to this command")
+	}
+	if len(o.Filename) == 0 {
+		return kcmdutil.UsageErrorf(cmd, "a policy file must be specified with --filename")
+	}
+
+	var data []byte
+	var err error
+	if o.Filename == "-" {
+		data, err = ioutil.ReadAll(o.In)
+	} else {
+		data, err = ioutil.ReadFile(o.Filename)
+	}
+	if err != nil {
+		return err
+	}
+	o.Policy, err = parsePolicy(data)
+	if err != nil {
+		return fmt.Errorf("invalid policy %s: %v", o.Filename, err)
+	}
+
+	if p := o.Policy.Builds; p != nil {
+		o.Builds = builds.NewPruneBuildsOptions(o.IOStreams)
+		if err := o.Builds.Complete(f, cmd, args); err != nil {
+			return err
+		}
+		o.Builds.Confirm = o.Confirm
+		p.apply(&o.Builds.KeepYoungerThan, &o.Builds.KeepComplete, &o.Builds.KeepFailed, &o.Builds.Orphans)
+	}
+	if p := o.Policy.Deployments; p != nil {
+		o.Deployments = deployments.NewPruneDeploymentsOptions(o.IOStreams)
+		if err := o.Deployments.Complete(f, cmd, args); err != nil {
+			return err
+		}
+		o.Deployments.Confirm = o.Confirm
+		o.Deployments.ReplicaSets = p.ReplicaSets
+		p.apply(&o.Deployments.KeepYoungerThan, &o.Deployments.KeepComplete, &o.Deployments.KeepFailed, &o.Deployments.Orphans)
+	}
+	return nil
+}
+
+// Validate ensures that a PrunePolicyOptions is valid and can be used to execute pruning.
+func (o PrunePolicyOptions) Validate() error {
+	if o.Builds == nil && o.Deployments == nil {
+		return fmt.Errorf("the policy must contain a builds or a deployments section")
+	}
+	return nil
+}
+
+// Run lists everything the policy selects in a single report and, with --confirm, deletes it.
+func (o PrunePolicyOptions) Run() error {
+	candidates, err := o.candidates()
+	if err != nil {
+		return err
+	}
+	if !o.Confirm {
+		fmt.Fprintln(o.ErrOut, "Dry run enabled - no modifications will be made. Add --confirm to remove builds and deployments")
+	}
+
+	w := tabwriter.NewWriter(o.Out, 10, 4, 3, ' ', 0)
+	if len(candidates) > 0 {
+		fmt.Fprintln(w, "NAMESPACE\tOWNER\tKIND\tNAME")
+	}
+	var errs []error
+	buildCount, deploymentCount := 0, 0
+	for _, c := range candidates {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.namespace, c.owner, c.kind, c.name)
+		if o.Confirm {
+			if err := c.delete(); err != nil {
+				errs = append(errs, fmt.Errorf("unable to delete %s %s/%s: %v", c.kind, c.namespace, c.name, err))
+				continue
+			}
+		}
+		if c.kind == "build" {
+			buildCount++
+		} else {
+			deploymentCount++
+		}
+	}
+	w.Flush()
+
+	if o.Confirm {
+		fmt.Fprintf(o.ErrOut, "Pruned %d builds and %d deployments\n", buildCount, deploymentCount)
+	} else {
+		fmt.Fprintf(o.ErrOut, "Would prune %d builds and %d deployments\n", buildCount, deploymentCount)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(kcmdutil.MultipleErrors("error: ", errs))
+	}
+	return nil
+}
+
+// candidate is a build, replication controller or replica set selected by the policy.
+type candidate struct {
+	namespace string
+	owner     string
+	kind      string
+	name      string
+	delete    func() error
+}
+
+// candidates returns everything the policy selects, sorted by namespace, owner, kind and name.
+func (o PrunePolicyOptions) candidates() ([]candidate, error) {
+	var result []candidate
+	if o.Builds != nil {
+		pruner, err := o.Builds.LoadPruner()
+		if err != nil {
+			return nil, err
+		}
+		deleter := builds.NewBuildDeleter(o.Builds.BuildClient)
+		collector := &collectingBuildDeleter{}
+		if err := pruner.Prune(collector); err != nil {
+			return nil, err
+		}
+		for _, build := range collector.builds {
+			build := build
+			result = append(result, candidate{
+				namespace: build.Namespace,
+				owner:     buildOwner(build),
+				kind:      "build",
+				name:      build.Name,
+				delete:    func() error { return deleter.DeleteBuild(build) },
+			})
+		}
+	}
+	if o.Deployments != nil {
+		pruner, err := o.Deployments.LoadPruner()
+		if err != nil {
+			return nil, err
+		}
+		deleter := deployments.NewReplicaDeleter(o.Deployments.KubeClient, o.Deployments.KAppsClient)
+		collector := &collectingReplicaDeleter{}
+		if err := pruner.Prune(collector); err != nil {
+			return nil, err
+		}
+		for _, replica := range collector.replicas {
+			replica := replica
+			owner, kind := replicaOwner(replica)
+			result = append(result, candidate{
+				namespace: replica.GetNamespace(),
+				owner:     owner,
+				kind:      kind,
+				name:      replica.GetName(),
+				delete:    func() error { return deleter.DeleteReplica(replica) },
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		switch {
+		case a.namespace != b.namespace:
+			return a.namespace < b.namespace
+		case a.owner != b.owner:
+			return a.owner < b.owner
+		case a.kind != b.kind:
+			return a.kind < b.kind
+		default:
+			return a.name < b.name
+		}
+	})
+	return result, nil
+}
+
+// collectingBuildDeleter records the builds the pruner selects instead of deleting them.
+type collectingBuildDeleter struct {
+	builds []*buildv1.Build
+}
+
+func (d *collectingBuildDeleter) DeleteBuild(build *buildv1.Build) error {
+	d.builds = append(d.builds, build)
+	return nil
+}
+
+// collectingReplicaDeleter records the replicas the pruner selects instead of deleting them.
+type collectingReplicaDeleter struct {
+	replicas []metav1.Object
+}
+
+func (d *collectingReplicaDeleter) DeleteReplica(replica metav1.Object) error {
+	d.replicas = append(d.replicas, replica)
+	return nil
+}
+
+// buildOwner returns the build config a build was started from.
+func buildOwner(build *buildv1.Build) string {
+	if build.Status.Config == nil || len(build.Status.Config.Name) == 0 {
+		return "<none>"
+	}
+	return "buildconfig/" + build.Status.Config.Name
+}
+
+// replicaOwner returns the deployment config or deployment that owns a replication controller
+// or replica set, and the kind of the replica.
+func replicaOwner(replica metav1.Object) (string, string) {
+	ownerKind, owner, kind := "", "", "replicationcontroller"
+	switch replica.(type) {
+	case *corev1.ReplicationController:
+		ownerKind, owner = "DeploymentConfig", "deploymentconfig/"
+	case *kappsv1.ReplicaSet:
+		ownerKind, owner, kind = "Deployment", "deployment/", "replicaset"
+	}
+	for _, ref := range replica.GetOwnerReferences() {
+		if ref.Kind == ownerKind && len(ref.Name) > 0 {
+			return owner + ref.Name, kind
+		}
+	}
+	return "<none>", kind
+}
+
+// parsePolicy decodes a policy file, rejecting unknown fields and negative values.
+func parsePolicy(data []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, err
+	}
+	if policy.Builds != nil {
+		if err := policy.Builds.validate("builds"); err != nil {
+			return nil, err
+		}
+	}
+	if policy.Deployments != nil {
+		if err := policy.Deployments.validate("deployments"); err != nil {
+			return nil, err
+		}
+	}
+	return policy, nil
+}
+
+func (p *RetentionPolicy) validate(section string) error {
+	if p.KeepYoungerThan != nil && p.KeepYoungerThan.Duration < 0 {
+		return fmt.Errorf("%s.keepYoungerThan must be greater than or equal to 0", section)
+	}
+	if p.KeepComplete != nil && *p.KeepComplete < 0 {
+		return fmt.Errorf("%s.keepComplete must be greater than or equal to 0", section)
+	}
+	if p.KeepFailed != nil && *p.KeepFailed < 0 {
+		return fmt.Errorf("%s.keepFailed must be greater than or equal to 0", section)
+	}
+	return nil
+}
+
+// apply overrides the given settings with the ones set in the policy.
+func (p *RetentionPolicy) apply(keepYoungerThan *time.Duration, keepComplete, keepFailed *int, orphans *bool) {
+	if p.KeepYoungerThan != nil {
+		*keepYoungerThan = p.KeepYoungerThan.Duration
+	}
+	if p.KeepComplete != nil {
+		*keepComplete = *p.KeepComplete
+	}
+	if p.KeepFailed != nil {
+		*keepFailed = *p.KeepFailed
+	}
+	*orphans = p.Orphans
+}
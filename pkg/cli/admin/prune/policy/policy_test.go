@@ -0,0 +1,192 @@
+package policy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	appsfake "github.com/openshift/client-go/apps/clientset/versioned/fake"
+	buildfake "github.com/openshift/client-go/build/clientset/versioned/fake"
+	"github.com/openshift/oc/pkg/cli/admin/prune/builds"
+	"github.com/openshift/oc/pkg/cli/admin/prune/deployments"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		expectedErr string
+	}{
+		{
+			name: "builds and deployments",
+			policy: `
+builds:
+  keepYoungerThan: 24h
+  keepComplete: 3
+deployments:
+  keepFailed: 0
+  replicaSets: true
+`,
+		},
+		{
+			name:        "unknown field",
+			policy:      "builds:\n  keepCompleted: 3\n",
+			expectedErr: "unknown field",
+		},
+		{
+			name:        "replica sets of builds",
+			policy:      "builds:\n  replicaSets: true\n",
+			expectedErr: "unknown field",
+		},
+		{
+			name:        "negative count",
+			policy:      "deployments:\n  keepComplete: -1\n",
+			expectedErr: "deployments.keepComplete must be greater than or equal to 0",
+		},
+		{
+			name:        "negative age",
+			policy:      "builds:\n  keepYoungerThan: -1h\n",
+			expectedErr: "builds.keepYoungerThan must be greater than or equal to 0",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parsePolicy([]byte(test.policy))
+			if len(test.expectedErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsDefaults(t *testing.T) {
+	policy, err := parsePolicy([]byte("builds:\n  keepComplete: 2\n  orphans: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := builds.NewPruneBuildsOptions(genericclioptions.NewTestIOStreamsDiscard())
+	policy.Builds.apply(&o.KeepYoungerThan, &o.KeepComplete, &o.KeepFailed, &o.Orphans)
+	if o.KeepYoungerThan != 60*time.Minute || o.KeepComplete != 2 || o.KeepFailed != 1 || !o.Orphans {
+		t.Errorf("unexpected options: keepYoungerThan=%v keepComplete=%d keepFailed=%d orphans=%t", o.KeepYoungerThan, o.KeepComplete, o.KeepFailed, o.Orphans)
+	}
+}
+
+func TestRun(t *testing.T) {
+	old := metav1.NewTime(time.Now().Add(-48 * time.Hour))
+	build := func(name string, age time.Duration, config string) *buildv1.Build {
+		b := &buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, CreationTimestamp: metav1.NewTime(old.Add(age))},
+			Status:     buildv1.BuildStatus{Phase: buildv1.BuildPhaseComplete},
+		}
+		if len(config) > 0 {
+			b.Status.Config = &corev1.ObjectReference{Name: config, Namespace: "ns"}
+		}
+		return b
+	}
+	replicaSet := func(name string, age time.Duration) *kappsv1.ReplicaSet {
+		zero := int32(0)
+		return &kappsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "ns",
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(old.Add(age)),
+				OwnerReferences:   []metav1.OwnerReference{{Kind: "Deployment", Name: "web"}},
+			},
+			Spec: kappsv1.ReplicaSetSpec{Replicas: &zero},
+		}
+	}
+
+	buildClient := buildfake.NewSimpleClientset(
+		&buildv1.BuildConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "app"}},
+		build("app-1", 0, "app"),
+		build("app-2", time.Minute, "app"),
+		build("app-3", 2*time.Minute, "app"),
+		build("orphan-1", 0, ""),
+	)
+	kubeClient := kubefake.NewSimpleClientset(
+		&kappsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web"}},
+		replicaSet("web-1", 0),
+		replicaSet("web-2", time.Minute),
+	)
+	appsClient := appsfake.NewSimpleClientset()
+
+	policy, err := parsePolicy([]byte(`
+builds:
+  keepComplete: 1
+  orphans: true
+deployments:
+  keepComplete: 1
+  replicaSets: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, confirm := range []bool{false, true} {
+		buildClient.ClearActions()
+		kubeClient.ClearActions()
+		out := &bytes.Buffer{}
+		streams := genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}}
+		o := &PrunePolicyOptions{
+			Confirm:     confirm,
+			Policy:      policy,
+			Builds:      builds.NewPruneBuildsOptions(streams),
+			Deployments: deployments.NewPruneDeploymentsOptions(streams),
+			IOStreams:   streams,
+		}
+		o.Builds.BuildClient = buildClient.BuildV1()
+		policy.Builds.apply(&o.Builds.KeepYoungerThan, &o.Builds.KeepComplete, &o.Builds.KeepFailed, &o.Builds.Orphans)
+		o.Deployments.AppsClient = appsClient.AppsV1()
+		o.Deployments.KubeClient = kubeClient.CoreV1()
+		o.Deployments.KAppsClient = kubeClient.AppsV1()
+		o.Deployments.ReplicaSets = policy.Deployments.ReplicaSets
+		policy.Deployments.apply(&o.Deployments.KeepYoungerThan, &o.Deployments.KeepComplete, &o.Deployments.KeepFailed, &o.Deployments.Orphans)
+
+		if err := o.Run(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var rows []string
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			rows = append(rows, strings.Join(strings.Fields(line), " "))
+		}
+		expected := []string{
+			"NAMESPACE OWNER KIND NAME",
+			"ns <none> build orphan-1",
+			"ns buildconfig/app build app-1",
+			"ns buildconfig/app build app-2",
+			"ns deployment/web replicaset web-1",
+		}
+		if strings.Join(rows, "\n") != strings.Join(expected, "\n") {
+			t.Errorf("unexpected report with confirm=%t:\n%s", confirm, out.String())
+		}
+
+		deleted := 0
+		for _, action := range append(buildClient.Actions(), kubeClient.Actions()...) {
+			if _, ok := action.(clienttesting.DeleteAction); ok {
+				deleted++
+			}
+		}
+		if confirm && deleted != 4 {
+			t.Errorf("expected 4 deletions, got %d", deleted)
+		}
+		if !confirm && deleted != 0 {
+			t.Errorf("expected no deletions in a dry run, got %d", deleted)
+		}
+	}
+}
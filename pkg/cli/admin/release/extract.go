@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +29,9 @@ import (
 
 	"github.com/openshift/library-go/pkg/image/dockerv1client"
 	"github.com/openshift/library-go/pkg/manifest"
+	"github.com/openshift/library-go/pkg/verify"
+	"github.com/openshift/library-go/pkg/verify/store"
+	"github.com/openshift/library-go/pkg/verify/store/sigstore"
 	"github.com/openshift/oc/pkg/cli/image/extract"
 	"github.com/openshift/oc/pkg/cli/image/imagesource"
 	imagemanifest "github.com/openshift/oc/pkg/cli/image/manifest"
@@ -82,9 +88,24 @@ func NewExtract(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.
 			signed by the key. For more advanced signing, use the generated sha256sum.txt and an
 			external tool like gpg.
 
+			The --file flag extracts only the manifests whose path within the payload matches
+			the given name or glob, printing them to standard output or writing them to the
+			--to directory. If nothing matches, the closest file names are suggested.
+
 			The --credentials-requests flag filters extracted manifests to only cloud credential
 			requests. The --cloud flag further filters credential requests to a specific cloud.
 			Valid values for --cloud include alibabacloud, aws, azure, gcp, ibmcloud, nutanix, openstack, ovirt, powervs, and vsphere.
+			The --included flag skips the credential requests of capabilities that are not enabled
+			and of other feature sets, as the cluster version operator does. The capabilities and
+			the feature set are read from the cluster, or from the install config passed with
+			--install-config when preparing a manual mode install.
+
+			The --verify-signature flag requires that the release image digest has been signed by
+			the keys trusted by the release payload before anything is extracted. Signatures are
+			looked up in the stores configured by the payload, and in any additional stores passed
+			with --signature-store (http://, https:// or file:// URLs) or --signature-dir (a local
+			directory laid out as DIR/ALGO=DIGEST/signature-N). The keys that verified the payload
+			are printed on success.
 
 			Instead of extracting the manifests, you can specify --git=DIR to perform a Git
 			checkout of the source code that comprises the release. A warning will be printed
@@ -98,6 +119,19 @@ func NewExtract(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.
 
 			# Extract cloud credential requests for AWS
 			oc adm release extract --credentials-requests --cloud=aws
+
+			# Extract the AWS cloud credential requests needed by the cluster to be installed from install-config.yaml
+			oc adm release extract --credentials-requests --cloud=aws --included --install-config=install-config.yaml --to=DIR
+
+			# Print a single manifest of the release
+			oc adm release extract --file=0000_50_cluster-ingress-operator_00-ingress-credentials-request.yaml
+
+			# Extract the CRDs of the cluster version operator to DIR
+			oc adm release extract --file='0000_00_cluster-version-operator_*crd*.yaml' --to=DIR
+
+			# Extract the manifests of a release only if it is signed, using signatures stored on disk
+			oc adm release extract --verify-signature --signature-dir=/tmp/signatures \
+				--from=quay.io/openshift-release-dev/ocp-release@sha256:a9bc... --to=/tmp/release
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.Complete(f, cmd, args))
@@ -109,7 +143,7 @@ func NewExtract(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.
 	o.ParallelOptions.Bind(flags)
 
 	flags.StringVar(&o.From, "from", o.From, "Image containing the release payload.")
-	flags.StringVar(&o.File, "file", o.File, "Extract a single file from the payload to standard output.")
+	flags.StringVar(&o.File, "file", o.File, "Extract a single file from the payload to standard output, or to the --to directory if set. Accepts a glob matching several files.")
 	flags.StringVar(&o.Directory, "to", o.Directory, "Directory to write release contents to, defaults to the current directory.")
 
 	flags.StringVar(&o.GitExtractDir, "git", o.GitExtractDir, "Check out the sources that created this release into the provided dir. Repos will be created at <dir>/<host>/<path>. Requires 'git' on your path.")
@@ -122,6 +156,12 @@ func NewExtract(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.
 
 	flags.BoolVar(&o.CredentialsRequests, "credentials-requests", o.CredentialsRequests, "Extract credential request manifests only")
 	flags.StringVar(&o.Cloud, "cloud", o.Cloud, "Specify the cloud for which credential request manifests should be extracted. Works only in combination with --credentials-requests.")
+	flags.BoolVar(&o.Included, "included", o.Included, "Only extract the credential request manifests the cluster applies, given its enabled capabilities and feature set. Works only in combination with --credentials-requests.")
+	flags.StringVar(&o.InstallConfig, "install-config", o.InstallConfig, "Read the enabled capabilities and feature set for --included from this install config instead of the cluster.")
+
+	flags.BoolVar(&o.VerifySignature, "verify-signature", o.VerifySignature, "Verify the signature of the release image before extracting its contents.")
+	flags.StringSliceVar(&o.SignatureStores, "signature-store", o.SignatureStores, "An additional http://, https:// or file:// URL to look up release image signatures in. Requires --verify-signature.")
+	flags.StringVar(&o.SignatureDir, "signature-dir", o.SignatureDir, "An additional local directory to look up release image signatures in. Requires --verify-signature.")
 
 	flags.StringVarP(&o.Output, "output", "o", o.Output, "Output format. Supports 'commit' when used with '--git'.")
 	return cmd
@@ -147,6 +187,11 @@ type ExtractOptions struct {
 	// If Cloud is specified, then only the credential requests for that cloud are extracted.
 	CredentialsRequests bool
 	Cloud               string
+	// Included limits the credential requests to those applied with the enabled capabilities and
+	// feature set of the cluster, or of InstallConfig when set.
+	Included      bool
+	InstallConfig string
+	inclusion     *manifestInclusion
 
 	// GitExtractDir is the path of a root directory to extract the source of a release to.
 	GitExtractDir string
@@ -155,6 +200,13 @@ type ExtractOptions struct {
 	File      string
 	FileDir   string
 
+	// VerifySignature requires the release image to be signed by the keys trusted by the
+	// release payload before any content is extracted. SignatureStores and SignatureDir
+	// provide additional locations to look up signatures in.
+	VerifySignature bool
+	SignatureStores []string
+	SignatureDir    string
+
 	ExtractManifests bool
 	Manifests        []manifest.Manifest
 
@@ -178,6 +230,17 @@ func (o *ExtractOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args [
 	}
 	o.From = args[0]
 
+	if o.Included && o.CredentialsRequests {
+		if len(o.InstallConfig) > 0 {
+			o.inclusion, err = installConfigInclusion(o.InstallConfig)
+		} else {
+			o.inclusion, err = clusterInclusion(f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -191,6 +254,9 @@ func (o *ExtractOptions) Run() error {
 	}
 	if len(o.File) > 0 {
 		sources++
+		if _, err := path.Match(o.File, ""); err != nil {
+			return fmt.Errorf("--file is not a valid glob: %v", err)
+		}
 	}
 	if len(o.Command) > 0 {
 		sources++
@@ -206,20 +272,35 @@ func (o *ExtractOptions) Run() error {
 	if !o.CredentialsRequests && len(o.Cloud) > 0 {
 		return fmt.Errorf("--cloud is only supported with --credentials-requests")
 	}
+	if !o.CredentialsRequests && o.Included {
+		return fmt.Errorf("--included is only supported with --credentials-requests")
+	}
+	if !o.Included && len(o.InstallConfig) > 0 {
+		return fmt.Errorf("--install-config is only supported with --included")
+	}
 	if len(o.Cloud) > 0 {
 		if _, ok := credRequestCloudProviderSpecKindMapping[o.Cloud]; !ok {
 			return fmt.Errorf("--cloud value not recognized, must be one of: %v", validCloudValues())
 		}
 	}
+	if !o.VerifySignature && (len(o.SignatureStores) > 0 || len(o.SignatureDir) > 0) {
+		return fmt.Errorf("--signature-store and --signature-dir require --verify-signature")
+	}
 
 	switch {
 	case sources > 1:
 		return fmt.Errorf("only one of --tools, --command, --credentials-requests, --file, or --git may be specified")
 	case len(o.From) == 0:
 		return fmt.Errorf("must specify an image containing a release payload with --from")
-	case o.Directory != "." && len(o.File) > 0:
-		return fmt.Errorf("only one of --to and --file may be set")
+	}
+
+	if o.VerifySignature {
+		if err := o.verifyReleaseSignature(); err != nil {
+			return err
+		}
+	}
 
+	switch {
 	case len(o.GitExtractDir) > 0:
 		return o.extractGit(o.GitExtractDir)
 	case o.Tools:
@@ -259,16 +340,13 @@ func (o *ExtractOptions) Run() error {
 		}
 		var manifestErrs []error
 		found := false
+		files := &fileExtractor{pattern: o.File, out: o.Out}
+		if o.Directory != "." {
+			files.dir = dir
+		}
 		opts.TarEntryCallback = func(hdr *tar.Header, _ extract.LayerInfo, r io.Reader) (bool, error) {
 			if !o.ExtractManifests {
-				if hdr.Name != o.File {
-					return true, nil
-				}
-				if _, err := io.Copy(o.Out, r); err != nil {
-					return false, err
-				}
-				found = true
-				return false, nil
+				return files.visit(hdr, r)
 			} else {
 				switch hdr.Name {
 				case o.File:
@@ -303,6 +381,9 @@ func (o *ExtractOptions) Run() error {
 		if err := opts.Run(); err != nil {
 			return err
 		}
+		if !o.ExtractManifests {
+			return files.complete()
+		}
 		if !found {
 			return fmt.Errorf("image did not contain %s", o.File)
 		}
@@ -346,6 +427,9 @@ func (o *ExtractOptions) Run() error {
 				if m.GVK != credentialsRequestGVK {
 					continue
 				}
+				if o.inclusion != nil && !o.inclusion.includes(m) {
+					continue
+				}
 				if len(expectedProviderSpecKind) > 0 {
 					kind, _, err := unstructured.NestedString(m.Obj.Object, "spec", "providerSpec", "kind")
 					if err != nil {
@@ -417,6 +501,73 @@ func (o *ExtractOptions) Run() error {
 	}
 }
 
+// verifyReleaseSignature loads the verification config map from the release payload and
+// returns an error unless the release digest is signed by every key it trusts.
+func (o *ExtractOptions) verifyReleaseSignature() error {
+	var stores []store.Store
+	for _, s := range o.SignatureStores {
+		u, err := url.Parse(s)
+		if err != nil {
+			return fmt.Errorf("--signature-store %q is not a valid URL: %v", s, err)
+		}
+		switch u.Scheme {
+		case "http", "https":
+			stores = append(stores, &sigstore.Store{URI: u, HTTPClient: sigstore.NewCachedHTTPClientConstructor(signatureHTTPClient, nil).HTTPClient})
+		case "file":
+			stores = append(stores, newSignatureDirStore(u.Path))
+		default:
+			return fmt.Errorf("--signature-store %q must be a URL with scheme file://, http://, or https://", s)
+		}
+	}
+	if len(o.SignatureDir) > 0 {
+		stores = append(stores, newSignatureDirStore(o.SignatureDir))
+	}
+
+	opts := NewInfoOptions(genericclioptions.IOStreams{Out: ioutil.Discard, ErrOut: o.ErrOut})
+	opts.SecurityOptions = o.SecurityOptions
+	opts.FileDir = o.FileDir
+	release, err := opts.LoadReleaseInfo(o.From, false)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range release.ManifestFiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var manifests []manifest.Manifest
+	for _, name := range names {
+		ms, err := manifest.ParseManifests(bytes.NewReader(release.ManifestFiles[name]))
+		if err != nil {
+			return errors.Wrapf(err, "error parsing %s", name)
+		}
+		manifests = append(manifests, ms...)
+	}
+
+	verifier, err := verify.NewFromManifests(manifests, sigstore.NewCachedHTTPClientConstructor(signatureHTTPClient, nil).HTTPClient)
+	if err != nil {
+		return fmt.Errorf("unable to load the release verification config map: %v", err)
+	}
+	if verifier == nil {
+		return fmt.Errorf("the release image %s does not define any trusted keys and cannot be verified", o.From)
+	}
+	for _, s := range stores {
+		verifier.AddStore(s)
+	}
+
+	releaseDigest := release.ContentDigest.String()
+	ctx, cancelFn := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancelFn()
+	if err := verifier.Verify(ctx, releaseDigest); err != nil {
+		return fmt.Errorf("the release image %s failed signature verification: %v", releaseDigest, err)
+	}
+	for _, key := range signingKeys(verifier, releaseDigest) {
+		fmt.Fprintf(o.ErrOut, "info: Release image %s signature verified by %s\n", releaseDigest, key)
+	}
+	return nil
+}
+
 func (o *ExtractOptions) extractGit(dir string) error {
 	switch o.Output {
 	case "commit", "":
@@ -514,3 +665,135 @@ func validCloudValues() []string {
 	}
 	return values
 }
+
+// fileExtractor copies the payload files whose name matches pattern to out, or into dir
+// when it is set. The names of every visited file are recorded so that the closest ones
+// can be suggested when nothing matched.
+type fileExtractor struct {
+	pattern string
+	dir     string
+	out     io.Writer
+
+	names     []string
+	extracted []string
+}
+
+func (e *fileExtractor) visit(hdr *tar.Header, r io.Reader) (bool, error) {
+	e.names = append(e.names, hdr.Name)
+	if ok, err := path.Match(e.pattern, hdr.Name); err != nil || !ok {
+		return err == nil, err
+	}
+
+	if len(e.dir) == 0 {
+		if len(e.extracted) > 0 {
+			fmt.Fprintln(e.out, "---")
+		}
+		if _, err := io.Copy(e.out, r); err != nil {
+			return false, err
+		}
+	} else {
+		// a file may only be written inside dir
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return false, fmt.Errorf("refusing to extract %s outside of %s", hdr.Name, e.dir)
+		}
+		target := filepath.Join(e.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+			return false, err
+		}
+		f, err := os.Create(target)
+		if err != nil {
+			return false, err
+		}
+		_, err = io.Copy(f, r)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return false, err
+		}
+		fmt.Fprintf(e.out, "Extracted %s\n", hdr.Name)
+	}
+	e.extracted = append(e.extracted, hdr.Name)
+
+	// an exact name can only match once, there is no need to read the rest of the payload
+	return isGlob(e.pattern), nil
+}
+
+// complete returns an error listing the closest file names if no file was extracted.
+func (e *fileExtractor) complete() error {
+	if len(e.extracted) > 0 {
+		return nil
+	}
+	closest := closestNames(e.pattern, e.names, 5)
+	if len(closest) == 0 {
+		return fmt.Errorf("image did not contain %s", e.pattern)
+	}
+	return fmt.Errorf("image did not contain %s, closest matches are:\n  %s", e.pattern, strings.Join(closest, "\n  "))
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// closestNames returns up to limit names ordered by their edit distance to the literal
+// part of pattern, skipping names that are too different to be a plausible typo.
+func closestNames(pattern string, names []string, limit int) []string {
+	literal := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`*?[]\`, r) {
+			return -1
+		}
+		return r
+	}, pattern)
+	maxDistance := len(literal) / 2
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, name := range names {
+		if d := editDistance(literal, name); d <= maxDistance {
+			candidates = append(candidates, candidate{name: name, distance: d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var closest []string
+	for i := 0; i < len(candidates) && i < limit; i++ {
+		closest = append(closest, candidates[i].name)
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if d := prev[j] + 1; d < curr[j] {
+				curr[j] = d
+			}
+			if d := curr[j-1] + 1; d < curr[j] {
+				curr[j] = d
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
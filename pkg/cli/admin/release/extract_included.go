@@ -0,0 +1,128 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"sigs.k8s.io/yaml"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/library-go/pkg/manifest"
+)
+
+const (
+	// capabilityAnnotation lists the capabilities, separated by '+', that must all be enabled for
+	// the cluster version operator to apply a manifest.
+	capabilityAnnotation = "capability.openshift.io/name"
+	// featureSetAnnotation lists the feature sets, separated by ',', a manifest is applied in.
+	featureSetAnnotation = "release.openshift.io/feature-set"
+)
+
+// manifestInclusion describes the optional manifests of a release that a cluster applies.
+type manifestInclusion struct {
+	// capabilities are the enabled capabilities, nil if every capability is enabled
+	capabilities sets.String
+	featureSet   configv1.FeatureSet
+}
+
+// includes returns true if the cluster applies the manifest.
+func (i *manifestInclusion) includes(m manifest.Manifest) bool {
+	annotations := m.Obj.GetAnnotations()
+	if value, ok := annotations[capabilityAnnotation]; ok && i.capabilities != nil {
+		for _, capability := range strings.Split(value, "+") {
+			if !i.capabilities.Has(strings.TrimSpace(capability)) {
+				return false
+			}
+		}
+	}
+	if value, ok := annotations[featureSetAnnotation]; ok {
+		featureSet := string(i.featureSet)
+		if len(featureSet) == 0 {
+			featureSet = "Default"
+		}
+		for _, fs := range strings.Split(value, ",") {
+			if strings.TrimSpace(fs) == featureSet {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// installConfig holds the fields of an installer install-config.yaml that select the manifests
+// applied to the cluster.
+type installConfig struct {
+	FeatureSet   configv1.FeatureSet                      `json:"featureSet,omitempty"`
+	Capabilities *configv1.ClusterVersionCapabilitiesSpec `json:"capabilities,omitempty"`
+}
+
+// installConfigInclusion reads the manifests a cluster installed from the install config at path
+// will apply.
+func installConfigInclusion(path string) (*manifestInclusion, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &installConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("unable to parse install config %s: %v", path, err)
+	}
+	inclusion := &manifestInclusion{featureSet: config.FeatureSet}
+	if config.Capabilities == nil {
+		return inclusion, nil
+	}
+	baseline := config.Capabilities.BaselineCapabilitySet
+	if len(baseline) == 0 {
+		baseline = configv1.ClusterVersionCapabilitySetCurrent
+	}
+	capabilities, ok := configv1.ClusterVersionCapabilitySets[baseline]
+	if !ok {
+		return nil, fmt.Errorf("install config %s has an unrecognized baselineCapabilitySet %q", path, baseline)
+	}
+	inclusion.capabilities = sets.NewString()
+	for _, capability := range append(capabilities, config.Capabilities.AdditionalEnabledCapabilities...) {
+		inclusion.capabilities.Insert(string(capability))
+	}
+	return inclusion, nil
+}
+
+// clusterInclusion reads the enabled capabilities and the feature set of the cluster.
+func clusterInclusion(f kcmdutil.Factory) (*manifestInclusion, error) {
+	cfg, err := f.ToRESTConfig()
+	if err != nil {
+		return nil, fmt.Errorf("--included requires --install-config or a connection to an OpenShift 4.x server: %v", err)
+	}
+	client, err := configv1client.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cv, err := client.ConfigV1().ClusterVersions().Get(context.TODO(), "version", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("--included requires --install-config or a connection to an OpenShift 4.x server: %v", err)
+	}
+	inclusion := &manifestInclusion{}
+	// clusters that predate capabilities apply the manifests of every capability
+	if cv.Spec.Capabilities != nil || len(cv.Status.Capabilities.EnabledCapabilities) > 0 {
+		inclusion.capabilities = sets.NewString()
+		for _, capability := range cv.Status.Capabilities.EnabledCapabilities {
+			inclusion.capabilities.Insert(string(capability))
+		}
+	}
+	featureGate, err := client.ConfigV1().FeatureGates().Get(context.TODO(), "cluster", metav1.GetOptions{})
+	switch {
+	case kapierrors.IsNotFound(err):
+	case err != nil:
+		return nil, err
+	default:
+		inclusion.featureSet = featureGate.Spec.FeatureSet
+	}
+	return inclusion, nil
+}
@@ -0,0 +1,225 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/library-go/pkg/manifest"
+)
+
+var testPayloadFiles = []struct {
+	name     string
+	contents string
+}{
+	{name: "0000_00_cluster-version-operator_01_clusteroperator.crd.yaml", contents: "kind: CustomResourceDefinition\nmetadata:\n  name: clusteroperators\n"},
+	{name: "0000_00_cluster-version-operator_01_clusterversion.crd.yaml", contents: "kind: CustomResourceDefinition\nmetadata:\n  name: clusterversions\n"},
+	{name: "0000_50_cluster-ingress-operator_02-deployment.yaml", contents: "kind: Deployment\n"},
+	{name: "image-references", contents: "{}"},
+}
+
+func visitTestPayload(t *testing.T, e *fileExtractor) {
+	for _, file := range testPayloadFiles {
+		cont, err := e.visit(&tar.Header{Name: file.name}, strings.NewReader(file.contents))
+		if err != nil {
+			t.Fatalf("unexpected error visiting %s: %v", file.name, err)
+		}
+		if !cont {
+			return
+		}
+	}
+}
+
+func TestFileExtractorSingleManifest(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := &fileExtractor{pattern: "0000_50_cluster-ingress-operator_02-deployment.yaml", out: out}
+	visitTestPayload(t, e)
+	if err := e.complete(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "kind: Deployment\n" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	if len(e.names) != 3 {
+		t.Errorf("expected extraction to stop after the matching file, visited %v", e.names)
+	}
+}
+
+func TestFileExtractorGlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "release-extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := &bytes.Buffer{}
+	e := &fileExtractor{pattern: "0000_00_cluster-version-operator_*.crd.yaml", dir: dir, out: out}
+	visitTestPayload(t, e)
+	if err := e.complete(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("expected 2 extracted files, got %d", len(files))
+	}
+	for _, file := range testPayloadFiles[:2] {
+		data, err := ioutil.ReadFile(filepath.Join(dir, file.name))
+		if err != nil {
+			t.Fatalf("expected %s to be extracted: %v", file.name, err)
+		}
+		if string(data) != file.contents {
+			t.Errorf("unexpected contents of %s: %q", file.name, string(data))
+		}
+	}
+	if !strings.Contains(out.String(), "Extracted 0000_00_cluster-version-operator_01_clusterversion.crd.yaml") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestFileExtractorOutsideDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "release-extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	target := filepath.Join(dir, "to")
+	if err := os.Mkdir(target, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"../escaped.yaml", "manifests/../../escaped.yaml", "/tmp/escaped.yaml"} {
+		e := &fileExtractor{pattern: name, dir: target, out: &bytes.Buffer{}}
+		if _, err := e.visit(&tar.Header{Name: name}, strings.NewReader("kind: Deployment\n")); err == nil || !strings.Contains(err.Error(), "refusing to extract") {
+			t.Errorf("%s: expected the file to be rejected, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped.yaml")); !os.IsNotExist(err) {
+		t.Errorf("expected no file to be written outside of the target directory: %v", err)
+	}
+}
+
+func TestFileExtractorGlobToStdout(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := &fileExtractor{pattern: "*.crd.yaml", out: out}
+	visitTestPayload(t, e)
+	if err := e.complete(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := testPayloadFiles[0].contents + "---\n" + testPayloadFiles[1].contents
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestFileExtractorNotFound(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := &fileExtractor{pattern: "0000_50_cluster-ingress-operator_02-deploymnt.yml", out: out}
+	visitTestPayload(t, e)
+	err := e.complete()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	expected := "image did not contain 0000_50_cluster-ingress-operator_02-deploymnt.yml, closest matches are:\n  0000_50_cluster-ingress-operator_02-deployment.yaml"
+	if err.Error() != expected {
+		t.Errorf("unexpected error:\n%v\nexpected:\n%s", err, expected)
+	}
+	if out.Len() > 0 {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+
+	e = &fileExtractor{pattern: "nothing-like-it", out: out}
+	visitTestPayload(t, e)
+	if err := e.complete(); err == nil || err.Error() != "image did not contain nothing-like-it" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestManifestInclusion(t *testing.T) {
+	credentialsRequest := func(annotations string) manifest.Manifest {
+		ms, err := manifest.ParseManifests(strings.NewReader("apiVersion: cloudcredential.openshift.io/v1\nkind: CredentialsRequest\nmetadata:\n  name: test\n  namespace: openshift-cloud-credential-operator\n  annotations:\n" + annotations))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ms[0]
+	}
+	tests := []struct {
+		name        string
+		inclusion   manifestInclusion
+		annotations string
+		expected    bool
+	}{
+		{name: "not annotated", annotations: "    other: value\n", expected: true},
+		{name: "enabled capability", inclusion: manifestInclusion{capabilities: sets.NewString("baremetal")}, annotations: "    capability.openshift.io/name: baremetal\n", expected: true},
+		{name: "disabled capability", inclusion: manifestInclusion{capabilities: sets.NewString()}, annotations: "    capability.openshift.io/name: baremetal\n"},
+		{name: "one of several capabilities disabled", inclusion: manifestInclusion{capabilities: sets.NewString("baremetal")}, annotations: "    capability.openshift.io/name: baremetal+openshift-samples\n"},
+		{name: "every capability enabled", annotations: "    capability.openshift.io/name: baremetal\n", expected: true},
+		{name: "default feature set", annotations: "    release.openshift.io/feature-set: Default,TechPreviewNoUpgrade\n", expected: true},
+		{name: "other feature set", annotations: "    release.openshift.io/feature-set: TechPreviewNoUpgrade\n"},
+		{name: "matching feature set", inclusion: manifestInclusion{featureSet: "TechPreviewNoUpgrade"}, annotations: "    release.openshift.io/feature-set: TechPreviewNoUpgrade\n", expected: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.inclusion.includes(credentialsRequest(test.annotations)); actual != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestInstallConfigInclusion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "install-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name                 string
+		config               string
+		expectedCapabilities []string
+		expectedFeatureSet   string
+		expectedErr          string
+	}{
+		{name: "no capabilities", config: "apiVersion: v1\nplatform:\n  aws:\n    region: us-east-1\n"},
+		{name: "feature set", config: "featureSet: TechPreviewNoUpgrade\n", expectedFeatureSet: "TechPreviewNoUpgrade"},
+		{name: "baseline and additional capabilities", config: "capabilities:\n  baselineCapabilitySet: None\n  additionalEnabledCapabilities:\n  - baremetal\n", expectedCapabilities: []string{"baremetal"}},
+		{name: "unknown baseline", config: "capabilities:\n  baselineCapabilitySet: v9.99\n", expectedErr: `unrecognized baselineCapabilitySet "v9.99"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, "install-config.yaml")
+			if err := ioutil.WriteFile(path, []byte(test.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+			inclusion, err := installConfigInclusion(path)
+			if len(test.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.expectedCapabilities == nil && inclusion.capabilities != nil {
+				t.Errorf("expected every capability to be enabled, got %v", inclusion.capabilities.List())
+			}
+			if test.expectedCapabilities != nil && !inclusion.capabilities.Equal(sets.NewString(test.expectedCapabilities...)) {
+				t.Errorf("expected capabilities %v, got %v", test.expectedCapabilities, inclusion.capabilities.List())
+			}
+			if string(inclusion.featureSet) != test.expectedFeatureSet {
+				t.Errorf("expected feature set %q, got %q", test.expectedFeatureSet, inclusion.featureSet)
+			}
+		})
+	}
+}
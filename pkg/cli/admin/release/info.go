@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -93,7 +94,9 @@ func NewInfo(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Com
 
 			The --bugs and --changelog flags will use git to clone the source of the release and display
 			the code changes that occurred between the two release arguments. This operation is slow
-			and requires sufficient disk space on the selected drive to clone all repositories.
+			and requires sufficient disk space on the selected drive to clone all repositories. Pass
+			-o json with --changelog to print the added, removed, and updated components along with
+			the commits made to each source repository as a structured document.
 
 			If the specified image supports multiple operating systems, the image that matches the
 			current operating system will be chosen. Otherwise you must pass --filter-by-os to
@@ -109,9 +112,18 @@ func NewInfo(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Com
 			# Show the source code difference between two releases
 			oc adm release info 4.2.0 4.2.2 --commits
 
+			# Generate a JSON changelog between two releases, cloning the sources under /tmp/git
+			oc adm release info 4.2.0 4.2.2 --changelog=/tmp/git -o json
+
 			# Show where the images referenced by the release are located
 			oc adm release info quay.io/openshift-release-dev/ocp-release:4.2.2 --pullspecs
 
+			# Show the pull specs of the operator images in a release
+			oc adm release info quay.io/openshift-release-dev/ocp-release:4.2.2 --pullspecs --filter='-operator$'
+
+			# Print the pull spec of the cli image in a release
+			oc adm release info quay.io/openshift-release-dev/ocp-release:4.2.2 --pullspec-for=cli
+
 			# Show information about linux/s390x image
 			# Note: Wildcard filter is not supported. Pass a single os/arch to extract
 			oc adm release info quay.io/openshift-release-dev/ocp-release:4.2.2 --filter-by-os=linux/s390x
@@ -138,7 +150,9 @@ func NewInfo(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Com
 	flags.BoolVar(&o.ShowCommitURL, "commit-urls", o.ShowCommitURL, "Display a link (if possible) to the source code.")
 	flags.BoolVar(&o.ShowPullSpec, "pullspecs", o.ShowPullSpec, "Display the pull spec of each image instead of the digest.")
 	flags.BoolVar(&o.ShowSize, "size", o.ShowSize, "Display the size of each image including overlap.")
+	flags.StringVar(&o.Filter, "filter", o.Filter, "Only display the component images whose names match this regular expression, in the default output and with --output.")
 	flags.StringVar(&o.ImageFor, "image-for", o.ImageFor, "Print the pull spec of the specified image or an error if it does not exist.")
+	flags.StringVar(&o.PullSpecFor, "pullspec-for", o.PullSpecFor, "Print the pull spec by digest of the specified component or an error listing the available components if it does not exist.")
 	flags.StringVarP(&o.Output, "output", "o", o.Output, "Display the release info in an alternative format: digest|json|name|pullspec|template|jsonpath.")
 	flags.StringVar(&o.ChangelogDir, "changelog", o.ChangelogDir, "Generate changelog output from the git directories extracted to this path.")
 	flags.StringVar(&o.BugsDir, "bugs", o.BugsDir, "Generate bug listings from the changelogs in the git repositories extracted to this path.")
@@ -157,7 +171,9 @@ type InfoOptions struct {
 	FileDir string
 
 	Output        string
+	Filter        string
 	ImageFor      string
+	PullSpecFor   string
 	IncludeImages bool
 	ShowContents  bool
 	ShowCommit    bool
@@ -170,6 +186,9 @@ type InfoOptions struct {
 	BugsDir      string
 	SkipBugCheck bool
 
+	// filter is the compiled Filter
+	filter *regexp.Regexp
+
 	ParallelOptions imagemanifest.ParallelOptions
 	SecurityOptions imagemanifest.SecurityOptions
 	FilterOptions   imagemanifest.FilterOptions
@@ -378,6 +397,12 @@ func (o *InfoOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []st
 		o.From = o.Images[0]
 		o.Images = o.Images[1:]
 	}
+	if len(o.Filter) > 0 {
+		o.filter, err = regexp.Compile(o.Filter)
+		if err != nil {
+			return fmt.Errorf("--filter is not a valid regular expression: %v", err)
+		}
+	}
 	return o.FilterOptions.Complete(cmd.Flags())
 }
 
@@ -386,6 +411,9 @@ func (o *InfoOptions) Validate() error {
 	if len(o.ImageFor) > 0 {
 		count++
 	}
+	if len(o.PullSpecFor) > 0 {
+		count++
+	}
 	if o.ShowCommit {
 		count++
 	}
@@ -405,11 +433,17 @@ func (o *InfoOptions) Validate() error {
 		count++
 	}
 	if count > 1 {
-		return fmt.Errorf("only one of --commits, --commit-urls, --pullspecs, --contents, --size, --verify may be specified")
+		return fmt.Errorf("only one of --commits, --commit-urls, --pullspecs, --contents, --size, --verify, --image-for, --pullspec-for may be specified")
 	}
 	if len(o.ImageFor) > 0 && len(o.Output) > 0 {
 		return fmt.Errorf("--output and --image-for may not both be specified")
 	}
+	if len(o.PullSpecFor) > 0 && len(o.Output) > 0 {
+		return fmt.Errorf("--output and --pullspec-for may not both be specified")
+	}
+	if len(o.Filter) > 0 && (len(o.From) > 0 || o.ShowContents || o.Verify || len(o.ImageFor) > 0 || len(o.PullSpecFor) > 0) {
+		return fmt.Errorf("--filter may not be used with --changes-from, --contents, --verify, --image-for or --pullspec-for")
+	}
 	if o.SkipBugCheck && len(o.BugsDir) == 0 {
 		return fmt.Errorf("--skip-bug-check requires --bugs")
 	}
@@ -432,8 +466,10 @@ func (o *InfoOptions) Validate() error {
 			return fmt.Errorf("--output only supports 'name' for --bugs")
 		}
 	case len(o.ChangelogDir) > 0:
-		if len(o.Output) > 0 {
-			return fmt.Errorf("--output is not supported for this mode")
+		switch o.Output {
+		case "", "json":
+		default:
+			return fmt.Errorf("--output only supports 'json' for --changelog")
 		}
 	default:
 		output := strings.SplitN(o.Output, "=", 2)[0]
@@ -486,6 +522,9 @@ func (o *InfoOptions) Run() error {
 			return describeBugs(o.Out, o.ErrOut, diff, o.BugsDir, o.Output, o.SkipBugCheck)
 		}
 		if len(o.ChangelogDir) > 0 {
+			if o.Output == "json" {
+				return describeChangelogJSON(o.Out, o.ErrOut, diff, o.ChangelogDir)
+			}
 			return describeChangelog(o.Out, o.ErrOut, diff, o.ChangelogDir)
 		}
 		return describeReleaseDiff(o.Out, diff, o.ShowCommit, o.Output)
@@ -534,6 +573,13 @@ func (o *InfoOptions) describeImage(release *ReleaseInfo) error {
 		_, err := io.Copy(o.Out, newContentStreamForRelease(release))
 		return err
 	}
+	if o.filter != nil {
+		release = filterReleaseComponents(release, o.filter)
+		if len(release.References.Spec.Tags) == 0 {
+			fmt.Fprintf(o.ErrOut, "No components of %s match --filter %q\n", release.PreferredName(), o.Filter)
+			return nil
+		}
+	}
 	output := strings.SplitN(o.Output, "=", 2)
 	switch output[0] {
 	case "json":
@@ -591,9 +637,39 @@ func (o *InfoOptions) describeImage(release *ReleaseInfo) error {
 		fmt.Fprintln(o.Out, spec)
 		return nil
 	}
+	if len(o.PullSpecFor) > 0 {
+		spec, err := findComponentPullSpec(release.References, o.PullSpecFor, release.Image)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, spec)
+		return nil
+	}
 	return describeReleaseInfo(o.Out, release, o.ShowCommit, o.ShowCommitURL, o.ShowPullSpec, o.ShowSize)
 }
 
+// filterReleaseComponents returns a copy of release that only contains the component images
+// whose names match filter.
+func filterReleaseComponents(release *ReleaseInfo, filter *regexp.Regexp) *ReleaseInfo {
+	filtered := *release
+	filtered.References = release.References.DeepCopy()
+	filtered.References.Spec.Tags = nil
+	for _, tag := range release.References.Spec.Tags {
+		if filter.MatchString(tag.Name) {
+			filtered.References.Spec.Tags = append(filtered.References.Spec.Tags, tag)
+		}
+	}
+	if release.Images != nil {
+		filtered.Images = make(map[string]*Image)
+		for name, image := range release.Images {
+			if filter.MatchString(name) {
+				filtered.Images[name] = image
+			}
+		}
+	}
+	return &filtered
+}
+
 func findImageSpec(image *imageapi.ImageStream, tagName, imageName string) (string, error) {
 	for _, tag := range image.Spec.Tags {
 		if tag.Name == tagName {
@@ -605,6 +681,31 @@ func findImageSpec(image *imageapi.ImageStream, tagName, imageName string) (stri
 	return "", fmt.Errorf("no image tag %q exists in the release image %s", tagName, imageName)
 }
 
+// findComponentPullSpec returns the pull spec by digest of the named component in the release,
+// or an error listing the names of the available components.
+func findComponentPullSpec(image *imageapi.ImageStream, tagName, imageName string) (string, error) {
+	var names []string
+	for _, tag := range image.Spec.Tags {
+		if tag.From == nil || tag.From.Kind != "DockerImage" || len(tag.From.Name) == 0 {
+			continue
+		}
+		if tag.Name != tagName {
+			names = append(names, tag.Name)
+			continue
+		}
+		ref, err := imagereference.Parse(tag.From.Name)
+		if err != nil {
+			return "", fmt.Errorf("the component %q in the release image %s has an invalid pull spec: %v", tagName, imageName, err)
+		}
+		if len(ref.ID) == 0 {
+			return "", fmt.Errorf("the component %q in the release image %s does not point to a digest", tagName, imageName)
+		}
+		return ref.Exact(), nil
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("no component %q exists in the release image %s, available components: %s", tagName, imageName, strings.Join(names, ", "))
+}
+
 func calculateDiff(from, to *ReleaseInfo) (*ReleaseDiff, error) {
 	diff := &ReleaseDiff{
 		From:             from,
@@ -1580,6 +1681,132 @@ func describeChangelog(out, errOut io.Writer, diff *ReleaseDiff, dir string) err
 	return nil
 }
 
+// ReleaseChangelog is the structured form of the changelog between two releases.
+type ReleaseChangelog struct {
+	From ChangelogRelease `json:"from"`
+	To   ChangelogRelease `json:"to"`
+
+	Components []ChangelogComponent `json:"components,omitempty"`
+
+	AddedImages   []string `json:"addedImages,omitempty"`
+	RemovedImages []string `json:"removedImages,omitempty"`
+	RebuiltImages []string `json:"rebuiltImages,omitempty"`
+
+	CodeChanges []ChangelogCodeChange `json:"codeChanges,omitempty"`
+}
+
+// ChangelogRelease identifies one of the releases in a changelog.
+type ChangelogRelease struct {
+	Name    string        `json:"name"`
+	Digest  digest.Digest `json:"digest"`
+	Created metav1.Time   `json:"created"`
+}
+
+// ChangelogComponent describes the version of a component in the newer release.
+type ChangelogComponent struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Version     string `json:"version"`
+	FromVersion string `json:"fromVersion,omitempty"`
+}
+
+// ChangelogCodeChange describes the commits made to a source repository between two releases
+// and the images built from them.
+type ChangelogCodeChange struct {
+	Repo   string   `json:"repo"`
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Images []string `json:"images"`
+
+	Commits []ChangelogCommit `json:"commits"`
+}
+
+// ChangelogCommit is a single merge commit in a source repository.
+type ChangelogCommit struct {
+	Commit      string    `json:"commit"`
+	Subject     string    `json:"subject"`
+	PullRequest int       `json:"pullRequest,omitempty"`
+	CommitDate  time.Time `json:"commitDate"`
+}
+
+// calculateChangelog gathers the changelog between the two releases in the diff, using git
+// to clone the source repositories into dir.
+func calculateChangelog(errOut io.Writer, diff *ReleaseDiff, dir string) (*ReleaseChangelog, bool) {
+	changelog := &ReleaseChangelog{
+		From: ChangelogRelease{Name: diff.From.PreferredName(), Digest: diff.From.Digest, Created: diff.From.References.CreationTimestamp},
+		To:   ChangelogRelease{Name: diff.To.PreferredName(), Digest: diff.To.Digest, Created: diff.To.References.CreationTimestamp},
+	}
+
+	for _, key := range diff.To.ComponentVersions.OrderedKeys() {
+		version := diff.To.ComponentVersions[key]
+		component := ChangelogComponent{Name: key, DisplayName: version.DisplayName, Version: version.Version}
+		if old, ok := diff.From.ComponentVersions[key]; ok && old != version {
+			component.FromVersion = old.Version
+		}
+		changelog.Components = append(changelog.Components, component)
+	}
+
+	for k, imageDiff := range diff.ChangedImages {
+		switch {
+		case imageDiff.From == nil:
+			changelog.AddedImages = append(changelog.AddedImages, k)
+		case imageDiff.To == nil:
+			changelog.RemovedImages = append(changelog.RemovedImages, k)
+		}
+	}
+	sort.Strings(changelog.AddedImages)
+	sort.Strings(changelog.RemovedImages)
+
+	codeChanges, imageChanges, incorrectImageChanges := releaseDiffContentChanges(diff)
+	for _, change := range imageChanges {
+		changelog.RebuiltImages = append(changelog.RebuiltImages, change.Name)
+	}
+	changelog.RebuiltImages = append(changelog.RebuiltImages, incorrectImageChanges...)
+
+	var hasError bool
+	for _, change := range codeChanges {
+		_, commits, err := commitsForRepo(dir, change, errOut, errOut)
+		if err != nil {
+			fmt.Fprintf(errOut, "error: %v\n", err)
+			hasError = true
+			continue
+		}
+		codeChange := ChangelogCodeChange{
+			Repo:    change.Repo,
+			From:    change.From,
+			To:      change.To,
+			Images:  change.ImagesAffected,
+			Commits: []ChangelogCommit{},
+		}
+		for _, commit := range commits {
+			codeChange.Commits = append(codeChange.Commits, ChangelogCommit{
+				Commit:      commit.Commit,
+				Subject:     commit.Subject,
+				PullRequest: commit.PullRequest,
+				CommitDate:  commit.CommitDate,
+			})
+		}
+		changelog.CodeChanges = append(changelog.CodeChanges, codeChange)
+	}
+	return changelog, hasError
+}
+
+func describeChangelogJSON(out, errOut io.Writer, diff *ReleaseDiff, dir string) error {
+	if diff.To.Digest == diff.From.Digest {
+		return fmt.Errorf("releases are identical")
+	}
+	changelog, hasError := calculateChangelog(errOut, diff, dir)
+	data, err := json.MarshalIndent(changelog, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, string(data))
+	if hasError {
+		return kcmdutil.ErrExit
+	}
+	return nil
+}
+
 func describeBugs(out, errOut io.Writer, diff *ReleaseDiff, dir string, format string, skipBugCheck bool) error {
 	if diff.To.Digest == diff.From.Digest {
 		return fmt.Errorf("releases are identical")
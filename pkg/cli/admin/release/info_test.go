@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/diff"
 
 	imageapi "github.com/openshift/api/image/v1"
@@ -279,3 +281,164 @@ func asStrings(a []error) []string {
 	}
 	return out
 }
+
+func Test_findComponentPullSpec(t *testing.T) {
+	is := &imageapi.ImageStream{
+		Spec: imageapi.ImageStreamSpec{
+			Tags: []imageapi.TagReference{
+				{
+					Name: "machine-config-operator",
+					From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift/release@sha256:0000000000000000000000000000000000000000000000000000000000000001"},
+				},
+				{
+					Name: "cli",
+					From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift/release@sha256:0000000000000000000000000000000000000000000000000000000000000002"},
+				},
+				{
+					Name: "tagged",
+					From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift/release:tagged"},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name    string
+		tag     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "component by name",
+			tag:  "cli",
+			want: "quay.io/openshift/release@sha256:0000000000000000000000000000000000000000000000000000000000000002",
+		},
+		{
+			name:    "component without digest",
+			tag:     "tagged",
+			wantErr: `the component "tagged" in the release image test does not point to a digest`,
+		},
+		{
+			name:    "missing component",
+			tag:     "missing",
+			wantErr: `no component "missing" exists in the release image test, available components: cli, machine-config-operator, tagged`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := findComponentPullSpec(is, tt.tag, "test")
+			if len(tt.wantErr) > 0 {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_describeImageFilter(t *testing.T) {
+	release := &ReleaseInfo{
+		References: &imageapi.ImageStream{
+			Spec: imageapi.ImageStreamSpec{
+				Tags: []imageapi.TagReference{
+					{Name: "machine-config-operator", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift/release@sha256:01"}},
+					{Name: "cli", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift/release@sha256:02"}},
+					{Name: "console-operator", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift/release@sha256:03"}},
+				},
+			},
+		},
+		Images: map[string]*Image{"cli": {Name: "cli"}, "console-operator": {Name: "console-operator"}},
+	}
+	release.References.Name = "4.11.0"
+	tests := []struct {
+		name   string
+		filter string
+		output string
+		want   string
+		errOut string
+	}{
+		{name: "names", filter: "-operator$", output: "name", want: "machine-config-operator\nconsole-operator\n"},
+		{name: "pullspecs", filter: "^cli$", output: "pullspec", want: "quay.io/openshift/release@sha256:02\n"},
+		{name: "json", filter: "^console", output: "json", want: `"name": "console-operator"`},
+		{name: "no match", filter: "^missing$", output: "name", errOut: `No components of 4.11.0 match --filter "^missing$"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+			o := &InfoOptions{Output: tt.output, Filter: tt.filter, filter: regexp.MustCompile(tt.filter)}
+			o.Out, o.ErrOut = out, errOut
+			if err := o.describeImage(release); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), tt.want) || (len(tt.want) == 0 && out.Len() > 0) {
+				t.Errorf("unexpected output %q", out.String())
+			}
+			if tt.output == "json" && strings.Contains(out.String(), "machine-config-operator") {
+				t.Errorf("unexpected filtered component in output %q", out.String())
+			}
+			if !strings.Contains(errOut.String(), tt.errOut) {
+				t.Errorf("expected error output %q, got %q", tt.errOut, errOut.String())
+			}
+		})
+	}
+	if len(release.References.Spec.Tags) != 3 || len(release.Images) != 2 {
+		t.Errorf("the release was modified by the filter")
+	}
+}
+
+func Test_calculateChangelog(t *testing.T) {
+	tag := func(name, pullSpec string) imageapi.TagReference {
+		return imageapi.TagReference{Name: name, From: &corev1.ObjectReference{Kind: "DockerImage", Name: pullSpec}}
+	}
+	from := &ReleaseInfo{
+		Digest: "sha256:0000000000000000000000000000000000000000000000000000000000000001",
+		References: &imageapi.ImageStream{
+			Spec: imageapi.ImageStreamSpec{
+				Tags: []imageapi.TagReference{
+					tag("cli", "quay.io/openshift/release@sha256:0000000000000000000000000000000000000000000000000000000000000010"),
+					tag("removed", "quay.io/openshift/release@sha256:0000000000000000000000000000000000000000000000000000000000000011"),
+				},
+			},
+		},
+		ComponentVersions: ComponentVersions{"kubernetes": {Version: "1.23.0"}},
+	}
+	to := &ReleaseInfo{
+		Digest: "sha256:0000000000000000000000000000000000000000000000000000000000000002",
+		References: &imageapi.ImageStream{
+			Spec: imageapi.ImageStreamSpec{
+				Tags: []imageapi.TagReference{
+					tag("cli", "quay.io/openshift/release@sha256:0000000000000000000000000000000000000000000000000000000000000020"),
+					tag("added", "quay.io/openshift/release@sha256:0000000000000000000000000000000000000000000000000000000000000021"),
+				},
+			},
+		},
+		ComponentVersions: ComponentVersions{"kubernetes": {Version: "1.23.5", DisplayName: "Kubernetes"}},
+	}
+	releaseDiff, err := calculateDiff(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changelog, hasError := calculateChangelog(io.Discard, releaseDiff, t.TempDir())
+	if hasError {
+		t.Fatalf("unexpected error calculating changelog")
+	}
+	if !reflect.DeepEqual(changelog.AddedImages, []string{"added"}) {
+		t.Errorf("unexpected added images: %v", changelog.AddedImages)
+	}
+	if !reflect.DeepEqual(changelog.RemovedImages, []string{"removed"}) {
+		t.Errorf("unexpected removed images: %v", changelog.RemovedImages)
+	}
+	if !reflect.DeepEqual(changelog.RebuiltImages, []string{"cli"}) {
+		t.Errorf("unexpected rebuilt images: %v", changelog.RebuiltImages)
+	}
+	expected := []ChangelogComponent{{Name: "kubernetes", DisplayName: "Kubernetes", Version: "1.23.5", FromVersion: "1.23.0"}}
+	if !reflect.DeepEqual(changelog.Components, expected) {
+		t.Errorf("%s", diff.ObjectReflectDiff(expected, changelog.Components))
+	}
+}
@@ -389,6 +389,38 @@ func (o *MirrorOptions) handleSignatures(context context.Context, signaturesByDi
 	return nil
 }
 
+// handleReleaseSignatures applies or writes the signature config maps once the release has been
+// mirrored. A release whose signature lookup found no signatures is mirrored without them, with a
+// warning, but an error is returned if the signatures could not be looked up. verifyErr is the
+// result of verifying releaseDigest.
+func (o *MirrorOptions) handleReleaseSignatures(ctx context.Context, signaturesByDigest map[string][][]byte, releaseDigest string, verifyErr error) error {
+	if signaturesByDigest == nil {
+		return errors.New("failed to retrieve cached signatures")
+	}
+	if _, ok := signaturesByDigest[releaseDigest]; !ok {
+		if verifyErr != nil && !isMissingSignatureError(verifyErr) {
+			return fmt.Errorf("failed to retrieve cached signatures: %v", verifyErr)
+		}
+		fmt.Fprintf(o.ErrOut, "warning: No signatures were found for the release image %s, no signature config map was created\n", releaseDigest)
+		if len(signaturesByDigest) > 0 {
+			digests := make([]string, 0, len(signaturesByDigest))
+			for digest := range signaturesByDigest {
+				digests = append(digests, digest)
+			}
+			sort.Strings(digests)
+			klog.V(2).Infof("Cached signatures are only available for: %s", strings.Join(digests, ", "))
+		}
+		return nil
+	}
+	return o.handleSignatures(ctx, signaturesByDigest)
+}
+
+// isMissingSignatureError returns true if err is the error returned by a release verifier when
+// the signature lookup succeeded but none of the signatures it found are valid for the digest.
+func isMissingSignatureError(err error) bool {
+	return strings.Contains(err.Error(), "unable to locate a valid signature")
+}
+
 func (o *MirrorOptions) Run() error {
 	var recreateRequired bool
 	var hasPrefix bool
@@ -538,8 +570,9 @@ func (o *MirrorOptions) Run() error {
 	// verify the provided payload
 	ctx, cancelFn := context.WithCancel(context.Background())
 	defer cancelFn()
-	if err := imageVerifier.Verify(ctx, releaseDigest); err != nil {
-		fmt.Fprintf(o.ErrOut, "warning: An image was retrieved that failed verification: %v\n", err)
+	verifyErr := imageVerifier.Verify(ctx, releaseDigest)
+	if verifyErr != nil {
+		fmt.Fprintf(o.ErrOut, "warning: An image was retrieved that failed verification: %v\n", verifyErr)
 	}
 	var mappings []mirror.Mapping
 	if len(o.From) > 0 {
@@ -823,22 +856,8 @@ func (o *MirrorOptions) Run() error {
 		}
 	}
 	if o.ApplyReleaseImageSignature || len(o.ReleaseImageSignatureToDir) > 0 {
-		signatures := imageVerifier.Signatures()
-		if signatures == nil || len(signatures) == 0 {
-			return errors.New("failed to retrieve cached signatures")
-		}
-		if _, ok := signatures[releaseDigest]; ok {
-			err := o.handleSignatures(ctx, signatures)
-			if err != nil {
-				return fmt.Errorf("handling release image signatures: %v", err)
-			}
-		} else {
-			digests := make([]string, 0, len(signatures))
-			for digest := range signatures {
-				digests = append(digests, digest)
-			}
-			sort.Strings(digests)
-			return fmt.Errorf("no cached signatures for digest %s, just:\n%s", releaseDigest, strings.Join(digests, "\n"))
+		if err := o.handleReleaseSignatures(ctx, imageVerifier.Signatures(), releaseDigest, verifyErr); err != nil {
+			return fmt.Errorf("handling release image signatures: %v", err)
 		}
 	}
 	return nil
@@ -1,12 +1,18 @@
 package release
 
 import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	operatorv1alpha1 "github.com/openshift/api/operator/v1alpha1"
 
 	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
 func Test_dedupeSortSources(t *testing.T) {
@@ -118,3 +124,73 @@ func Test_dedupeSortSources(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleReleaseSignatures(t *testing.T) {
+	const releaseDigest = "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+	tests := []struct {
+		name       string
+		signatures map[string][][]byte
+		verifyErr  error
+		files      []string
+		warning    string
+		err        string
+	}{
+		{
+			name:       "unsigned release",
+			signatures: map[string][][]byte{},
+			verifyErr:  errors.New("unable to locate a valid signature for one or more sources"),
+			warning:    "No signatures were found for the release image " + releaseDigest,
+		},
+		{
+			name:       "signed release",
+			signatures: map[string][][]byte{releaseDigest: {[]byte("signature")}},
+			files:      []string{"signature-sha256-a3ed95caeb02ffe6.yaml"},
+		},
+		{
+			name: "no verifier",
+			err:  "failed to retrieve cached signatures",
+		},
+		{
+			name:       "signature lookup failed",
+			signatures: map[string][][]byte{},
+			verifyErr:  errors.New("context deadline exceeded"),
+			err:        "failed to retrieve cached signatures: context deadline exceeded",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "signatures")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := NewMirrorOptions(streams)
+			o.ReleaseImageSignatureToDir = dir
+			err = o.handleReleaseSignatures(context.TODO(), tc.signatures, releaseDigest, tc.verifyErr)
+			if len(tc.err) > 0 {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(errOut.String(), tc.warning) || (len(tc.warning) == 0 && errOut.Len() > 0) {
+				t.Errorf("unexpected warnings: %s", errOut.String())
+			}
+
+			var files []string
+			entries, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, entry := range entries {
+				files = append(files, entry.Name())
+			}
+			if !reflect.DeepEqual(files, tc.files) {
+				t.Errorf("expected files %v, got %v", tc.files, files)
+			}
+		})
+	}
+}
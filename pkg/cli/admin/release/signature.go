@@ -1,9 +1,23 @@
 package release
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
+
+	"golang.org/x/crypto/openpgp"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
+
+	"github.com/openshift/library-go/pkg/verify"
+	"github.com/openshift/library-go/pkg/verify/store"
+	"github.com/openshift/library-go/pkg/verify/store/sigstore"
 )
 
 // createReleaseSignatureMessage creates the core message to sign the release payload.
@@ -70,3 +84,72 @@ type optionalSignature struct {
 	Creator   string `json:"creator"`
 	Timestamp int64  `json:"timestamp"`
 }
+
+// signatureDirStore reads release image signatures from a local directory using the same
+// layout as a signature store, DIR/ALGO=DIGEST/signature-N, by serving the directory to the
+// library-go signature store.
+type signatureDirStore struct {
+	*sigstore.Store
+	dir string
+}
+
+var _ store.Store = &signatureDirStore{}
+
+func newSignatureDirStore(dir string) *signatureDirStore {
+	client := &http.Client{Transport: http.NewFileTransport(http.Dir(dir))}
+	return &signatureDirStore{
+		Store: &sigstore.Store{
+			URI:        &url.URL{Scheme: "http", Path: "/"},
+			HTTPClient: func() (*http.Client, error) { return client, nil },
+		},
+		dir: dir,
+	}
+}
+
+func (s *signatureDirStore) String() string {
+	return fmt.Sprintf("file://%s", s.dir)
+}
+
+// signatureHTTPClient returns the client used to retrieve release image signatures from
+// http:// and https:// signature stores.
+func signatureHTTPClient() (*http.Client, error) {
+	transport, err := transport.HTTPWrappersForConfig(
+		&transport.Config{
+			UserAgent: rest.DefaultKubernetesUserAgent() + "(release-extract)",
+		},
+		http.DefaultTransport,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Transport: transport,
+	}, nil
+}
+
+// signingKeys returns a description of each trusted key that has signed the release digest,
+// using the signatures cached by a successful verification.
+func signingKeys(verifier verify.Interface, releaseDigest string) []string {
+	signatures := verifier.Signatures()[releaseDigest]
+	var keys []string
+	for name, keyring := range verifier.Verifiers() {
+		for _, signature := range signatures {
+			md, err := openpgp.ReadMessage(bytes.NewReader(signature), keyring, nil, nil)
+			if err != nil || !md.IsSigned || md.SignedBy == nil {
+				continue
+			}
+			if _, err := ioutil.ReadAll(md.UnverifiedBody); err != nil || md.SignatureError != nil {
+				continue
+			}
+			var identities []string
+			for identity := range md.SignedBy.Entity.Identities {
+				identities = append(identities, identity)
+			}
+			sort.Strings(identities)
+			keys = append(keys, fmt.Sprintf("%s %X (%s)", strings.TrimPrefix(name, "verifier-public-key-"), md.SignedBy.PublicKey.Fingerprint, strings.Join(identities, ", ")))
+			break
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
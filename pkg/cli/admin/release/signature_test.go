@@ -0,0 +1,160 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+
+	"github.com/openshift/library-go/pkg/verify"
+)
+
+const testReleaseDigest = "sha256:817a12c32a39bbe394944ba49de563e085f1d3c5266eb8e9723256bc4448680e"
+
+// writeTestSignatures writes each signature to the layout of a signature store in dir.
+func writeTestSignatures(t *testing.T, dir, releaseDigest string, signatures ...[]byte) {
+	digestDir := filepath.Join(dir, strings.Replace(releaseDigest, ":", "=", 1))
+	if err := os.MkdirAll(digestDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i, signature := range signatures {
+		if err := ioutil.WriteFile(filepath.Join(digestDir, "signature-"+strconv.Itoa(i+1)), signature, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// signTestRelease returns a signature of releaseDigest by entity.
+func signTestRelease(t *testing.T, entity *openpgp.Entity, releaseDigest string) []byte {
+	message, err := createReleaseSignatureMessage("test", time.Now(), releaseDigest, "quay.io/openshift-release-dev/ocp-release@"+releaseDigest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	w, err := openpgp.Sign(buf, entity, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(message); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSignatureDirStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signatures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeTestSignatures(t, dir, testReleaseDigest, []byte("first"), []byte("second"))
+
+	s := newSignatureDirStore(dir)
+	if s.String() != "file://"+dir {
+		t.Errorf("unexpected description %q", s.String())
+	}
+	var read []string
+	err = s.Signatures(context.Background(), "", testReleaseDigest, func(ctx context.Context, signature []byte, errIn error) (bool, error) {
+		if errIn != nil {
+			t.Fatalf("unexpected error reading a signature: %v", errIn)
+		}
+		read = append(read, string(signature))
+		return false, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"first", "second"}; !reflect.DeepEqual(read, expected) {
+		t.Errorf("expected signatures %v, got %v", expected, read)
+	}
+
+	read = nil
+	err = s.Signatures(context.Background(), "", "sha256:0000000000000000000000000000000000000000000000000000000000000000", func(ctx context.Context, signature []byte, errIn error) (bool, error) {
+		read = append(read, string(signature))
+		return false, errIn
+	})
+	if err != nil || len(read) != 0 {
+		t.Errorf("expected no signatures for an unknown digest, got %v: %v", read, err)
+	}
+}
+
+func TestVerifyReleaseSignatureFromDir(t *testing.T) {
+	config := &packet.Config{RSABits: 1024, DefaultHash: crypto.SHA256}
+	trusted, err := openpgp.NewEntity("Trusted", "", "trusted@example.com", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrusted, err := openpgp.NewEntity("Untrusted", "", "untrusted@example.com", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDigest := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+
+	tests := []struct {
+		name       string
+		signatures [][]byte
+		err        string
+	}{
+		{
+			name:       "signed by the trusted key",
+			signatures: [][]byte{signTestRelease(t, trusted, testReleaseDigest)},
+		},
+		{
+			name:       "trusted signature after an untrusted one",
+			signatures: [][]byte{signTestRelease(t, untrusted, testReleaseDigest), signTestRelease(t, trusted, testReleaseDigest)},
+		},
+		{
+			name:       "signed by an untrusted key",
+			signatures: [][]byte{signTestRelease(t, untrusted, testReleaseDigest)},
+			err:        "unable to locate a valid signature",
+		},
+		{
+			name:       "signature of another digest",
+			signatures: [][]byte{signTestRelease(t, trusted, otherDigest)},
+			err:        "unable to locate a valid signature",
+		},
+		{
+			name: "no signatures",
+			err:  "unable to locate a valid signature",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "signatures")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			writeTestSignatures(t, dir, testReleaseDigest, test.signatures...)
+
+			verifier := verify.NewReleaseVerifier(map[string]openpgp.EntityList{"verifier-public-key-trusted": {trusted}}, newSignatureDirStore(dir))
+			err = verifier.Verify(context.Background(), testReleaseDigest)
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys := signingKeys(verifier, testReleaseDigest)
+			if len(keys) != 1 || !strings.HasPrefix(keys[0], "trusted ") || !strings.Contains(keys[0], "trusted@example.com") {
+				t.Errorf("expected the trusted key to be reported, got %v", keys)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package top
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
@@ -44,10 +46,36 @@ var (
 	topImagesExample = templates.Examples(`
 		# Show usage statistics for images
 		oc adm top images
+
+		# Show the ten images using the most storage
+		oc adm top images --sort-by=size --limit=10
+
+		# Show usage statistics for images as JSON
+		oc adm top images -o json
+
+		# Show the images that 'oc adm prune images' would remove with the same thresholds
+		oc adm top images --prunable --keep-tag-revisions=3 --keep-younger-than=60m
 	`)
 )
 
+const (
+	imageSortBySize    = "size"
+	imageSortByStreams = "streams"
+	imageSortByCreated = "created"
+)
+
 type TopImagesOptions struct {
+	// SortBy is the column the images are sorted by in descending order: size, streams or created.
+	SortBy string
+	// Limit is the number of images to show, zero shows all of them.
+	Limit  int
+	Output string
+
+	// Prunable only shows the images that are candidates for pruning with the given thresholds.
+	Prunable         bool
+	KeepYoungerThan  time.Duration
+	KeepTagRevisions int
+
 	// internal values
 	Images  *imagev1.ImageList
 	Streams *imagev1.ImageStreamList
@@ -58,7 +86,10 @@ type TopImagesOptions struct {
 
 func NewTopImagesOptions(streams genericclioptions.IOStreams) *TopImagesOptions {
 	return &TopImagesOptions{
-		IOStreams: streams,
+		SortBy:           imageSortBySize,
+		KeepYoungerThan:  60 * time.Minute,
+		KeepTagRevisions: 3,
+		IOStreams:        streams,
 	}
 }
 
@@ -77,6 +108,13 @@ func NewCmdTopImages(f kcmdutil.Factory, streams genericclioptions.IOStreams) *c
 		},
 	}
 
+	cmd.Flags().StringVar(&o.SortBy, "sort-by", o.SortBy, "Sort the images in descending order by one of: size, streams or created.")
+	cmd.Flags().IntVar(&o.Limit, "limit", o.Limit, "If greater than zero, only show this many images after sorting.")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. Only json is supported.")
+	cmd.Flags().BoolVar(&o.Prunable, "prunable", o.Prunable, "If true, only show the images that are candidates for 'oc adm prune images' and the storage that pruning them would reclaim.")
+	cmd.Flags().DurationVar(&o.KeepYoungerThan, "keep-younger-than", o.KeepYoungerThan, "With --prunable, the minimum age of an image for it to be a candidate for pruning.")
+	cmd.Flags().IntVar(&o.KeepTagRevisions, "keep-tag-revisions", o.KeepTagRevisions, "With --prunable, the number of image revisions for a tag in an image stream that are preserved.")
+
 	return cmd
 }
 
@@ -99,6 +137,8 @@ func (o *TopImagesOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args
 	namespace := cmd.Flag("namespace").Value.String()
 	if len(namespace) == 0 {
 		namespace = metav1.NamespaceAll
+	} else if o.Prunable {
+		return kcmdutil.UsageErrorf(cmd, "--prunable considers the image streams and pods of all namespaces and cannot be used with --namespace")
 	}
 
 	allImages, err := imageClient.Images().List(context.TODO(), metav1.ListOptions{})
@@ -124,26 +164,153 @@ func (o *TopImagesOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args
 
 // Validate ensures that a TopImagesOptions is valid and can be used to execute command.
 func (o TopImagesOptions) Validate(cmd *cobra.Command) error {
+	switch o.SortBy {
+	case imageSortBySize, imageSortByStreams, imageSortByCreated:
+	default:
+		return kcmdutil.UsageErrorf(cmd, "--sort-by must be one of size, streams or created")
+	}
+	if o.Limit < 0 {
+		return kcmdutil.UsageErrorf(cmd, "--limit may not be negative")
+	}
+	if len(o.Output) > 0 && o.Output != "json" {
+		return kcmdutil.UsageErrorf(cmd, "--output only supports json")
+	}
+	if !o.Prunable && (cmd.Flags().Changed("keep-younger-than") || cmd.Flags().Changed("keep-tag-revisions")) {
+		return kcmdutil.UsageErrorf(cmd, "--keep-younger-than and --keep-tag-revisions may only be used with --prunable")
+	}
+	if o.KeepYoungerThan < 0 {
+		return kcmdutil.UsageErrorf(cmd, "--keep-younger-than may not be negative")
+	}
+	if o.KeepTagRevisions < 0 {
+		return kcmdutil.UsageErrorf(cmd, "--keep-tag-revisions may not be negative")
+	}
 	return nil
 }
 
 // Run contains all the necessary functionality to show current image references.
 func (o TopImagesOptions) Run() error {
 	infos := o.imagesTop()
+	var reclaimable int64
+	if o.Prunable {
+		infos, reclaimable = o.prunableImages(infos, time.Now())
+	}
+	prunable := len(infos)
+	sortImageInfos(infos, o.SortBy)
+	if o.Limit > 0 && len(infos) > o.Limit {
+		infos = infos[:o.Limit]
+	}
+	if o.Output == "json" {
+		if o.Prunable {
+			fmt.Fprintf(o.ErrOut, "%d image(s) can be pruned, reclaiming %s\n", prunable, units.BytesSize(float64(reclaimable)))
+		}
+		rows := make([]imageInfo, 0, len(infos))
+		for _, info := range infos {
+			rows = append(rows, info.(imageInfo))
+		}
+		data, err := json.MarshalIndent(rows, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s\n", data)
+		return nil
+	}
 	Print(o.Out, ImageColumns, infos)
+	if o.Prunable {
+		fmt.Fprintf(o.Out, "\n%d image(s) can be pruned, reclaiming %s\n", prunable, units.BytesSize(float64(reclaimable)))
+	}
 	return nil
 }
 
+// prunableImages returns the images that are candidates for pruning: images managed by
+// OpenShift that are older than KeepYoungerThan, not used by any pod and not among the
+// KeepTagRevisions most recent revisions of any image stream tag. It also returns the
+// storage pruning them would reclaim, which excludes blobs shared with the kept images.
+func (o TopImagesOptions) prunableImages(infos []Info, now time.Time) ([]Info, int64) {
+	kept := sets.NewString()
+	for _, stream := range o.Streams.Items {
+		for _, tag := range stream.Status.Tags {
+			for i := 0; i < len(tag.Items) && i < o.KeepTagRevisions; i++ {
+				kept.Insert(tag.Items[i].Image)
+			}
+		}
+	}
+
+	images := make(map[string]*imagev1.Image)
+	for i := range o.Images.Items {
+		images[o.Images.Items[i].Name] = &o.Images.Items[i]
+	}
+
+	prunable := []Info{}
+	prunableNames := sets.NewString()
+	for _, info := range infos {
+		i := info.(imageInfo)
+		image, ok := images[i.Image]
+		if !ok || kept.Has(i.Image) || len(i.Usage) > 0 {
+			continue
+		}
+		if image.Annotations[imagev1.ManagedByOpenShiftAnnotation] != "true" {
+			continue
+		}
+		if now.Sub(image.CreationTimestamp.Time) < o.KeepYoungerThan {
+			continue
+		}
+		prunable = append(prunable, info)
+		prunableNames.Insert(i.Image)
+	}
+
+	keptBlobs := sets.NewString()
+	for name, image := range images {
+		if prunableNames.Has(name) {
+			continue
+		}
+		for blob := range getBlobs(image) {
+			keptBlobs.Insert(blob)
+		}
+	}
+	reclaimable := int64(0)
+	reclaimed := sets.NewString()
+	for _, name := range prunableNames.List() {
+		for blob, size := range getBlobs(images[name]) {
+			if keptBlobs.Has(blob) || reclaimed.Has(blob) {
+				continue
+			}
+			reclaimed.Insert(blob)
+			reclaimable += size
+		}
+	}
+	return prunable, reclaimable
+}
+
+// sortImageInfos sorts the image statistics in descending order of the given column,
+// keeping the existing order of images that compare equal.
+func sortImageInfos(infos []Info, sortBy string) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		a, b := infos[i].(imageInfo), infos[j].(imageInfo)
+		switch sortBy {
+		case imageSortByStreams:
+			if len(a.ImageStreamTags) != len(b.ImageStreamTags) {
+				return len(a.ImageStreamTags) > len(b.ImageStreamTags)
+			}
+			return a.Storage > b.Storage
+		case imageSortByCreated:
+			return b.Created.Before(&a.Created)
+		default:
+			return a.Storage > b.Storage
+		}
+	})
+}
+
 var ImageColumns = []string{"NAME", "IMAGESTREAMTAG", "PARENTS", "USAGE", "METADATA", "STORAGE"}
 
 // imageInfo contains statistic information about Image usage.
 type imageInfo struct {
-	Image           string
-	ImageStreamTags []string
-	Parents         []string
-	Usage           []string
-	Metadata        bool
-	Storage         int64
+	Image           string      `json:"image"`
+	ImageStreamTags []string    `json:"imageStreamTags"`
+	Parents         []string    `json:"parents"`
+	Usage           []string    `json:"usage"`
+	Metadata        bool        `json:"metadata"`
+	Storage         int64       `json:"storage"`
+	Created         metav1.Time `json:"created"`
 }
 
 var _ Info = &imageInfo{}
@@ -190,6 +357,7 @@ func (o TopImagesOptions) imagesTop() []Info {
 			Usage:           usage,
 			Metadata:        metadata,
 			Storage:         storage,
+			Created:         image.CreationTimestamp,
 		})
 	}
 	sort.Slice(infos, func(i, j int) bool {
@@ -208,26 +376,32 @@ func (o TopImagesOptions) imagesTop() []Info {
 
 func getStorage(image *imagev1.Image) int64 {
 	storage := int64(0)
-	blobSet := sets.NewString()
+	for _, size := range getBlobs(image) {
+		storage += size
+	}
+	return storage
+}
+
+// getBlobs returns the size of each layer and config blob of the image, by blob name.
+func getBlobs(image *imagev1.Image) map[string]int64 {
+	blobs := make(map[string]int64)
 	for _, layer := range image.DockerImageLayers {
-		if blobSet.Has(layer.Name) {
+		if _, ok := blobs[layer.Name]; ok {
 			continue
 		}
-		blobSet.Insert(layer.Name)
-		storage += layer.LayerSize
+		blobs[layer.Name] = layer.LayerSize
 	}
 	if err := imageutil.ImageWithMetadata(image); err != nil {
-		return storage
+		return blobs
 	}
 	dockerImage, ok := image.DockerImageMetadata.Object.(*dockerv10.DockerImage)
 	if !ok {
-		return storage
+		return blobs
 	}
-	if len(image.DockerImageConfig) > 0 && !blobSet.Has(dockerImage.ID) {
-		blobSet.Insert(dockerImage.ID)
-		storage += int64(len(image.DockerImageConfig))
+	if _, ok := blobs[dockerImage.ID]; len(image.DockerImageConfig) > 0 && !ok {
+		blobs[dockerImage.ID] = int64(len(image.DockerImageConfig))
 	}
-	return storage
+	return blobs
 }
 
 func getImageStreamTags(g genericgraph.Graph, node *imagegraph.ImageNode) []string {
@@ -1,11 +1,18 @@
 package top
 
 import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 
 	appsv1 "github.com/openshift/api/apps/v1"
 	buildv1 "github.com/openshift/api/build/v1"
@@ -753,3 +760,138 @@ func stringsEqual(actual, expected []string) bool {
 	}
 	return true
 }
+
+func TestSortImageInfos(t *testing.T) {
+	older := metav1.NewTime(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	newer := metav1.NewTime(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+	newInfos := func() []Info {
+		return []Info{
+			imageInfo{Image: "small", ImageStreamTags: []string{"ns/a (latest)", "ns/b (latest)"}, Storage: 10, Created: newer},
+			imageInfo{Image: "large", Storage: 300, Created: older},
+			imageInfo{Image: "medium", ImageStreamTags: []string{"ns/c (latest)"}, Storage: 200},
+		}
+	}
+	tests := map[string][]string{
+		imageSortBySize:    {"large", "medium", "small"},
+		imageSortByStreams: {"small", "medium", "large"},
+		imageSortByCreated: {"small", "large", "medium"},
+	}
+	for sortBy, expected := range tests {
+		infos := newInfos()
+		sortImageInfos(infos, sortBy)
+		var actual []string
+		for _, info := range infos {
+			actual = append(actual, info.(imageInfo).Image)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %v, got %v", sortBy, expected, actual)
+		}
+	}
+}
+
+func TestImagesTopLimitJSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	o := TopImagesOptions{
+		SortBy: imageSortBySize,
+		Limit:  1,
+		Output: "json",
+		Images: &imagev1.ImageList{
+			Items: []imagev1.Image{
+				{ObjectMeta: metav1.ObjectMeta{Name: "image1"}, DockerImageLayers: []imagev1.ImageLayer{{Name: "layer1", LayerSize: 512}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "image2"}, DockerImageLayers: []imagev1.ImageLayer{{Name: "layer2", LayerSize: 1024}}},
+			},
+		},
+		Streams:   &imagev1.ImageStreamList{},
+		Pods:      &corev1.PodList{},
+		IOStreams: genericclioptions.IOStreams{Out: out},
+	}
+	if err := o.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var rows []imageInfo
+	if err := json.Unmarshal(out.Bytes(), &rows); err != nil {
+		t.Fatalf("unexpected error decoding %s: %v", out.String(), err)
+	}
+	if len(rows) != 1 || rows[0].Image != "image2" || rows[0].Storage != 1024 {
+		t.Errorf("unexpected rows: %#v", rows)
+	}
+}
+
+func TestImagesTopPrunable(t *testing.T) {
+	now := time.Now()
+	old := metav1.NewTime(now.Add(-2 * time.Hour))
+	recent := metav1.NewTime(now.Add(-10 * time.Minute))
+	managed := map[string]string{imagev1.ManagedByOpenShiftAnnotation: "true"}
+	newImage := func(name string, created metav1.Time, annotations map[string]string, layers ...imagev1.ImageLayer) imagev1.Image {
+		return imagev1.Image{
+			ObjectMeta:        metav1.ObjectMeta{Name: name, CreationTimestamp: created, Annotations: annotations},
+			DockerImageLayers: layers,
+		}
+	}
+	shared := imagev1.ImageLayer{Name: "shared", LayerSize: 1000}
+	digest := func(c string) string { return "sha256:" + strings.Repeat(c, 64) }
+	current, previous, orphan, young, external, running := digest("1"), digest("2"), digest("3"), digest("4"), digest("5"), digest("6")
+
+	out := &bytes.Buffer{}
+	o := TopImagesOptions{
+		SortBy:           imageSortBySize,
+		Prunable:         true,
+		KeepYoungerThan:  time.Hour,
+		KeepTagRevisions: 1,
+		Images: &imagev1.ImageList{
+			Items: []imagev1.Image{
+				newImage(current, old, managed, shared, imagev1.ImageLayer{Name: "current", LayerSize: 1}),
+				newImage(previous, old, managed, shared, imagev1.ImageLayer{Name: "previous", LayerSize: 100}),
+				newImage(orphan, old, managed, imagev1.ImageLayer{Name: "orphan", LayerSize: 200}, imagev1.ImageLayer{Name: "previous", LayerSize: 100}),
+				newImage(young, recent, managed, imagev1.ImageLayer{Name: "young", LayerSize: 400}),
+				newImage(external, old, nil, imagev1.ImageLayer{Name: "external", LayerSize: 800}),
+				newImage(running, old, managed, imagev1.ImageLayer{Name: "running", LayerSize: 1600}),
+			},
+		},
+		Streams: &imagev1.ImageStreamList{
+			Items: []imagev1.ImageStream{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "stream1", Namespace: "ns1"},
+					Status: imagev1.ImageStreamStatus{
+						DockerImageRepository: "registry.example.com/ns1/stream1",
+						Tags: []imagev1.NamedTagEventList{
+							{Tag: "latest", Items: []imagev1.TagEvent{{Image: current}, {Image: previous}}},
+						},
+					},
+				},
+			},
+		},
+		Pods: &corev1.PodList{
+			Items: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns1"},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Image: "registry.example.com/ns1/stream1@" + running}}},
+					Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+				},
+			},
+		},
+		IOStreams: genericclioptions.IOStreams{Out: out},
+	}
+
+	infos := o.imagesTop()
+	prunable, reclaimable := o.prunableImages(infos, now)
+	var names []string
+	for _, info := range prunable {
+		names = append(names, info.(imageInfo).Image)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{previous, orphan}) {
+		t.Errorf("unexpected prunable images: %v", names)
+	}
+	// the shared layer is still used by the current image and the previous layer is counted once
+	if reclaimable != 300 {
+		t.Errorf("expected 300 reclaimable bytes, got %d", reclaimable)
+	}
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(out.String(), "2 image(s) can be pruned, reclaiming 300B\n") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,328 @@
+package top
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/cmd/top"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/metricsutil"
+	metricsapi "k8s.io/metrics/pkg/apis/metrics"
+	metricsv1beta1api "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	cmdutil "github.com/openshift/oc/pkg/helpers/cmd"
+)
+
+const aggregateByOwner = "owner"
+
+var topPodsExample = `
+  # Show the usage of the pods running on node worker-1 in all namespaces
+  oc adm top pod -A --node=worker-1
+
+  # Show the usage of every deployment, daemon set and other controller, highest memory first
+  oc adm top pod -A --aggregate-by=owner --sort-by=memory`
+
+// TopPodsOptions adds filtering by node and aggregation by owner to the pod usage command.
+type TopPodsOptions struct {
+	top.TopPodOptions
+
+	NodeName    string
+	AggregateBy string
+
+	KubeClient kubernetes.Interface
+}
+
+// NewCmdTopPods returns the upstream top pod command with the --node and --aggregate-by flags.
+func NewCmdTopPods(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &TopPodsOptions{
+		TopPodOptions: top.TopPodOptions{
+			IOStreams:          streams,
+			UseProtocolBuffers: true,
+		},
+	}
+	cmd := cmdutil.ReplaceCommandName("kubectl", "oc adm", top.NewCmdTopPod(f, &o.TopPodOptions, streams))
+	cmd.Flags().StringVar(&o.NodeName, "node", o.NodeName, "If set, only show the pods running on this node.")
+	cmd.Flags().StringVar(&o.AggregateBy, "aggregate-by", o.AggregateBy, "If set to 'owner', sum the usage of the pods of every deployment, daemon set or other top-level controller and print one row for each.")
+	cmd.Example += "\n" + topPodsExample
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if len(o.NodeName) == 0 && len(o.AggregateBy) == 0 {
+			run(c, args)
+			return
+		}
+		kcmdutil.CheckErr(o.Complete(f, c, args))
+		kcmdutil.CheckErr(o.Validate())
+		kcmdutil.CheckErr(o.Run())
+	}
+	return cmd
+}
+
+func (o *TopPodsOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if err := o.TopPodOptions.Complete(f, cmd, args); err != nil {
+		return err
+	}
+	var err error
+	o.KubeClient, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *TopPodsOptions) Validate() error {
+	if len(o.AggregateBy) > 0 && o.AggregateBy != aggregateByOwner {
+		return fmt.Errorf("--aggregate-by accepts only %s", aggregateByOwner)
+	}
+	if len(o.AggregateBy) > 0 && o.PrintContainers {
+		return errors.New("--aggregate-by and --containers may not be specified together")
+	}
+	return o.TopPodOptions.Validate()
+}
+
+// Run prints the usage of the pods on the node, or of their owners.
+func (o *TopPodsOptions) Run() error {
+	labelSelector := labels.Everything()
+	if len(o.LabelSelector) > 0 {
+		var err error
+		if labelSelector, err = labels.Parse(o.LabelSelector); err != nil {
+			return err
+		}
+	}
+	fieldSelector := fields.Everything()
+	if len(o.FieldSelector) > 0 {
+		var err error
+		if fieldSelector, err = fields.ParseSelector(o.FieldSelector); err != nil {
+			return err
+		}
+	}
+
+	apiGroups, err := o.DiscoveryClient.ServerGroups()
+	if err != nil {
+		return err
+	}
+	if !top.SupportedMetricsAPIVersionAvailable(apiGroups) {
+		return errors.New("Metrics API not available")
+	}
+	metrics, err := o.podMetrics(labelSelector, fieldSelector)
+	if err != nil {
+		return err
+	}
+
+	// the metrics API does not support selecting pods by node, so the pods are listed instead
+	podSelector := fieldSelector
+	if len(o.NodeName) > 0 {
+		podSelector = fields.AndSelectors(fieldSelector, fields.OneTermEqualSelector("spec.nodeName", o.NodeName))
+	}
+	pods, err := o.KubeClient.CoreV1().Pods(o.namespace()).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labelSelector.String(),
+		FieldSelector: podSelector.String(),
+	})
+	if err != nil {
+		return err
+	}
+	podsByName := map[string]*corev1.Pod{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		podsByName[pod.Namespace+"/"+pod.Name] = pod
+	}
+	if len(o.NodeName) > 0 {
+		filtered := metrics[:0]
+		for _, m := range metrics {
+			if _, ok := podsByName[m.Namespace+"/"+m.Name]; ok {
+				filtered = append(filtered, m)
+			}
+		}
+		metrics = filtered
+	}
+
+	if len(metrics) == 0 {
+		if o.AllNamespaces {
+			fmt.Fprintln(o.ErrOut, "No resources found")
+		} else {
+			fmt.Fprintf(o.ErrOut, "No resources found in %s namespace.\n", o.Namespace)
+		}
+		return nil
+	}
+	if len(o.AggregateBy) == 0 {
+		return o.Printer.PrintPodMetrics(metrics, o.PrintContainers, o.AllNamespaces, o.NoHeaders, o.SortBy)
+	}
+
+	usages := o.usageByOwner(metrics, podsByName)
+	sortOwnerUsages(usages, o.SortBy)
+	return printOwnerUsages(o.Out, usages, o.AllNamespaces, o.NoHeaders)
+}
+
+func (o *TopPodsOptions) namespace() string {
+	if o.AllNamespaces {
+		return metav1.NamespaceAll
+	}
+	return o.Namespace
+}
+
+// podMetrics returns the metrics of the pod selected by name or by the selectors.
+func (o *TopPodsOptions) podMetrics(labelSelector labels.Selector, fieldSelector fields.Selector) ([]metricsapi.PodMetrics, error) {
+	versionedMetrics := &metricsv1beta1api.PodMetricsList{}
+	if len(o.ResourceName) > 0 {
+		m, err := o.MetricsClient.MetricsV1beta1().PodMetricses(o.namespace()).Get(context.TODO(), o.ResourceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		versionedMetrics.Items = []metricsv1beta1api.PodMetrics{*m}
+	} else {
+		var err error
+		versionedMetrics, err = o.MetricsClient.MetricsV1beta1().PodMetricses(o.namespace()).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: labelSelector.String(),
+			FieldSelector: fieldSelector.String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	metrics := &metricsapi.PodMetricsList{}
+	if err := metricsv1beta1api.Convert_v1beta1_PodMetricsList_To_metrics_PodMetricsList(versionedMetrics, metrics, nil); err != nil {
+		return nil, err
+	}
+	return metrics.Items, nil
+}
+
+// ownerUsage is the summed usage of the pods of a top-level owner.
+type ownerUsage struct {
+	Namespace string
+	Owner     string
+	Pods      int
+	Usage     corev1.ResourceList
+}
+
+// usageByOwner sums the usage of the pods in metrics by their top-level owner. Pods without a
+// controller are reported on their own.
+func (o *TopPodsOptions) usageByOwner(metrics []metricsapi.PodMetrics, pods map[string]*corev1.Pod) []*ownerUsage {
+	resolver := &ownerResolver{client: o.KubeClient, owners: map[string]string{}}
+	usages := map[string]*ownerUsage{}
+	var result []*ownerUsage
+	for _, m := range metrics {
+		owner := "pod/" + m.Name
+		if pod, ok := pods[m.Namespace+"/"+m.Name]; ok {
+			owner = resolver.topLevelOwner(pod)
+		}
+		key := m.Namespace + "/" + owner
+		usage, ok := usages[key]
+		if !ok {
+			usage = &ownerUsage{Namespace: m.Namespace, Owner: owner, Usage: corev1.ResourceList{}}
+			usages[key] = usage
+			result = append(result, usage)
+		}
+		usage.Pods++
+		for _, c := range m.Containers {
+			for _, res := range metricsutil.MeasuredResources {
+				quantity := usage.Usage[res]
+				quantity.Add(c.Usage[res])
+				usage.Usage[res] = quantity
+			}
+		}
+	}
+	return result
+}
+
+// ownerResolver follows the controller references of pods through replica sets, replication
+// controllers and jobs to the deployment, deployment config or cron job that owns them.
+type ownerResolver struct {
+	client kubernetes.Interface
+	// owners caches the top-level owner of namespace/kind/name
+	owners map[string]string
+}
+
+func (r *ownerResolver) topLevelOwner(pod *corev1.Pod) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "pod/" + pod.Name
+	}
+	return r.resolve(pod.Namespace, ref)
+}
+
+func (r *ownerResolver) resolve(namespace string, ref *metav1.OwnerReference) string {
+	key := namespace + "/" + ref.Kind + "/" + ref.Name
+	if owner, ok := r.owners[key]; ok {
+		return owner
+	}
+
+	var parent metav1.Object
+	var err error
+	switch ref.Kind {
+	case "ReplicaSet":
+		parent, err = r.client.AppsV1().ReplicaSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	case "ReplicationController":
+		parent, err = r.client.CoreV1().ReplicationControllers(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	case "Job":
+		parent, err = r.client.BatchV1().Jobs(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+	}
+	owner := strings.ToLower(ref.Kind) + "/" + ref.Name
+	switch {
+	case err != nil:
+		if !kapierrors.IsNotFound(err) {
+			klog.V(4).Infof("Unable to find the owner of %s: %v", owner, err)
+		}
+	case parent != nil:
+		if parentRef := metav1.GetControllerOf(parent); parentRef != nil {
+			owner = r.resolve(namespace, parentRef)
+		}
+	}
+	r.owners[key] = owner
+	return owner
+}
+
+// sortOwnerUsages sorts by the given resource, highest first, and by namespace and owner.
+func sortOwnerUsages(usages []*ownerUsage, sortBy string) {
+	sort.SliceStable(usages, func(i, j int) bool {
+		a, b := usages[i], usages[j]
+		var res corev1.ResourceName
+		switch sortBy {
+		case "cpu":
+			res = corev1.ResourceCPU
+		case "memory":
+			res = corev1.ResourceMemory
+		}
+		if len(res) > 0 {
+			qa, qb := a.Usage[res], b.Usage[res]
+			if c := qa.Cmp(qb); c != 0 {
+				return c > 0
+			}
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Owner < b.Owner
+	})
+}
+
+func printOwnerUsages(out io.Writer, usages []*ownerUsage, withNamespace, noHeaders bool) error {
+	w := printers.GetNewTabWriter(out)
+	defer w.Flush()
+
+	if !noHeaders {
+		if withNamespace {
+			fmt.Fprint(w, "NAMESPACE\t")
+		}
+		fmt.Fprintln(w, "OWNER\tPODS\tCPU(cores)\tMEMORY(bytes)")
+	}
+	for _, usage := range usages {
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", usage.Namespace)
+		}
+		cpu, memory := usage.Usage[corev1.ResourceCPU], usage.Usage[corev1.ResourceMemory]
+		fmt.Fprintf(w, "%s\t%d\t%vm\t%vMi\n", usage.Owner, usage.Pods, cpu.MilliValue(), memory.Value()/(1024*1024))
+	}
+	return nil
+}
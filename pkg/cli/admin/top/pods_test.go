@@ -0,0 +1,120 @@
+package top
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	kappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	metricsapi "k8s.io/metrics/pkg/apis/metrics"
+)
+
+func TestUsageByOwner(t *testing.T) {
+	controller := true
+	ownedBy := func(kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &controller}}
+	}
+	pod := func(name string, owners []metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name, OwnerReferences: owners}}
+	}
+	usage := func(name, cpu, memory string) metricsapi.PodMetrics {
+		return metricsapi.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Containers: []metricsapi.ContainerMetrics{{
+				Name: "c",
+				Usage: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			}},
+		}
+	}
+
+	pods := map[string]*corev1.Pod{}
+	for _, p := range []*corev1.Pod{
+		pod("web-1", ownedBy("ReplicaSet", "web-abc")),
+		pod("web-2", ownedBy("ReplicaSet", "web-def")),
+		pod("agent-1", ownedBy("DaemonSet", "agent")),
+		pod("orphan-rs-1", ownedBy("ReplicaSet", "gone")),
+		pod("standalone", nil),
+	} {
+		pods[p.Namespace+"/"+p.Name] = p
+	}
+	client := fake.NewSimpleClientset(
+		&kappsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web-abc", OwnerReferences: ownedBy("Deployment", "web")}},
+		&kappsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web-def", OwnerReferences: ownedBy("Deployment", "web")}},
+	)
+	o := &TopPodsOptions{KubeClient: client}
+
+	usages := o.usageByOwner([]metricsapi.PodMetrics{
+		usage("web-1", "100m", "64Mi"),
+		usage("web-2", "150m", "64Mi"),
+		usage("agent-1", "50m", "256Mi"),
+		usage("orphan-rs-1", "10m", "16Mi"),
+		usage("standalone", "5m", "8Mi"),
+		usage("unknown", "1m", "1Mi"),
+	}, pods)
+
+	tests := []struct {
+		sortBy   string
+		expected string
+	}{
+		{
+			sortBy: "",
+			expected: `NAMESPACE OWNER PODS CPU(cores) MEMORY(bytes)
+ns daemonset/agent 1 50m 256Mi
+ns deployment/web 2 250m 128Mi
+ns pod/standalone 1 5m 8Mi
+ns pod/unknown 1 1m 1Mi
+ns replicaset/gone 1 10m 16Mi`,
+		},
+		{
+			sortBy: "cpu",
+			expected: `NAMESPACE OWNER PODS CPU(cores) MEMORY(bytes)
+ns deployment/web 2 250m 128Mi
+ns daemonset/agent 1 50m 256Mi
+ns replicaset/gone 1 10m 16Mi
+ns pod/standalone 1 5m 8Mi
+ns pod/unknown 1 1m 1Mi`,
+		},
+		{
+			sortBy: "memory",
+			expected: `NAMESPACE OWNER PODS CPU(cores) MEMORY(bytes)
+ns daemonset/agent 1 50m 256Mi
+ns deployment/web 2 250m 128Mi
+ns replicaset/gone 1 10m 16Mi
+ns pod/standalone 1 5m 8Mi
+ns pod/unknown 1 1m 1Mi`,
+		},
+	}
+	for _, test := range tests {
+		t.Run("sort by "+test.sortBy, func(t *testing.T) {
+			sortOwnerUsages(usages, test.sortBy)
+			out := &bytes.Buffer{}
+			if err := printOwnerUsages(out, usages, true, false); err != nil {
+				t.Fatal(err)
+			}
+			var rows []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				rows = append(rows, strings.Join(strings.Fields(line), " "))
+			}
+			if got := strings.Join(rows, "\n"); got != test.expected {
+				t.Errorf("expected\n%s\ngot\n%s", test.expected, got)
+			}
+		})
+	}
+
+	gets := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "get" {
+			gets++
+		}
+	}
+	if gets != 3 {
+		t.Errorf("expected every replica set to be looked up once, got %d lookups", gets)
+	}
+}
@@ -31,7 +31,7 @@ func NewCommandTop(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cob
 	}
 
 	cmdTopNode := cmdutil.ReplaceCommandName("kubectl", "oc adm", top.NewCmdTopNode(f, nil, streams))
-	cmdTopPod := cmdutil.ReplaceCommandName("kubectl", "oc adm", top.NewCmdTopPod(f, nil, streams))
+	cmdTopPod := NewCmdTopPods(f, streams)
 
 	cmds.AddCommand(NewCmdTopImages(f, streams))
 	cmds.AddCommand(NewCmdTopImageStreams(f, streams))
@@ -0,0 +1,265 @@
+// Package status contains a command for following the progress of a cluster update.
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned"
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+)
+
+// maxHistory is the number of previous updates the remaining time of an update is estimated from.
+const maxHistory = 5
+
+var errNoClusterVersion = fmt.Errorf("No cluster version information available - you must be connected to an OpenShift version 4 server to fetch the current version")
+
+func NewOptions(streams genericclioptions.IOStreams) *Options {
+	return &Options{
+		Interval:  30 * time.Second,
+		IOStreams: streams,
+	}
+}
+
+func New(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewOptions(streams)
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Display the progress of a cluster update",
+		Long: templates.LongDesc(`
+			Display the progress of a cluster update.
+
+			This command prints the version the cluster is updating to, how far the update has
+			progressed, the cluster operators that are still updating, and an estimate of the
+			remaining time based on the duration of previous updates of the cluster.
+
+			If the update is failing, the reason is printed along with the cluster operators that
+			are degraded or unavailable, and the command exits with an error.
+
+			Pass --watch to refresh the status until the update completes or fails. While
+			watching, errors reaching the server are reported and retried at the next refresh.
+		`),
+		Example: templates.Examples(`
+			# Display the progress of the current cluster update
+			oc adm upgrade status
+
+			# Follow the update every 10 seconds until it completes or fails
+			oc adm upgrade status --watch --interval=10s
+		`),
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Run())
+		},
+	}
+	flags := cmd.Flags()
+	flags.BoolVarP(&o.Watch, "watch", "w", o.Watch, "Refresh the status until the update completes or fails.")
+	flags.DurationVar(&o.Interval, "interval", o.Interval, "With --watch, the time to wait between refreshes.")
+	return cmd
+}
+
+type Options struct {
+	genericclioptions.IOStreams
+
+	Watch    bool
+	Interval time.Duration
+
+	Client configv1client.Interface
+}
+
+func (o *Options) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) > 0 {
+		return kcmdutil.UsageErrorf(cmd, "no positional arguments may be given")
+	}
+	if o.Interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	cfg, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := configv1client.NewForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	o.Client = client
+	return nil
+}
+
+func (o *Options) Run() error {
+	if !o.Watch {
+		_, err := o.printStatus(context.TODO())
+		return err
+	}
+	first := true
+	return wait.PollImmediateInfinite(o.Interval, func() (bool, error) {
+		cv, operators, err := o.getStatus(context.TODO())
+		if err != nil {
+			if err == errNoClusterVersion {
+				return false, err
+			}
+			// the API server may be briefly unavailable while the cluster updates
+			fmt.Fprintf(o.ErrOut, "warning: unable to get the status of the update, retrying in %s: %v\n", o.Interval, err)
+			return false, nil
+		}
+		if !first {
+			fmt.Fprintln(o.Out)
+		}
+		first = false
+		return writeStatus(o.Out, cv, operators, time.Now())
+	})
+}
+
+// printStatus prints the status of the cluster update and returns true once it is complete.
+func (o *Options) printStatus(ctx context.Context) (bool, error) {
+	cv, operators, err := o.getStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+	return writeStatus(o.Out, cv, operators, time.Now())
+}
+
+// getStatus returns the cluster version and the cluster operators.
+func (o *Options) getStatus(ctx context.Context) (*configv1.ClusterVersion, []configv1.ClusterOperator, error) {
+	cv, err := o.Client.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil, errNoClusterVersion
+		}
+		return nil, nil, err
+	}
+	operators, err := o.Client.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return cv, operators.Items, nil
+}
+
+// writeStatus writes the progress of the update of cv and returns true once it is complete. An
+// error is returned when the update is failing.
+func writeStatus(out io.Writer, cv *configv1.ClusterVersion, operators []configv1.ClusterOperator, now time.Time) (bool, error) {
+	progressing := v1helpers.FindStatusCondition(cv.Status.Conditions, configv1.OperatorProgressing)
+	if progressing == nil || progressing.Status != configv1.ConditionTrue {
+		fmt.Fprintf(out, "info: No update in progress, the cluster is at version %s\n", cv.Status.Desired.Version)
+		return true, failingError(out, cv, operators)
+	}
+
+	fmt.Fprintf(out, "Updating to %s\n", releaseVersionString(cv.Status.Desired))
+	if len(progressing.Message) > 0 {
+		fmt.Fprintf(out, "  %s\n", progressing.Message)
+	}
+
+	var updating []string
+	updated := 0
+	for _, operator := range operators {
+		if c := v1helpers.FindStatusCondition(operator.Status.Conditions, configv1.OperatorProgressing); c != nil && c.Status == configv1.ConditionTrue {
+			updating = append(updating, operator.Name)
+		}
+		if operatorVersion(operator) == cv.Status.Desired.Version {
+			updated++
+		}
+	}
+	sort.Strings(updating)
+	fmt.Fprintf(out, "Cluster operators updated: %d of %d\n", updated, len(operators))
+	if len(updating) > 0 {
+		fmt.Fprintf(out, "Cluster operators updating: %s\n", strings.Join(updating, ", "))
+	}
+
+	if len(cv.Status.History) > 0 && cv.Status.History[0].State == configv1.PartialUpdate {
+		elapsed := now.Sub(cv.Status.History[0].StartedTime.Time)
+		fmt.Fprintf(out, "Elapsed: %s\n", duration.HumanDuration(elapsed))
+		fmt.Fprintln(out, estimateRemaining(cv.Status.History[1:], elapsed))
+	}
+
+	return false, failingError(out, cv, operators)
+}
+
+// failingError prints why the update of cv is failing and the cluster operators that are
+// degraded or unavailable, and returns an error if it is.
+func failingError(out io.Writer, cv *configv1.ClusterVersion, operators []configv1.ClusterOperator) error {
+	c := v1helpers.FindStatusCondition(cv.Status.Conditions, "Failing")
+	if c == nil || c.Status != configv1.ConditionTrue {
+		return nil
+	}
+	fmt.Fprintf(out, "\nFailing=True\n\n  Reason: %s\n  Message: %s\n", c.Reason, strings.ReplaceAll(c.Message, "\n", "\n  "))
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	header := false
+	for _, operator := range operators {
+		condition := v1helpers.FindStatusCondition(operator.Status.Conditions, configv1.OperatorDegraded)
+		if condition == nil || condition.Status != configv1.ConditionTrue {
+			condition = v1helpers.FindStatusCondition(operator.Status.Conditions, configv1.OperatorAvailable)
+			if condition == nil || condition.Status != configv1.ConditionFalse {
+				continue
+			}
+		}
+		if !header {
+			fmt.Fprintf(w, "\n  NAME\tCONDITION\tREASON\tMESSAGE\n")
+			header = true
+		}
+		fmt.Fprintf(w, "  %s\t%s=%s\t%s\t%s\n", operator.Name, condition.Type, condition.Status, condition.Reason, strings.ReplaceAll(condition.Message, "\n", " "))
+	}
+	w.Flush()
+	return fmt.Errorf("the update to %s is failing: %s", releaseVersionString(cv.Status.Desired), c.Reason)
+}
+
+// estimateRemaining describes the time left in an update that has been running for elapsed,
+// based on the average duration of the most recent completed updates in history.
+func estimateRemaining(history []configv1.UpdateHistory, elapsed time.Duration) string {
+	var total time.Duration
+	count := 0
+	for _, update := range history {
+		if count == maxHistory {
+			break
+		}
+		if update.State != configv1.CompletedUpdate || update.CompletionTime == nil {
+			continue
+		}
+		total += update.CompletionTime.Sub(update.StartedTime.Time)
+		count++
+	}
+	if count == 0 {
+		return "Estimated time remaining: unknown, no previous update has completed"
+	}
+	average := total / time.Duration(count)
+	if elapsed >= average {
+		return fmt.Sprintf("Estimated time remaining: unknown, the update is taking longer than the %s average of the last %d updates", duration.HumanDuration(average), count)
+	}
+	return fmt.Sprintf("Estimated time remaining: %s, based on the %s average of the last %d updates", duration.HumanDuration(average-elapsed), duration.HumanDuration(average), count)
+}
+
+// operatorVersion returns the version of the operator of a cluster operator.
+func operatorVersion(operator configv1.ClusterOperator) string {
+	for _, version := range operator.Status.Versions {
+		if version.Name == "operator" {
+			return version.Version
+		}
+	}
+	return ""
+}
+
+func releaseVersionString(release configv1.Release) string {
+	if len(release.Version) > 0 {
+		return release.Version
+	}
+	if len(release.Image) > 0 {
+		return release.Image
+	}
+	return "<unknown>"
+}
@@ -0,0 +1,142 @@
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func TestWriteStatus(t *testing.T) {
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(now.Add(d)) }
+	completed := func(start, end time.Duration) configv1.UpdateHistory {
+		completion := at(end)
+		return configv1.UpdateHistory{State: configv1.CompletedUpdate, StartedTime: at(start), CompletionTime: &completion}
+	}
+	condition := func(conditionType configv1.ClusterStatusConditionType, status configv1.ConditionStatus, reason, message string) configv1.ClusterOperatorStatusCondition {
+		return configv1.ClusterOperatorStatusCondition{Type: conditionType, Status: status, Reason: reason, Message: message}
+	}
+	operator := func(name, version string, conditions ...configv1.ClusterOperatorStatusCondition) configv1.ClusterOperator {
+		return configv1.ClusterOperator{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: configv1.ClusterOperatorStatus{
+				Conditions: conditions,
+				Versions:   []configv1.OperandVersion{{Name: "operator", Version: version}},
+			},
+		}
+	}
+	updating := configv1.ClusterVersionStatus{
+		Desired: configv1.Release{Version: "4.11.1"},
+		History: []configv1.UpdateHistory{
+			{State: configv1.PartialUpdate, StartedTime: at(-20 * time.Minute), Version: "4.11.1"},
+			completed(-48*time.Hour, -48*time.Hour+50*time.Minute),
+			completed(-96*time.Hour, -96*time.Hour+70*time.Minute),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		status    configv1.ClusterVersionStatus
+		operators []configv1.ClusterOperator
+		done      bool
+		err       string
+		expected  []string
+	}{
+		{
+			name: "not updating",
+			status: configv1.ClusterVersionStatus{
+				Desired:    configv1.Release{Version: "4.11.0"},
+				Conditions: []configv1.ClusterOperatorStatusCondition{condition(configv1.OperatorProgressing, configv1.ConditionFalse, "", "Cluster version is 4.11.0")},
+			},
+			done:     true,
+			expected: []string{"No update in progress, the cluster is at version 4.11.0"},
+		},
+		{
+			name: "updating",
+			status: func() configv1.ClusterVersionStatus {
+				status := *updating.DeepCopy()
+				status.Conditions = []configv1.ClusterOperatorStatusCondition{condition(configv1.OperatorProgressing, configv1.ConditionTrue, "", "Working towards 4.11.1: 512 of 829 done (61% complete)")}
+				return status
+			}(),
+			operators: []configv1.ClusterOperator{
+				operator("etcd", "4.11.1"),
+				operator("kube-apiserver", "4.11.0", condition(configv1.OperatorProgressing, configv1.ConditionTrue, "", "")),
+				operator("dns", "4.11.0"),
+			},
+			expected: []string{
+				"Updating to 4.11.1\n  Working towards 4.11.1: 512 of 829 done (61% complete)\n",
+				"Cluster operators updated: 1 of 3\n",
+				"Cluster operators updating: kube-apiserver\n",
+				"Elapsed: 20m\n",
+				"Estimated time remaining: 40m, based on the 60m average of the last 2 updates\n",
+			},
+		},
+		{
+			name: "failing",
+			status: func() configv1.ClusterVersionStatus {
+				status := *updating.DeepCopy()
+				status.Conditions = []configv1.ClusterOperatorStatusCondition{
+					condition(configv1.OperatorProgressing, configv1.ConditionTrue, "", "Unable to apply 4.11.1"),
+					condition("Failing", configv1.ConditionTrue, "ClusterOperatorDegraded", "Cluster operator etcd is degraded"),
+				}
+				return status
+			}(),
+			operators: []configv1.ClusterOperator{
+				operator("etcd", "4.11.0", condition(configv1.OperatorDegraded, configv1.ConditionTrue, "EtcdMembersDown", "2 of 3 members are available")),
+				operator("dns", "4.11.0", condition(configv1.OperatorAvailable, configv1.ConditionFalse, "NoPods", "no dns pods")),
+				operator("console", "4.11.0", condition(configv1.OperatorAvailable, configv1.ConditionTrue, "", "")),
+			},
+			err: "the update to 4.11.1 is failing: ClusterOperatorDegraded",
+			expected: []string{
+				"Failing=True\n\n  Reason: ClusterOperatorDegraded\n  Message: Cluster operator etcd is degraded\n",
+				"etcd  Degraded=True    EtcdMembersDown  2 of 3 members are available",
+				"dns   Available=False  NoPods           no dns pods",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			cv := &configv1.ClusterVersion{Status: test.status}
+			done, err := writeStatus(out, cv, test.operators, now)
+			if len(test.err) > 0 {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("expected error %q, got %v", test.err, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if done != test.done {
+				t.Errorf("expected done %t, got %t", test.done, done)
+			}
+			for _, expected := range test.expected {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+				}
+			}
+			if strings.Contains(out.String(), "console") {
+				t.Errorf("unexpected available operator in output:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestEstimateRemaining(t *testing.T) {
+	start := metav1.NewTime(time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(30 * time.Minute))
+	history := []configv1.UpdateHistory{
+		{State: configv1.PartialUpdate, StartedTime: start},
+		{State: configv1.CompletedUpdate, StartedTime: start, CompletionTime: &end},
+	}
+	if got := estimateRemaining(history[:1], time.Minute); !strings.Contains(got, "no previous update has completed") {
+		t.Errorf("unexpected estimate without history: %s", got)
+	}
+	if got := estimateRemaining(history, 45*time.Minute); !strings.Contains(got, "taking longer than the 30m average of the last 1 updates") {
+		t.Errorf("unexpected estimate for an overdue update: %s", got)
+	}
+}
@@ -26,6 +26,7 @@ import (
 	imagereference "github.com/openshift/library-go/pkg/image/reference"
 
 	"github.com/openshift/oc/pkg/cli/admin/upgrade/channel"
+	"github.com/openshift/oc/pkg/cli/admin/upgrade/status"
 )
 
 var upgradeExample = templates.Examples(`
@@ -34,6 +35,9 @@ var upgradeExample = templates.Examples(`
 
 	# Update to the latest version
 	oc adm upgrade --to-latest=true
+
+	# Follow the progress of the update until it completes or fails
+	oc adm upgrade status --watch
 `)
 
 func NewOptions(streams genericclioptions.IOStreams) *Options {
@@ -61,7 +65,7 @@ func New(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command
 			Passing --to=VERSION or --to-image=IMAGE will upgrade the cluster to one of the available
 			updates or report an error if no such version exists. The cluster will then upgrade
 			itself and report status that is available via "oc get clusterversion" and "oc describe
-			clusterversion".
+			clusterversion", or followed with "oc adm upgrade status --watch".
 
 			If there are no versions available, or a bug in the cluster version operator prevents
 			updates from being retrieved, --to-image may be combined with the more powerful and
@@ -100,6 +104,7 @@ func New(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command
 	flags.BoolVar(&o.AllowNotRecommended, "allow-not-recommended", o.AllowNotRecommended, "Allows upgrade to a version when it is supported but not recommended for updates")
 
 	cmd.AddCommand(channel.New(f, streams))
+	cmd.AddCommand(status.New(f, streams))
 
 	return cmd
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/openshift/oc/pkg/cli/cancelbuild"
 	"github.com/openshift/oc/pkg/cli/debug"
 	"github.com/openshift/oc/pkg/cli/deployer"
+	"github.com/openshift/oc/pkg/cli/explain"
 	"github.com/openshift/oc/pkg/cli/expose"
 	"github.com/openshift/oc/pkg/cli/extract"
 	"github.com/openshift/oc/pkg/cli/idle"
@@ -180,7 +181,7 @@ func NewOcCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 				status.NewCmdStatus(f, ioStreams),
 				project.NewCmdProject(f, ioStreams),
 				projects.NewCmdProjects(f, ioStreams),
-				kubectlwrappers.NewCmdExplain(f, ioStreams),
+				explain.NewCmdExplain("oc", f, ioStreams),
 			},
 		},
 		{
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kclientcmd "k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/templates"
+
+	"github.com/openshift/oc/pkg/helpers/term"
+)
+
+var (
+	switchLong = templates.LongDesc(`
+		Switch to a context whose name contains the given substring.
+
+		The match is case insensitive. If exactly one context matches, or one context is named
+		exactly as the substring, it becomes the current context. If several contexts match, a
+		numbered menu of them is printed and you are prompted to pick one. Without an argument
+		the contexts are listed and the current one is marked with an asterisk.
+	`)
+
+	switchExample = templates.Examples(`
+		# List the contexts in the kubeconfig file
+		oc config switch
+
+		# Switch to the only context that contains 'staging' in its name
+		oc config switch staging
+	`)
+)
+
+type SwitchOptions struct {
+	PathOptions *kclientcmd.PathOptions
+	Substring   string
+
+	Config *clientcmdapi.Config
+
+	genericclioptions.IOStreams
+}
+
+func NewSwitchOptions(pathOptions *kclientcmd.PathOptions, streams genericclioptions.IOStreams) *SwitchOptions {
+	return &SwitchOptions{
+		PathOptions: pathOptions,
+		IOStreams:   streams,
+	}
+}
+
+// NewCmdSwitch returns a command that switches to a context by a part of its name
+func NewCmdSwitch(pathOptions *kclientcmd.PathOptions, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewSwitchOptions(pathOptions, streams)
+	cmd := &cobra.Command{
+		Use:                   "switch [SUBSTRING]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Switch to a context by a part of its name",
+		Long:                  switchLong,
+		Example:               switchExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Complete(cmd, args))
+			kcmdutil.CheckErr(o.Run())
+		},
+	}
+	return cmd
+}
+
+func (o *SwitchOptions) Complete(cmd *cobra.Command, args []string) error {
+	switch len(args) {
+	case 0:
+	case 1:
+		o.Substring = args[0]
+	default:
+		return kcmdutil.UsageErrorf(cmd, "expected at most one substring, got %q", args)
+	}
+
+	var err error
+	o.Config, err = o.PathOptions.GetStartingConfig()
+	return err
+}
+
+func (o *SwitchOptions) Run() error {
+	if len(o.Config.Contexts) == 0 {
+		return fmt.Errorf("no contexts are defined in the kubeconfig file")
+	}
+	if len(o.Substring) == 0 {
+		for _, name := range contextNames(o.Config) {
+			marker := " "
+			if name == o.Config.CurrentContext {
+				marker = "*"
+			}
+			fmt.Fprintf(o.Out, "%s %s\n", marker, name)
+		}
+		return nil
+	}
+
+	matches := matchingContexts(o.Config, o.Substring)
+	var name string
+	switch len(matches) {
+	case 0:
+		return fmt.Errorf("no context matches %q", o.Substring)
+	case 1:
+		name = matches[0]
+	default:
+		var err error
+		if name, err = o.selectContext(matches); err != nil {
+			return err
+		}
+	}
+
+	o.Config.CurrentContext = name
+	if err := kclientcmd.ModifyConfig(o.PathOptions, *o.Config, true); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, "Switched to context %q.\n", name)
+	return nil
+}
+
+// selectContext prints a numbered menu of names and prompts for one of them.
+func (o *SwitchOptions) selectContext(names []string) (string, error) {
+	fmt.Fprintf(o.Out, "Contexts matching %q:\n", o.Substring)
+	for i, name := range names {
+		marker := " "
+		if name == o.Config.CurrentContext {
+			marker = "*"
+		}
+		fmt.Fprintf(o.Out, "%s %d) %s\n", marker, i+1, name)
+	}
+	answer := term.PromptForString(o.In, o.Out, "Select a context [1-%d]: ", len(names))
+	i, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || i < 1 || i > len(names) {
+		return "", fmt.Errorf("invalid selection %q, expected a number between 1 and %d", answer, len(names))
+	}
+	return names[i-1], nil
+}
+
+// contextNames returns the names of the contexts in config, sorted.
+func contextNames(config *clientcmdapi.Config) []string {
+	var names []string
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matchingContexts returns the contexts whose name contains substring, ignoring case. A context
+// named exactly substring is the only match.
+func matchingContexts(config *clientcmdapi.Config, substring string) []string {
+	if _, ok := config.Contexts[substring]; ok {
+		return []string{substring}
+	}
+	var matches []string
+	for _, name := range contextNames(config) {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(substring)) {
+			matches = append(matches, name)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kclientcmd "k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestSwitch(t *testing.T) {
+	tests := []struct {
+		name      string
+		substring string
+		in        string
+		current   string
+		output    string
+		err       string
+	}{
+		{name: "list", current: "dev/api-one:6443/admin", output: "  admin\n* dev/api-one:6443/admin\n  staging/api-one:6443/admin\n  staging/api-two:6443/developer\n"},
+		{name: "unique match", substring: "DEVELOPER", current: "staging/api-two:6443/developer"},
+		{name: "exact match", substring: "admin", current: "admin"},
+		{name: "menu", substring: "staging", in: "2\n", current: "staging/api-two:6443/developer", output: "1) staging/api-one:6443/admin"},
+		{name: "invalid selection", substring: "staging", in: "3\n", err: "invalid selection"},
+		{name: "no match", substring: "prod", err: `no context matches "prod"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "config-switch")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			config := clientcmdapi.NewConfig()
+			for _, name := range []string{"admin", "dev/api-one:6443/admin", "staging/api-one:6443/admin", "staging/api-two:6443/developer"} {
+				config.Contexts[name] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: "user"}
+			}
+			config.CurrentContext = "dev/api-one:6443/admin"
+			path := filepath.Join(dir, "kubeconfig")
+			if err := kclientcmd.WriteToFile(*config, path); err != nil {
+				t.Fatal(err)
+			}
+
+			pathOptions := kclientcmd.NewDefaultPathOptions()
+			pathOptions.LoadingRules.ExplicitPath = path
+			streams, in, out, _ := genericclioptions.NewTestIOStreams()
+			in.WriteString(tc.in)
+			o := NewSwitchOptions(pathOptions, streams)
+			o.Substring = tc.substring
+			o.Config, err = pathOptions.GetStartingConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = o.Run()
+			if len(tc.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), tc.output) {
+				t.Errorf("expected output to contain %q, got:\n%s", tc.output, out.String())
+			}
+
+			written, err := kclientcmd.LoadFromFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if written.CurrentContext != tc.current {
+				t.Errorf("expected current context %q, got %q", tc.current, written.CurrentContext)
+			}
+		})
+	}
+}
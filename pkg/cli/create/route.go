@@ -1,39 +1,126 @@
 package create
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/scheme"
 	"k8s.io/kubectl/pkg/util/templates"
 
+	routev1 "github.com/openshift/api/route/v1"
 	routev1client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+	fileutil "github.com/openshift/oc/pkg/helpers/file"
 )
 
+const (
+	rateLimitConnectionsAnnotation              = "haproxy.router.openshift.io/rate-limit-connections"
+	rateLimitConnectionsConcurrentTCPAnnotation = "haproxy.router.openshift.io/rate-limit-connections.concurrent-tcp"
+	rateLimitConnectionsRateHTTPAnnotation      = "haproxy.router.openshift.io/rate-limit-connections.rate-http"
+	rateLimitConnectionsRateTCPAnnotation       = "haproxy.router.openshift.io/rate-limit-connections.rate-tcp"
+	ipWhitelistAnnotation                       = "haproxy.router.openshift.io/ip_whitelist"
+	hstsHeaderAnnotation                        = "haproxy.router.openshift.io/hsts_header"
+	timeoutAnnotation                           = "haproxy.router.openshift.io/timeout"
+
+	// caCertKey is the default key of the CA certificates in secrets
+	caCertKey = "ca.crt"
+
+	// maxAlternateBackends is the number of backends a route allows besides its primary one.
+	maxAlternateBackends = 3
+	// maxBackendWeight is the largest weight the API accepts for a route backend.
+	maxBackendWeight = 256
+)
+
+// routeAdmissionPollInterval is how often the route is checked while waiting for it to be admitted.
+var routeAdmissionPollInterval = time.Second
+
 var (
 	routeLong = templates.LongDesc(`
 		Expose containers externally via secured routes.
 
 		Three types of secured routes are supported: edge, passthrough, and reencrypt.
 		If you want to create unsecured routes, see "oc expose -h".
+
+		Instead of a subcommand, the --termination flag may select the type of the route,
+		or "none" for an unsecured route. Flags that the selected type does not support
+		are rejected.
+
+		The certificates and keys of edge and reencrypt routes may be read from files, or
+		with the --*-secret flags from keys of secrets in the namespace of the route.
+		Before the route is created, the key is checked against the certificate, the
+		certificate must be currently valid, and it must chain to the CA certificate when one
+		is given. Edge routes may instead obtain their certificate from an ACME server with
+		--acme, see "oc create route edge -h".
+
+		Traffic may be split between services with --backend=SERVICE=WEIGHT. A backend for the
+		service of --service sets its weight, and each other service becomes an alternate
+		backend of the route. The primary backend has a weight of 100 if no backend sets it.
+
+		Instead of a hostname, --subdomain may be set. Every router that admits the route
+		then serves it on the subdomain of its own ingress domain.
+
+		Router behavior can be set without knowing the annotation keys: --hsts-max-age adds a
+		Strict-Transport-Security header, --router-timeout changes the server timeout, and the
+		--rate-limit-connections flags limit the connections of each client.
+
+		A warning is printed when --port matches neither the name nor the target port of a
+		port of the service, since the route would never receive traffic. Pass
+		--validate-port to fail instead.
+
+		To expose the same service more than once, pass --generate-name or end the route
+		name with a dash. The server then appends a random suffix to the name, which is
+		reported once the route is created.
+	`)
+
+	routeExample = templates.Examples(`
+		# Create an edge route named "my-route" that exposes the frontend service
+		oc create route my-route --service=frontend --termination=edge
+
+		# Create an unsecured route for a hostname that exposes the frontend service
+		oc create route --service=frontend --hostname=www.example.com --termination=none
 	`)
 )
 
 // NewCmdCreateRoute is a macro command to create a secured route.
 func NewCmdCreateRoute(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewCreateRouteOptions(streams)
+	subcommandRun := kcmdutil.DefaultSubCommandRun(streams.ErrOut)
 	cmd := &cobra.Command{
-		Use:   "route",
-		Short: "Expose containers externally via secured routes",
-		Long:  routeLong,
-		Run:   kcmdutil.DefaultSubCommandRun(streams.ErrOut),
+		Use:     "route [NAME] --service=SERVICE --termination=edge|passthrough|reencrypt|none",
+		Short:   "Expose containers externally via secured routes",
+		Long:    routeLong,
+		Example: routeExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			if !cmd.Flags().Changed("termination") {
+				subcommandRun(cmd, args)
+				return
+			}
+			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Validate())
+			kcmdutil.CheckErr(o.Run())
+		},
 	}
+	o.AddFlags(cmd)
 
 	cmd.AddCommand(NewCmdCreateEdgeRoute(f, streams))
 	cmd.AddCommand(NewCmdCreatePassthroughRoute(f, streams))
@@ -49,6 +136,9 @@ type CreateRouteSubcommandOptions struct {
 	// Name of resource being created
 	Name        string
 	ServiceName string
+	// GenerateName lets the server pick a unique name for the route that starts with Name, or
+	// with the service name when Name is empty
+	GenerateName bool
 
 	DryRunStrategy kcmdutil.DryRunStrategy
 
@@ -56,6 +146,45 @@ type CreateRouteSubcommandOptions struct {
 	EnforceNamespace bool
 	CreateAnnotation bool
 
+	// RateLimitConnections enables connection rate limiting on the router for the route
+	RateLimitConnections bool
+	// RateLimitConcurrentTCP, RateLimitRateHTTP and RateLimitRateTCP set the limits applied when
+	// rate limiting is enabled, a zero value leaves the router default in place
+	RateLimitConcurrentTCP int
+	RateLimitRateHTTP      int
+	RateLimitRateTCP       int
+
+	// HSTSMaxAge, when set, makes the router send a Strict-Transport-Security header with this
+	// max-age, which HSTSIncludeSubdomains and HSTSPreload extend
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+
+	// RouterTimeout is the server timeout of the route on the router, zero for the router default
+	RouterTimeout time.Duration
+
+	// SourceRanges restricts the source addresses allowed to reach the route to these CIDRs
+	SourceRanges []string
+
+	// HostnameTemplate is a text/template rendered with the route name, namespace and service
+	// to set the hostname of the route
+	HostnameTemplate string
+
+	// Backends are the SERVICE=WEIGHT values of --backend. The weight of --service is set by
+	// its backend, the other services become alternate backends.
+	Backends []string
+	backends []routeBackend
+
+	// Subdomain is the first labels of the hostname of the route, the router of each ingress
+	// controller appends its domain. It cannot be set together with a hostname.
+	Subdomain string
+
+	// Wait is how long to wait for a router to admit the created route, zero to not wait
+	Wait time.Duration
+
+	// ValidatePort fails instead of warning when --port does not match a port of the service
+	ValidatePort bool
+
 	Mapper meta.RESTMapper
 
 	Printer printers.ResourcePrinter
@@ -76,6 +205,71 @@ func NewCreateRouteSubcommandOptions(ioStreams genericclioptions.IOStreams) *Cre
 func (o *CreateRouteSubcommandOptions) AddFlags(cmd *cobra.Command) {
 	o.PrintFlags.AddFlags(cmd)
 	cmdutil.AddApplyAnnotationVarFlags(cmd, &o.CreateAnnotation)
+
+	cmd.Flags().BoolVar(&o.RateLimitConnections, "rate-limit-connections", o.RateLimitConnections, "Enable connection rate limiting for the route on the router. Implied by the other --rate-limit-connections-* flags.")
+	cmd.Flags().IntVar(&o.RateLimitConcurrentTCP, "rate-limit-connections-concurrent-tcp", o.RateLimitConcurrentTCP, "Limit the number of concurrent TCP connections made by the same client IP address.")
+	cmd.Flags().IntVar(&o.RateLimitRateHTTP, "rate-limit-connections-rate-http", o.RateLimitRateHTTP, "Limit the rate at which a client with the same IP address can make HTTP requests.")
+	cmd.Flags().IntVar(&o.RateLimitRateTCP, "rate-limit-connections-rate-tcp", o.RateLimitRateTCP, "Limit the rate at which a client with the same IP address can make TCP connections.")
+	cmd.Flags().DurationVar(&o.HSTSMaxAge, "hsts-max-age", o.HSTSMaxAge, "Make the router send a Strict-Transport-Security header for the route with this max-age, e.g. 8760h. Not supported by passthrough and unsecured routes.")
+	cmd.Flags().BoolVar(&o.HSTSIncludeSubdomains, "hsts-include-subdomains", o.HSTSIncludeSubdomains, "If true, the Strict-Transport-Security header also applies to subdomains of the host. Requires --hsts-max-age.")
+	cmd.Flags().BoolVar(&o.HSTSPreload, "hsts-preload", o.HSTSPreload, "If true, the Strict-Transport-Security header allows the host to be preloaded by browsers. Requires --hsts-max-age.")
+	cmd.Flags().DurationVar(&o.RouterTimeout, "router-timeout", o.RouterTimeout, "Set how long the router waits for a response from the service of the route, e.g. 90s. Zero keeps the router default.")
+	cmd.Flags().StringSliceVar(&o.SourceRanges, "source-range", o.SourceRanges, "Only allow connections to the route from this source CIDR, e.g. 192.168.1.0/24. May be repeated.")
+	cmd.Flags().StringVar(&o.HostnameTemplate, "hostname-template", o.HostnameTemplate, "Set the hostname of the new route from a template rendered with the route's {{.Name}}, {{.Namespace}} and {{.Service}}. {{.Name}} may not be used with --generate-name. Mutually exclusive with --hostname.")
+	cmd.Flags().StringSliceVar(&o.Backends, "backend", o.Backends, "Send traffic to a service with a weight between 0 and 256, as SERVICE=WEIGHT. Sets the weight of --service, or adds an alternate backend for another service. May be repeated.")
+	cmd.Flags().StringVar(&o.Subdomain, "subdomain", o.Subdomain, "Set the subdomain of the new route, to which each router that admits it appends its ingress domain. Mutually exclusive with --hostname and --hostname-template.")
+	cmd.Flags().DurationVar(&o.Wait, "wait", o.Wait, "Wait up to this long after creating the route until a router admits it, failing if it is rejected. The duration must be given as --wait=DURATION, --wait alone waits up to 2m. Ignored with --dry-run.")
+	cmd.Flags().Lookup("wait").NoOptDefVal = "2m"
+	cmd.Flags().BoolVar(&o.ValidatePort, "validate-port", o.ValidatePort, "If true, fail when --port matches neither the name nor the target port of a port of the service instead of printing a warning. Ignored with --dry-run.")
+	cmd.Flags().BoolVar(&o.GenerateName, "generate-name", o.GenerateName, "If true, the server adds a random suffix to the route name, or to the service name when NAME is omitted. Implied by a NAME that ends with '-'.")
+}
+
+// addRouteFlags registers the flags that every kind of route accepts.
+func addRouteFlags(cmd *cobra.Command, hostname, port, service, wildcardPolicy *string) {
+	cmd.Flags().StringVar(hostname, "hostname", *hostname, "Set a hostname for the new route")
+	cmd.Flags().StringVar(port, "port", *port, "Name of the service port or number of the container port the route will route traffic to")
+	cmd.Flags().StringVar(service, "service", *service, "Name of the service that the new route is exposing")
+	cmd.Flags().StringVar(wildcardPolicy, "wildcard-policy", *wildcardPolicy, "Sets the WilcardPolicy for the hostname, the default is \"None\". valid values are \"None\" and \"Subdomain\"")
+}
+
+// addInsecurePolicyFlags registers --insecure-policy with the allowed policies, and
+// --default-insecure-redirect unless defaultRedirect is nil.
+func addInsecurePolicyFlags(cmd *cobra.Command, policy *string, defaultRedirect *bool, allowed ...routev1.InsecureEdgeTerminationPolicyType) {
+	values := make([]string, 0, len(allowed))
+	for _, p := range allowed {
+		values = append(values, strconv.Quote(string(p)))
+	}
+	cmd.Flags().StringVar(policy, "insecure-policy", *policy, fmt.Sprintf("Set an insecure policy for the new route, valid values are %s", strings.Join(values, ", ")))
+	if defaultRedirect != nil {
+		cmd.Flags().BoolVar(defaultRedirect, "default-insecure-redirect", *defaultRedirect, "If true and --hostname is set without --insecure-policy, redirect insecure requests to HTTPS instead of rejecting them.")
+	}
+}
+
+// addPathFlag registers --path for the routes that are not passthrough routes.
+func addPathFlag(cmd *cobra.Command, path *string) {
+	cmd.Flags().StringVar(path, "path", *path, "Path that the router watches to route traffic to the service.")
+}
+
+// addTLSFlags registers the flags that read the certificate, key and CA certificate of edge and
+// reencrypt routes from files or secrets.
+func addTLSFlags(cmd *cobra.Command, cert, key, caCert, certSecret, keySecret, caSecret *string) {
+	cmd.Flags().StringVar(cert, "cert", *cert, "Path to a certificate file.")
+	cmd.MarkFlagFilename("cert")
+	cmd.Flags().StringVar(key, "key", *key, "Path to a key file.")
+	cmd.MarkFlagFilename("key")
+	cmd.Flags().StringVar(caCert, "ca-cert", *caCert, "Path to a CA certificate file.")
+	cmd.MarkFlagFilename("ca-cert")
+	cmd.Flags().StringVar(certSecret, "cert-secret", *certSecret, "Read the certificate from this secret in the namespace of the route, as NAME or NAME:KEY. KEY defaults to tls.crt.")
+	cmd.Flags().StringVar(keySecret, "key-secret", *keySecret, "Read the key from this secret in the namespace of the route, as NAME or NAME:KEY. KEY defaults to tls.key.")
+	cmd.Flags().StringVar(caSecret, "ca-secret", *caSecret, "Read the CA certificate from this secret in the namespace of the route, as NAME or NAME:KEY. KEY defaults to ca.crt.")
+}
+
+// addDestinationCAFlags registers the flags that read the CA certificate reencrypt routes verify
+// the service with from a file or a secret.
+func addDestinationCAFlags(cmd *cobra.Command, destCACert, destCASecret *string) {
+	cmd.Flags().StringVar(destCACert, "dest-ca-cert", *destCACert, "Path to a CA certificate file, used for securing the connection from the router to the destination. Defaults to the Service CA.")
+	cmd.MarkFlagFilename("dest-ca-cert")
+	cmd.Flags().StringVar(destCASecret, "dest-ca-secret", *destCASecret, "Read the destination CA certificate from this secret in the namespace of the route, as NAME or NAME:KEY. KEY defaults to ca.crt.")
 }
 
 func (o *CreateRouteSubcommandOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
@@ -84,6 +278,10 @@ func (o *CreateRouteSubcommandOptions) Complete(f kcmdutil.Factory, cmd *cobra.C
 	if err != nil {
 		return err
 	}
+	if strings.HasSuffix(o.Name, "-") {
+		o.GenerateName = true
+		o.Name = strings.TrimSuffix(o.Name, "-")
+	}
 
 	clientConfig, err := f.ToRESTConfig()
 	if err != nil {
@@ -124,6 +322,411 @@ func (o *CreateRouteSubcommandOptions) Complete(f kcmdutil.Factory, cmd *cobra.C
 	return nil
 }
 
+func (o *CreateRouteSubcommandOptions) Validate() error {
+	if o.RateLimitConcurrentTCP < 0 {
+		return fmt.Errorf("--rate-limit-connections-concurrent-tcp must not be negative, got %d", o.RateLimitConcurrentTCP)
+	}
+	if o.RateLimitRateHTTP < 0 {
+		return fmt.Errorf("--rate-limit-connections-rate-http must not be negative, got %d", o.RateLimitRateHTTP)
+	}
+	if o.RateLimitRateTCP < 0 {
+		return fmt.Errorf("--rate-limit-connections-rate-tcp must not be negative, got %d", o.RateLimitRateTCP)
+	}
+	if o.Wait < 0 {
+		return fmt.Errorf("--wait must not be negative, got %s", o.Wait)
+	}
+	if o.HSTSMaxAge < 0 {
+		return fmt.Errorf("--hsts-max-age must not be negative, got %s", o.HSTSMaxAge)
+	}
+	if o.HSTSMaxAge == 0 && (o.HSTSIncludeSubdomains || o.HSTSPreload) {
+		return fmt.Errorf("--hsts-include-subdomains and --hsts-preload require --hsts-max-age")
+	}
+	if o.RouterTimeout < 0 {
+		return fmt.Errorf("--router-timeout must not be negative, got %s", o.RouterTimeout)
+	}
+	var err error
+	if o.backends, err = parseRouteBackends(o.Backends); err != nil {
+		return err
+	}
+	if len(o.Subdomain) > 0 {
+		if errs := validation.IsDNS1123Subdomain(o.Subdomain); len(errs) > 0 {
+			return fmt.Errorf("--subdomain %q is invalid: %s", o.Subdomain, strings.Join(errs, ", "))
+		}
+	}
+	for _, cidr := range o.SourceRanges {
+		if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
+			return fmt.Errorf("--source-range %q is not a valid CIDR, e.g. 192.168.1.0/24", cidr)
+		}
+	}
+	return nil
+}
+
+// setSourceRangeAnnotation sets the router annotation that restricts the source addresses
+// allowed to reach the route from the --source-range flags.
+func (o *CreateRouteSubcommandOptions) setSourceRangeAnnotation(route *routev1.Route) {
+	if len(o.SourceRanges) == 0 {
+		return
+	}
+	ranges := make([]string, 0, len(o.SourceRanges))
+	for _, cidr := range o.SourceRanges {
+		ranges = append(ranges, strings.TrimSpace(cidr))
+	}
+	if route.Annotations == nil {
+		route.Annotations = make(map[string]string)
+	}
+	route.Annotations[ipWhitelistAnnotation] = strings.Join(ranges, " ")
+}
+
+// setGenerateName replaces the name of the route with a prefix the server completes with a
+// random suffix when --generate-name is set.
+func (o *CreateRouteSubcommandOptions) setGenerateName(route *routev1.Route) {
+	if !o.GenerateName {
+		return
+	}
+	route.GenerateName = route.Name + "-"
+	route.Name = ""
+}
+
+// setHSTSAnnotation sets the router annotation that adds a Strict-Transport-Security header to
+// the responses of the route from the --hsts-* flags.
+func (o *CreateRouteSubcommandOptions) setHSTSAnnotation(route *routev1.Route) {
+	if o.HSTSMaxAge == 0 {
+		return
+	}
+	header := fmt.Sprintf("max-age=%d", int64(o.HSTSMaxAge/time.Second))
+	if o.HSTSIncludeSubdomains {
+		header += ";includeSubDomains"
+	}
+	if o.HSTSPreload {
+		header += ";preload"
+	}
+	if route.Annotations == nil {
+		route.Annotations = make(map[string]string)
+	}
+	route.Annotations[hstsHeaderAnnotation] = header
+}
+
+// setTimeoutAnnotation sets the router annotation for the server timeout of the route from
+// --router-timeout, in whole seconds when possible and in milliseconds otherwise.
+func (o *CreateRouteSubcommandOptions) setTimeoutAnnotation(route *routev1.Route) {
+	if o.RouterTimeout == 0 {
+		return
+	}
+	timeout := fmt.Sprintf("%dms", o.RouterTimeout.Milliseconds())
+	if o.RouterTimeout%time.Second == 0 {
+		timeout = fmt.Sprintf("%ds", int64(o.RouterTimeout/time.Second))
+	}
+	if route.Annotations == nil {
+		route.Annotations = make(map[string]string)
+	}
+	route.Annotations[timeoutAnnotation] = timeout
+}
+
+// setRateLimitAnnotations sets the router annotations that configure connection rate limiting
+// for the route from the --rate-limit-connections flags.
+func (o *CreateRouteSubcommandOptions) setRateLimitAnnotations(route *routev1.Route) {
+	limits := map[string]int{
+		rateLimitConnectionsConcurrentTCPAnnotation: o.RateLimitConcurrentTCP,
+		rateLimitConnectionsRateHTTPAnnotation:      o.RateLimitRateHTTP,
+		rateLimitConnectionsRateTCPAnnotation:       o.RateLimitRateTCP,
+	}
+	enabled := o.RateLimitConnections
+	for _, value := range limits {
+		if value > 0 {
+			enabled = true
+		}
+	}
+	if !enabled {
+		return
+	}
+
+	if route.Annotations == nil {
+		route.Annotations = make(map[string]string)
+	}
+	route.Annotations[rateLimitConnectionsAnnotation] = "true"
+	for annotation, value := range limits {
+		if value > 0 {
+			route.Annotations[annotation] = strconv.Itoa(value)
+		}
+	}
+}
+
+// checkServicePort warns, or fails with --validate-port, when port is neither the name nor the
+// target port of a port of the service, since such a route never receives traffic. The check is
+// skipped on a dry run or when the service does not exist.
+func (o *CreateRouteSubcommandOptions) checkServicePort(serviceName, port string) error {
+	if len(port) == 0 || o.DryRunStrategy != kcmdutil.DryRunNone {
+		return nil
+	}
+	svc, err := o.CoreClient.Services(o.Namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		fmt.Fprintf(o.ErrOut, "warning: unable to check --port against service %q: %v\n", serviceName, err)
+		return nil
+	}
+	available := make([]string, 0, len(svc.Spec.Ports))
+	for _, p := range svc.Spec.Ports {
+		// the target port defaults to the port of the service
+		targetPort := p.TargetPort.String()
+		if p.TargetPort.Type == intstr.Int && p.TargetPort.IntVal == 0 {
+			targetPort = strconv.Itoa(int(p.Port))
+		}
+		if port == p.Name || port == targetPort {
+			return nil
+		}
+		description := fmt.Sprintf("%s/%s", targetPort, p.Protocol)
+		if len(p.Name) > 0 {
+			description = fmt.Sprintf("%s (%s)", p.Name, description)
+		}
+		available = append(available, description)
+	}
+	msg := fmt.Sprintf("port %q does not match the name or target port of any port of service %q, available ports are: %s", port, serviceName, strings.Join(available, ", "))
+	if len(available) == 0 {
+		msg = fmt.Sprintf("port %q does not match any port of service %q, the service has no ports", port, serviceName)
+	}
+	if o.ValidatePort {
+		return fmt.Errorf("%s", msg)
+	}
+	fmt.Fprintf(o.ErrOut, "warning: %s\n", msg)
+	return nil
+}
+
+// waitForAdmission polls the created route until a router admits or rejects it, or --wait
+// elapses, and reports the admitting router and host. Errors reading the route are retried
+// until --wait elapses. It does nothing on a dry run.
+func (o *CreateRouteSubcommandOptions) waitForAdmission(route *routev1.Route) error {
+	if o.Wait == 0 || o.DryRunStrategy != kcmdutil.DryRunNone {
+		return nil
+	}
+	var ingress *routev1.RouteIngress
+	var lastErr error
+	err := wait.PollImmediate(routeAdmissionPollInterval, o.Wait, func() (bool, error) {
+		latest, err := o.Client.Routes(o.Namespace).Get(context.TODO(), route.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.V(4).Infof("Unable to get route %q while waiting for it to be admitted: %v", route.Name, err)
+			lastErr = err
+			return false, nil
+		}
+		ingress, err = admittedIngress(latest)
+		return ingress != nil, err
+	})
+	if err == wait.ErrWaitTimeout {
+		if lastErr != nil {
+			return fmt.Errorf("timed out after %s waiting for route %q to be admitted by a router: %v", o.Wait, route.Name, lastErr)
+		}
+		return fmt.Errorf("timed out after %s waiting for route %q to be admitted by a router", o.Wait, route.Name)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.ErrOut, "route/%s admitted by router %q with host %s\n", route.Name, ingress.RouterName, ingress.Host)
+	return nil
+}
+
+// admittedIngress returns the first ingress of route that a router admitted. It returns an error
+// when no router admitted the route and at least one rejected it, and nil when no router has
+// decided yet.
+func admittedIngress(route *routev1.Route) (*routev1.RouteIngress, error) {
+	var rejections []string
+	for i := range route.Status.Ingress {
+		ingress := &route.Status.Ingress[i]
+		for _, condition := range ingress.Conditions {
+			if condition.Type != routev1.RouteAdmitted {
+				continue
+			}
+			switch condition.Status {
+			case corev1.ConditionTrue:
+				return ingress, nil
+			case corev1.ConditionFalse:
+				rejections = append(rejections, fmt.Sprintf("router %q: %s: %s", ingress.RouterName, condition.Reason, condition.Message))
+			}
+		}
+	}
+	if len(rejections) > 0 {
+		return nil, fmt.Errorf("route %q was rejected by %s", route.Name, strings.Join(rejections, ", "))
+	}
+	return nil, nil
+}
+
+// routeBackend is a service and its weight from --backend.
+type routeBackend struct {
+	Service string
+	Weight  int32
+}
+
+// parseRouteBackends parses the SERVICE=WEIGHT values of --backend.
+func parseRouteBackends(values []string) ([]routeBackend, error) {
+	var backends []routeBackend
+	seen := map[string]bool{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("--backend %q must be SERVICE=WEIGHT", value)
+		}
+		if errs := validation.IsDNS1035Label(parts[0]); len(errs) > 0 {
+			return nil, fmt.Errorf("--backend %q is not a valid service name: %s", parts[0], strings.Join(errs, ", "))
+		}
+		weight, err := strconv.Atoi(parts[1])
+		if err != nil || weight < 0 || weight > maxBackendWeight {
+			return nil, fmt.Errorf("--backend %q must have a weight between 0 and %d", value, maxBackendWeight)
+		}
+		if seen[parts[0]] {
+			return nil, fmt.Errorf("--backend %q may only be set once", parts[0])
+		}
+		seen[parts[0]] = true
+		backends = append(backends, routeBackend{Service: parts[0], Weight: int32(weight)})
+	}
+	return backends, nil
+}
+
+// setBackends sets the weight of the primary backend of the route and its alternate backends from
+// --backend. It fails when there are too many backends or none of them would receive traffic.
+func (o *CreateRouteSubcommandOptions) setBackends(route *routev1.Route) error {
+	if len(o.backends) == 0 {
+		return nil
+	}
+	// the API defaults the weight of the primary backend to 100
+	total := int32(100)
+	var alternates []routev1.RouteTargetReference
+	for _, backend := range o.backends {
+		weight := backend.Weight
+		if backend.Service == route.Spec.To.Name {
+			total += weight - 100
+			route.Spec.To.Weight = &weight
+			continue
+		}
+		total += weight
+		alternates = append(alternates, routev1.RouteTargetReference{Kind: "Service", Name: backend.Service, Weight: &weight})
+	}
+	if len(alternates) > maxAlternateBackends {
+		return fmt.Errorf("a route may have at most %d backends besides --service, got %d", maxAlternateBackends, len(alternates))
+	}
+	if total == 0 {
+		return fmt.Errorf("the weights of all backends are zero, the route would not send traffic to any service")
+	}
+	route.Spec.AlternateBackends = alternates
+	return nil
+}
+
+// loadTLSData returns the contents of file, or of the key of the secret referenced by secretRef as
+// NAME or NAME:KEY, where KEY defaults to defaultKey. The flag names are used in errors.
+func (o *CreateRouteSubcommandOptions) loadTLSData(fileFlag, file, secretFlag, secretRef, defaultKey string) (string, error) {
+	if len(secretRef) == 0 {
+		data, err := fileutil.LoadData(file)
+		return string(data), err
+	}
+	if len(file) > 0 {
+		return "", fmt.Errorf("--%s and --%s are mutually exclusive", fileFlag, secretFlag)
+	}
+	name, key := secretRef, defaultKey
+	if i := strings.Index(secretRef, ":"); i != -1 {
+		name, key = secretRef[:i], secretRef[i+1:]
+	}
+	if len(name) == 0 || len(key) == 0 {
+		return "", fmt.Errorf("--%s must be NAME or NAME:KEY, got %q", secretFlag, secretRef)
+	}
+	secret, err := o.CoreClient.Secrets(o.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to read --%s: %v", secretFlag, err)
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		keys := make([]string, 0, len(secret.Data))
+		for k := range secret.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return "", fmt.Errorf("secret %q has no key %q for --%s, available keys are: %s", name, key, secretFlag, strings.Join(keys, ", "))
+	}
+	return string(data), nil
+}
+
+// hostnameTemplateData is the data --hostname-template is rendered with.
+type hostnameTemplateData struct {
+	Name      string
+	Namespace string
+	Service   string
+}
+
+// completeHostname returns hostname, or the hostname rendered from --hostname-template for the
+// route that exposes service when the template is set.
+func (o *CreateRouteSubcommandOptions) completeHostname(hostname, service string) (string, error) {
+	if len(o.Subdomain) > 0 {
+		switch {
+		case len(hostname) > 0:
+			return "", fmt.Errorf("--hostname and --subdomain are mutually exclusive")
+		case len(o.HostnameTemplate) > 0:
+			return "", fmt.Errorf("--hostname-template and --subdomain are mutually exclusive")
+		}
+	}
+	if len(o.HostnameTemplate) == 0 {
+		return hostname, nil
+	}
+	if len(hostname) > 0 {
+		return "", fmt.Errorf("--hostname and --hostname-template are mutually exclusive")
+	}
+	serviceName, err := resolveServiceName(o.Mapper, service)
+	if err != nil {
+		return "", err
+	}
+	data := hostnameTemplateData{Name: o.Name, Namespace: o.Namespace, Service: serviceName}
+	if len(data.Name) == 0 {
+		data.Name = serviceName
+	}
+	hostname, err = renderHostname(o.HostnameTemplate, data)
+	if err != nil || !o.GenerateName {
+		return hostname, err
+	}
+	// the server only completes the name once the route is created, so a hostname rendered from
+	// the name would be the same for every generated route
+	other := data
+	other.Name += "-x"
+	if otherHostname, err := renderHostname(o.HostnameTemplate, other); err != nil || otherHostname != hostname {
+		return "", fmt.Errorf("--hostname-template may not use {{.Name}} with --generate-name, the generated name is not known when the hostname is set")
+	}
+	return hostname, nil
+}
+
+// renderHostname executes a hostname template and checks that the result is a valid DNS-1123
+// subdomain.
+func renderHostname(text string, data hostnameTemplateData) (string, error) {
+	tmpl, err := template.New("hostname").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("--hostname-template is not a valid template: %v", err)
+	}
+	out := &strings.Builder{}
+	if err := tmpl.Execute(out, data); err != nil {
+		return "", fmt.Errorf("unable to render --hostname-template: %v", err)
+	}
+	hostname := out.String()
+	if errs := validation.IsDNS1123Subdomain(hostname); len(errs) > 0 {
+		return "", fmt.Errorf("--hostname-template rendered an invalid hostname %q: %s", hostname, strings.Join(errs, ", "))
+	}
+	return hostname, nil
+}
+
+// completeInsecurePolicy checks that policy is one of the allowed insecure edge termination
+// policies. When policy is empty and defaultRedirect is set, a route with a hostname redirects
+// insecure requests so that they do not fail once the route is exposed.
+func completeInsecurePolicy(policy, hostname string, defaultRedirect bool, allowed ...routev1.InsecureEdgeTerminationPolicyType) (string, error) {
+	if len(policy) == 0 {
+		if defaultRedirect && len(hostname) > 0 {
+			return string(routev1.InsecureEdgeTerminationPolicyRedirect), nil
+		}
+		return "", nil
+	}
+	valid := make([]string, 0, len(allowed))
+	for _, p := range allowed {
+		if policy == string(p) {
+			return policy, nil
+		}
+		valid = append(valid, string(p))
+	}
+	return "", fmt.Errorf("invalid --insecure-policy %q, valid values are %s", policy, strings.Join(valid, ", "))
+}
+
 func resolveRouteName(args []string) (string, error) {
 	switch len(args) {
 	case 0:
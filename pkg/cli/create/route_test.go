@@ -0,0 +1,750 @@
+package create
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	routev1 "github.com/openshift/api/route/v1"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+)
+
+func TestSetRateLimitAnnotations(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  CreateRouteSubcommandOptions
+		expected map[string]string
+	}{
+		{
+			name: "no flags",
+		},
+		{
+			name:    "rate-limit-connections",
+			options: CreateRouteSubcommandOptions{RateLimitConnections: true},
+			expected: map[string]string{
+				rateLimitConnectionsAnnotation: "true",
+			},
+		},
+		{
+			name:    "rate-limit-connections-concurrent-tcp",
+			options: CreateRouteSubcommandOptions{RateLimitConcurrentTCP: 10},
+			expected: map[string]string{
+				rateLimitConnectionsAnnotation:              "true",
+				rateLimitConnectionsConcurrentTCPAnnotation: "10",
+			},
+		},
+		{
+			name:    "rate-limit-connections-rate-http",
+			options: CreateRouteSubcommandOptions{RateLimitRateHTTP: 100},
+			expected: map[string]string{
+				rateLimitConnectionsAnnotation:         "true",
+				rateLimitConnectionsRateHTTPAnnotation: "100",
+			},
+		},
+		{
+			name:    "rate-limit-connections-rate-tcp",
+			options: CreateRouteSubcommandOptions{RateLimitRateTCP: 20},
+			expected: map[string]string{
+				rateLimitConnectionsAnnotation:        "true",
+				rateLimitConnectionsRateTCPAnnotation: "20",
+			},
+		},
+		{
+			name: "all flags",
+			options: CreateRouteSubcommandOptions{
+				RateLimitConnections:   true,
+				RateLimitConcurrentTCP: 10,
+				RateLimitRateHTTP:      100,
+				RateLimitRateTCP:       20,
+			},
+			expected: map[string]string{
+				rateLimitConnectionsAnnotation:              "true",
+				rateLimitConnectionsConcurrentTCPAnnotation: "10",
+				rateLimitConnectionsRateHTTPAnnotation:      "100",
+				rateLimitConnectionsRateTCPAnnotation:       "20",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := &routev1.Route{}
+			test.options.setRateLimitAnnotations(route)
+			if !reflect.DeepEqual(route.Annotations, test.expected) {
+				t.Errorf("expected annotations %v, got %v", test.expected, route.Annotations)
+			}
+		})
+	}
+}
+
+func TestCreateRouteSubcommandOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options CreateRouteSubcommandOptions
+		wantErr bool
+	}{
+		{name: "no limits"},
+		{name: "positive limits", options: CreateRouteSubcommandOptions{RateLimitConcurrentTCP: 1, RateLimitRateHTTP: 1, RateLimitRateTCP: 1}},
+		{name: "negative concurrent tcp", options: CreateRouteSubcommandOptions{RateLimitConcurrentTCP: -1}, wantErr: true},
+		{name: "negative rate http", options: CreateRouteSubcommandOptions{RateLimitRateHTTP: -1}, wantErr: true},
+		{name: "negative rate tcp", options: CreateRouteSubcommandOptions{RateLimitRateTCP: -1}, wantErr: true},
+		{name: "subdomain", options: CreateRouteSubcommandOptions{Subdomain: "frontend.shop"}},
+		{name: "hsts", options: CreateRouteSubcommandOptions{HSTSMaxAge: time.Hour, HSTSPreload: true}},
+		{name: "hsts preload without max age", options: CreateRouteSubcommandOptions{HSTSPreload: true}, wantErr: true},
+		{name: "negative timeout", options: CreateRouteSubcommandOptions{RouterTimeout: -time.Second}, wantErr: true},
+		{name: "invalid subdomain", options: CreateRouteSubcommandOptions{Subdomain: "Frontend_"}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.options.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSourceRanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		ranges      []string
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			name: "no source ranges",
+		},
+		{
+			name:     "single range",
+			ranges:   []string{"192.168.1.0/24"},
+			expected: map[string]string{ipWhitelistAnnotation: "192.168.1.0/24"},
+		},
+		{
+			name:     "multiple ranges",
+			ranges:   []string{"10.0.0.0/8", " 192.168.1.0/24", "2001:db8::/32"},
+			expected: map[string]string{ipWhitelistAnnotation: "10.0.0.0/8 192.168.1.0/24 2001:db8::/32"},
+		},
+		{
+			name:        "address without prefix",
+			ranges:      []string{"10.0.0.0/8", "192.168.1.1"},
+			expectedErr: `--source-range "192.168.1.1" is not a valid CIDR, e.g. 192.168.1.0/24`,
+		},
+		{
+			name:        "invalid prefix",
+			ranges:      []string{"10.0.0.0/33"},
+			expectedErr: `--source-range "10.0.0.0/33" is not a valid CIDR, e.g. 192.168.1.0/24`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := CreateRouteSubcommandOptions{SourceRanges: test.ranges}
+			err := o.Validate()
+			if len(test.expectedErr) > 0 {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			route := &routev1.Route{}
+			o.setSourceRangeAnnotation(route)
+			if !reflect.DeepEqual(route.Annotations, test.expected) {
+				t.Errorf("expected annotations %v, got %v", test.expected, route.Annotations)
+			}
+		})
+	}
+}
+
+func TestCompleteHostname(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  CreateRouteSubcommandOptions
+		hostname string
+		expected string
+		err      string
+	}{
+		{name: "no template", hostname: "www.example.com", expected: "www.example.com"},
+		{
+			name:     "name and namespace",
+			options:  CreateRouteSubcommandOptions{Name: "my-route", Namespace: "test", HostnameTemplate: "{{.Name}}-{{.Namespace}}.apps.example.com"},
+			expected: "my-route-test.apps.example.com",
+		},
+		{
+			name:     "name defaults to the service",
+			options:  CreateRouteSubcommandOptions{Namespace: "test", HostnameTemplate: "{{.Name}}.{{.Service}}.apps.example.com"},
+			expected: "frontend.frontend.apps.example.com",
+		},
+		{
+			name:     "hostname and template",
+			options:  CreateRouteSubcommandOptions{HostnameTemplate: "{{.Name}}.apps.example.com"},
+			hostname: "www.example.com",
+			err:      "mutually exclusive",
+		},
+		{
+			name:     "hostname and subdomain",
+			options:  CreateRouteSubcommandOptions{Subdomain: "frontend"},
+			hostname: "www.example.com",
+			err:      "--hostname and --subdomain are mutually exclusive",
+		},
+		{
+			name:    "template and subdomain",
+			options: CreateRouteSubcommandOptions{Subdomain: "frontend", HostnameTemplate: "{{.Name}}.apps.example.com"},
+			err:     "--hostname-template and --subdomain are mutually exclusive",
+		},
+		{
+			name:    "subdomain",
+			options: CreateRouteSubcommandOptions{Subdomain: "frontend"},
+		},
+		{
+			name:    "invalid template",
+			options: CreateRouteSubcommandOptions{HostnameTemplate: "{{.Name"},
+			err:     "not a valid template",
+		},
+		{
+			name:    "unknown field",
+			options: CreateRouteSubcommandOptions{HostnameTemplate: "{{.Host}}.apps.example.com"},
+			err:     "unable to render",
+		},
+		{
+			name:    "generated name with name in template",
+			options: CreateRouteSubcommandOptions{Name: "frontend", GenerateName: true, HostnameTemplate: "{{.Name}}.apps.example.com"},
+			err:     "--hostname-template may not use {{.Name}} with --generate-name",
+		},
+		{
+			name:     "generated name with service in template",
+			options:  CreateRouteSubcommandOptions{Name: "frontend", Namespace: "test", GenerateName: true, HostnameTemplate: "{{.Service}}-{{.Namespace}}.apps.example.com"},
+			expected: "frontend-test.apps.example.com",
+		},
+		{
+			name:    "invalid hostname",
+			options: CreateRouteSubcommandOptions{Name: "My_Route", HostnameTemplate: "{{.Name}}.apps.example.com"},
+			err:     "invalid hostname",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.options.Mapper = meta.NewDefaultRESTMapper(nil)
+			hostname, err := test.options.completeHostname(test.hostname, "frontend")
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hostname != test.expected {
+				t.Errorf("expected hostname %q, got %q", test.expected, hostname)
+			}
+		})
+	}
+}
+
+func TestCompleteInsecurePolicy(t *testing.T) {
+	edgePolicies := []routev1.InsecureEdgeTerminationPolicyType{routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyAllow, routev1.InsecureEdgeTerminationPolicyRedirect}
+	passthroughPolicies := []routev1.InsecureEdgeTerminationPolicyType{routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyRedirect}
+	tests := []struct {
+		name            string
+		policy          string
+		hostname        string
+		defaultRedirect bool
+		allowed         []routev1.InsecureEdgeTerminationPolicyType
+		expected        string
+		err             string
+	}{
+		{name: "valid policy", policy: "Allow", allowed: edgePolicies, expected: "Allow"},
+		{name: "explicit policy is kept", policy: "None", hostname: "www.example.com", defaultRedirect: true, allowed: edgePolicies, expected: "None"},
+		{name: "invalid policy", policy: "redirect", allowed: edgePolicies, err: `invalid --insecure-policy "redirect", valid values are None, Allow, Redirect`},
+		{name: "policy not allowed", policy: "Allow", allowed: passthroughPolicies, err: "valid values are None, Redirect"},
+		{name: "redirect with hostname", hostname: "www.example.com", defaultRedirect: true, allowed: edgePolicies, expected: "Redirect"},
+		{name: "no hostname", defaultRedirect: true, allowed: edgePolicies},
+		{name: "default disabled", hostname: "www.example.com", allowed: edgePolicies},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := completeInsecurePolicy(test.policy, test.hostname, test.defaultRedirect, test.allowed...)
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if policy != test.expected {
+				t.Errorf("expected policy %q, got %q", test.expected, policy)
+			}
+		})
+	}
+}
+
+func TestSetGenerateName(t *testing.T) {
+	tests := []struct {
+		name                 string
+		generateName         bool
+		expectedName         string
+		expectedGenerateName string
+	}{
+		{name: "named route", expectedName: "frontend"},
+		{name: "generated name", generateName: true, expectedGenerateName: "frontend-"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := &routev1.Route{ObjectMeta: metav1.ObjectMeta{Name: "frontend"}}
+			o := &CreateRouteSubcommandOptions{GenerateName: test.generateName}
+			o.setGenerateName(route)
+			if route.Name != test.expectedName || route.GenerateName != test.expectedGenerateName {
+				t.Errorf("expected name %q and generateName %q, got %q and %q", test.expectedName, test.expectedGenerateName, route.Name, route.GenerateName)
+			}
+		})
+	}
+}
+
+func TestWaitForAdmission(t *testing.T) {
+	defer func(interval time.Duration) { routeAdmissionPollInterval = interval }(routeAdmissionPollInterval)
+	routeAdmissionPollInterval = time.Millisecond
+	admitted := func(router string, status corev1.ConditionStatus, reason string) routev1.RouteIngress {
+		return routev1.RouteIngress{
+			Host:       "frontend-test.apps.example.com",
+			RouterName: router,
+			Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: status, Reason: reason}},
+		}
+	}
+	tests := []struct {
+		name      string
+		ingress   []routev1.RouteIngress
+		getErrors int
+		dryRun    kcmdutil.DryRunStrategy
+		out       string
+		err       string
+	}{
+		{name: "admitted", ingress: []routev1.RouteIngress{admitted("default", corev1.ConditionTrue, "")}, out: `route/frontend admitted by router "default" with host frontend-test.apps.example.com`},
+		{name: "admitted by one router", ingress: []routev1.RouteIngress{admitted("sharded", corev1.ConditionFalse, "HostAlreadyClaimed"), admitted("default", corev1.ConditionTrue, "")}, out: `admitted by router "default"`},
+		{name: "rejected", ingress: []routev1.RouteIngress{admitted("default", corev1.ConditionFalse, "HostAlreadyClaimed")}, err: `rejected by router "default": HostAlreadyClaimed`},
+		{name: "not admitted", err: "timed out"},
+		{name: "admitted after transient errors", ingress: []routev1.RouteIngress{admitted("default", corev1.ConditionTrue, "")}, getErrors: 2, out: `admitted by router "default"`},
+		{name: "errors until timeout", ingress: []routev1.RouteIngress{admitted("default", corev1.ConditionTrue, "")}, getErrors: -1, err: "timed out after 50ms waiting for route \"frontend\" to be admitted by a router: connection refused"},
+		{name: "dry run", dryRun: kcmdutil.DryRunClient},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "test"},
+				Status:     routev1.RouteStatus{Ingress: test.ingress},
+			}
+			client := routefake.NewSimpleClientset(route)
+			getErrors := test.getErrors
+			client.PrependReactor("get", "routes", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if getErrors == 0 {
+					return false, nil, nil
+				}
+				getErrors--
+				return true, nil, errors.New("connection refused")
+			})
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := &CreateRouteSubcommandOptions{
+				Namespace:      "test",
+				Wait:           50 * time.Millisecond,
+				DryRunStrategy: test.dryRun,
+				Client:         client.RouteV1(),
+				IOStreams:      streams,
+			}
+			err := o.waitForAdmission(route)
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(errOut.String(), test.out) || (len(test.out) == 0 && errOut.Len() > 0) {
+				t.Errorf("unexpected output: %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestCheckServicePort(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "test"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "web", Port: 80, TargetPort: intstr.FromInt(8080), Protocol: corev1.ProtocolTCP},
+				{Port: 8443, TargetPort: intstr.FromString("https"), Protocol: corev1.ProtocolTCP},
+				{Port: 9000, Protocol: corev1.ProtocolUDP},
+			},
+		},
+	}
+	tests := []struct {
+		name     string
+		service  string
+		port     string
+		validate bool
+		dryRun   kcmdutil.DryRunStrategy
+		getErr   error
+		warning  string
+		err      string
+	}{
+		{name: "port name", service: "frontend", port: "web"},
+		{name: "target port number", service: "frontend", port: "8080"},
+		{name: "target port name", service: "frontend", port: "https"},
+		{name: "default target port", service: "frontend", port: "9000"},
+		{name: "service port number", service: "frontend", port: "80", warning: `port "80" does not match the name or target port of any port of service "frontend", available ports are: web (8080/TCP), https/TCP, 9000/UDP`},
+		{name: "missing", service: "frontend", port: "9090", warning: `port "9090" does not match the name or target port of any port of service "frontend"`},
+		{name: "missing with validation", service: "frontend", port: "9090", validate: true, err: `port "9090" does not match the name or target port of any port of service "frontend"`},
+		{name: "dry run", service: "frontend", port: "9090", validate: true, dryRun: kcmdutil.DryRunClient},
+		{name: "no service", service: "backend", port: "9090", validate: true},
+		{name: "service lookup error", service: "frontend", port: "9090", validate: true, getErr: errors.New("connection refused"), warning: `warning: unable to check --port against service "frontend": connection refused`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(service)
+			if test.getErr != nil {
+				client.PrependReactor("get", "services", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, test.getErr
+				})
+			}
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := &CreateRouteSubcommandOptions{
+				Namespace:      "test",
+				ValidatePort:   test.validate,
+				DryRunStrategy: test.dryRun,
+				CoreClient:     client.CoreV1(),
+				IOStreams:      streams,
+			}
+			err := o.checkServicePort(test.service, test.port)
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(errOut.String(), test.warning) || (len(test.warning) == 0 && errOut.Len() > 0) {
+				t.Errorf("unexpected output: %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestRouteCreator(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  CreateRouteOptions
+		expected routeCreator
+		err      string
+	}{
+		{
+			name:     "edge",
+			options:  CreateRouteOptions{Termination: "edge", Service: "frontend", Path: "/assets", Cert: "tls.crt", DefaultInsecureRedirect: true},
+			expected: &CreateEdgeRouteOptions{Service: "frontend", Path: "/assets", Cert: "tls.crt", DefaultInsecureRedirect: true},
+		},
+		{
+			name:     "passthrough",
+			options:  CreateRouteOptions{Termination: "passthrough", Service: "frontend", InsecurePolicy: "Redirect"},
+			expected: &CreatePassthroughRouteOptions{Service: "frontend", InsecurePolicy: "Redirect"},
+		},
+		{
+			name:     "reencrypt",
+			options:  CreateRouteOptions{Termination: "reencrypt", Service: "frontend", DestCACert: "ca.crt"},
+			expected: &CreateReencryptRouteOptions{Service: "frontend", DestCACert: "ca.crt"},
+		},
+		{
+			name:     "none",
+			options:  CreateRouteOptions{Termination: "none", Service: "frontend", Hostname: "www.example.com", Path: "/"},
+			expected: &createUnsecuredRouteOptions{Service: "frontend", Hostname: "www.example.com", Path: "/"},
+		},
+		{
+			name:     "edge with ACME",
+			options:  CreateRouteOptions{Termination: "edge", Service: "frontend", Hostname: "www.example.com", ACMEOptions: ACMEOptions{ACME: true, ACMESolver: "./solver.sh", ACMEAgreeTOS: true}},
+			expected: &CreateEdgeRouteOptions{Service: "frontend", Hostname: "www.example.com", ACMEOptions: ACMEOptions{ACME: true, ACMESolver: "./solver.sh", ACMEAgreeTOS: true}},
+		},
+		{
+			name:    "passthrough with certificates",
+			options: CreateRouteOptions{Termination: "passthrough", Path: "/", Cert: "tls.crt", Key: "tls.key"},
+			err:     "--cert, --key, --path may not be used with --termination=passthrough",
+		},
+		{
+			name:    "passthrough with a certificate secret",
+			options: CreateRouteOptions{Termination: "passthrough", CertSecret: "frontend-tls"},
+			err:     "--cert-secret may not be used with --termination=passthrough",
+		},
+		{
+			name:    "edge with destination CA",
+			options: CreateRouteOptions{Termination: "edge", DestCACert: "ca.crt"},
+			err:     "--dest-ca-cert may not be used with --termination=edge",
+		},
+		{
+			name:    "reencrypt with ACME",
+			options: CreateRouteOptions{Termination: "reencrypt", ACMEOptions: ACMEOptions{ACME: true}},
+			err:     "--acme may not be used with --termination=reencrypt",
+		},
+		{
+			name:    "none with insecure policy",
+			options: CreateRouteOptions{Termination: "none", InsecurePolicy: "Redirect"},
+			err:     "--insecure-policy may not be used with --termination=none",
+		},
+		{
+			name:    "unknown termination",
+			options: CreateRouteOptions{Termination: "Edge"},
+			err:     `invalid --termination "Edge"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			creator, err := test.options.routeCreator()
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(creator, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, creator)
+			}
+		})
+	}
+}
+
+func TestCreateRouteFlags(t *testing.T) {
+	streams := genericclioptions.NewTestIOStreamsDiscard()
+	cmd := NewCmdCreateRoute(nil, streams)
+	for _, subcommand := range cmd.Commands() {
+		subcommand.Flags().VisitAll(func(flag *pflag.Flag) {
+			if cmd.Flags().Lookup(flag.Name) == nil {
+				t.Errorf("--%s of %q is missing from the route command", flag.Name, subcommand.Name())
+			}
+		})
+	}
+}
+
+func TestLoadTLSData(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend-tls", Namespace: "test"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("certificate"),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+			"ca.crt":                []byte("ca"),
+		},
+	}
+	dir, err := ioutil.TempDir("", "route-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "tls.crt")
+	if err := ioutil.WriteFile(file, []byte("file certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		file      string
+		secretRef string
+		expected  string
+		err       string
+	}{
+		{name: "nothing set"},
+		{name: "file", file: file, expected: "file certificate"},
+		{name: "default key", secretRef: "frontend-tls", expected: "certificate"},
+		{name: "explicit key", secretRef: "frontend-tls:ca.crt", expected: "ca"},
+		{name: "file and secret", file: file, secretRef: "frontend-tls", err: "--cert and --cert-secret are mutually exclusive"},
+		{name: "missing key", secretRef: "frontend-tls:cert.pem", err: `secret "frontend-tls" has no key "cert.pem" for --cert-secret, available keys are: ca.crt, tls.crt, tls.key`},
+		{name: "missing secret", secretRef: "other", err: "unable to read --cert-secret"},
+		{name: "empty key", secretRef: "frontend-tls:", err: "--cert-secret must be NAME or NAME:KEY"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := &CreateRouteSubcommandOptions{
+				Namespace:  "test",
+				CoreClient: fake.NewSimpleClientset(secret).CoreV1(),
+			}
+			data, err := o.loadTLSData("cert", test.file, "cert-secret", test.secretRef, corev1.TLSCertKey)
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, data)
+			}
+		})
+	}
+}
+
+func TestSetBackends(t *testing.T) {
+	weight := func(w int32) *int32 { return &w }
+	tests := []struct {
+		name               string
+		backends           []string
+		expectedWeight     *int32
+		expectedAlternates []routev1.RouteTargetReference
+		err                string
+	}{
+		{name: "no backends"},
+		{
+			name:               "weighted primary and alternate",
+			backends:           []string{"frontend=90", "canary=10"},
+			expectedWeight:     weight(90),
+			expectedAlternates: []routev1.RouteTargetReference{{Kind: "Service", Name: "canary", Weight: weight(10)}},
+		},
+		{
+			name:               "primary keeps the default weight",
+			backends:           []string{"canary=0"},
+			expectedAlternates: []routev1.RouteTargetReference{{Kind: "Service", Name: "canary", Weight: weight(0)}},
+		},
+		{name: "no traffic", backends: []string{"frontend=0", "canary=0"}, err: "the weights of all backends are zero"},
+		{name: "too many alternates", backends: []string{"a=1", "b=1", "c=1", "d=1"}, err: "at most 3 backends besides --service, got 4"},
+		{name: "missing weight", backends: []string{"canary"}, err: `--backend "canary" must be SERVICE=WEIGHT`},
+		{name: "weight out of range", backends: []string{"canary=257"}, err: "must have a weight between 0 and 256"},
+		{name: "percentage", backends: []string{"canary=10%"}, err: "must have a weight between 0 and 256"},
+		{name: "duplicate", backends: []string{"canary=1", "canary=2"}, err: `--backend "canary" may only be set once`},
+		{name: "invalid service", backends: []string{"service/canary=1"}, err: "is not a valid service name"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := &CreateRouteSubcommandOptions{Backends: test.backends}
+			route := &routev1.Route{Spec: routev1.RouteSpec{To: routev1.RouteTargetReference{Name: "frontend"}}}
+			err := o.Validate()
+			if err == nil {
+				err = o.setBackends(route)
+			}
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(route.Spec.To.Weight, test.expectedWeight) {
+				t.Errorf("expected primary weight %v, got %v", test.expectedWeight, route.Spec.To.Weight)
+			}
+			if !reflect.DeepEqual(route.Spec.AlternateBackends, test.expectedAlternates) {
+				t.Errorf("expected alternate backends %#v, got %#v", test.expectedAlternates, route.Spec.AlternateBackends)
+			}
+		})
+	}
+}
+
+func TestCreateEdgeRouteOptionsValidateACME(t *testing.T) {
+	valid := func() *CreateEdgeRouteOptions {
+		return &CreateEdgeRouteOptions{
+			CreateRouteSubcommandOptions: &CreateRouteSubcommandOptions{},
+			Hostname:                     "www.example.com",
+			ACMEOptions: ACMEOptions{
+				ACME:          true,
+				ACMEChallenge: "http-01",
+				ACMESolver:    "./solver.sh",
+				ACMEAgreeTOS:  true,
+			},
+		}
+	}
+	tests := []struct {
+		name   string
+		modify func(*CreateEdgeRouteOptions)
+		err    string
+	}{
+		{name: "valid", modify: func(o *CreateEdgeRouteOptions) {}},
+		{name: "no hostname", modify: func(o *CreateEdgeRouteOptions) { o.Hostname = "" }, err: "--acme requires --hostname"},
+		{name: "wildcard hostname", modify: func(o *CreateEdgeRouteOptions) { o.Hostname = "*.example.com" }, err: "wildcard"},
+		{name: "certificate", modify: func(o *CreateEdgeRouteOptions) { o.CertSecret = "frontend-tls" }, err: "may not be used with a certificate or key"},
+		{name: "CA certificate", modify: func(o *CreateEdgeRouteOptions) { o.CASecret = "frontend-ca" }, err: "may not be used with a CA certificate"},
+		{name: "no solver", modify: func(o *CreateEdgeRouteOptions) { o.ACMESolver = "" }, err: "--acme requires --acme-solver"},
+		{name: "terms of service not agreed", modify: func(o *CreateEdgeRouteOptions) { o.ACMEAgreeTOS = false }, err: "--acme-agree-tos"},
+		{name: "unknown challenge", modify: func(o *CreateEdgeRouteOptions) { o.ACMEChallenge = "tls-alpn-01" }, err: "--acme-challenge must be http-01 or dns-01"},
+		{
+			name:   "dry run",
+			modify: func(o *CreateEdgeRouteOptions) { o.CreateRouteSubcommandOptions.DryRunStrategy = kcmdutil.DryRunClient },
+			err:    "--acme may not be used with --dry-run",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := valid()
+			test.modify(o)
+			err := o.Validate()
+			if len(test.err) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestSetHSTSAndTimeoutAnnotations(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  CreateRouteSubcommandOptions
+		expected map[string]string
+	}{
+		{name: "no flags"},
+		{
+			name:     "hsts max age",
+			options:  CreateRouteSubcommandOptions{HSTSMaxAge: 8760 * time.Hour},
+			expected: map[string]string{hstsHeaderAnnotation: "max-age=31536000"},
+		},
+		{
+			name:     "hsts with subdomains and preload",
+			options:  CreateRouteSubcommandOptions{HSTSMaxAge: time.Hour, HSTSIncludeSubdomains: true, HSTSPreload: true},
+			expected: map[string]string{hstsHeaderAnnotation: "max-age=3600;includeSubDomains;preload"},
+		},
+		{
+			name:     "timeout in seconds",
+			options:  CreateRouteSubcommandOptions{RouterTimeout: 2 * time.Minute},
+			expected: map[string]string{timeoutAnnotation: "120s"},
+		},
+		{
+			name:     "timeout in milliseconds",
+			options:  CreateRouteSubcommandOptions{RouterTimeout: 1500 * time.Millisecond},
+			expected: map[string]string{timeoutAnnotation: "1500ms"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route := &routev1.Route{}
+			test.options.setHSTSAnnotation(route)
+			test.options.setTimeoutAnnotation(route)
+			if !reflect.DeepEqual(route.Annotations, test.expected) {
+				t.Errorf("expected annotations %v, got %v", test.expected, route.Annotations)
+			}
+		})
+	}
+}
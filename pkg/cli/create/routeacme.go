@@ -0,0 +1,240 @@
+package create
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	// letsEncryptStagingURL is the directory of the Let's Encrypt staging server, whose rate
+	// limits allow experimenting but whose certificates are not trusted.
+	letsEncryptStagingURL = "https://acme-staging-v02.api.letsencrypt.org/directory"
+
+	acmeChallengeHTTP01 = "http-01"
+	acmeChallengeDNS01  = "dns-01"
+
+	// acmeTimeout bounds obtaining a certificate, including answering the challenges.
+	acmeTimeout = 10 * time.Minute
+)
+
+// ACMEOptions obtain the certificate of an edge route from the ACME server at ACMEDirectory,
+// answering the ACMEChallenge challenge with the ACMESolver command.
+type ACMEOptions struct {
+	ACME           bool
+	ACMEDirectory  string
+	ACMEEmail      string
+	ACMEChallenge  string
+	ACMESolver     string
+	ACMEAccountKey string
+	ACMEAgreeTOS   bool
+}
+
+func NewACMEOptions() ACMEOptions {
+	return ACMEOptions{
+		ACMEDirectory: letsEncryptStagingURL,
+		ACMEChallenge: acmeChallengeHTTP01,
+	}
+}
+
+func (o *ACMEOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.ACME, "acme", o.ACME, "If true, obtain the certificate for --hostname from an ACME server. Requires --acme-solver.")
+	cmd.Flags().StringVar(&o.ACMEDirectory, "acme-directory", o.ACMEDirectory, "The directory URL of the ACME server. Defaults to the Let's Encrypt staging server.")
+	cmd.Flags().StringVar(&o.ACMEEmail, "acme-email", o.ACMEEmail, "The contact email of the ACME account.")
+	cmd.Flags().StringVar(&o.ACMEChallenge, "acme-challenge", o.ACMEChallenge, "The ACME challenge to answer, \"http-01\" or \"dns-01\".")
+	cmd.Flags().StringVar(&o.ACMESolver, "acme-solver", o.ACMESolver, "The command that publishes and removes the answer to the ACME challenge.")
+	cmd.MarkFlagFilename("acme-solver")
+	cmd.Flags().StringVar(&o.ACMEAccountKey, "acme-account-key", o.ACMEAccountKey, "Path to the PEM encoded private key of an existing ACME account to use instead of creating a new account.")
+	cmd.MarkFlagFilename("acme-account-key")
+	cmd.Flags().BoolVar(&o.ACMEAgreeTOS, "acme-agree-tos", o.ACMEAgreeTOS, "If true, agree to the terms of service of the ACME server. Required with --acme.")
+}
+
+// execSolver answers ACME challenges by running a command as
+//
+//	COMMAND present|cleanup TYPE DOMAIN TOKEN VALUE
+//
+// where VALUE is the content to serve at /.well-known/acme-challenge/TOKEN for http-01, or the
+// TXT record of _acme-challenge.DOMAIN for dns-01. The command must only exit once the value is
+// published.
+type execSolver struct {
+	command string
+
+	out    io.Writer
+	errOut io.Writer
+}
+
+func (s *execSolver) run(ctx context.Context, action, challengeType, domain, token, value string) error {
+	cmd := exec.CommandContext(ctx, s.command, action, challengeType, domain, token, value)
+	cmd.Stdout = s.out
+	cmd.Stderr = s.errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s failed: %v", s.command, action, err)
+	}
+	return nil
+}
+
+// loadACMEAccountKey parses the PEM encoded EC or RSA private key of an ACME account.
+func loadACMEAccountKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded key found")
+	}
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+// obtainACMECertificate returns the PEM encoded certificate chain and key for the hostname of the
+// route issued by the ACME server.
+func (o *CreateEdgeRouteOptions) obtainACMECertificate(ctx context.Context) (string, string, error) {
+	var accountKey crypto.Signer
+	var err error
+	if len(o.ACMEAccountKey) > 0 {
+		data, err := ioutil.ReadFile(o.ACMEAccountKey)
+		if err != nil {
+			return "", "", err
+		}
+		if accountKey, err = loadACMEAccountKey(data); err != nil {
+			return "", "", fmt.Errorf("invalid --acme-account-key: %v", err)
+		}
+	} else if accountKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		return "", "", err
+	}
+
+	errOut := o.CreateRouteSubcommandOptions.ErrOut
+	client := &acme.Client{Key: accountKey, DirectoryURL: o.ACMEDirectory}
+	account := &acme.Account{}
+	if len(o.ACMEEmail) > 0 {
+		account.Contact = []string{"mailto:" + o.ACMEEmail}
+	}
+	// Validate ensures the terms of service were accepted with --acme-agree-tos
+	_, err = client.Register(ctx, account, func(tosURL string) bool {
+		fmt.Fprintf(errOut, "Agreeing to the terms of service of the ACME server at %s\n", tosURL)
+		return o.ACMEAgreeTOS
+	})
+	if err != nil && err != acme.ErrAccountAlreadyExists {
+		return "", "", fmt.Errorf("unable to register an ACME account: %v", err)
+	}
+
+	fmt.Fprintf(errOut, "Requesting a certificate for %s from %s ...\n", o.Hostname, o.ACMEDirectory)
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(o.Hostname))
+	if err != nil {
+		return "", "", fmt.Errorf("unable to create an order for %s: %v", o.Hostname, err)
+	}
+	solver := &execSolver{command: o.ACMESolver, out: errOut, errOut: errOut}
+	for _, authzURL := range order.AuthzURLs {
+		if err := o.authorizeACME(ctx, client, solver, authzURL); err != nil {
+			return "", "", err
+		}
+	}
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return "", "", fmt.Errorf("the order for %s failed: %v", o.Hostname, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: o.Hostname},
+		DNSNames: []string{o.Hostname},
+	}, key)
+	if err != nil {
+		return "", "", err
+	}
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to obtain the certificate for %s: %v", o.Hostname, err)
+	}
+
+	var certPEM strings.Builder
+	for _, der := range chain {
+		if err := pem.Encode(&certPEM, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+			return "", "", err
+		}
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	return certPEM.String(), string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})), nil
+}
+
+// authorizeACME answers the challenge of the configured type of an authorization with the solver
+// and waits for the server to validate it.
+func (o *CreateEdgeRouteOptions) authorizeACME(ctx context.Context, client *acme.Client, solver *execSolver, authzURL string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("unable to read the authorization: %v", err)
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+	var challenge *acme.Challenge
+	var types []string
+	for _, c := range authz.Challenges {
+		types = append(types, c.Type)
+		if c.Type == o.ACMEChallenge {
+			challenge = c
+		}
+	}
+	domain := authz.Identifier.Value
+	if challenge == nil {
+		return fmt.Errorf("the ACME server does not offer a %s challenge for %s, it offers: %s", o.ACMEChallenge, domain, strings.Join(types, ", "))
+	}
+
+	var value string
+	if challenge.Type == acmeChallengeDNS01 {
+		value, err = client.DNS01ChallengeRecord(challenge.Token)
+	} else {
+		value, err = client.HTTP01ChallengeResponse(challenge.Token)
+	}
+	if err != nil {
+		return err
+	}
+	if err := solver.run(ctx, "present", challenge.Type, domain, challenge.Token, value); err != nil {
+		return fmt.Errorf("unable to present the %s challenge for %s: %v", challenge.Type, domain, err)
+	}
+	defer func() {
+		if err := solver.run(ctx, "cleanup", challenge.Type, domain, challenge.Token, value); err != nil {
+			fmt.Fprintf(o.CreateRouteSubcommandOptions.ErrOut, "warning: unable to clean up the %s challenge for %s: %v\n", challenge.Type, domain, err)
+		}
+	}()
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("unable to start the %s challenge for %s: %v", challenge.Type, domain, err)
+	}
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("the %s challenge for %s failed: %v", challenge.Type, domain, err)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package create
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	routev1 "github.com/openshift/api/route/v1"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+)
+
+// fakeACMEServer is a minimal ACME server that issues a certificate for the CSR it is sent once
+// the challenge was accepted. Signatures are not verified.
+type fakeACMEServer struct {
+	t      *testing.T
+	server *httptest.Server
+	ca     *x509.Certificate
+	caKey  *ecdsa.PrivateKey
+
+	accepted    string
+	certificate []byte
+}
+
+func newFakeACMEServer(t *testing.T) *fakeACMEServer {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake ACME CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeACMEServer{t: t, ca: ca, caKey: caKey}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *fakeACMEServer) reply(w http.ResponseWriter, code int, location string, body interface{}) {
+	if len(location) > 0 {
+		w.Header().Set("Location", s.server.URL+location)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (s *fakeACMEServer) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Replay-Nonce", "nonce")
+	url := func(path string) string { return s.server.URL + path }
+	switch r.URL.Path {
+	case "/directory":
+		s.reply(w, http.StatusOK, "", map[string]interface{}{
+			"newNonce":   url("/new-nonce"),
+			"newAccount": url("/new-account"),
+			"newOrder":   url("/new-order"),
+			"meta":       map[string]string{"termsOfService": url("/terms")},
+		})
+	case "/new-nonce":
+	case "/new-account":
+		s.reply(w, http.StatusCreated, "/account/1", map[string]string{"status": "valid"})
+	case "/new-order":
+		s.reply(w, http.StatusCreated, "/order/1", map[string]interface{}{
+			"status":         "pending",
+			"authorizations": []string{url("/authz/1")},
+			"finalize":       url("/finalize/1"),
+		})
+	case "/order/1":
+		s.reply(w, http.StatusOK, "/order/1", map[string]interface{}{"status": "ready", "finalize": url("/finalize/1")})
+	case "/authz/1":
+		status := "pending"
+		if len(s.accepted) > 0 {
+			status = "valid"
+		}
+		s.reply(w, http.StatusOK, "", map[string]interface{}{
+			"status":     status,
+			"identifier": map[string]string{"type": "dns", "value": "www.example.com"},
+			"challenges": []map[string]string{
+				{"type": "http-01", "url": url("/challenge/http-01"), "token": "http-token", "status": "pending"},
+				{"type": "dns-01", "url": url("/challenge/dns-01"), "token": "dns-token", "status": "pending"},
+			},
+		})
+	case "/challenge/http-01", "/challenge/dns-01":
+		s.accepted = strings.TrimPrefix(r.URL.Path, "/challenge/")
+		s.reply(w, http.StatusOK, "", map[string]string{"type": s.accepted, "url": url(r.URL.Path), "status": "processing"})
+	case "/finalize/1":
+		var jws struct{ Payload string }
+		var payload struct{ CSR string }
+		json.NewDecoder(r.Body).Decode(&jws)
+		data, _ := base64.RawURLEncoding.DecodeString(jws.Payload)
+		json.Unmarshal(data, &payload)
+		der, _ := base64.RawURLEncoding.DecodeString(payload.CSR)
+		csr, err := x509.ParseCertificateRequest(der)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(2),
+			Subject:      csr.Subject,
+			DNSNames:     csr.DNSNames,
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}
+		cert, err := x509.CreateCertificate(rand.Reader, template, s.ca, csr.PublicKey, s.caKey)
+		if err != nil {
+			s.t.Fatal(err)
+		}
+		s.certificate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})
+		s.reply(w, http.StatusOK, "/order/1", map[string]interface{}{"status": "valid", "certificate": url("/certificate/1")})
+	case "/certificate/1":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.Write(s.certificate)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSetACMECertificate(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	dir, err := ioutil.TempDir("", "route-acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	solver := filepath.Join(dir, "solver.sh")
+	if err := ioutil.WriteFile(solver, []byte("#!/bin/sh\necho \"$@\" >> "+filepath.Join(dir, "calls")+"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(accountKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountKeyFile := filepath.Join(dir, "account.key")
+	if err := ioutil.WriteFile(accountKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, challengeType := range []string{acmeChallengeHTTP01, acmeChallengeDNS01} {
+		t.Run(challengeType, func(t *testing.T) {
+			os.Remove(filepath.Join(dir, "calls"))
+			server := newFakeACMEServer(t)
+			defer server.server.Close()
+
+			route := &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "frontend"},
+				Spec: routev1.RouteSpec{
+					Host: "www.example.com",
+					TLS:  &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge},
+				},
+			}
+			o := &CreateEdgeRouteOptions{
+				CreateRouteSubcommandOptions: &CreateRouteSubcommandOptions{
+					Client:    routefake.NewSimpleClientset(route).RouteV1(),
+					IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+				},
+				Hostname: "www.example.com",
+				ACMEOptions: ACMEOptions{
+					ACME:           true,
+					ACMEDirectory:  server.server.URL + "/directory",
+					ACMEChallenge:  challengeType,
+					ACMESolver:     solver,
+					ACMEAccountKey: accountKeyFile,
+					ACMEAgreeTOS:   true,
+				},
+			}
+			updated, err := o.setACMECertificate(route)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if server.accepted != challengeType {
+				t.Errorf("expected the %s challenge to be accepted, got %q", challengeType, server.accepted)
+			}
+			if updated.Spec.TLS.Termination != routev1.TLSTerminationEdge || len(updated.Spec.TLS.Key) == 0 {
+				t.Errorf("unexpected TLS configuration: %#v", updated.Spec.TLS)
+			}
+			block, _ := pem.Decode([]byte(updated.Spec.TLS.Certificate))
+			if block == nil {
+				t.Fatalf("expected a PEM certificate, got %q", updated.Spec.TLS.Certificate)
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cert.DNSNames) != 1 || cert.DNSNames[0] != "www.example.com" {
+				t.Errorf("unexpected certificate for %v", cert.DNSNames)
+			}
+
+			client := &acme.Client{Key: accountKey}
+			token, value := "http-token", ""
+			if challengeType == acmeChallengeDNS01 {
+				token = "dns-token"
+				value, err = client.DNS01ChallengeRecord(token)
+			} else {
+				value, err = client.HTTP01ChallengeResponse(token)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			calls, err := ioutil.ReadFile(filepath.Join(dir, "calls"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			args := fmt.Sprintf("%s www.example.com %s %s", challengeType, token, value)
+			if expected := "present " + args + "\ncleanup " + args + "\n"; string(calls) != expected {
+				t.Errorf("expected solver calls:\n%s\ngot:\n%s", expected, calls)
+			}
+		})
+	}
+}
+
+func TestLoadACMEAccountKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		data      []byte
+		expectErr bool
+	}{
+		{name: "EC key", data: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER})},
+		{name: "PKCS #8 key", data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER})},
+		{name: "not PEM", data: []byte("key"), expectErr: true},
+		{name: "invalid key", data: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("key")}), expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := loadACMEAccountKey(test.data)
+			if test.expectErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", test.expectErr, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package create
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -10,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/util/retry"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/scheme"
 	"k8s.io/kubectl/pkg/util"
@@ -18,7 +21,6 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/oc/pkg/cli/create/route"
 	cmdutil "github.com/openshift/oc/pkg/helpers/cmd"
-	fileutil "github.com/openshift/oc/pkg/helpers/file"
 )
 
 var (
@@ -27,6 +29,23 @@ var (
 
 		Specify the service (either just its name or using type/name syntax) that the
 		generated route should expose via the --service flag.
+
+		When --hostname is set without --insecure-policy, insecure HTTP requests are redirected
+		to HTTPS. Pass --default-insecure-redirect=false to reject them instead.
+
+		With --acme, a certificate for the hostname is obtained from an ACME server once the
+		route is created, and set on the route. The ACME server is the Let's Encrypt staging
+		server by default, whose certificates are not trusted; pass the directory of a production
+		server with --acme-directory once the setup works. The terms of service of the server
+		must be accepted with --acme-agree-tos. The http-01 or dns-01 challenge is answered
+		by the command passed with --acme-solver, which is run as
+
+		  COMMAND present|cleanup TYPE DOMAIN TOKEN VALUE
+
+		and must publish VALUE at http://DOMAIN/.well-known/acme-challenge/TOKEN for http-01,
+		or as the TXT record of _acme-challenge.DOMAIN for dns-01, before it exits. A new
+		ACME account is created for every route unless --acme-account-key is set. The
+		certificate is not renewed.
 	`)
 
 	edgeRouteExample = templates.Examples(`
@@ -36,6 +55,32 @@ var (
 		# Create an edge route that exposes the frontend service and specify a path
 		# If the route name is omitted, the service name will be used
 		oc create route edge --service=frontend --path /assets
+
+		# Create an edge route that limits each client to 100 HTTP requests in a 10 second window
+		oc create route edge --service=frontend --rate-limit-connections-rate-http=100
+
+		# Create an edge route that only accepts connections from two source networks
+		oc create route edge --service=frontend --source-range=10.0.0.0/8 --source-range=192.168.1.0/24
+
+		# Create an edge route and wait up to 30 seconds for a router to admit it
+		oc create route edge --service=frontend --wait=30s
+
+		# Create an edge route that sends 90% of the traffic to frontend and 10% to frontend-canary
+		oc create route edge --service=frontend --backend=frontend=90 --backend=frontend-canary=10
+
+		# Create an edge route that serves the certificate and key of the frontend-tls secret
+		oc create route edge --service=frontend --cert-secret=frontend-tls --key-secret=frontend-tls
+
+		# Create an edge route with a Let's Encrypt certificate, publishing the dns-01 challenge with a script
+		oc create route edge --service=frontend --hostname=www.example.com --acme --acme-agree-tos \
+		  --acme-directory=https://acme-v02.api.letsencrypt.org/directory --acme-account-key=account.key \
+		  --acme-challenge=dns-01 --acme-solver=./update-dns.sh
+
+		# Create an edge route for a hostname that rejects insecure HTTP requests
+		oc create route edge --service=frontend --hostname=www.example.com --insecure-policy=None
+
+		# Create a second edge route for the frontend service, named frontend- followed by a random suffix
+		oc create route edge --service=frontend --hostname=shop.example.com --generate-name
 	`)
 )
 
@@ -51,12 +96,26 @@ type CreateEdgeRouteOptions struct {
 	Key            string
 	CACert         string
 	WildcardPolicy string
+
+	// CertSecret, KeySecret and CASecret reference the secret keys to read the TLS material from
+	// instead of files.
+	CertSecret string
+	KeySecret  string
+	CASecret   string
+
+	// DefaultInsecureRedirect redirects insecure requests when a hostname is set without an
+	// insecure policy.
+	DefaultInsecureRedirect bool
+
+	ACMEOptions
 }
 
 // NewCmdCreateEdgeRoute is a macro command to create an edge route.
 func NewCmdCreateEdgeRoute(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
 	o := &CreateEdgeRouteOptions{
 		CreateRouteSubcommandOptions: NewCreateRouteSubcommandOptions(streams),
+		DefaultInsecureRedirect:      true,
+		ACMEOptions:                  NewACMEOptions(),
 	}
 	cmd := &cobra.Command{
 		Use:     "edge [NAME] --service=SERVICE",
@@ -65,23 +124,17 @@ func NewCmdCreateEdgeRoute(f kcmdutil.Factory, streams genericclioptions.IOStrea
 		Example: edgeRouteExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Validate())
 			kcmdutil.CheckErr(o.Run())
 		},
 	}
 
-	cmd.Flags().StringVar(&o.Hostname, "hostname", o.Hostname, "Set a hostname for the new route")
-	cmd.Flags().StringVar(&o.Port, "port", o.Port, "Name of the service port or number of the container port the route will route traffic to")
-	cmd.Flags().StringVar(&o.InsecurePolicy, "insecure-policy", o.InsecurePolicy, "Set an insecure policy for the new route")
-	cmd.Flags().StringVar(&o.Service, "service", o.Service, "Name of the service that the new route is exposing")
+	addRouteFlags(cmd, &o.Hostname, &o.Port, &o.Service, &o.WildcardPolicy)
 	cmd.MarkFlagRequired("service")
-	cmd.Flags().StringVar(&o.Path, "path", o.Path, "Path that the router watches to route traffic to the service.")
-	cmd.Flags().StringVar(&o.Cert, "cert", o.Cert, "Path to a certificate file.")
-	cmd.MarkFlagFilename("cert")
-	cmd.Flags().StringVar(&o.Key, "key", o.Key, "Path to a key file.")
-	cmd.MarkFlagFilename("key")
-	cmd.Flags().StringVar(&o.CACert, "ca-cert", o.CACert, "Path to a CA certificate file.")
-	cmd.MarkFlagFilename("ca-cert")
-	cmd.Flags().StringVar(&o.WildcardPolicy, "wildcard-policy", o.WildcardPolicy, "Sets the WilcardPolicy for the hostname, the default is \"None\". valid values are \"None\" and \"Subdomain\"")
+	addInsecurePolicyFlags(cmd, &o.InsecurePolicy, &o.DefaultInsecureRedirect, routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyAllow, routev1.InsecureEdgeTerminationPolicyRedirect)
+	addPathFlag(cmd, &o.Path)
+	addTLSFlags(cmd, &o.Cert, &o.Key, &o.CACert, &o.CertSecret, &o.KeySecret, &o.CASecret)
+	o.ACMEOptions.AddFlags(cmd)
 
 	kcmdutil.AddValidateFlags(cmd)
 	o.CreateRouteSubcommandOptions.AddFlags(cmd)
@@ -91,7 +144,40 @@ func NewCmdCreateEdgeRoute(f kcmdutil.Factory, streams genericclioptions.IOStrea
 }
 
 func (o *CreateEdgeRouteOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
-	return o.CreateRouteSubcommandOptions.Complete(f, cmd, args)
+	if err := o.CreateRouteSubcommandOptions.Complete(f, cmd, args); err != nil {
+		return err
+	}
+	var err error
+	o.Hostname, err = o.CreateRouteSubcommandOptions.completeHostname(o.Hostname, o.Service)
+	if err != nil {
+		return err
+	}
+	o.InsecurePolicy, err = completeInsecurePolicy(o.InsecurePolicy, o.Hostname, o.DefaultInsecureRedirect, routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyAllow, routev1.InsecureEdgeTerminationPolicyRedirect)
+	return err
+}
+
+func (o *CreateEdgeRouteOptions) Validate() error {
+	if o.ACME {
+		switch {
+		case len(o.Hostname) == 0:
+			return fmt.Errorf("--acme requires --hostname")
+		case strings.HasPrefix(o.Hostname, "*."):
+			return fmt.Errorf("--acme does not support wildcard hostnames")
+		case len(o.Cert) > 0 || len(o.Key) > 0 || len(o.CertSecret) > 0 || len(o.KeySecret) > 0:
+			return fmt.Errorf("--acme may not be used with a certificate or key")
+		case len(o.CACert) > 0 || len(o.CASecret) > 0:
+			return fmt.Errorf("--acme may not be used with a CA certificate, the chain is provided by the ACME server")
+		case len(o.ACMESolver) == 0:
+			return fmt.Errorf("--acme requires --acme-solver")
+		case !o.ACMEAgreeTOS:
+			return fmt.Errorf("--acme requires agreeing to the terms of service of the ACME server with --acme-agree-tos")
+		case o.ACMEChallenge != acmeChallengeHTTP01 && o.ACMEChallenge != acmeChallengeDNS01:
+			return fmt.Errorf("--acme-challenge must be %s or %s", acmeChallengeHTTP01, acmeChallengeDNS01)
+		case o.CreateRouteSubcommandOptions.DryRunStrategy != kcmdutil.DryRunNone:
+			return fmt.Errorf("--acme may not be used with --dry-run, since it obtains a certificate")
+		}
+	}
+	return o.CreateRouteSubcommandOptions.Validate()
 }
 
 func (o *CreateEdgeRouteOptions) Run() error {
@@ -103,36 +189,49 @@ func (o *CreateEdgeRouteOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if err := o.CreateRouteSubcommandOptions.checkServicePort(serviceName, o.Port); err != nil {
+		return err
+	}
 
 	if len(o.WildcardPolicy) > 0 {
 		route.Spec.WildcardPolicy = routev1.WildcardPolicyType(o.WildcardPolicy)
 	}
 
 	route.Spec.Host = o.Hostname
+	route.Spec.Subdomain = o.CreateRouteSubcommandOptions.Subdomain
+	if err := o.CreateRouteSubcommandOptions.setBackends(route); err != nil {
+		return err
+	}
 	route.Spec.Path = o.Path
 
 	route.Spec.TLS = new(routev1.TLSConfig)
 	route.Spec.TLS.Termination = routev1.TLSTerminationEdge
-	cert, err := fileutil.LoadData(o.Cert)
+	route.Spec.TLS.Certificate, err = o.CreateRouteSubcommandOptions.loadTLSData("cert", o.Cert, "cert-secret", o.CertSecret, corev1.TLSCertKey)
 	if err != nil {
 		return err
 	}
-	route.Spec.TLS.Certificate = string(cert)
-	key, err := fileutil.LoadData(o.Key)
+	route.Spec.TLS.Key, err = o.CreateRouteSubcommandOptions.loadTLSData("key", o.Key, "key-secret", o.KeySecret, corev1.TLSPrivateKeyKey)
 	if err != nil {
 		return err
 	}
-	route.Spec.TLS.Key = string(key)
-	caCert, err := fileutil.LoadData(o.CACert)
+	route.Spec.TLS.CACertificate, err = o.CreateRouteSubcommandOptions.loadTLSData("ca-cert", o.CACert, "ca-secret", o.CASecret, caCertKey)
 	if err != nil {
 		return err
 	}
-	route.Spec.TLS.CACertificate = string(caCert)
+	if err := validateRouteTLS(route.Spec.TLS.Certificate, route.Spec.TLS.Key, route.Spec.TLS.CACertificate, time.Now()); err != nil {
+		return err
+	}
 
 	if len(o.InsecurePolicy) > 0 {
 		route.Spec.TLS.InsecureEdgeTerminationPolicy = routev1.InsecureEdgeTerminationPolicyType(o.InsecurePolicy)
 	}
 
+	o.CreateRouteSubcommandOptions.setGenerateName(route)
+	o.CreateRouteSubcommandOptions.setRateLimitAnnotations(route)
+	o.CreateRouteSubcommandOptions.setHSTSAnnotation(route)
+	o.CreateRouteSubcommandOptions.setTimeoutAnnotation(route)
+	o.CreateRouteSubcommandOptions.setSourceRangeAnnotation(route)
+
 	if err := util.CreateOrUpdateAnnotation(o.CreateRouteSubcommandOptions.CreateAnnotation, route, scheme.DefaultJSONEncoder()); err != nil {
 		return err
 	}
@@ -143,8 +242,52 @@ func (o *CreateEdgeRouteOptions) Run() error {
 			return err
 		}
 	}
+	// the certificate is only requested once the route exists, so a route that cannot be
+	// created does not use up an issuance of the ACME server
+	if o.ACME {
+		if route, err = o.setACMECertificate(route); err != nil {
+			return err
+		}
+	}
+
+	if err := o.CreateRouteSubcommandOptions.Printer.PrintObj(route, o.CreateRouteSubcommandOptions.Out); err != nil {
+		return err
+	}
+	return o.CreateRouteSubcommandOptions.waitForAdmission(route)
+}
+
+// setACMECertificate obtains a certificate for the created route from the ACME server and
+// updates the route with it.
+func (o *CreateEdgeRouteOptions) setACMECertificate(route *routev1.Route) (*routev1.Route, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), acmeTimeout)
+	defer cancel()
+	cert, key, err := o.obtainACMECertificate(ctx)
+	if err == nil {
+		err = validateRouteTLS(cert, key, "", time.Now())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("route/%s was created without a certificate: %v", route.Name, err)
+	}
 
-	return o.CreateRouteSubcommandOptions.Printer.PrintObj(route, o.CreateRouteSubcommandOptions.Out)
+	routes := o.CreateRouteSubcommandOptions.Client.Routes(route.Namespace)
+	var updated *routev1.Route
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := routes.Get(context.TODO(), route.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.Spec.TLS == nil {
+			current.Spec.TLS = &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge}
+		}
+		current.Spec.TLS.Certificate = cert
+		current.Spec.TLS.Key = key
+		updated, err = routes.Update(context.TODO(), current, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to set the certificate of route/%s: %v", route.Name, err)
+	}
+	return updated, nil
 }
 
 func resolveServiceName(mapper meta.RESTMapper, resource string) (string, error) {
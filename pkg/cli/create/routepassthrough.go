@@ -2,6 +2,7 @@ package create
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -31,6 +32,18 @@ var (
 		# Create a passthrough route that exposes the frontend service and specify
 		# a host name. If the route name is omitted, the service name will be used
 		oc create route passthrough --service=frontend --hostname=www.example.com
+
+		# Create a passthrough route whose host name is built from the route name and namespace
+		oc create route passthrough --service=frontend --hostname-template='{{.Name}}-{{.Namespace}}.apps.example.com'
+
+		# Create a passthrough route to the https port of the frontend service, failing if the service has no such port
+		oc create route passthrough --service=frontend --port=https --validate-port
+
+		# Create a passthrough route served on the "secure" subdomain of each ingress domain
+		oc create route passthrough --service=frontend --subdomain=secure
+
+		# Create a passthrough route named "secure-" followed by a random suffix
+		oc create route passthrough secure- --service=frontend --hostname=secure.example.com
 	`)
 )
 
@@ -56,15 +69,13 @@ func NewCmdCreatePassthroughRoute(f kcmdutil.Factory, streams genericclioptions.
 		Example: passthroughRouteExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Validate())
 			kcmdutil.CheckErr(o.Run())
 		},
 	}
-	cmd.Flags().StringVar(&o.Hostname, "hostname", o.Hostname, "Set a hostname for the new route")
-	cmd.Flags().StringVar(&o.Port, "port", o.Port, "Name of the service port or number of the container port the route will route traffic to")
-	cmd.Flags().StringVar(&o.InsecurePolicy, "insecure-policy", o.InsecurePolicy, "Set an insecure policy for the new route")
-	cmd.Flags().StringVar(&o.Service, "service", o.Service, "Name of the service that the new route is exposing")
+	addRouteFlags(cmd, &o.Hostname, &o.Port, &o.Service, &o.WildcardPolicy)
 	cmd.MarkFlagRequired("service")
-	cmd.Flags().StringVar(&o.WildcardPolicy, "wildcard-policy", o.WildcardPolicy, "Sets the WilcardPolicy for the hostname, the default is \"None\". valid values are \"None\" and \"Subdomain\"")
+	addInsecurePolicyFlags(cmd, &o.InsecurePolicy, nil, routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyRedirect)
 
 	kcmdutil.AddValidateFlags(cmd)
 	o.CreateRouteSubcommandOptions.AddFlags(cmd)
@@ -74,7 +85,23 @@ func NewCmdCreatePassthroughRoute(f kcmdutil.Factory, streams genericclioptions.
 }
 
 func (o *CreatePassthroughRouteOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
-	return o.CreateRouteSubcommandOptions.Complete(f, cmd, args)
+	if err := o.CreateRouteSubcommandOptions.Complete(f, cmd, args); err != nil {
+		return err
+	}
+	var err error
+	o.Hostname, err = o.CreateRouteSubcommandOptions.completeHostname(o.Hostname, o.Service)
+	if err != nil {
+		return err
+	}
+	o.InsecurePolicy, err = completeInsecurePolicy(o.InsecurePolicy, o.Hostname, false, routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyRedirect)
+	return err
+}
+
+func (o *CreatePassthroughRouteOptions) Validate() error {
+	if o.CreateRouteSubcommandOptions.HSTSMaxAge > 0 {
+		return fmt.Errorf("--hsts-max-age is not supported by passthrough routes, since the router does not terminate their TLS connections")
+	}
+	return o.CreateRouteSubcommandOptions.Validate()
 }
 
 func (o *CreatePassthroughRouteOptions) Run() error {
@@ -86,12 +113,19 @@ func (o *CreatePassthroughRouteOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if err := o.CreateRouteSubcommandOptions.checkServicePort(serviceName, o.Port); err != nil {
+		return err
+	}
 
 	if len(o.WildcardPolicy) > 0 {
 		route.Spec.WildcardPolicy = routev1.WildcardPolicyType(o.WildcardPolicy)
 	}
 
 	route.Spec.Host = o.Hostname
+	route.Spec.Subdomain = o.CreateRouteSubcommandOptions.Subdomain
+	if err := o.CreateRouteSubcommandOptions.setBackends(route); err != nil {
+		return err
+	}
 	route.Spec.TLS = new(routev1.TLSConfig)
 	route.Spec.TLS.Termination = routev1.TLSTerminationPassthrough
 
@@ -99,6 +133,12 @@ func (o *CreatePassthroughRouteOptions) Run() error {
 		route.Spec.TLS.InsecureEdgeTerminationPolicy = routev1.InsecureEdgeTerminationPolicyType(o.InsecurePolicy)
 	}
 
+	o.CreateRouteSubcommandOptions.setGenerateName(route)
+	o.CreateRouteSubcommandOptions.setRateLimitAnnotations(route)
+	o.CreateRouteSubcommandOptions.setHSTSAnnotation(route)
+	o.CreateRouteSubcommandOptions.setTimeoutAnnotation(route)
+	o.CreateRouteSubcommandOptions.setSourceRangeAnnotation(route)
+
 	if err := util.CreateOrUpdateAnnotation(o.CreateRouteSubcommandOptions.CreateAnnotation, route, scheme.DefaultJSONEncoder()); err != nil {
 		return err
 	}
@@ -110,5 +150,8 @@ func (o *CreatePassthroughRouteOptions) Run() error {
 		}
 	}
 
-	return o.CreateRouteSubcommandOptions.Printer.PrintObj(route, o.CreateRouteSubcommandOptions.Out)
+	if err := o.CreateRouteSubcommandOptions.Printer.PrintObj(route, o.CreateRouteSubcommandOptions.Out); err != nil {
+		return err
+	}
+	return o.CreateRouteSubcommandOptions.waitForAdmission(route)
 }
@@ -2,9 +2,11 @@ package create
 
 import (
 	"context"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -14,7 +16,6 @@ import (
 
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/oc/pkg/cli/create/route"
-	fileutil "github.com/openshift/oc/pkg/helpers/file"
 )
 
 var (
@@ -36,6 +37,12 @@ var (
 		# route name default to the service name and the destination CA certificate
 		# default to the service CA
 		oc create route reencrypt --service=frontend
+
+		# Create a reencrypt route that enables HSTS for a year and waits up to 2 minutes for responses
+		oc create route reencrypt --service=frontend --hsts-max-age=8760h --hsts-include-subdomains --router-timeout=2m
+
+		# Create a reencrypt route that trusts the CA in the service-ca key of the backend-ca secret
+		oc create route reencrypt --service=frontend --dest-ca-secret=backend-ca:service-ca
 	`)
 )
 
@@ -52,12 +59,24 @@ type CreateReencryptRouteOptions struct {
 	CACert         string
 	DestCACert     string
 	WildcardPolicy string
+
+	// CertSecret, KeySecret, CASecret and DestCASecret reference the secret keys to read the TLS
+	// material from instead of files.
+	CertSecret   string
+	KeySecret    string
+	CASecret     string
+	DestCASecret string
+
+	// DefaultInsecureRedirect redirects insecure requests when a hostname is set without an
+	// insecure policy.
+	DefaultInsecureRedirect bool
 }
 
 // NewCmdCreateReencryptRoute is a macro command to create a reencrypt route.
 func NewCmdCreateReencryptRoute(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
 	o := &CreateReencryptRouteOptions{
 		CreateRouteSubcommandOptions: NewCreateRouteSubcommandOptions(streams),
+		DefaultInsecureRedirect:      true,
 	}
 	cmd := &cobra.Command{
 		Use:     "reencrypt [NAME] --service=SERVICE",
@@ -66,25 +85,17 @@ func NewCmdCreateReencryptRoute(f kcmdutil.Factory, streams genericclioptions.IO
 		Example: reencryptRouteExample,
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Validate())
 			kcmdutil.CheckErr(o.Run())
 		},
 	}
 
-	cmd.Flags().StringVar(&o.Hostname, "hostname", o.Hostname, "Set a hostname for the new route")
-	cmd.Flags().StringVar(&o.Port, "port", o.Port, "Name of the service port or number of the container port the route will route traffic to")
-	cmd.Flags().StringVar(&o.InsecurePolicy, "insecure-policy", o.InsecurePolicy, "Set an insecure policy for the new route")
-	cmd.Flags().StringVar(&o.Service, "service", o.Service, "Name of the service that the new route is exposing")
+	addRouteFlags(cmd, &o.Hostname, &o.Port, &o.Service, &o.WildcardPolicy)
 	cmd.MarkFlagRequired("service")
-	cmd.Flags().StringVar(&o.Path, "path", o.Path, "Path that the router watches to route traffic to the service.")
-	cmd.Flags().StringVar(&o.Cert, "cert", o.Cert, "Path to a certificate file.")
-	cmd.MarkFlagFilename("cert")
-	cmd.Flags().StringVar(&o.Key, "key", o.Key, "Path to a key file.")
-	cmd.MarkFlagFilename("key")
-	cmd.Flags().StringVar(&o.CACert, "ca-cert", o.CACert, "Path to a CA certificate file.")
-	cmd.MarkFlagFilename("ca-cert")
-	cmd.Flags().StringVar(&o.DestCACert, "dest-ca-cert", o.DestCACert, "Path to a CA certificate file, used for securing the connection from the router to the destination. Defaults to the Service CA.")
-	cmd.MarkFlagFilename("dest-ca-cert")
-	cmd.Flags().StringVar(&o.WildcardPolicy, "wildcard-policy", o.WildcardPolicy, "Sets the WilcardPolicy for the hostname, the default is \"None\". valid values are \"None\" and \"Subdomain\"")
+	addInsecurePolicyFlags(cmd, &o.InsecurePolicy, &o.DefaultInsecureRedirect, routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyAllow, routev1.InsecureEdgeTerminationPolicyRedirect)
+	addPathFlag(cmd, &o.Path)
+	addTLSFlags(cmd, &o.Cert, &o.Key, &o.CACert, &o.CertSecret, &o.KeySecret, &o.CASecret)
+	addDestinationCAFlags(cmd, &o.DestCACert, &o.DestCASecret)
 
 	kcmdutil.AddValidateFlags(cmd)
 	o.CreateRouteSubcommandOptions.AddFlags(cmd)
@@ -94,7 +105,20 @@ func NewCmdCreateReencryptRoute(f kcmdutil.Factory, streams genericclioptions.IO
 }
 
 func (o *CreateReencryptRouteOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
-	return o.CreateRouteSubcommandOptions.Complete(f, cmd, args)
+	if err := o.CreateRouteSubcommandOptions.Complete(f, cmd, args); err != nil {
+		return err
+	}
+	var err error
+	o.Hostname, err = o.CreateRouteSubcommandOptions.completeHostname(o.Hostname, o.Service)
+	if err != nil {
+		return err
+	}
+	o.InsecurePolicy, err = completeInsecurePolicy(o.InsecurePolicy, o.Hostname, o.DefaultInsecureRedirect, routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyAllow, routev1.InsecureEdgeTerminationPolicyRedirect)
+	return err
+}
+
+func (o *CreateReencryptRouteOptions) Validate() error {
+	return o.CreateRouteSubcommandOptions.Validate()
 }
 
 func (o *CreateReencryptRouteOptions) Run() error {
@@ -106,6 +130,9 @@ func (o *CreateReencryptRouteOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if err := o.CreateRouteSubcommandOptions.checkServicePort(serviceName, o.Port); err != nil {
+		return err
+	}
 
 	// If the namespace is not the default, add it. This makes sure it will eventually
 	// appear in the manifest if the dry-run is enabled.
@@ -118,36 +145,48 @@ func (o *CreateReencryptRouteOptions) Run() error {
 	}
 
 	route.Spec.Host = o.Hostname
+	route.Spec.Subdomain = o.CreateRouteSubcommandOptions.Subdomain
+	if err := o.CreateRouteSubcommandOptions.setBackends(route); err != nil {
+		return err
+	}
 	route.Spec.Path = o.Path
 
 	route.Spec.TLS = new(routev1.TLSConfig)
 	route.Spec.TLS.Termination = routev1.TLSTerminationReencrypt
 
-	cert, err := fileutil.LoadData(o.Cert)
+	route.Spec.TLS.Certificate, err = o.CreateRouteSubcommandOptions.loadTLSData("cert", o.Cert, "cert-secret", o.CertSecret, corev1.TLSCertKey)
 	if err != nil {
 		return err
 	}
-	route.Spec.TLS.Certificate = string(cert)
-	key, err := fileutil.LoadData(o.Key)
+	route.Spec.TLS.Key, err = o.CreateRouteSubcommandOptions.loadTLSData("key", o.Key, "key-secret", o.KeySecret, corev1.TLSPrivateKeyKey)
 	if err != nil {
 		return err
 	}
-	route.Spec.TLS.Key = string(key)
-	caCert, err := fileutil.LoadData(o.CACert)
+	route.Spec.TLS.CACertificate, err = o.CreateRouteSubcommandOptions.loadTLSData("ca-cert", o.CACert, "ca-secret", o.CASecret, caCertKey)
 	if err != nil {
 		return err
 	}
-	route.Spec.TLS.CACertificate = string(caCert)
-	destCACert, err := fileutil.LoadData(o.DestCACert)
+	if err := validateRouteTLS(route.Spec.TLS.Certificate, route.Spec.TLS.Key, route.Spec.TLS.CACertificate, time.Now()); err != nil {
+		return err
+	}
+	route.Spec.TLS.DestinationCACertificate, err = o.CreateRouteSubcommandOptions.loadTLSData("dest-ca-cert", o.DestCACert, "dest-ca-secret", o.DestCASecret, caCertKey)
 	if err != nil {
 		return err
 	}
-	route.Spec.TLS.DestinationCACertificate = string(destCACert)
+	if err := validateCABundle("destination CA certificate", route.Spec.TLS.DestinationCACertificate); err != nil {
+		return err
+	}
 
 	if len(o.InsecurePolicy) > 0 {
 		route.Spec.TLS.InsecureEdgeTerminationPolicy = routev1.InsecureEdgeTerminationPolicyType(o.InsecurePolicy)
 	}
 
+	o.CreateRouteSubcommandOptions.setGenerateName(route)
+	o.CreateRouteSubcommandOptions.setRateLimitAnnotations(route)
+	o.CreateRouteSubcommandOptions.setHSTSAnnotation(route)
+	o.CreateRouteSubcommandOptions.setTimeoutAnnotation(route)
+	o.CreateRouteSubcommandOptions.setSourceRangeAnnotation(route)
+
 	if err := util.CreateOrUpdateAnnotation(o.CreateRouteSubcommandOptions.CreateAnnotation, route, scheme.DefaultJSONEncoder()); err != nil {
 		return err
 	}
@@ -159,5 +198,8 @@ func (o *CreateReencryptRouteOptions) Run() error {
 		}
 	}
 
-	return o.CreateRouteSubcommandOptions.Printer.PrintObj(route, o.CreateRouteSubcommandOptions.Out)
+	if err := o.CreateRouteSubcommandOptions.Printer.PrintObj(route, o.CreateRouteSubcommandOptions.Out); err != nil {
+		return err
+	}
+	return o.CreateRouteSubcommandOptions.waitForAdmission(route)
 }
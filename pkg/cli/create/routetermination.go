@@ -0,0 +1,289 @@
+package create
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/kubectl/pkg/util"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/openshift/oc/pkg/cli/create/route"
+)
+
+const terminationNone = "none"
+
+// CreateRouteOptions creates a route with the termination selected by --termination, through the
+// options of the edge, passthrough or reencrypt subcommand.
+type CreateRouteOptions struct {
+	CreateRouteSubcommandOptions *CreateRouteSubcommandOptions
+
+	Termination string
+
+	Hostname       string
+	Port           string
+	InsecurePolicy string
+	Service        string
+	Path           string
+	Cert           string
+	Key            string
+	CACert         string
+	DestCACert     string
+	WildcardPolicy string
+
+	CertSecret   string
+	KeySecret    string
+	CASecret     string
+	DestCASecret string
+
+	// DefaultInsecureRedirect redirects insecure requests when a hostname is set without an
+	// insecure policy.
+	DefaultInsecureRedirect bool
+
+	ACMEOptions
+
+	// creator is the options of the selected termination
+	creator routeCreator
+}
+
+// routeCreator is implemented by the options of every kind of route.
+type routeCreator interface {
+	Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error
+	Validate() error
+	Run() error
+}
+
+func NewCreateRouteOptions(streams genericclioptions.IOStreams) *CreateRouteOptions {
+	return &CreateRouteOptions{
+		CreateRouteSubcommandOptions: NewCreateRouteSubcommandOptions(streams),
+		DefaultInsecureRedirect:      true,
+		ACMEOptions:                  NewACMEOptions(),
+	}
+}
+
+func (o *CreateRouteOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Termination, "termination", o.Termination, "Create a route with this TLS termination, one of \"edge\", \"passthrough\", \"reencrypt\" or \"none\", instead of using a subcommand.")
+	addRouteFlags(cmd, &o.Hostname, &o.Port, &o.Service, &o.WildcardPolicy)
+	addInsecurePolicyFlags(cmd, &o.InsecurePolicy, &o.DefaultInsecureRedirect, routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyAllow, routev1.InsecureEdgeTerminationPolicyRedirect)
+	addPathFlag(cmd, &o.Path)
+	addTLSFlags(cmd, &o.Cert, &o.Key, &o.CACert, &o.CertSecret, &o.KeySecret, &o.CASecret)
+	addDestinationCAFlags(cmd, &o.DestCACert, &o.DestCASecret)
+	o.ACMEOptions.AddFlags(cmd)
+
+	kcmdutil.AddValidateFlags(cmd)
+	o.CreateRouteSubcommandOptions.AddFlags(cmd)
+	kcmdutil.AddDryRunFlag(cmd)
+}
+
+func (o *CreateRouteOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	o.creator, err = o.routeCreator()
+	if err != nil {
+		return err
+	}
+	return o.creator.Complete(f, cmd, args)
+}
+
+// routeCreator returns the options of the termination, failing when a flag is set that the
+// termination does not use.
+func (o *CreateRouteOptions) routeCreator() (routeCreator, error) {
+	unsupported := map[string]string{
+		"path":         o.Path,
+		"cert":         o.Cert,
+		"key":          o.Key,
+		"ca-cert":      o.CACert,
+		"dest-ca-cert": o.DestCACert,
+
+		"cert-secret":    o.CertSecret,
+		"key-secret":     o.KeySecret,
+		"ca-secret":      o.CASecret,
+		"dest-ca-secret": o.DestCASecret,
+	}
+	if o.ACME {
+		unsupported["acme"] = "true"
+	}
+	switch o.Termination {
+	case string(routev1.TLSTerminationEdge):
+		delete(unsupported, "path")
+		delete(unsupported, "cert")
+		delete(unsupported, "key")
+		delete(unsupported, "ca-cert")
+		delete(unsupported, "cert-secret")
+		delete(unsupported, "key-secret")
+		delete(unsupported, "ca-secret")
+		delete(unsupported, "acme")
+	case string(routev1.TLSTerminationPassthrough):
+	case string(routev1.TLSTerminationReencrypt):
+		delete(unsupported, "path")
+		delete(unsupported, "cert")
+		delete(unsupported, "key")
+		delete(unsupported, "ca-cert")
+		delete(unsupported, "dest-ca-cert")
+		delete(unsupported, "cert-secret")
+		delete(unsupported, "key-secret")
+		delete(unsupported, "ca-secret")
+		delete(unsupported, "dest-ca-secret")
+	case terminationNone:
+		delete(unsupported, "path")
+		unsupported["insecure-policy"] = o.InsecurePolicy
+	default:
+		return nil, fmt.Errorf("invalid --termination %q, valid values are edge, passthrough, reencrypt and none", o.Termination)
+	}
+	var set []string
+	for flag, value := range unsupported {
+		if len(value) > 0 {
+			set = append(set, "--"+flag)
+		}
+	}
+	if len(set) > 0 {
+		sort.Strings(set)
+		return nil, fmt.Errorf("%s may not be used with --termination=%s", strings.Join(set, ", "), o.Termination)
+	}
+
+	switch o.Termination {
+	case string(routev1.TLSTerminationEdge):
+		return &CreateEdgeRouteOptions{
+			CreateRouteSubcommandOptions: o.CreateRouteSubcommandOptions,
+			Hostname:                     o.Hostname,
+			Port:                         o.Port,
+			InsecurePolicy:               o.InsecurePolicy,
+			Service:                      o.Service,
+			Path:                         o.Path,
+			Cert:                         o.Cert,
+			Key:                          o.Key,
+			CACert:                       o.CACert,
+			WildcardPolicy:               o.WildcardPolicy,
+			CertSecret:                   o.CertSecret,
+			KeySecret:                    o.KeySecret,
+			CASecret:                     o.CASecret,
+			DefaultInsecureRedirect:      o.DefaultInsecureRedirect,
+			ACMEOptions:                  o.ACMEOptions,
+		}, nil
+	case string(routev1.TLSTerminationPassthrough):
+		return &CreatePassthroughRouteOptions{
+			CreateRouteSubcommandOptions: o.CreateRouteSubcommandOptions,
+			Hostname:                     o.Hostname,
+			Port:                         o.Port,
+			InsecurePolicy:               o.InsecurePolicy,
+			Service:                      o.Service,
+			WildcardPolicy:               o.WildcardPolicy,
+		}, nil
+	case string(routev1.TLSTerminationReencrypt):
+		return &CreateReencryptRouteOptions{
+			CreateRouteSubcommandOptions: o.CreateRouteSubcommandOptions,
+			Hostname:                     o.Hostname,
+			Port:                         o.Port,
+			InsecurePolicy:               o.InsecurePolicy,
+			Service:                      o.Service,
+			Path:                         o.Path,
+			Cert:                         o.Cert,
+			Key:                          o.Key,
+			CACert:                       o.CACert,
+			DestCACert:                   o.DestCACert,
+			WildcardPolicy:               o.WildcardPolicy,
+			CertSecret:                   o.CertSecret,
+			KeySecret:                    o.KeySecret,
+			CASecret:                     o.CASecret,
+			DestCASecret:                 o.DestCASecret,
+			DefaultInsecureRedirect:      o.DefaultInsecureRedirect,
+		}, nil
+	default:
+		return &createUnsecuredRouteOptions{
+			CreateRouteSubcommandOptions: o.CreateRouteSubcommandOptions,
+			Hostname:                     o.Hostname,
+			Port:                         o.Port,
+			Service:                      o.Service,
+			Path:                         o.Path,
+			WildcardPolicy:               o.WildcardPolicy,
+		}, nil
+	}
+}
+
+func (o *CreateRouteOptions) Validate() error {
+	return o.creator.Validate()
+}
+
+func (o *CreateRouteOptions) Run() error {
+	return o.creator.Run()
+}
+
+// createUnsecuredRouteOptions creates a route without TLS for --termination=none.
+type createUnsecuredRouteOptions struct {
+	CreateRouteSubcommandOptions *CreateRouteSubcommandOptions
+
+	Hostname       string
+	Port           string
+	Service        string
+	Path           string
+	WildcardPolicy string
+}
+
+func (o *createUnsecuredRouteOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if err := o.CreateRouteSubcommandOptions.Complete(f, cmd, args); err != nil {
+		return err
+	}
+	var err error
+	o.Hostname, err = o.CreateRouteSubcommandOptions.completeHostname(o.Hostname, o.Service)
+	return err
+}
+
+func (o *createUnsecuredRouteOptions) Validate() error {
+	if o.CreateRouteSubcommandOptions.HSTSMaxAge > 0 {
+		return fmt.Errorf("--hsts-max-age is not supported by unsecured routes")
+	}
+	return o.CreateRouteSubcommandOptions.Validate()
+}
+
+func (o *createUnsecuredRouteOptions) Run() error {
+	serviceName, err := resolveServiceName(o.CreateRouteSubcommandOptions.Mapper, o.Service)
+	if err != nil {
+		return err
+	}
+	route, err := route.UnsecuredRoute(o.CreateRouteSubcommandOptions.CoreClient, o.CreateRouteSubcommandOptions.Namespace, o.CreateRouteSubcommandOptions.Name, serviceName, o.Port, false, o.CreateRouteSubcommandOptions.EnforceNamespace)
+	if err != nil {
+		return err
+	}
+	if err := o.CreateRouteSubcommandOptions.checkServicePort(serviceName, o.Port); err != nil {
+		return err
+	}
+
+	if len(o.WildcardPolicy) > 0 {
+		route.Spec.WildcardPolicy = routev1.WildcardPolicyType(o.WildcardPolicy)
+	}
+
+	route.Spec.Host = o.Hostname
+	route.Spec.Subdomain = o.CreateRouteSubcommandOptions.Subdomain
+	if err := o.CreateRouteSubcommandOptions.setBackends(route); err != nil {
+		return err
+	}
+	route.Spec.Path = o.Path
+
+	o.CreateRouteSubcommandOptions.setGenerateName(route)
+	o.CreateRouteSubcommandOptions.setRateLimitAnnotations(route)
+	o.CreateRouteSubcommandOptions.setHSTSAnnotation(route)
+	o.CreateRouteSubcommandOptions.setTimeoutAnnotation(route)
+	o.CreateRouteSubcommandOptions.setSourceRangeAnnotation(route)
+
+	if err := util.CreateOrUpdateAnnotation(o.CreateRouteSubcommandOptions.CreateAnnotation, route, scheme.DefaultJSONEncoder()); err != nil {
+		return err
+	}
+
+	if o.CreateRouteSubcommandOptions.DryRunStrategy != kcmdutil.DryRunClient {
+		route, err = o.CreateRouteSubcommandOptions.Client.Routes(o.CreateRouteSubcommandOptions.Namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := o.CreateRouteSubcommandOptions.Printer.PrintObj(route, o.CreateRouteSubcommandOptions.Out); err != nil {
+		return err
+	}
+	return o.CreateRouteSubcommandOptions.waitForAdmission(route)
+}
@@ -0,0 +1,97 @@
+package create
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// validateRouteTLS checks the certificate, key and CA certificate of a route before it is created,
+// since the router only reports invalid TLS material once it tries to serve the route. The key
+// must match the certificate, the certificate must be valid at now, and when a CA certificate
+// is set the certificate and the intermediates that follow it must chain to it.
+func validateRouteTLS(cert, key, caCert string, now time.Time) error {
+	if len(caCert) > 0 {
+		if _, err := parseCertificates(caCert); err != nil {
+			return fmt.Errorf("invalid CA certificate: %v", err)
+		}
+	}
+	if len(cert) == 0 {
+		if len(key) > 0 {
+			return fmt.Errorf("a key was provided without a certificate")
+		}
+		return nil
+	}
+	certs, err := parseCertificates(cert)
+	if err != nil {
+		return fmt.Errorf("invalid certificate: %v", err)
+	}
+	leaf := certs[0]
+	if len(key) > 0 {
+		if _, err := tls.X509KeyPair([]byte(cert), []byte(key)); err != nil {
+			return fmt.Errorf("the key does not match the certificate for %s: %v", leaf.Subject, err)
+		}
+	}
+	switch {
+	case now.After(leaf.NotAfter):
+		return fmt.Errorf("the certificate for %s expired on %s", leaf.Subject, leaf.NotAfter.Format(time.RFC3339))
+	case now.Before(leaf.NotBefore):
+		return fmt.Errorf("the certificate for %s is not valid before %s", leaf.Subject, leaf.NotBefore.Format(time.RFC3339))
+	}
+	if len(caCert) == 0 {
+		return nil
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM([]byte(caCert))
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("the certificate for %s is not signed by the CA certificate: %v", leaf.Subject, err)
+	}
+	return nil
+}
+
+// validateCABundle checks that data holds at least one PEM encoded certificate.
+func validateCABundle(description, data string) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if _, err := parseCertificates(data); err != nil {
+		return fmt.Errorf("invalid %s: %v", description, err)
+	}
+	return nil
+}
+
+// parseCertificates returns the PEM encoded certificates in data, in order.
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificates found")
+	}
+	return certs, nil
+}
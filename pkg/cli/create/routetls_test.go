@@ -0,0 +1,105 @@
+package create
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+	// keyPEM is the PEM encoded private key
+	keyPEM string
+}
+
+func newTestCertificate(t *testing.T, name string, notBefore, notAfter time.Time, isCA bool, signer *testCertificate) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	parent, parentKey := template, key
+	if signer != nil {
+		parent, parentKey = signer.cert, signer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCertificate{
+		cert:   cert,
+		key:    key,
+		pem:    string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		keyPEM: string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+	}
+}
+
+func TestValidateRouteTLS(t *testing.T) {
+	now := time.Now()
+	year := 365 * 24 * time.Hour
+	ca := newTestCertificate(t, "root", now.Add(-year), now.Add(year), true, nil)
+	intermediate := newTestCertificate(t, "intermediate", now.Add(-year), now.Add(year), true, ca)
+	serving := newTestCertificate(t, "www.example.com", now.Add(-time.Hour), now.Add(year), false, intermediate)
+	expired := newTestCertificate(t, "expired.example.com", now.Add(-year), now.Add(-time.Hour), false, ca)
+	future := newTestCertificate(t, "future.example.com", now.Add(time.Hour), now.Add(year), false, ca)
+	otherCA := newTestCertificate(t, "other", now.Add(-year), now.Add(year), true, nil)
+
+	tests := []struct {
+		name   string
+		cert   string
+		key    string
+		caCert string
+		err    string
+	}{
+		{name: "no TLS material"},
+		{name: "certificate and key", cert: serving.pem + intermediate.pem, key: serving.keyPEM},
+		{name: "chain verifies against the CA", cert: serving.pem + intermediate.pem, key: serving.keyPEM, caCert: ca.pem},
+		{name: "key of another certificate", cert: serving.pem, key: expired.keyPEM, err: "the key does not match the certificate for CN=www.example.com"},
+		{name: "key without certificate", key: serving.keyPEM, err: "a key was provided without a certificate"},
+		{name: "not PEM", cert: "certificate", err: "invalid certificate: no PEM encoded certificates found"},
+		{name: "invalid CA", cert: serving.pem, caCert: serving.keyPEM, err: "invalid CA certificate"},
+		{name: "expired", cert: expired.pem, err: "the certificate for CN=expired.example.com expired on"},
+		{name: "not yet valid", cert: future.pem, err: "the certificate for CN=future.example.com is not valid before"},
+		{name: "missing intermediate", cert: serving.pem, caCert: ca.pem, err: "is not signed by the CA certificate"},
+		{name: "other CA", cert: serving.pem + intermediate.pem, caCert: otherCA.pem, err: "is not signed by the CA certificate"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateRouteTLS(test.cert, test.key, test.caCert, now)
+			if len(test.err) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
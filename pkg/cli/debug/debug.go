@@ -3,6 +3,7 @@ package debug
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -58,6 +60,8 @@ import (
 const (
 	debugPodAnnotationSourceContainer = "debug.openshift.io/source-container"
 	debugPodAnnotationSourceResource  = "debug.openshift.io/source-resource"
+	// debugPodLabelPreserved is set on debug pods that are not deleted when the command exits
+	debugPodLabelPreserved = "debug.openshift.io/preserved"
 	// containerResourcesAnnotationPrefix contains resource annotation prefix that will be used by CRI-O to set cpu shares
 	containerResourcesAnnotationPrefix = "resources.workload.openshift.io/"
 	// podWorkloadTargetAnnotationPrefix contains the prefix for the pod workload target annotation
@@ -93,7 +97,24 @@ var (
 		'--image=IMAGE' to start a simple shell session in an image with a shell program
 
 		The debug pod is deleted when the remote command completes or the user interrupts
-		the shell.
+		the shell. Use --grace-period to give the debug pod time to shut down when it is deleted.
+		If the pod cannot be deleted the command prints how to remove it. Pass --preserve-pod
+		to keep it for further 'oc exec' or 'oc cp' commands.
+		Preserved debug pods are labeled debug.openshift.io/preserved=true so that they can
+		be deleted together later.
+
+		To debug a pod without restarting it, pass --ephemeral-container. An ephemeral container
+		running the debug image and command is added to the running pod and attached to. It
+		shares the process namespace of the container selected with -c, which defaults to the
+		first container. Ephemeral containers cannot be removed from a pod, so the container
+		remains (stopped once the command exits) until the pod itself is deleted. The cluster
+		must have ephemeral containers enabled.
+
+		Pass --preserve-volumes to reproduce failures that depend on storage or configuration. The
+		debugged container then mounts every volume that any container of the source pod mounts,
+		including those of containers removed with --one-container, at the same paths. A warning is
+		printed for ReadWriteOnce persistent volume claims that are mounted by a pod on a node,
+		since the debug pod can then only run on that node.
 	`)
 
 	debugExample = templates.Examples(`
@@ -115,9 +136,28 @@ var (
 		# Debug a specific failing container by running the env command in the 'second' container
 		oc debug daemonset/test -c second -- /bin/env
 
+		# Give the debug pod of a node 30 seconds to shut down when the shell exits
+		oc debug node/master-1 --grace-period=30
+
+		# Keep the debug pod of a deployment after the shell exits, then delete all preserved debug pods
+		oc debug deploy/test --preserve-pod
+		oc delete pods -l debug.openshift.io/preserved=true
+
+		# Step through the initialization of a pod by debugging its 'setup' init container with another image
+		oc debug pod/mypod-9xbc -c setup --image=registry.example.com/tools
+
+		# Debug a crashing pod with the volumes and mounts of all of its containers
+		oc debug pod/mypod-9xbc --one-container --preserve-volumes
+
+		# Start the debug shell of a deployment in the directory of its data volume
+		oc debug deploy/test --workdir=/var/lib/data
+
 		# See the pod that would be created to debug
 		oc debug mypod-9xbc -o yaml
 
+		# Attach an ephemeral debug container to the running pod mypod-9xbc, targeting container 'app'
+		oc debug pod/mypod-9xbc --ephemeral-container -c app --image=registry.example.com/tools
+
 		# Debug a resource but launch the debug pod in another namespace
 		# Note: Not all resources can be debugged using --to-namespace without modification. For example,
 		# volumes and service accounts are namespace-dependent. Add '-o yaml' to output the debug pod definition
@@ -140,6 +180,7 @@ type DebugOptions struct {
 	RESTClientGetter genericclioptions.RESTClientGetter
 
 	PreservePod bool
+	GracePeriod int64
 	NoStdin     bool
 	ForceTTY    bool
 	DisableTTY  bool
@@ -157,6 +198,8 @@ type DebugOptions struct {
 	KeepStartup        bool
 	KeepInitContainers bool
 	OneContainer       bool
+	PreserveVolumes    bool
+	Workdir            string
 	NodeName           string
 	NodeNameSet        bool
 	AddEnv             []corev1.EnvVar
@@ -170,6 +213,7 @@ type DebugOptions struct {
 	Image              string
 	ImageStream        string
 	ToNamespace        string
+	EphemeralContainer bool
 
 	// IsNode is set after we see the object we're debugging.  We use it to be able to print pertinent advice.
 	IsNode bool
@@ -228,10 +272,12 @@ func NewCmdDebug(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 	cmd.Flags().BoolVar(&o.KeepAnnotations, "keep-annotations", o.KeepAnnotations, "If true, keep the original pod annotations")
 	cmd.Flags().BoolVar(&o.KeepLabels, "keep-labels", o.KeepLabels, "If true, keep the original pod labels")
 	cmd.Flags().BoolVar(&o.KeepLiveness, "keep-liveness", o.KeepLiveness, "If true, keep the original pod liveness probes")
-	cmd.Flags().BoolVar(&o.KeepInitContainers, "keep-init-containers", o.KeepInitContainers, "Run the init containers for the pod. Defaults to true.")
+	cmd.Flags().BoolVar(&o.KeepInitContainers, "keep-init-containers", o.KeepInitContainers, "If true, keep the init containers of the pod so that they run before the debug container, and an init container selected with -c is debugged in place. If false, remove them except for the one selected with -c. Defaults to true.")
 	cmd.Flags().BoolVar(&o.KeepReadiness, "keep-readiness", o.KeepReadiness, "If true, keep the original pod readiness probes")
 	cmd.Flags().BoolVar(&o.KeepStartup, "keep-startup", o.KeepStartup, "If true, keep the original startup probes")
 	cmd.Flags().BoolVar(&o.OneContainer, "one-container", o.OneContainer, "If true, run only the selected container, remove all others")
+	cmd.Flags().BoolVar(&o.PreserveVolumes, "preserve-volumes", o.PreserveVolumes, "If true, mount every volume mounted by a container of the pod into the debugged container, and warn about ReadWriteOnce claims in use elsewhere.")
+	cmd.Flags().StringVar(&o.Workdir, "workdir", o.Workdir, "Set the working directory of the debug container, in which the shell or command is started.")
 	cmd.Flags().StringVar(&o.NodeName, "node-name", o.NodeName, "Set a specific node to run on - by default the pod will run on any valid node")
 	cmd.Flags().BoolVar(&o.AsRoot, "as-root", o.AsRoot, "If true, try to run the container as the root user")
 	cmd.Flags().Int64Var(&o.AsUser, "as-user", o.AsUser, "Try to run the container as a specific user UID (note: admins may limit your ability to use this flag)")
@@ -239,6 +285,8 @@ func NewCmdDebug(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 	cmd.Flags().StringVar(&o.ImageStream, "image-stream", o.ImageStream, "Specify an image stream (namespace/name:tag) containing a debug image to run.")
 	cmd.Flags().StringVar(&o.ToNamespace, "to-namespace", o.ToNamespace, "Override the namespace to create the pod into (instead of using --namespace).")
 	cmd.Flags().BoolVar(&o.PreservePod, "preserve-pod", o.PreservePod, "If true, the pod will not be deleted after the debug command exits.")
+	cmd.Flags().Int64Var(&o.GracePeriod, "grace-period", o.GracePeriod, "Period of time in seconds given to the debug pod to terminate gracefully when it is deleted. Defaults to 0, deleting the pod immediately.")
+	cmd.Flags().BoolVar(&o.EphemeralContainer, "ephemeral-container", o.EphemeralContainer, "If true, add an ephemeral debug container to the running pod instead of creating a copy of it. The container is removed only when the pod is deleted.")
 
 	o.PrintFlags.AddFlags(cmd)
 	kcmdutil.AddDryRunFlag(cmd)
@@ -362,6 +410,26 @@ func (o DebugOptions) Validate() error {
 	if (o.AsRoot || o.AsNonRoot) && o.AsUser > 0 {
 		return fmt.Errorf("you may not specify --as-root and --as-user=%d at the same time", o.AsUser)
 	}
+	if o.GracePeriod < 0 {
+		return fmt.Errorf("--grace-period must be zero or greater")
+	}
+	if o.EphemeralContainer {
+		switch {
+		case len(o.Resources) == 0 && len(o.FilenameOptions.Filenames) == 0:
+			return fmt.Errorf("--ephemeral-container requires a running pod to debug")
+		case len(o.ToNamespace) > 0:
+			return fmt.Errorf("--ephemeral-container and --to-namespace may not be specified together")
+		case o.NodeNameSet:
+			return fmt.Errorf("--ephemeral-container and --node-name may not be specified together")
+		case o.OneContainer:
+			return fmt.Errorf("--ephemeral-container and --one-container may not be specified together")
+		case o.PreserveVolumes:
+			return fmt.Errorf("--ephemeral-container and --preserve-volumes may not be specified together")
+		}
+	}
+	if o.PreserveVolumes && len(o.ToNamespace) > 0 {
+		return fmt.Errorf("--preserve-volumes and --to-namespace may not be specified together, volumes refer to objects in the namespace of the pod")
+	}
 	return nil
 }
 
@@ -371,19 +439,9 @@ func (o *DebugOptions) RunDebug() error {
 
 	// the simplest possible debug is an image
 	if len(o.Resources) == 0 && len(o.FilenameOptions.Filenames) == 0 {
-		image := o.Image
-
-		if len(image) == 0 {
-			imageStream := o.ImageStream
-			if len(imageStream) == 0 {
-				imageStream = "openshift/tools:latest"
-			}
-			imageFromStream, err := o.resolveImageStreamTagString(imageStream)
-			if err != nil {
-				return fmt.Errorf("unable to resolve a default pod image from image stream %s: %v", imageStream, err)
-			}
-			image = imageFromStream
-			klog.V(4).Infof("Defaulted image from imagestream %s: %s", imageStream, image)
+		image, err := o.debugImage()
+		if err != nil {
+			return err
 		}
 
 		infos = append(infos, &resource.Info{
@@ -424,6 +482,9 @@ func (o *DebugOptions) RunDebug() error {
 		klog.V(4).Infof("Objects: %#v", infos)
 		return fmt.Errorf("you must identify a single resource with a pod template to debug")
 	}
+	if o.EphemeralContainer {
+		return o.runEphemeralContainer(infos[0])
+	}
 
 	template, err := o.approximatePodTemplateForObject(infos[0].Object)
 	if err != nil && template == nil {
@@ -490,6 +551,10 @@ func (o *DebugOptions) RunDebug() error {
 		return nil
 	}
 
+	if o.PreserveVolumes {
+		o.warnAboutClaimsInUse(pod)
+	}
+
 	klog.V(5).Infof("Creating pod: %#v", pod)
 	pod, err = o.createPod(pod)
 	if err != nil {
@@ -500,24 +565,20 @@ func (o *DebugOptions) RunDebug() error {
 	o.Attach.InterruptParent = interrupt.New(
 		func(os.Signal) { os.Exit(1) },
 		func() {
-			if o.PreservePod {
-				return
-			}
 			stderr := o.ErrOut
 			if stderr == nil {
 				stderr = os.Stderr
 			}
+			if o.PreservePod {
+				if !o.Attach.Quiet {
+					fmt.Fprintf(stderr, "\nPreserving debug pod/%s, to remove it run:\n  oc delete pod %s -n %s\n", pod.Name, pod.Name, pod.Namespace)
+				}
+				return
+			}
 			if !o.Attach.Quiet {
 				fmt.Fprintf(stderr, "\nRemoving debug pod ...\n")
 			}
-			if err := o.CoreClient.Pods(pod.Namespace).Delete(context.TODO(), pod.Name, *metav1.NewDeleteOptions(0)); err != nil {
-				if !kapierrors.IsNotFound(err) {
-					klog.V(2).Infof("Unable to delete the debug pod %q: %v", pod.Name, err)
-					if !o.Attach.Quiet {
-						fmt.Fprintf(stderr, "error: unable to delete the debug pod %q: %v\n", pod.Name, err)
-					}
-				}
-			}
+			o.deletePod(pod, stderr)
 		},
 	)
 
@@ -623,6 +684,24 @@ func (o *DebugOptions) RunDebug() error {
 	})
 }
 
+// debugImage returns the image set with --image, or the image that --image-stream (or the
+// default tools image stream) points to.
+func (o *DebugOptions) debugImage() (string, error) {
+	if len(o.Image) > 0 {
+		return o.Image, nil
+	}
+	imageStream := o.ImageStream
+	if len(imageStream) == 0 {
+		imageStream = "openshift/tools:latest"
+	}
+	image, err := o.resolveImageStreamTagString(imageStream)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve a default pod image from image stream %s: %v", imageStream, err)
+	}
+	klog.V(4).Infof("Defaulted image from imagestream %s: %s", imageStream, image)
+	return image, nil
+}
+
 // getContainerImageViaDeploymentConfig attempts to return an Image for a given
 // Container.  It tries to walk from the Container's Pod to its DeploymentConfig
 // (via the "openshift.io/deployment-config.name" annotation), then tries to
@@ -757,6 +836,9 @@ func (o *DebugOptions) transformPodForDebug(annotations map[string]string) (*cor
 	command := o.getContainerCommand()
 	container.Command = command
 	container.Args = nil
+	if len(o.Workdir) > 0 {
+		container.WorkingDir = o.Workdir
+	}
 	container.TTY = o.Attach.Stdin && o.Attach.TTY
 	container.Stdin = o.Attach.Stdin
 	container.StdinOnce = o.Attach.Stdin
@@ -815,6 +897,10 @@ func (o *DebugOptions) transformPodForDebug(annotations map[string]string) (*cor
 		pod.Spec.SecurityContext.RunAsNonRoot = nil
 	}
 
+	if o.PreserveVolumes {
+		preserveVolumeMounts(pod, container)
+	}
+
 	switch {
 	case o.OneContainer:
 		pod.Spec.InitContainers = nil
@@ -853,6 +939,9 @@ func (o *DebugOptions) transformPodForDebug(annotations map[string]string) (*cor
 	} else {
 		pod.Labels = map[string]string{}
 	}
+	if o.PreservePod {
+		pod.Labels[debugPodLabelPreserved] = "true"
+	}
 
 	pod.ResourceVersion = ""
 	pod.Spec.RestartPolicy = corev1.RestartPolicyNever
@@ -868,6 +957,116 @@ func (o *DebugOptions) transformPodForDebug(annotations map[string]string) (*cor
 	return pod, originalCommand
 }
 
+// preserveVolumeMounts adds the volume mounts and devices of every other container of pod to
+// container, unless container already uses the same path.
+func preserveVolumeMounts(pod *corev1.Pod, container *corev1.Container) {
+	mountPaths := sets.NewString()
+	for _, mount := range container.VolumeMounts {
+		mountPaths.Insert(mount.MountPath)
+	}
+	devicePaths := sets.NewString()
+	for _, device := range container.VolumeDevices {
+		devicePaths.Insert(device.DevicePath)
+	}
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range containers {
+			for _, mount := range c.VolumeMounts {
+				if !mountPaths.Has(mount.MountPath) {
+					mountPaths.Insert(mount.MountPath)
+					container.VolumeMounts = append(container.VolumeMounts, mount)
+				}
+			}
+			for _, device := range c.VolumeDevices {
+				if !devicePaths.Has(device.DevicePath) {
+					devicePaths.Insert(device.DevicePath)
+					container.VolumeDevices = append(container.VolumeDevices, device)
+				}
+			}
+		}
+	}
+}
+
+// warnAboutClaimsInUse warns about the ReadWriteOnce persistent volume claims of pod that are
+// mounted by a pod scheduled to a node, because the debug pod can then only run on that node.
+func (o *DebugOptions) warnAboutClaimsInUse(pod *corev1.Pod) {
+	var pods *corev1.PodList
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claimName := volume.PersistentVolumeClaim.ClaimName
+		claim, err := o.CoreClient.PersistentVolumeClaims(pod.Namespace).Get(context.TODO(), claimName, metav1.GetOptions{})
+		if err != nil {
+			klog.V(4).Infof("Unable to check persistent volume claim %s: %v", claimName, err)
+			continue
+		}
+		if !isReadWriteOnce(claim) {
+			continue
+		}
+		if pods == nil {
+			pods, err = o.CoreClient.Pods(pod.Namespace).List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				klog.V(4).Infof("Unable to list the pods using persistent volume claims: %v", err)
+				return
+			}
+		}
+		for _, other := range pods.Items {
+			if other.Name == pod.Name || len(other.Spec.NodeName) == 0 || other.Status.Phase == corev1.PodSucceeded || other.Status.Phase == corev1.PodFailed || !usesClaim(&other, claimName) {
+				continue
+			}
+			if o.NodeNameSet && o.NodeName == other.Spec.NodeName {
+				break
+			}
+			fmt.Fprintf(o.ErrOut, "warning: persistent volume claim %s is ReadWriteOnce and mounted by pod/%s on node %s, the debug pod may not be scheduled unless it runs there (--node-name=%s)\n", claimName, other.Name, other.Spec.NodeName, other.Spec.NodeName)
+			break
+		}
+	}
+}
+
+// isReadWriteOnce returns true if claim can only be mounted read-write on a single node.
+func isReadWriteOnce(claim *corev1.PersistentVolumeClaim) bool {
+	modes := claim.Spec.AccessModes
+	if len(claim.Status.AccessModes) > 0 {
+		modes = claim.Status.AccessModes
+	}
+	once := false
+	for _, mode := range modes {
+		switch mode {
+		case corev1.ReadWriteMany, corev1.ReadOnlyMany:
+			return false
+		case corev1.ReadWriteOnce, corev1.ReadWriteOncePod:
+			once = true
+		}
+	}
+	return once
+}
+
+func usesClaim(pod *corev1.Pod, claimName string) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName {
+			return true
+		}
+	}
+	return false
+}
+
+// deletePod deletes the debug pod with the requested grace period, and tries once more if that
+// fails. The user is told how to delete the pod if both attempts fail.
+func (o *DebugOptions) deletePod(pod *corev1.Pod, stderr io.Writer) {
+	var err error
+	for i := 0; i < 2; i++ {
+		err = o.CoreClient.Pods(pod.Namespace).Delete(context.TODO(), pod.Name, *metav1.NewDeleteOptions(o.GracePeriod))
+		if err == nil || kapierrors.IsNotFound(err) {
+			if !o.Attach.Quiet {
+				fmt.Fprintf(stderr, "Removed debug pod/%s\n", pod.Name)
+			}
+			return
+		}
+		klog.V(2).Infof("Unable to delete the debug pod %q: %v", pod.Name, err)
+	}
+	fmt.Fprintf(stderr, "error: unable to delete the debug pod %q: %v, to remove it run:\n  oc delete pod %s -n %s\n", pod.Name, err, pod.Name, pod.Namespace)
+}
+
 // createPod creates the debug pod, and will attempt to delete an existing debug
 // pod with the same name, but will return an error in any other case.
 func (o *DebugOptions) createPod(pod *corev1.Pod) (*corev1.Pod, error) {
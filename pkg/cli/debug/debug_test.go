@@ -0,0 +1,215 @@
+package debug
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestTransformPodForDebugPreservePod(t *testing.T) {
+	for _, preserve := range []bool{false, true} {
+		o := NewDebugOptions(genericclioptions.NewTestIOStreamsDiscard())
+		o.PreservePod = preserve
+		o.Command = []string{"/bin/sh"}
+		pod := runningPod()
+		pod.Labels = map[string]string{"app": "web"}
+		pod.Spec.Containers[0].Command = []string{"/usr/bin/web"}
+		o.Attach.Pod = pod
+		o.Attach.ContainerName = "app"
+
+		debugPod, _ := o.transformPodForDebug(nil)
+		if _, ok := debugPod.Labels["app"]; ok {
+			t.Errorf("expected the original labels to be removed: %v", debugPod.Labels)
+		}
+		if got := debugPod.Labels[debugPodLabelPreserved] == "true"; got != preserve {
+			t.Errorf("expected preserved label %t, got labels %v", preserve, debugPod.Labels)
+		}
+	}
+}
+
+func TestTransformPodForDebugInitContainers(t *testing.T) {
+	tests := []struct {
+		name               string
+		container          string
+		keepInitContainers bool
+		image              string
+		expectInit         []string
+		expectContainers   []string
+	}{
+		{name: "keep init containers", container: "app", keepInitContainers: true, expectInit: []string{"prepare", "setup"}, expectContainers: []string{"app", "debug"}},
+		{name: "remove init containers", container: "app", expectContainers: []string{"app", "debug"}},
+		{name: "debug an init container in place", container: "setup", keepInitContainers: true, image: "tools:latest", expectInit: []string{"prepare", "setup"}, expectContainers: []string{"app", "debug"}},
+		{name: "debug only the selected init container", container: "setup", image: "tools:latest", expectInit: []string{"setup"}, expectContainers: []string{"app", "debug"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := NewDebugOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.KeepInitContainers = test.keepInitContainers
+			o.Image = test.image
+			o.Command = []string{"/bin/sh"}
+			pod := runningPod()
+			pod.Spec.Containers[0].Command = []string{"/usr/bin/web"}
+			pod.Spec.InitContainers = []corev1.Container{
+				{Name: "prepare", Image: "prepare:latest", Command: []string{"/prepare"}},
+				{Name: "setup", Image: "setup:latest", Command: []string{"/setup"}},
+			}
+			o.Attach.Pod = pod
+			o.Attach.ContainerName = test.container
+
+			debugPod, _ := o.transformPodForDebug(nil)
+			var initNames, names []string
+			for _, c := range debugPod.Spec.InitContainers {
+				initNames = append(initNames, c.Name)
+			}
+			for _, c := range debugPod.Spec.Containers {
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(initNames, test.expectInit) || !reflect.DeepEqual(names, test.expectContainers) {
+				t.Fatalf("expected init containers %v and containers %v, got %v and %v", test.expectInit, test.expectContainers, initNames, names)
+			}
+
+			debugged := containerForName(debugPod, test.container)
+			if !reflect.DeepEqual(debugged.Command, o.Command) {
+				t.Errorf("expected %s to run the debug command, got %v", test.container, debugged.Command)
+			}
+			if len(test.image) > 0 && debugged.Image != test.image {
+				t.Errorf("expected %s to use image %s, got %s", test.container, test.image, debugged.Image)
+			}
+			for _, c := range debugPod.Spec.InitContainers {
+				if c.Name != test.container && len(c.Command) > 0 && c.Command[0] == "/bin/sh" {
+					t.Errorf("expected init container %s to keep its command", c.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestDeletePod(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int
+		wantDeletes int
+		wantOut     string
+	}{
+		{name: "deleted", wantDeletes: 1, wantOut: "Removed debug pod/test-debug"},
+		{name: "deleted on retry", failures: 1, wantDeletes: 2, wantOut: "Removed debug pod/test-debug"},
+		{name: "not deleted", failures: 2, wantDeletes: 2, wantOut: "to remove it run:\n  oc delete pod test-debug -n test"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-debug", Namespace: "test"}}
+			client := fake.NewSimpleClientset(pod)
+			deletes := 0
+			client.PrependReactor("delete", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				deletes++
+				if gracePeriod := action.(clienttesting.DeleteAction).GetDeleteOptions().GracePeriodSeconds; gracePeriod == nil || *gracePeriod != 30 {
+					t.Errorf("unexpected grace period %v", gracePeriod)
+				}
+				if deletes <= test.failures {
+					return true, nil, fmt.Errorf("connection refused")
+				}
+				return false, nil, nil
+			})
+
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := NewDebugOptions(streams)
+			o.CoreClient = client.CoreV1()
+			o.GracePeriod = 30
+			o.deletePod(pod, errOut)
+			if deletes != test.wantDeletes {
+				t.Errorf("expected %d deletes, got %d", test.wantDeletes, deletes)
+			}
+			if !strings.Contains(errOut.String(), test.wantOut) {
+				t.Errorf("expected output %q, got %q", test.wantOut, errOut.String())
+			}
+		})
+	}
+}
+
+func TestTransformPodForDebugPreserveVolumes(t *testing.T) {
+	for _, preserve := range []bool{false, true} {
+		o := NewDebugOptions(genericclioptions.NewTestIOStreamsDiscard())
+		o.OneContainer = true
+		o.PreserveVolumes = preserve
+		o.Command = []string{"/bin/sh"}
+		pod := runningPod()
+		pod.Spec.Volumes = []corev1.Volume{{Name: "config"}, {Name: "data"}, {Name: "cache"}}
+		pod.Spec.InitContainers = []corev1.Container{
+			{Name: "setup", VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}},
+		}
+		pod.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "config", MountPath: "/etc/app"}}
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+			Name: "sidecar",
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "data", MountPath: "/data", SubPath: "sidecar"},
+				{Name: "cache", MountPath: "/etc/app"},
+			},
+		})
+		o.Attach.Pod = pod
+		o.Attach.ContainerName = "app"
+
+		debugPod, _ := o.transformPodForDebug(nil)
+		if len(debugPod.Spec.Containers) != 1 || len(debugPod.Spec.Volumes) != 3 {
+			t.Fatalf("unexpected debug pod: %#v", debugPod.Spec)
+		}
+		expected := []corev1.VolumeMount{{Name: "config", MountPath: "/etc/app"}}
+		if preserve {
+			expected = append(expected,
+				corev1.VolumeMount{Name: "cache", MountPath: "/cache"},
+				corev1.VolumeMount{Name: "data", MountPath: "/data", SubPath: "sidecar"},
+			)
+		}
+		if mounts := debugPod.Spec.Containers[0].VolumeMounts; !reflect.DeepEqual(mounts, expected) {
+			t.Errorf("expected mounts %v with preserve %t, got %v", expected, preserve, mounts)
+		}
+	}
+}
+
+func TestWarnAboutClaimsInUse(t *testing.T) {
+	claim := func(name string, mode corev1.PersistentVolumeAccessMode) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Spec:       corev1.PersistentVolumeClaimSpec{AccessModes: []corev1.PersistentVolumeAccessMode{mode}},
+		}
+	}
+	podUsing := func(name, node string, claims ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"}, Spec: corev1.PodSpec{NodeName: node}}
+		for _, c := range claims {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{Name: c, VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: c},
+			}})
+		}
+		return pod
+	}
+
+	client := fake.NewSimpleClientset(
+		claim("rwo", corev1.ReadWriteOnce),
+		claim("rwx", corev1.ReadWriteMany),
+		claim("unused", corev1.ReadWriteOnce),
+		podUsing("web-1", "node-a", "rwo", "rwx"),
+	)
+	streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+	o := NewDebugOptions(streams)
+	o.CoreClient = client.CoreV1()
+	o.warnAboutClaimsInUse(podUsing("web-1-debug", "", "rwo", "rwx", "unused"))
+
+	warnings := strings.Split(strings.TrimSpace(errOut.String()), "\n")
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "persistent volume claim rwo is ReadWriteOnce and mounted by pod/web-1 on node node-a") {
+		t.Errorf("unexpected warnings: %q", errOut.String())
+	}
+
+	errOut.Reset()
+	o.NodeName, o.NodeNameSet = "node-a", true
+	o.warnAboutClaimsInUse(podUsing("web-1-debug", "", "rwo"))
+	if errOut.Len() > 0 {
+		t.Errorf("expected no warning on the node of the claim, got %q", errOut.String())
+	}
+}
@@ -0,0 +1,190 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+
+	"github.com/openshift/oc/pkg/helpers/conditions"
+)
+
+// ephemeralContainerName is the name of the debug container added to a running pod,
+// suffixed with a number if the pod already has a container with that name.
+const ephemeralContainerName = "debug"
+
+// runEphemeralContainer adds an ephemeral debug container to the running pod described by
+// info and attaches to it. Ephemeral containers cannot be removed, so nothing is cleaned
+// up on exit - the container stays in the pod spec until the pod is deleted.
+func (o *DebugOptions) runEphemeralContainer(info *resource.Info) error {
+	pod, ok := info.Object.(*corev1.Pod)
+	if !ok {
+		return fmt.Errorf("--ephemeral-container can only be used to debug a pod, not %s/%s", info.Mapping.Resource.Resource, info.Name)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return fmt.Errorf("pod %q is %s: ephemeral containers can only be added to running pods", pod.Name, pod.Status.Phase)
+	}
+
+	if len(o.Attach.ContainerName) == 0 && len(pod.Spec.Containers) > 0 {
+		o.Attach.ContainerName = pod.Spec.Containers[0].Name
+	}
+	target := o.Attach.ContainerName
+	found := false
+	for _, c := range pod.Spec.Containers {
+		if c.Name == target {
+			found = true
+			break
+		}
+	}
+	if !found {
+		var names []string
+		for _, c := range pod.Spec.Containers {
+			names = append(names, c.Name)
+		}
+		return fmt.Errorf("the container %q is not a valid container name; must be one of %v", target, names)
+	}
+
+	image, err := o.debugImage()
+	if err != nil {
+		return err
+	}
+
+	o.Attach.Pod = pod
+	container := o.ephemeralContainerForPod(pod, image, target)
+	debugPod := pod.DeepCopy()
+	debugPod.Spec.EphemeralContainers = append(debugPod.Spec.EphemeralContainers, *container)
+
+	if o.Printer != nil {
+		return o.Printer.PrintObj(debugPod, o.Out)
+	}
+
+	if o.DryRun {
+		return nil
+	}
+
+	klog.V(5).Infof("Adding ephemeral container: %#v", container)
+	updated, err := o.addEphemeralContainer(pod, debugPod)
+	if err != nil {
+		return err
+	}
+	o.Attach.Pod = updated
+	o.Attach.ContainerName = container.Name
+
+	if !o.Attach.Quiet {
+		fmt.Fprintf(o.ErrOut, "Starting ephemeral container %s in pod/%s, targeting container %s ...\n", container.Name, pod.Name, target)
+	}
+
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", pod.Name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return o.CoreClient.Pods(pod.Namespace).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return o.CoreClient.Pods(pod.Namespace).Watch(context.TODO(), options)
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+	defer cancel()
+	_, err = watchtools.UntilWithSync(ctx, lw, &corev1.Pod{}, nil, conditions.PodContainerRunning(container.Name, o.CoreClient, nil))
+	switch {
+	case err == conditions.ErrContainerTerminated:
+		return o.getLogs(updated)
+	case err == conditions.ErrNonZeroExitCode:
+		if err = o.getLogs(updated); err != nil {
+			return err
+		}
+		return conditions.ErrNonZeroExitCode
+	case err != nil:
+		return err
+	case !o.Attach.Stdin:
+		return o.getLogs(updated)
+	default:
+		return o.Attach.Run()
+	}
+}
+
+// ephemeralContainerForPod returns the debug container to add to pod, running the debug
+// command in image and sharing the process namespace of the target container.
+func (o *DebugOptions) ephemeralContainerForPod(pod *corev1.Pod, image, target string) *corev1.EphemeralContainer {
+	existing := sets.NewString(containerNames(pod)...)
+	for _, c := range pod.Spec.EphemeralContainers {
+		existing.Insert(c.Name)
+	}
+	name := ephemeralContainerName
+	for i := 1; existing.Has(name); i++ {
+		name = fmt.Sprintf("%s-%d", ephemeralContainerName, i)
+	}
+
+	container := &corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			Command:                  o.getContainerCommand(),
+			WorkingDir:               o.Workdir,
+			Env:                      o.AddEnv,
+			TTY:                      o.Attach.Stdin && o.Attach.TTY,
+			Stdin:                    o.Attach.Stdin,
+			StdinOnce:                o.Attach.Stdin,
+			TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+		},
+		TargetContainerName: target,
+	}
+
+	switch {
+	case o.AsNonRoot:
+		b := true
+		container.SecurityContext = &corev1.SecurityContext{RunAsNonRoot: &b}
+	case o.AsRoot:
+		zero := int64(0)
+		container.SecurityContext = &corev1.SecurityContext{RunAsUser: &zero}
+	case o.AsUser != -1:
+		container.SecurityContext = &corev1.SecurityContext{RunAsUser: &o.AsUser}
+	}
+	return container
+}
+
+// addEphemeralContainer patches the ephemeralcontainers subresource of pod so that its
+// ephemeral containers match debugPod, and returns the updated pod.
+func (o *DebugOptions) addEphemeralContainer(pod, debugPod *corev1.Pod) (*corev1.Pod, error) {
+	podJSON, err := json.Marshal(pod)
+	if err != nil {
+		return nil, err
+	}
+	debugJSON, err := json.Marshal(debugPod)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := strategicpatch.CreateTwoWayMergePatch(podJSON, debugJSON, pod)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create a patch to add the debug container: %v", err)
+	}
+
+	updated, err := o.CoreClient.Pods(pod.Namespace).Patch(context.TODO(), pod.Name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "ephemeralcontainers")
+	if err != nil {
+		// a missing subresource is reported as not found without the name of the pod
+		if status, ok := err.(*kapierrors.StatusError); ok && kapierrors.IsNotFound(err) && (status.ErrStatus.Details == nil || len(status.ErrStatus.Details.Name) == 0) {
+			return nil, fmt.Errorf("ephemeral containers are not enabled on this cluster: %v", err)
+		}
+		if runtime.IsNotRegisteredError(err) {
+			return nil, fmt.Errorf("this cluster does not support the ephemeral containers API used by this client: %v", err)
+		}
+		return nil, err
+	}
+	return updated, nil
+}
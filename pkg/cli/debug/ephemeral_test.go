@@ -0,0 +1,119 @@
+package debug
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func runningPod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app", Image: "app:latest"},
+				{Name: "debug", Image: "sidecar:latest"},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestEphemeralContainerForPod(t *testing.T) {
+	o := NewDebugOptions(genericclioptions.NewTestIOStreamsDiscard())
+	o.Command = []string{"/bin/bash"}
+	o.AddEnv = []corev1.EnvVar{{Name: "A", Value: "1"}}
+	o.AsRoot = true
+	o.Attach.TTY = true
+
+	pod := runningPod()
+	pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debug-1"}}}
+	o.Attach.Pod = pod
+
+	c := o.ephemeralContainerForPod(pod, "tools:latest", "app")
+	if c.Name != "debug-2" {
+		t.Errorf("expected a name that does not conflict with existing containers, got %q", c.Name)
+	}
+	if c.Image != "tools:latest" || c.TargetContainerName != "app" {
+		t.Errorf("unexpected image or target: %#v", c)
+	}
+	if !reflect.DeepEqual(c.Command, []string{"/bin/bash"}) || !reflect.DeepEqual(c.Env, o.AddEnv) {
+		t.Errorf("unexpected command or env: %#v", c)
+	}
+	if !c.Stdin || !c.StdinOnce || !c.TTY {
+		t.Errorf("expected an interactive container: %#v", c)
+	}
+	if c.SecurityContext == nil || c.SecurityContext.RunAsUser == nil || *c.SecurityContext.RunAsUser != 0 {
+		t.Errorf("expected container to run as root: %#v", c.SecurityContext)
+	}
+}
+
+func TestAddEphemeralContainer(t *testing.T) {
+	pod := runningPod()
+	client := fake.NewSimpleClientset(pod)
+	o := NewDebugOptions(genericclioptions.NewTestIOStreamsDiscard())
+	o.CoreClient = client.CoreV1()
+	o.Command = []string{"/bin/sh"}
+	o.Attach.Pod = pod
+
+	debugPod := pod.DeepCopy()
+	debugPod.Spec.EphemeralContainers = append(debugPod.Spec.EphemeralContainers, *o.ephemeralContainerForPod(pod, "tools:latest", "app"))
+	if _, err := o.addEphemeralContainer(pod, debugPod); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var patched bool
+	for _, action := range client.Actions() {
+		patch, ok := action.(clienttesting.PatchAction)
+		if !ok {
+			continue
+		}
+		if patch.GetSubresource() != "ephemeralcontainers" {
+			t.Errorf("expected the ephemeralcontainers subresource to be patched, got %q", patch.GetSubresource())
+		}
+		patched = true
+	}
+	if !patched {
+		t.Fatalf("expected the pod to be patched: %#v", client.Actions())
+	}
+
+	updated, err := client.CoreV1().Pods("test").Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Spec.EphemeralContainers) != 1 {
+		t.Fatalf("expected one ephemeral container: %#v", updated.Spec.EphemeralContainers)
+	}
+	if c := updated.Spec.EphemeralContainers[0]; c.Name != "debug-1" || c.Image != "tools:latest" || c.TargetContainerName != "app" || !reflect.DeepEqual(c.Command, []string{"/bin/sh"}) {
+		t.Errorf("unexpected ephemeral container: %#v", c)
+	}
+}
+
+func TestRunEphemeralContainerRequiresRunningPod(t *testing.T) {
+	o := NewDebugOptions(genericclioptions.NewTestIOStreamsDiscard())
+	o.Image = "tools:latest"
+
+	pod := runningPod()
+	pod.Status.Phase = corev1.PodPending
+	if err := o.runEphemeralContainer(podInfo(pod)); err == nil {
+		t.Errorf("expected an error for a pod that is not running")
+	}
+
+	o.Attach.ContainerName = "missing"
+	if err := o.runEphemeralContainer(podInfo(runningPod())); err == nil {
+		t.Errorf("expected an error for an unknown target container")
+	}
+}
+
+func podInfo(obj runtime.Object) *resource.Info {
+	pod := obj.(*corev1.Pod)
+	return &resource.Info{Name: pod.Name, Namespace: pod.Namespace, Object: obj}
+}
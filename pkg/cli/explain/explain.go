@@ -0,0 +1,166 @@
+package explain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kexplain "k8s.io/kubectl/pkg/cmd/explain"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/explain"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	explainLong = templates.LongDesc(`
+		List the fields for supported resources.
+
+		This command describes the fields associated with each supported API resource.
+		Fields are identified via a simple JSONPath identifier:
+
+			<type>.<fieldName>[.<fieldName>]
+
+		Add the --recursive flag to display all of the fields at once without descriptions.
+		Information about each field is retrieved from the server in OpenAPI format, which
+		includes the published schema of resources defined by custom resource definitions.
+
+		Use --api-version to explain a specific served version of a resource when its API
+		group serves more than one.
+	`)
+
+	explainExample = templates.Examples(`
+		# Get the documentation of the resource and its fields
+		oc explain pods
+
+		# Get the documentation of a specific field of a resource
+		oc explain pods.spec.containers
+
+		# Get all the fields of a route, including the nested ones
+		oc explain routes --recursive
+
+		# Get the documentation of a specific version of a resource
+		oc explain route.spec --api-version=route.openshift.io/v1
+	`)
+)
+
+// ExplainOptions extends the upstream explain options with group aware resolution of
+// --api-version and defaults for schemas without a type or descriptions.
+type ExplainOptions struct {
+	*kexplain.ExplainOptions
+}
+
+func NewExplainOptions(parent string, streams genericclioptions.IOStreams) *ExplainOptions {
+	return &ExplainOptions{
+		ExplainOptions: kexplain.NewExplainOptions(parent, streams),
+	}
+}
+
+// NewCmdExplain returns a cobra command for the documentation of the fields of a resource
+func NewCmdExplain(parent string, f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewExplainOptions(parent, streams)
+
+	cmd := &cobra.Command{
+		Use:                   "explain RESOURCE",
+		DisableFlagsInUseLine: true,
+		Short:                 "Get documentation for a resource",
+		Long:                  explainLong + "\n\n" + kcmdutil.SuggestAPIResources(parent),
+		Example:               explainExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Complete(f, cmd))
+			kcmdutil.CheckErr(o.Validate(args))
+			kcmdutil.CheckErr(o.Run(args))
+		},
+	}
+	cmd.Flags().BoolVar(&o.Recursive, "recursive", o.Recursive, "Print the fields of fields, all the way down the schema.")
+	cmd.Flags().StringVar(&o.APIVersion, "api-version", o.APIVersion, "Get different explanations for particular API version (API group/version)")
+	return cmd
+}
+
+// Run prints the documentation of the requested resource or field
+func (o *ExplainOptions) Run(args []string) error {
+	gvk, fieldsPath, err := resolveResource(o.Mapper, args[0], o.APIVersion)
+	if err != nil {
+		return err
+	}
+
+	resourceSchema := o.Schema.LookupResource(gvk)
+	if resourceSchema == nil {
+		if versions := servedVersions(o.Mapper, gvk.GroupKind()); len(versions) > 0 {
+			return fmt.Errorf("couldn't find the schema of %q, the server publishes it for: %s", gvk, strings.Join(versions, ", "))
+		}
+		return fmt.Errorf("couldn't find resource for %q", gvk)
+	}
+
+	return explain.PrintModelDescription(fieldsPath, o.Out, withDefaults(resourceSchema), gvk, o.Recursive)
+}
+
+// resolveResource returns the kind and field path requested by arg. If apiVersion is set, the
+// resource is looked up in its group, so that resources with the same name in other groups or
+// the preferred version of the group are not used instead.
+func resolveResource(mapper meta.RESTMapper, arg, apiVersion string) (schema.GroupVersionKind, []string, error) {
+	if len(apiVersion) == 0 {
+		gvr, fieldsPath, err := explain.SplitAndParseResourceRequestWithMatchingPrefix(arg, mapper)
+		if err != nil {
+			return schema.GroupVersionKind{}, nil, err
+		}
+		gvk, _ := mapper.KindFor(gvr)
+		if gvk.Empty() {
+			gvk, err = mapper.KindFor(gvr.GroupResource().WithVersion(""))
+			if err != nil {
+				return schema.GroupVersionKind{}, nil, err
+			}
+		}
+		return gvk, fieldsPath, nil
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, nil, err
+	}
+	parts := strings.Split(strings.TrimSuffix(arg, "."), ".")
+	resource, fieldsPath := parts[0], parts[1:]
+
+	gvr, err := mapper.ResourceFor(gv.WithResource(resource))
+	if err != nil {
+		if versions := servedVersions(mapper, schema.GroupKind{Group: gv.Group, Kind: kindFor(mapper, gv.Group, resource)}); len(versions) > 0 {
+			return schema.GroupVersionKind{}, nil, fmt.Errorf("%q is not served in %s, served versions are: %s", resource, gv, strings.Join(versions, ", "))
+		}
+		return schema.GroupVersionKind{}, nil, fmt.Errorf("the server doesn't have a resource type %q in %s", resource, gv)
+	}
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionKind{}, nil, err
+	}
+	return gv.WithKind(gvk.Kind), fieldsPath, nil
+}
+
+// kindFor returns the kind of resource in group in any served version, or an empty string.
+func kindFor(mapper meta.RESTMapper, group, resource string) string {
+	gvk, err := mapper.KindFor(schema.GroupVersionResource{Group: group, Resource: resource})
+	if err != nil {
+		return ""
+	}
+	return gvk.Kind
+}
+
+// servedVersions returns the group versions the server serves the kind in, sorted.
+func servedVersions(mapper meta.RESTMapper, gk schema.GroupKind) []string {
+	if len(gk.Kind) == 0 {
+		return nil
+	}
+	mappings, err := mapper.RESTMappings(gk)
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, mapping := range mappings {
+		versions = append(versions, mapping.GroupVersionKind.GroupVersion().String())
+	}
+	sort.Strings(versions)
+	return versions
+}
@@ -0,0 +1,200 @@
+package explain
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/util/proto"
+	"k8s.io/kubectl/pkg/explain"
+)
+
+func TestResolveResource(t *testing.T) {
+	routeV1 := schema.GroupVersion{Group: "route.openshift.io", Version: "v1"}
+	imageV1 := schema.GroupVersion{Group: "image.openshift.io", Version: "v1"}
+	configV1 := schema.GroupVersion{Group: "config.openshift.io", Version: "v1"}
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{imageV1, configV1, routeV1})
+	mapper.Add(imageV1.WithKind("Image"), meta.RESTScopeRoot)
+	mapper.Add(configV1.WithKind("Image"), meta.RESTScopeRoot)
+	mapper.Add(routeV1.WithKind("Route"), meta.RESTScopeNamespace)
+
+	tests := []struct {
+		name       string
+		arg        string
+		apiVersion string
+		gvk        schema.GroupVersionKind
+		fieldsPath []string
+		err        string
+	}{
+		{name: "preferred group", arg: "images.status", gvk: imageV1.WithKind("Image"), fieldsPath: []string{"status"}},
+		{name: "api version selects group", arg: "images.status", apiVersion: "config.openshift.io/v1", gvk: configV1.WithKind("Image"), fieldsPath: []string{"status"}},
+		{name: "route", arg: "route.spec.tls", apiVersion: "route.openshift.io/v1", gvk: routeV1.WithKind("Route"), fieldsPath: []string{"spec", "tls"}},
+		{name: "unserved version", arg: "route.spec", apiVersion: "route.openshift.io/v2", err: "served versions are: route.openshift.io/v1"},
+		{name: "other group", arg: "route", apiVersion: "config.openshift.io/v1", err: "doesn't have a resource type"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gvk, fieldsPath, err := resolveResource(mapper, tc.arg, tc.apiVersion)
+			if len(tc.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gvk != tc.gvk || !reflect.DeepEqual(fieldsPath, tc.fieldsPath) {
+				t.Errorf("expected %v %v, got %v %v", tc.gvk, tc.fieldsPath, gvk, fieldsPath)
+			}
+		})
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	str := &proto.Primitive{Type: "string"}
+	tls := &proto.Kind{
+		BaseSchema: proto.BaseSchema{Path: proto.NewPath("tls")},
+		Fields: map[string]proto.Schema{
+			"termination": &proto.Primitive{BaseSchema: proto.BaseSchema{Description: "termination indicates termination type."}, Type: "string"},
+			"certificate": str,
+		},
+		RequiredFields: []string{"termination"},
+	}
+	spec := &proto.Kind{
+		BaseSchema: proto.BaseSchema{Description: "spec is the desired state of the route"},
+		Fields: map[string]proto.Schema{
+			"host":   str,
+			"tls":    tls,
+			"plugin": &proto.Arbitrary{},
+			"headers": &proto.Array{SubType: &proto.Kind{
+				Fields: map[string]proto.Schema{"name": str},
+			}},
+		},
+	}
+	route := &proto.Kind{Fields: map[string]proto.Schema{"spec": spec}}
+	gvk := schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+	out := &bytes.Buffer{}
+	if err := explain.PrintModelDescription(nil, out, withDefaults(route), gvk, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := `KIND:     Route
+VERSION:  route.openshift.io/v1
+
+DESCRIPTION:
+     <empty>
+
+FIELDS:
+   spec	<Object>
+      headers	<[]Object>
+         name	<string>
+      host	<string>
+      plugin	<Object>
+      tls	<Object>
+         certificate	<string>
+         termination	<string>
+`
+	if out.String() != expected {
+		t.Errorf("unexpected recursive output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := explain.PrintModelDescription([]string{"spec", "tls"}, out, withDefaults(route), gvk, false); err != nil {
+		t.Fatal(err)
+	}
+	expected = `KIND:     Route
+VERSION:  route.openshift.io/v1
+
+RESOURCE: tls <Object>
+
+DESCRIPTION:
+     <no description>
+
+FIELDS:
+   certificate	<string>
+     <no description>
+
+   termination	<string> -required-
+     termination indicates termination type.
+
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+// testReference refers to a schema by name, like the references of an OpenAPI document.
+type testReference struct {
+	proto.BaseSchema
+	name   string
+	schema func() proto.Schema
+}
+
+func (r *testReference) Accept(v proto.SchemaVisitor) { v.VisitReference(r) }
+func (r *testReference) GetName() string              { return r.name }
+func (r *testReference) Reference() string            { return r.name }
+func (r *testReference) SubSchema() proto.Schema      { return r.schema() }
+
+func TestWithDefaultsRecursiveReference(t *testing.T) {
+	var node *proto.Kind
+	node = &proto.Kind{
+		BaseSchema: proto.BaseSchema{Description: "a node of the tree"},
+		Fields: map[string]proto.Schema{
+			"value":    &proto.Primitive{Type: "string"},
+			"children": &proto.Array{SubType: &testReference{name: "node", schema: func() proto.Schema { return node }}},
+		},
+	}
+	tree := &proto.Kind{Fields: map[string]proto.Schema{
+		"root": &testReference{name: "node", schema: func() proto.Schema { return node }},
+	}}
+	gvk := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Tree"}
+
+	out := &bytes.Buffer{}
+	if err := explain.PrintModelDescription(nil, out, withDefaults(tree), gvk, true); err != nil {
+		t.Fatal(err)
+	}
+	expected := `KIND:     Tree
+VERSION:  example.com/v1
+
+DESCRIPTION:
+     <empty>
+
+FIELDS:
+   root	<Object>
+      children	<[]Object>
+      value	<string>
+`
+	if out.String() != expected {
+		t.Errorf("unexpected recursive output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := explain.PrintModelDescription([]string{"root"}, out, withDefaults(tree), gvk, false); err != nil {
+		t.Fatal(err)
+	}
+	expected = `KIND:     Tree
+VERSION:  example.com/v1
+
+RESOURCE: root <Object>
+
+DESCRIPTION:
+     <no description>
+
+     a node of the tree
+
+FIELDS:
+   children	<[]Object>
+     <no description>
+
+   value	<string>
+     <no description>
+
+`
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,90 @@
+package explain
+
+import (
+	"k8s.io/kube-openapi/pkg/util/proto"
+)
+
+// noDescription is printed in place of an empty field description, which is common in the
+// schemas published by custom resource definitions.
+const noDescription = "<no description>"
+
+// withDefaults returns a copy of s that the upstream printer explains with the defaults of
+// oc explain. Schemas without a type, such as the fields of custom resources that preserve
+// unknown fields, are objects, and fields without a description say so.
+func withDefaults(s proto.Schema) proto.Schema {
+	switch s := s.(type) {
+	case *proto.Kind:
+		k := *s
+		k.Fields = make(map[string]proto.Schema, len(s.Fields))
+		for name, field := range s.Fields {
+			field = withDefaults(field)
+			if len(field.GetDescription()) == 0 {
+				setDescription(field, noDescription)
+			}
+			k.Fields[name] = field
+		}
+		return &k
+	case *proto.Array:
+		a := *s
+		a.SubType = withDefaults(s.SubType)
+		return &a
+	case *proto.Map:
+		m := *s
+		m.SubType = withDefaults(s.SubType)
+		return &m
+	case *proto.Primitive:
+		p := *s
+		return &p
+	case *proto.Arbitrary:
+		return &proto.Kind{BaseSchema: s.BaseSchema}
+	case proto.Reference:
+		return &reference{ref: s}
+	}
+	return s
+}
+
+// setDescription sets the description of a schema returned by withDefaults.
+func setDescription(s proto.Schema, description string) {
+	switch s := s.(type) {
+	case *proto.Kind:
+		s.Description = description
+	case *proto.Array:
+		s.Description = description
+	case *proto.Map:
+		s.Description = description
+	case *proto.Primitive:
+		s.Description = description
+	case *reference:
+		s.description = description
+	}
+}
+
+// reference applies withDefaults to the schema it refers to when it is visited, so that
+// recursive types are not copied ahead of time.
+type reference struct {
+	ref         proto.Reference
+	description string
+}
+
+var _ proto.Reference = &reference{}
+
+func (r *reference) Accept(v proto.SchemaVisitor) {
+	v.VisitReference(r)
+}
+
+func (r *reference) GetName() string                       { return r.ref.GetName() }
+func (r *reference) GetPath() *proto.Path                  { return r.ref.GetPath() }
+func (r *reference) GetDefault() interface{}               { return r.ref.GetDefault() }
+func (r *reference) GetExtensions() map[string]interface{} { return r.ref.GetExtensions() }
+func (r *reference) Reference() string                     { return r.ref.Reference() }
+
+func (r *reference) GetDescription() string {
+	if len(r.description) > 0 {
+		return r.description
+	}
+	return r.ref.GetDescription()
+}
+
+func (r *reference) SubSchema() proto.Schema {
+	return withDefaults(r.ref.SubSchema())
+}
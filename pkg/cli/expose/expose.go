@@ -1,18 +1,26 @@
 package expose
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/dynamic"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/kubectl/pkg/cmd/expose"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
 	"k8s.io/kubectl/pkg/scheme"
 	"k8s.io/kubectl/pkg/util"
 	"k8s.io/kubectl/pkg/util/completion"
@@ -30,6 +38,20 @@ var (
 		There is also the ability to expose a deployment config, replication controller, service, or pod
 		as a new service on a specified port. If no labels are specified, the new object will reuse the
 		labels from the object it exposes.
+
+		When exposing an object with multiple ports without --port, the service serves on all of
+		them and its ports are named like the container ports. An object that declares no ports
+		can only be exposed as a service with --port.
+
+		Use --overrides to change fields of the generated service or route before it is created.
+		By default the JSON is applied as a strategic merge patch; use --override-type to select
+		a JSON merge patch or a JSON patch instead. Fields that do not exist on the generated object
+		are rejected.
+
+		A service can be exposed as a Gateway API HTTPRoute attached to an existing Gateway instead of
+		a route with --route-kind=HTTPRoute and --gateway. The HTTPRoute sends the requests for
+		--hostname and --path to the service port selected with --port, or to the first TCP port
+		of the service.
 	`)
 
 	exposeExample = templates.Examples(`
@@ -51,6 +73,12 @@ var (
 
 		# Expose a service as a route in the specified path
 		oc expose service nginx --path=/nginx
+
+		# Expose a deployment as a service with client IP session affinity and an annotation
+		oc expose deployment nginx --port=8080 --overrides='{"metadata":{"annotations":{"team":"web"}},"spec":{"sessionAffinity":"ClientIP"}}'
+
+		# Expose a service as a Gateway API HTTPRoute attached to the gateway "public" in namespace "ingress"
+		oc expose service nginx --route-kind=HTTPRoute --gateway=ingress/public --hostname=www.example.com
 	`)
 )
 
@@ -58,12 +86,16 @@ type ExposeOptions struct {
 	Hostname       string
 	Path           string
 	WildcardPolicy string
+	RouteKind      string
+	Gateway        string
 
 	Args        []string
 	Cmd         *cobra.Command
 	CoreClient  corev1client.CoreV1Interface
 	RouteClient routev1client.RouteV1Interface
-	Builder     *resource.Builder
+	// DynamicClient creates HTTPRoutes, whose types are not available in a typed client
+	DynamicClient dynamic.Interface
+	Builder       *resource.Builder
 
 	// Embed kubectl's ExposeServiceOptions directly.
 	*expose.ExposeServiceOptions
@@ -71,6 +103,7 @@ type ExposeOptions struct {
 
 func NewExposeOptions(streams genericclioptions.IOStreams) *ExposeOptions {
 	return &ExposeOptions{
+		RouteKind:            routeKindRoute,
 		ExposeServiceOptions: expose.NewExposeServiceOptions(streams),
 	}
 }
@@ -84,6 +117,11 @@ func NewCmdExpose(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 	cmd.Long = exposeLong
 	cmd.Example = exposeExample
 	cmd.Flags().Set("protocol", "")
+	// overrides are strategically merged by default, rather than with a JSON merge patch
+	if flag := cmd.Flags().Lookup("override-type"); flag != nil {
+		flag.Value.Set(string(kcmdutil.OverrideTypeStrategic))
+		flag.DefValue = string(kcmdutil.OverrideTypeStrategic)
+	}
 	cmd.Run = func(cmd *cobra.Command, args []string) {
 		kcmdutil.CheckErr(o.Complete(cmd, f, args))
 		kcmdutil.CheckErr(o.Validate())
@@ -95,6 +133,8 @@ func NewCmdExpose(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 	cmd.Flags().StringVar(&o.Hostname, "hostname", o.Hostname, "Set a hostname for the new route")
 	cmd.Flags().StringVar(&o.Path, "path", o.Path, "Set a path for the new route")
 	cmd.Flags().StringVar(&o.WildcardPolicy, "wildcard-policy", o.WildcardPolicy, "Sets the WildcardPolicy for the hostname, the default is \"None\". Valid values are \"None\" and \"Subdomain\"")
+	cmd.Flags().StringVar(&o.RouteKind, "route-kind", o.RouteKind, "The kind of object created when exposing a service. Valid values are \"Route\" and \"HTTPRoute\" for a Gateway API HTTPRoute")
+	cmd.Flags().StringVar(&o.Gateway, "gateway", o.Gateway, "The Gateway, as [NAMESPACE/]NAME, that the HTTPRoute is attached to. Required with --route-kind=HTTPRoute")
 
 	return cmd
 }
@@ -114,6 +154,8 @@ func (o *ExposeOptions) Complete(cmd *cobra.Command, f kcmdutil.Factory, args []
 	o.ExposeServiceOptions.Name = kcmdutil.GetFlagString(cmd, "name")
 	o.ExposeServiceOptions.SessionAffinity = kcmdutil.GetFlagString(cmd, "session-affinity")
 	o.ExposeServiceOptions.ClusterIP = kcmdutil.GetFlagString(cmd, "cluster-ip")
+	o.ExposeServiceOptions.Overrides = kcmdutil.GetFlagString(cmd, "overrides")
+	o.ExposeServiceOptions.OverrideType = kcmdutil.OverrideType(kcmdutil.GetFlagString(cmd, "override-type"))
 	output := kcmdutil.GetFlagString(cmd, "output")
 	o.ExposeServiceOptions.PrintFlags.OutputFormat = &output
 
@@ -134,6 +176,10 @@ func (o *ExposeOptions) Complete(cmd *cobra.Command, f kcmdutil.Factory, args []
 	if err != nil {
 		return err
 	}
+	o.DynamicClient, err = dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
 
 	return o.ExposeServiceOptions.Complete(f, cmd)
 }
@@ -142,6 +188,32 @@ func (o *ExposeOptions) Validate() error {
 	if len(o.WildcardPolicy) > 0 && (o.WildcardPolicy != string(routev1.WildcardPolicySubdomain) && o.WildcardPolicy != string(routev1.WildcardPolicyNone)) {
 		return fmt.Errorf("only \"Subdomain\" or \"None\" are supported for wildcard-policy")
 	}
+	switch o.OverrideType {
+	case kcmdutil.OverrideTypeJSON, kcmdutil.OverrideTypeMerge, kcmdutil.OverrideTypeStrategic:
+	default:
+		return fmt.Errorf("--override-type must be one of %q, %q or %q", kcmdutil.OverrideTypeJSON, kcmdutil.OverrideTypeMerge, kcmdutil.OverrideTypeStrategic)
+	}
+	return o.validateHTTPRoute()
+}
+
+// validateOverrides returns an error if the --overrides patch sets fields that do not exist
+// on obj, which would otherwise be silently dropped when the patched object is decoded.
+func (o *ExposeOptions) validateOverrides(obj runtime.Object) error {
+	if len(o.Overrides) == 0 {
+		return nil
+	}
+	if o.OverrideType == kcmdutil.OverrideTypeJSON {
+		var patch []interface{}
+		if err := json.Unmarshal([]byte(o.Overrides), &patch); err != nil {
+			return fmt.Errorf("--overrides must be a JSON patch: %v", err)
+		}
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewBufferString(o.Overrides))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return fmt.Errorf("--overrides is not valid for a %T: %v", obj, err)
+	}
 	return nil
 }
 
@@ -164,11 +236,18 @@ func (o *ExposeOptions) Run() error {
 	info := infos[0]
 	mapping := info.ResourceMapping()
 
+	if o.RouteKind == routeKindHTTPRoute && mapping.Resource.GroupResource() != corev1.Resource("services") {
+		return fmt.Errorf("only services can be exposed with --route-kind=%s", routeKindHTTPRoute)
+	}
+
 	switch mapping.Resource.GroupResource() {
 	case corev1.Resource("services"):
 		if len(o.ExposeServiceOptions.Type) != 0 {
 			return fmt.Errorf("cannot use --type when exposing route")
 		}
+		if o.RouteKind == routeKindHTTPRoute {
+			return o.runHTTPRoute(info.Name)
+		}
 		// The upstream generator will incorrectly chose service.Port instead of service.TargetPort
 		// for the route TargetPort when no port is present.  Passing forcePort=true
 		// causes UnsecuredRoute to always set a Port so the upstream default is not used.
@@ -179,12 +258,29 @@ func (o *ExposeOptions) Run() error {
 		route.Spec.Host = o.Hostname
 		route.Spec.Path = o.Path
 		route.Spec.WildcardPolicy = routev1.WildcardPolicyType(o.WildcardPolicy)
+
+		if err := o.validateOverrides(&routev1.Route{}); err != nil {
+			return err
+		}
+		overridden, err := o.NewOverrider(&routev1.Route{}).Apply(route)
+		if err != nil {
+			return fmt.Errorf("unable to apply --overrides to the route: %v", err)
+		}
+		route, ok := overridden.(*routev1.Route)
+		if !ok {
+			return fmt.Errorf("--overrides changed the route into a %T", overridden)
+		}
+
 		if err := util.CreateOrUpdateAnnotation(kcmdutil.GetFlagBool(o.Cmd, kcmdutil.ApplyAnnotationsFlag), route, scheme.DefaultJSONEncoder()); err != nil {
 			return err
 		}
 
 		if o.DryRunStrategy != kcmdutil.DryRunClient {
-			route, err = o.RouteClient.Routes(o.ExposeServiceOptions.Namespace).Create(context.TODO(), route, metav1.CreateOptions{})
+			createOptions := metav1.CreateOptions{}
+			if o.DryRunStrategy == kcmdutil.DryRunServer {
+				createOptions.DryRun = []string{metav1.DryRunAll}
+			}
+			route, err = o.RouteClient.Routes(o.ExposeServiceOptions.Namespace).Create(context.TODO(), route, createOptions)
 			if err != nil {
 				return err
 			}
@@ -193,6 +289,14 @@ func (o *ExposeOptions) Run() error {
 		return o.ExposeServiceOptions.PrintObj(route, o.ExposeServiceOptions.Out)
 	}
 
+	if err := o.validateOverrides(&corev1.Service{}); err != nil {
+		return err
+	}
+
+	if err := o.completePorts(info.Object, fmt.Sprintf("%s/%s", mapping.Resource.Resource, info.Name)); err != nil {
+		return err
+	}
+
 	// Set default protocol back for generating services
 	if len(kcmdutil.GetFlagString(o.Cmd, "protocol")) == 0 {
 		o.ExposeServiceOptions.Protocol = "TCP"
@@ -200,3 +304,91 @@ func (o *ExposeOptions) Run() error {
 
 	return o.ExposeServiceOptions.RunExpose(o.Cmd, o.Args)
 }
+
+// completePorts checks the ports that the exposed object declares. It warns when --port is used
+// for an object without ports, and names the ports of the service after the container ports when
+// all the ports of an object with multiple ports are exposed.
+func (o *ExposeOptions) completePorts(obj runtime.Object, ref string) error {
+	ports, err := o.PortsForObject(obj)
+	if err != nil {
+		// reported when the service is generated
+		return nil
+	}
+	if len(ports) == 0 {
+		switch {
+		case len(o.Port) > 0:
+			fmt.Fprintf(o.ErrOut, "warning: no ports were discovered in %s, the service will use port %s. Make sure the containers listen on it.\n", ref, o.Port)
+		case o.ClusterIP != "None":
+			return fmt.Errorf("no ports were discovered in %s, specify the port of the service with --port", ref)
+		}
+		return nil
+	}
+
+	// with --target-port all service ports would target the same container port, and the names
+	// are left to the user when the service is overridden
+	if len(o.Port) > 0 || len(ports) < 2 || len(o.TargetPort) > 0 || len(o.Overrides) > 0 {
+		return nil
+	}
+	servicePorts := namedServicePorts(obj, ports)
+	if len(servicePorts) == 0 {
+		return nil
+	}
+	// the ports are merged by number into the generated ones, which keeps their target ports
+	var patchPorts []map[string]interface{}
+	for _, port := range servicePorts {
+		patchPorts = append(patchPorts, map[string]interface{}{"name": port.Name, "port": port.Port})
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"ports": patchPorts},
+	})
+	if err != nil {
+		return err
+	}
+	o.Overrides = string(patch)
+	o.OverrideType = kcmdutil.OverrideTypeStrategic
+	return nil
+}
+
+// namedServicePorts returns a service port for each of ports, named like the container port or
+// after its protocol and number if the container port has no name. It returns nil if a port is
+// declared more than once, for instance for different protocols.
+func namedServicePorts(obj runtime.Object, ports []string) []corev1.ServicePort {
+	names := make(map[string]string)
+	duplicate := false
+	_, err := polymorphichelpers.UpdatePodSpecForObjectFn(obj, func(spec *corev1.PodSpec) error {
+		for _, container := range spec.Containers {
+			for _, port := range container.Ports {
+				number := strconv.Itoa(int(port.ContainerPort))
+				if _, ok := names[number]; ok {
+					duplicate = true
+				}
+				name := port.Name
+				if len(name) == 0 {
+					protocol := port.Protocol
+					if len(protocol) == 0 {
+						protocol = corev1.ProtocolTCP
+					}
+					name = fmt.Sprintf("%s-%s", strings.ToLower(string(protocol)), number)
+				}
+				names[number] = name
+			}
+		}
+		return nil
+	})
+	if err != nil || duplicate {
+		return nil
+	}
+
+	used := sets.NewString()
+	var servicePorts []corev1.ServicePort
+	for _, port := range ports {
+		number, err := strconv.Atoi(port)
+		name, ok := names[port]
+		if err != nil || !ok || used.Has(name) {
+			return nil
+		}
+		used.Insert(name)
+		servicePorts = append(servicePorts, corev1.ServicePort{Name: name, Port: int32(number)})
+	}
+	return servicePorts
+}
@@ -0,0 +1,337 @@
+package expose
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/openshift/api/route"
+	routev1 "github.com/openshift/api/route/v1"
+)
+
+func TestRouteOverrides(t *testing.T) {
+	// we need to install OpenShift API types to kubectl's scheme to decode the patched route
+	route.Install(scheme.Scheme)
+
+	o := NewExposeOptions(genericclioptions.NewTestIOStreamsDiscard())
+	o.OverrideType = kcmdutil.OverrideTypeStrategic
+	o.Overrides = `{"metadata":{"annotations":{"haproxy.router.openshift.io/timeout":"60s"}},"spec":{"tls":{"termination":"edge"}}}`
+
+	if err := o.validateOverrides(&routev1.Route{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	generated := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx", Labels: map[string]string{"app": "nginx"}},
+		Spec:       routev1.RouteSpec{To: routev1.RouteTargetReference{Kind: "Service", Name: "nginx"}},
+	}
+	obj, err := o.NewOverrider(&routev1.Route{}).Apply(generated)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	overridden := obj.(*routev1.Route)
+	if overridden.Annotations["haproxy.router.openshift.io/timeout"] != "60s" || overridden.Labels["app"] != "nginx" {
+		t.Errorf("unexpected metadata: %#v", overridden.ObjectMeta)
+	}
+	if overridden.Spec.TLS == nil || overridden.Spec.TLS.Termination != routev1.TLSTerminationEdge || overridden.Spec.To.Name != "nginx" {
+		t.Errorf("unexpected spec: %#v", overridden.Spec)
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	tests := []struct {
+		name         string
+		overrideType kcmdutil.OverrideType
+		overrides    string
+		expectErr    bool
+	}{
+		{name: "valid", overrideType: kcmdutil.OverrideTypeStrategic, overrides: `{"spec":{"sessionAffinity":"ClientIP"}}`},
+		{name: "unknown field", overrideType: kcmdutil.OverrideTypeStrategic, overrides: `{"spec":{"sessionAfinity":"ClientIP"}}`, expectErr: true},
+		{name: "wrong type", overrideType: kcmdutil.OverrideTypeMerge, overrides: `{"spec":{"ports":"80"}}`, expectErr: true},
+		{name: "json patch", overrideType: kcmdutil.OverrideTypeJSON, overrides: `[{"op":"add","path":"/spec/sessionAffinity","value":"ClientIP"}]`},
+		{name: "invalid json patch", overrideType: kcmdutil.OverrideTypeJSON, overrides: `{"spec":{}}`, expectErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewExposeOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.OverrideType = tc.overrideType
+			o.Overrides = tc.overrides
+			err := o.validateOverrides(&corev1.Service{})
+			if tc.expectErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestCompletePorts(t *testing.T) {
+	deployment := func(ports ...corev1.ContainerPort) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "web", Ports: ports}},
+					},
+				},
+			},
+		}
+	}
+	generated := func(ports ...int32) *corev1.Service {
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+		for i, port := range ports {
+			service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+				Name:       fmt.Sprintf("port-%d", i+1),
+				Port:       port,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt(int(port)),
+			})
+		}
+		return service
+	}
+
+	tests := []struct {
+		name          string
+		object        *appsv1.Deployment
+		port          string
+		targetPort    string
+		expectErr     bool
+		expectWarning bool
+		expectedNames []string
+	}{
+		{
+			name:          "multiple ports are named after the container ports",
+			object:        deployment(corev1.ContainerPort{Name: "http", ContainerPort: 8080}, corev1.ContainerPort{ContainerPort: 9090, Protocol: corev1.ProtocolTCP}),
+			expectedNames: []string{"http", "tcp-9090"},
+		},
+		{
+			name:          "multiple ports with --target-port keep the generated names",
+			object:        deployment(corev1.ContainerPort{Name: "http", ContainerPort: 8080}, corev1.ContainerPort{Name: "metrics", ContainerPort: 9090}),
+			targetPort:    "http",
+			expectedNames: []string{"port-1", "port-2"},
+		},
+		{
+			name:          "same port for different protocols keeps the generated names",
+			object:        deployment(corev1.ContainerPort{Name: "dns", ContainerPort: 53, Protocol: corev1.ProtocolUDP}, corev1.ContainerPort{Name: "dns-tcp", ContainerPort: 53, Protocol: corev1.ProtocolTCP}, corev1.ContainerPort{Name: "metrics", ContainerPort: 9153}),
+			expectedNames: []string{"port-1", "port-2", "port-3"},
+		},
+		{
+			name:          "no ports with --port warns",
+			object:        deployment(),
+			port:          "8080",
+			expectWarning: true,
+		},
+		{
+			name:      "no ports without --port fails",
+			object:    deployment(),
+			expectErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := NewExposeOptions(streams)
+			o.PortsForObject = polymorphichelpers.PortsForObjectFn
+			o.Port = tc.port
+			o.TargetPort = tc.targetPort
+
+			err := o.completePorts(tc.object, "deployments/web")
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if warned := strings.Contains(errOut.String(), "warning: no ports were discovered in deployments/web"); warned != tc.expectWarning {
+				t.Errorf("unexpected warning: %q", errOut.String())
+			}
+			if tc.expectedNames == nil {
+				return
+			}
+
+			ports, _ := o.PortsForObject(tc.object)
+			var numbers []int32
+			for _, port := range ports {
+				number, _ := strconv.Atoi(port)
+				numbers = append(numbers, int32(number))
+			}
+			obj, err := o.NewOverrider(&corev1.Service{}).Apply(generated(numbers...))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var names []string
+			for _, port := range obj.(*corev1.Service).Spec.Ports {
+				names = append(names, port.Name)
+				if port.TargetPort.IntValue() != int(port.Port) {
+					t.Errorf("expected the target port of %d to be kept, got %s", port.Port, port.TargetPort.String())
+				}
+			}
+			if !reflect.DeepEqual(tc.expectedNames, names) {
+				t.Errorf("expected port names %v, got %v", tc.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestValidateHTTPRoute(t *testing.T) {
+	tests := []struct {
+		name        string
+		routeKind   string
+		gateway     string
+		wildcard    string
+		path        string
+		expectedErr string
+	}{
+		{name: "route", routeKind: "Route"},
+		{name: "gateway without HTTPRoute", routeKind: "Route", gateway: "public", expectedErr: "--gateway can only be used"},
+		{name: "unknown kind", routeKind: "Ingress", expectedErr: "--route-kind must be"},
+		{name: "HTTPRoute", routeKind: "HTTPRoute", gateway: "ingress/public", path: "/api"},
+		{name: "HTTPRoute without gateway", routeKind: "HTTPRoute", expectedErr: "--gateway is required"},
+		{name: "invalid gateway namespace", routeKind: "HTTPRoute", gateway: "In_gress/public", expectedErr: "invalid gateway namespace"},
+		{name: "wildcard policy", routeKind: "HTTPRoute", gateway: "public", wildcard: "Subdomain", expectedErr: "--wildcard-policy cannot be used"},
+		{name: "relative path", routeKind: "HTTPRoute", gateway: "public", path: "api", expectedErr: "--path must start with a slash"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewExposeOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.OverrideType = kcmdutil.OverrideTypeStrategic
+			o.RouteKind = tc.routeKind
+			o.Gateway = tc.gateway
+			o.WildcardPolicy = tc.wildcard
+			o.Path = tc.path
+			err := o.Validate()
+			if len(tc.expectedErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestHTTPRoute(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "web", Name: "nginx", Labels: map[string]string{"app": "nginx"}},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "dns", Port: 53, Protocol: corev1.ProtocolUDP},
+			{Name: "http", Port: 80, Protocol: corev1.ProtocolTCP},
+			{Name: "https", Port: 443, Protocol: corev1.ProtocolTCP},
+		}},
+	}
+	tests := []struct {
+		name         string
+		service      string
+		port         string
+		expectedPort int64
+		expectedErr  string
+	}{
+		{name: "first TCP port", service: "nginx", expectedPort: 80},
+		{name: "port by name", service: "nginx", port: "https", expectedPort: 443},
+		{name: "port by number", service: "nginx", port: "8443", expectedPort: 8443},
+		{name: "unknown port name", service: "nginx", port: "dns", expectedErr: `service "nginx" has no TCP port named "dns"`},
+		{name: "missing service with port", service: "missing", port: "8080", expectedPort: 8080},
+		{name: "missing service without port", service: "missing", expectedErr: "you need to provide the port of the service"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewExposeOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.CoreClient = kubefake.NewSimpleClientset(service).CoreV1()
+			o.Namespace = "web"
+			o.RouteKind = "HTTPRoute"
+			o.Gateway = "ingress/public"
+			o.Hostname = "www.example.com"
+			o.Path = "/api"
+			o.Port = tc.port
+
+			route, err := o.httpRoute(tc.service)
+			if len(tc.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if route.GetAPIVersion() != "gateway.networking.k8s.io/v1beta1" || route.GetKind() != "HTTPRoute" || route.GetName() != tc.service {
+				t.Errorf("unexpected object: %s %s %s", route.GetAPIVersion(), route.GetKind(), route.GetName())
+			}
+			parentRefs, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+			expectedParent := map[string]interface{}{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "public", "namespace": "ingress"}
+			if len(parentRefs) != 1 || !reflect.DeepEqual(parentRefs[0], expectedParent) {
+				t.Errorf("unexpected parentRefs: %v", parentRefs)
+			}
+			hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+			if !reflect.DeepEqual(hostnames, []string{"www.example.com"}) {
+				t.Errorf("unexpected hostnames: %v", hostnames)
+			}
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			if len(rules) != 1 {
+				t.Fatalf("unexpected rules: %v", rules)
+			}
+			rule := rules[0].(map[string]interface{})
+			path, _, _ := unstructured.NestedString(rule["matches"].([]interface{})[0].(map[string]interface{}), "path", "value")
+			if path != "/api" {
+				t.Errorf("unexpected path: %q", path)
+			}
+			backend := rule["backendRefs"].([]interface{})[0].(map[string]interface{})
+			if backend["name"] != tc.service || backend["port"] != tc.expectedPort {
+				t.Errorf("unexpected backend: %v", backend)
+			}
+		})
+	}
+}
+
+func TestHTTPRouteOverrides(t *testing.T) {
+	route := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1beta1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]interface{}{"name": "nginx"},
+		"spec":       map[string]interface{}{"hostnames": []interface{}{"www.example.com"}},
+	}}
+	tests := []struct {
+		name         string
+		overrideType kcmdutil.OverrideType
+		overrides    string
+		expectErr    bool
+	}{
+		{name: "strategic", overrideType: kcmdutil.OverrideTypeStrategic, overrides: `{"metadata":{"annotations":{"team":"web"}}}`},
+		{name: "json patch", overrideType: kcmdutil.OverrideTypeJSON, overrides: `[{"op":"add","path":"/metadata/annotations","value":{"team":"web"}}]`},
+		{name: "kind changed", overrideType: kcmdutil.OverrideTypeMerge, overrides: `{"kind":"GRPCRoute"}`, expectErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewExposeOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.OverrideType = tc.overrideType
+			o.Overrides = tc.overrides
+			overridden, err := o.applyHTTPRouteOverrides(route.DeepCopy())
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			hostnames, _, _ := unstructured.NestedStringSlice(overridden.Object, "spec", "hostnames")
+			if overridden.GetAnnotations()["team"] != "web" || len(hostnames) != 1 {
+				t.Errorf("unexpected HTTPRoute: %v", overridden.Object)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package expose
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util"
+)
+
+const (
+	routeKindRoute     = "Route"
+	routeKindHTTPRoute = "HTTPRoute"
+)
+
+// httpRouteGVR is the Gateway API HTTPRoute resource. The Gateway API types are not vendored, so
+// HTTPRoutes are handled as unstructured objects.
+var httpRouteGVR = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "httproutes"}
+
+// parseGatewayRef splits a [NAMESPACE/]NAME reference to a Gateway.
+func parseGatewayRef(ref string) (string, string, error) {
+	namespace, name := "", ref
+	if i := strings.Index(ref, "/"); i >= 0 {
+		namespace, name = ref[:i], ref[i+1:]
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return "", "", fmt.Errorf("invalid gateway namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid gateway name %q: %s", name, strings.Join(errs, ", "))
+	}
+	return namespace, name, nil
+}
+
+// validateHTTPRoute checks the flags that apply when exposing a service as an HTTPRoute.
+func (o *ExposeOptions) validateHTTPRoute() error {
+	switch o.RouteKind {
+	case routeKindRoute:
+		if len(o.Gateway) > 0 {
+			return fmt.Errorf("--gateway can only be used with --route-kind=%s", routeKindHTTPRoute)
+		}
+		return nil
+	case routeKindHTTPRoute:
+	default:
+		return fmt.Errorf("--route-kind must be %q or %q", routeKindRoute, routeKindHTTPRoute)
+	}
+	if len(o.Gateway) == 0 {
+		return fmt.Errorf("--gateway is required with --route-kind=%s", routeKindHTTPRoute)
+	}
+	if _, _, err := parseGatewayRef(o.Gateway); err != nil {
+		return err
+	}
+	if len(o.WildcardPolicy) > 0 {
+		return fmt.Errorf("--wildcard-policy cannot be used with --route-kind=%s, use a hostname like *.example.com instead", routeKindHTTPRoute)
+	}
+	if len(o.Path) > 0 && !strings.HasPrefix(o.Path, "/") {
+		return fmt.Errorf("--path must start with a slash")
+	}
+	return nil
+}
+
+// httpRoute returns an HTTPRoute attached to the gateway that sends the requests for the hostname
+// and path to a port of the service.
+func (o *ExposeOptions) httpRoute(serviceName string) (*unstructured.Unstructured, error) {
+	gatewayNamespace, gatewayName, err := parseGatewayRef(o.Gateway)
+	if err != nil {
+		return nil, err
+	}
+
+	var labels map[string]string
+	svc, err := o.CoreClient.Services(o.Namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	switch {
+	case kapierrors.IsNotFound(err):
+		svc = nil
+	case err != nil:
+		return nil, err
+	default:
+		labels = svc.Labels
+	}
+	port, err := httpRouteBackendPort(svc, o.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	parentRef := map[string]interface{}{
+		"group": "gateway.networking.k8s.io",
+		"kind":  "Gateway",
+		"name":  gatewayName,
+	}
+	if len(gatewayNamespace) > 0 {
+		parentRef["namespace"] = gatewayNamespace
+	}
+	rule := map[string]interface{}{
+		"backendRefs": []interface{}{
+			map[string]interface{}{"kind": "Service", "name": serviceName, "port": port},
+		},
+	}
+	if len(o.Path) > 0 {
+		rule["matches"] = []interface{}{
+			map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": o.Path}},
+		}
+	}
+	spec := map[string]interface{}{
+		"parentRefs": []interface{}{parentRef},
+		"rules":      []interface{}{rule},
+	}
+	if len(o.Hostname) > 0 {
+		spec["hostnames"] = []interface{}{o.Hostname}
+	}
+
+	name := o.ExposeServiceOptions.Name
+	if len(name) == 0 {
+		name = serviceName
+	}
+	route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	route.SetAPIVersion(httpRouteGVR.GroupVersion().String())
+	route.SetKind(routeKindHTTPRoute)
+	route.SetName(name)
+	route.SetLabels(labels)
+	if o.EnforceNamespace {
+		route.SetNamespace(o.Namespace)
+	}
+	return route, nil
+}
+
+// httpRouteBackendPort returns the number of the service port selected by name or number with
+// --port, or the first TCP port of the service. Unlike routes, HTTPRoutes reference the port of the
+// service rather than the port of its endpoints.
+func httpRouteBackendPort(svc *corev1.Service, port string) (int64, error) {
+	if number, err := strconv.ParseInt(port, 10, 32); err == nil {
+		return number, nil
+	}
+	if svc == nil {
+		return 0, fmt.Errorf("you need to provide the port of the service by number via --port when exposing a non-existent service")
+	}
+	for _, p := range svc.Spec.Ports {
+		if len(p.Protocol) > 0 && p.Protocol != corev1.ProtocolTCP {
+			continue
+		}
+		if len(port) == 0 || p.Name == port {
+			return int64(p.Port), nil
+		}
+	}
+	if len(port) > 0 {
+		return 0, fmt.Errorf("service %q has no TCP port named %q", svc.Name, port)
+	}
+	return 0, fmt.Errorf("service %q doesn't support TCP", svc.Name)
+}
+
+// applyHTTPRouteOverrides applies --overrides to the HTTPRoute. There is no schema to merge
+// strategically against, so strategic merge patches are applied as JSON merge patches.
+func (o *ExposeOptions) applyHTTPRouteOverrides(route *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if len(o.Overrides) == 0 {
+		return route, nil
+	}
+	var patched runtime.Object
+	var err error
+	if o.OverrideType == kcmdutil.OverrideTypeJSON {
+		patched, err = kcmdutil.JSONPatch(unstructured.UnstructuredJSONScheme, route, o.Overrides)
+	} else {
+		patched, err = kcmdutil.Merge(unstructured.UnstructuredJSONScheme, route, o.Overrides)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply --overrides to the HTTPRoute: %v", err)
+	}
+	overridden, ok := patched.(*unstructured.Unstructured)
+	if !ok || overridden.GetKind() != routeKindHTTPRoute {
+		return nil, fmt.Errorf("--overrides changed the HTTPRoute into a %s", patched.GetObjectKind().GroupVersionKind().Kind)
+	}
+	return overridden, nil
+}
+
+// runHTTPRoute creates an HTTPRoute for the service and prints it.
+func (o *ExposeOptions) runHTTPRoute(serviceName string) error {
+	route, err := o.httpRoute(serviceName)
+	if err != nil {
+		return err
+	}
+	route, err = o.applyHTTPRouteOverrides(route)
+	if err != nil {
+		return err
+	}
+	if err := util.CreateOrUpdateAnnotation(kcmdutil.GetFlagBool(o.Cmd, kcmdutil.ApplyAnnotationsFlag), route, unstructured.UnstructuredJSONScheme); err != nil {
+		return err
+	}
+
+	if o.DryRunStrategy != kcmdutil.DryRunClient {
+		createOptions := metav1.CreateOptions{}
+		if o.DryRunStrategy == kcmdutil.DryRunServer {
+			createOptions.DryRun = []string{metav1.DryRunAll}
+		}
+		route, err = o.DynamicClient.Resource(httpRouteGVR).Namespace(o.Namespace).Create(context.TODO(), route, createOptions)
+		if err != nil {
+			if kapierrors.IsNotFound(err) {
+				return fmt.Errorf("unable to create the HTTPRoute, make sure the Gateway API is installed in the cluster: %v", err)
+			}
+			return err
+		}
+	}
+
+	return o.ExposeServiceOptions.PrintObj(route, o.ExposeServiceOptions.Out)
+}
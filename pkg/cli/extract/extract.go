@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -33,7 +34,8 @@ var (
 		names of each key will be written to standard error.
 
 		You can limit which keys are extracted with the --keys=NAME flag, or set the directory to extract to
-		with --to=DIRECTORY.
+		with --to=DIRECTORY. A key may be written to a file with another name with --keys=NAME=FILENAME.
+		Extraction fails if a key passed to --keys does not exist.
 	`)
 
 	extractExample = templates.Examples(`
@@ -48,6 +50,12 @@ var (
 
 		# Extract only the key "nginx.conf" from config map "nginx" to the /tmp directory
 		oc extract configmap/nginx --to=/tmp --keys=nginx.conf
+
+		# Extract the certificate and key of the secret "router-certs" to server.crt and server.key
+		oc extract secret/router-certs --keys=tls.crt=server.crt,tls.key=server.key
+
+		# Print only the value of the key "password" of the secret "db" in a script
+		oc extract secret/db --keys=password --to=- 2>/dev/null
 	`)
 )
 
@@ -57,6 +65,9 @@ type ExtractOptions struct {
 	TargetDirectory string
 	Overwrite       bool
 
+	// KeyFilenames maps the keys passed to --keys to the name of the file they are written to.
+	KeyFilenames map[string]string
+
 	Namespace         string
 	ExplicitNamespace bool
 	Resources         []string
@@ -92,7 +103,7 @@ func NewCmdExtract(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cob
 	cmd.Flags().StringVar(&o.TargetDirectory, "to", o.TargetDirectory, "Directory to extract files to.")
 	cmd.Flags().StringSliceVarP(&o.Filenames, "filename", "f", o.Filenames, "Filename, directory, or URL to file to identify to extract the resource.")
 	cmd.MarkFlagFilename("filename")
-	cmd.Flags().StringSliceVar(&o.OnlyKeys, "keys", o.OnlyKeys, "An optional list of keys to extract (default is all keys).")
+	cmd.Flags().StringSliceVar(&o.OnlyKeys, "keys", o.OnlyKeys, "An optional list of keys to extract (default is all keys). Use KEY=FILENAME to write a key to a file with another name.")
 	return cmd
 }
 
@@ -108,10 +119,24 @@ func (o *ExtractOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args [
 	o.Resources = args
 	o.Builder = f.NewBuilder
 
+	o.KeyFilenames = make(map[string]string)
+	for _, key := range o.OnlyKeys {
+		filename := key
+		if i := strings.Index(key, "="); i != -1 {
+			key, filename = key[:i], key[i+1:]
+		}
+		o.KeyFilenames[key] = filename
+	}
+
 	return nil
 }
 
 func (o *ExtractOptions) Validate() error {
+	for key, filename := range o.KeyFilenames {
+		if len(key) == 0 || len(filename) == 0 || strings.ContainsRune(filename, filepath.Separator) {
+			return fmt.Errorf("--keys must be KEY or KEY=FILENAME, where FILENAME is not a path: %s=%s", key, filename)
+		}
+	}
 	if o.TargetDirectory != "-" {
 		// determine if output location is valid before continuing
 		if _, err := os.Stat(o.TargetDirectory); err != nil {
@@ -139,7 +164,6 @@ func (o *ExtractOptions) Run() error {
 	}
 
 	count := 0
-	contains := sets.NewString(o.OnlyKeys...)
 	err := r.Visit(func(info *resource.Info, err error) error {
 		if err != nil {
 			return fmt.Errorf("%s: %v", name(info), err)
@@ -153,31 +177,7 @@ func (o *ExtractOptions) Run() error {
 			return nil
 		}
 		count++
-		var errs []error
-		for k, v := range contents {
-			if contains.Len() == 0 || contains.Has(k) {
-				switch {
-				case o.TargetDirectory == "-":
-					fmt.Fprintf(o.ErrOut, "# %s\n", k)
-					o.Out.Write(v)
-					if !bytes.HasSuffix(v, []byte("\n")) {
-						fmt.Fprintln(o.Out)
-					}
-				default:
-					target := filepath.Join(o.TargetDirectory, k)
-					if err := o.writeToDisk(target, v); err != nil {
-						if os.IsExist(err) {
-							err = fmt.Errorf("file exists, pass --confirm to overwrite")
-						}
-						errs = append(errs, fmt.Errorf("%s: %v", k, err))
-					}
-				}
-			}
-		}
-		if len(errs) > 0 {
-			return fmt.Errorf(kcmdutil.MultipleErrors("error: ", errs))
-		}
-		return nil
+		return o.extract(name(info), contents)
 	})
 	if err != nil {
 		return err
@@ -188,6 +188,48 @@ func (o *ExtractOptions) Run() error {
 	return nil
 }
 
+// extract writes the selected keys of the contents of name, in order, to the target directory or to the
+// output. Every key passed to --keys must exist.
+func (o *ExtractOptions) extract(name string, contents map[string][]byte) error {
+	keys := sets.StringKeySet(contents)
+	if len(o.KeyFilenames) > 0 {
+		requested := sets.StringKeySet(o.KeyFilenames)
+		if missing := requested.Difference(keys); missing.Len() > 0 {
+			return fmt.Errorf("%s: keys %s not found, available keys are: %s", name, strings.Join(missing.List(), ", "), strings.Join(keys.List(), ", "))
+		}
+		keys = requested
+	}
+
+	var errs []error
+	for _, k := range keys.List() {
+		v := contents[k]
+		switch {
+		case o.TargetDirectory == "-":
+			fmt.Fprintf(o.ErrOut, "# %s\n", k)
+			o.Out.Write(v)
+			if !bytes.HasSuffix(v, []byte("\n")) {
+				fmt.Fprintln(o.Out)
+			}
+		default:
+			filename := k
+			if f, ok := o.KeyFilenames[k]; ok {
+				filename = f
+			}
+			target := filepath.Join(o.TargetDirectory, filename)
+			if err := o.writeToDisk(target, v); err != nil {
+				if os.IsExist(err) {
+					err = fmt.Errorf("file exists, pass --confirm to overwrite")
+				}
+				errs = append(errs, fmt.Errorf("%s: %v", k, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(kcmdutil.MultipleErrors("error: ", errs))
+	}
+	return nil
+}
+
 func (o *ExtractOptions) writeToDisk(path string, data []byte) error {
 	if o.Overwrite {
 		if err := ioutil.WriteFile(path, data, 0600); err != nil {
@@ -0,0 +1,103 @@
+package extract
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kcmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+)
+
+func TestExtract(t *testing.T) {
+	contents := map[string][]byte{
+		"tls.crt": []byte("cert\n"),
+		"tls.key": []byte("key"),
+		"ca.crt":  []byte("ca\n"),
+	}
+	tests := []struct {
+		name   string
+		keys   []string
+		files  map[string]string
+		stdout string
+		err    string
+	}{
+		{
+			name:  "all keys",
+			files: map[string]string{"tls.crt": "cert\n", "tls.key": "key", "ca.crt": "ca\n"},
+		},
+		{
+			name:  "selected keys renamed",
+			keys:  []string{"tls.crt=server.crt", "tls.key"},
+			files: map[string]string{"server.crt": "cert\n", "tls.key": "key"},
+		},
+		{
+			name:   "single key to stdout",
+			keys:   []string{"tls.key"},
+			stdout: "key\n",
+		},
+		{
+			name: "missing key",
+			keys: []string{"tls.crt", "password"},
+			err:  "secret/test: keys password not found, available keys are: ca.crt, tls.crt, tls.key",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "extract")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := NewExtractOptions(dir, streams)
+			if tc.files == nil && len(tc.err) == 0 {
+				o.TargetDirectory = "-"
+			}
+			o.OnlyKeys = tc.keys
+			tf := kcmdtesting.NewTestFactory().WithNamespace("test")
+			defer tf.Cleanup()
+			if err := o.Complete(tf, nil, []string{"secret/test"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := o.Validate(); err != nil {
+				t.Fatal(err)
+			}
+
+			err = o.extract("secret/test", contents)
+			if len(tc.err) > 0 {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.TargetDirectory == "-" {
+				if out.String() != tc.stdout {
+					t.Errorf("expected output %q, got %q", tc.stdout, out.String())
+				}
+				return
+			}
+			entries, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(tc.files) {
+				t.Errorf("expected %d files, got %d", len(tc.files), len(entries))
+			}
+			for file, expected := range tc.files {
+				data, err := ioutil.ReadFile(filepath.Join(dir, file))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(data) != expected {
+					t.Errorf("expected %s to contain %q, got %q", file, expected, data)
+				}
+			}
+		})
+	}
+}
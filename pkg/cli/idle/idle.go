@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -50,11 +52,18 @@ var (
 
 		Upon receiving network traffic, the services (and any associated routes) will "wake up" the
 		associated resources by scaling them back up to their previous scale.
+
+		Services that are already idled are skipped and listed separately. When services are selected
+		with a label selector, --all or --all-namespaces, a summary of the idled resources and the
+		scale they will be restored to is printed at the end.
 	`)
 
 	idleExample = templates.Examples(`
 		# Idle the scalable controllers associated with the services listed in to-idle.txt
 		$ oc idle --resource-names-file to-idle.txt
+
+		# Idle the services labeled schedule=off-hours in every namespace
+		$ oc idle -l schedule=off-hours --all-namespaces
 	`)
 )
 
@@ -235,6 +244,8 @@ type idleUpdateInfo struct {
 	obj       *corev1.Endpoints
 	service   *corev1.Service
 	scaleRefs map[unidlingapi.CrossGroupObjectReference]struct{}
+	// idledAt is set if the service was already idled
+	idledAt string
 }
 
 // calculateIdlableAnnotationsByService calculates the list of objects involved in the idling process from a list of services in a file.
@@ -321,6 +332,12 @@ func (o *IdleOptions) calculateIdlableAnnotationsByService(infoVisitor func(reso
 			Namespace: endpoints.Namespace,
 			Name:      endpoints.Name,
 		}
+		// an idled service has no endpoints to find scalable resources from, so report it instead
+		if idledAt, ok := endpoints.Annotations[unidlingapi.IdledAtAnnotation]; ok {
+			endpointsInfo[endpointsName] = idleUpdateInfo{obj: endpoints, idledAt: idledAt}
+			return nil
+		}
+
 		scaleRefs, err := findScalableResourcesForEndpoints(endpoints, getPod, getController)
 		if err != nil {
 			return fmt.Errorf("unable to calculate scalable resources for service %s/%s: %v", endpoints.Namespace, endpoints.Name, err)
@@ -689,8 +706,16 @@ func (o *IdleOptions) RunIdle() error {
 		return err
 	}
 
+	var idled, skipped []idleSummaryEntry
+
 	// annotate the endpoints objects to indicate which scalable resources need to be unidled on traffic
 	for serviceName, info := range byService {
+		if len(info.idledAt) > 0 {
+			fmt.Fprintf(o.Out, "The service %q was already idled at %s, skipping\n", serviceName.String(), info.idledAt)
+			skipped = append(skipped, alreadyIdledEntries(serviceName, info)...)
+			continue
+		}
+
 		if info.obj.Annotations == nil {
 			info.obj.Annotations = make(map[string]string)
 		}
@@ -747,6 +772,16 @@ func (o *IdleOptions) RunIdle() error {
 		}
 
 		fmt.Fprintf(o.Out, "%s \"%s/%s\" has been idled %s\n", scaleRef.Kind, info.namespace, scaleRef.Name, dryRunText)
+		idled = append(idled, idleSummaryEntry{
+			Service:  byScalable[scaleRef],
+			Kind:     scaleRef.Kind,
+			Name:     scaleRef.Name,
+			Replicas: replicas[scaleRef],
+		})
+	}
+
+	if len(o.selector) > 0 || o.all || o.allNamespaces {
+		printIdleSummary(o.Out, idled, skipped, o.dryRun)
 	}
 
 	if hadError {
@@ -755,3 +790,83 @@ func (o *IdleOptions) RunIdle() error {
 
 	return nil
 }
+
+// idleSummaryEntry is a scalable resource idled, or left idle, for a service, along with the
+// number of replicas it will be scaled back up to when the service is unidled.
+type idleSummaryEntry struct {
+	Service  types.NamespacedName
+	Kind     string
+	Name     string
+	Replicas int32
+	IdledAt  string
+}
+
+// alreadyIdledEntries returns the scalable resources recorded on the endpoints of a service that
+// was idled before.
+func alreadyIdledEntries(serviceName types.NamespacedName, info idleUpdateInfo) []idleSummaryEntry {
+	var scaleRefs []unidlingapi.RecordedScaleReference
+	if targets, ok := info.obj.Annotations[unidlingapi.UnidleTargetAnnotation]; ok {
+		if err := json.Unmarshal([]byte(targets), &scaleRefs); err != nil {
+			scaleRefs = nil
+		}
+	}
+	if len(scaleRefs) == 0 {
+		return []idleSummaryEntry{{Service: serviceName, IdledAt: info.idledAt}}
+	}
+	entries := make([]idleSummaryEntry, 0, len(scaleRefs))
+	for _, ref := range scaleRefs {
+		entries = append(entries, idleSummaryEntry{Service: serviceName, Kind: ref.Kind, Name: ref.Name, Replicas: ref.Replicas, IdledAt: info.idledAt})
+	}
+	return entries
+}
+
+// printIdleSummary prints the resources that were idled and those that were skipped because
+// their service was already idled.
+func printIdleSummary(out io.Writer, idled, skipped []idleSummaryEntry, dryRun bool) {
+	sortIdleSummary(idled)
+	sortIdleSummary(skipped)
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	status := "IDLED"
+	if dryRun {
+		status = "WOULD IDLE"
+	}
+	fmt.Fprintf(w, "\nNAMESPACE\tSERVICE\tRESOURCE\tPREVIOUS REPLICAS\tSTATUS\n")
+	for _, e := range idled {
+		fmt.Fprintf(w, "%s\t%s\t%s/%s\t%d\t%s\n", e.Service.Namespace, e.Service.Name, e.Kind, e.Name, e.Replicas, status)
+	}
+	for _, e := range skipped {
+		resource, replicas := "<unknown>", "<unknown>"
+		if len(e.Kind) > 0 {
+			resource, replicas = fmt.Sprintf("%s/%s", e.Kind, e.Name), fmt.Sprintf("%d", e.Replicas)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\tALREADY IDLED (%s)\n", e.Service.Namespace, e.Service.Name, resource, replicas, e.IdledAt)
+	}
+	fmt.Fprintf(w, "\n%d resource(s) idled, %d service(s) already idled\n", len(idled), countServices(skipped))
+}
+
+func sortIdleSummary(entries []idleSummaryEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Service != b.Service {
+			if a.Service.Namespace != b.Service.Namespace {
+				return a.Service.Namespace < b.Service.Namespace
+			}
+			return a.Service.Name < b.Service.Name
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+}
+
+func countServices(entries []idleSummaryEntry) int {
+	services := make(map[types.NamespacedName]struct{})
+	for _, e := range entries {
+		services[e.Service] = struct{}{}
+	}
+	return len(services)
+}
@@ -1,8 +1,10 @@
 package idle
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	unidlingapi "github.com/openshift/api/unidling/v1alpha1"
@@ -12,6 +14,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
 )
 
 func makePod(name string, rc metav1.Object, namespace string, t *testing.T) corev1.Pod {
@@ -323,3 +327,55 @@ func TestPairScalesWithIdlables(t *testing.T) {
 		}
 	}
 }
+
+func TestIdleSkipsAlreadyIdledServices(t *testing.T) {
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "ns1",
+			Annotations: map[string]string{
+				unidlingapi.IdledAtAnnotation:      "2021-06-01T22:00:00Z",
+				unidlingapi.UnidleTargetAnnotation: `[{"kind":"DeploymentConfig","name":"web","replicas":3}]`,
+			},
+		},
+	}
+	o := NewIdleOptions(genericclioptions.NewTestIOStreamsDiscard())
+	byService, byScalable, err := o.calculateIdlableAnnotationsByService(func(fn resource.VisitorFunc) error {
+		return fn(&resource.Info{Object: endpoints}, nil)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(byScalable) != 0 {
+		t.Errorf("expected nothing to scale for an idled service, got %v", byScalable)
+	}
+	serviceName := ktypes.NamespacedName{Namespace: "ns1", Name: "web"}
+	info, ok := byService[serviceName]
+	if !ok || info.idledAt != "2021-06-01T22:00:00Z" {
+		t.Fatalf("expected the service to be reported as idled: %#v", byService)
+	}
+
+	skipped := alreadyIdledEntries(serviceName, info)
+	idled := []idleSummaryEntry{
+		{Service: ktypes.NamespacedName{Namespace: "ns2", Name: "api"}, Kind: "Deployment", Name: "api", Replicas: 2},
+		{Service: ktypes.NamespacedName{Namespace: "ns1", Name: "db"}, Kind: "DeploymentConfig", Name: "db", Replicas: 1},
+	}
+	out := &bytes.Buffer{}
+	printIdleSummary(out, idled, skipped, false)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expected := []string{
+		"NAMESPACE  SERVICE  RESOURCE             PREVIOUS REPLICAS  STATUS",
+		"ns1        db       DeploymentConfig/db  1                  IDLED",
+		"ns2        api      Deployment/api       2                  IDLED",
+		"ns1        web      DeploymentConfig/web 3                  ALREADY IDLED (2021-06-01T22:00:00Z)",
+	}
+	for i, line := range expected {
+		if i >= len(lines) || strings.Join(strings.Fields(lines[i]), " ") != strings.Join(strings.Fields(line), " ") {
+			t.Fatalf("unexpected summary:\n%s", out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "2 resource(s) idled, 1 service(s) already idled") {
+		t.Errorf("unexpected summary totals:\n%s", out.String())
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -22,6 +23,7 @@ import (
 	units "github.com/docker/go-units"
 	digest "github.com/opencontainers/go-digest"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
@@ -32,6 +34,7 @@ import (
 	"github.com/openshift/oc/pkg/cli/image/imagesource"
 	imagemanifest "github.com/openshift/oc/pkg/cli/image/manifest"
 	"github.com/openshift/oc/pkg/cli/image/workqueue"
+	utilenv "github.com/openshift/oc/pkg/helpers/env"
 	"github.com/openshift/oc/pkg/helpers/image/dockerlayer"
 	"github.com/openshift/oc/pkg/helpers/image/dockerlayer/add"
 )
@@ -57,6 +60,10 @@ var (
 		add the --drop-history flag to remove information from the image about the system that
 		built the base image.
 
+		Labels and environment variables on the image may be set with --label and --env, which
+		may be repeated, and labels removed with --remove-label. These changes are applied after
+		--image and --meta.
+
 		Images in manifest list format will automatically select an image that matches the current
 		operating system and architecture unless you use --filter-by-os to select a different image.
 		This flag has no effect on regular images.
@@ -69,6 +76,9 @@ var (
 		# Add a new layer to the image
 		oc image append --from mysql:latest --to myregistry.com/myimage:latest layer.tar.gz
 
+		# Add a new layer to the image along with a label describing where it came from
+		oc image append --from mysql:latest --to myregistry.com/myimage:latest --label io.example.source=ci layer.tar.gz
+
 		# Add a new layer to the image and store the result on disk
 		# This results in $(pwd)/v2/mysql/blobs,manifests
 		oc image append --from mysql:latest --to file://mysql:local layer.tar.gz
@@ -98,6 +108,13 @@ type AppendImageOptions struct {
 	ConfigPatch string
 	MetaPatch   string
 
+	Labels       []string
+	RemoveLabels []string
+	Env          []string
+
+	labels map[string]string
+	env    []corev1.EnvVar
+
 	ConfigurationCallback func(dgst, contentDigest digest.Digest, config *dockerv1client.DockerImageConfig) error
 	// ToDigest is set after a new image is uploaded
 	ToDigest digest.Digest
@@ -157,6 +174,9 @@ func NewCmdAppendImage(streams genericclioptions.IOStreams) *cobra.Command {
 
 	flag.StringVar(&o.ConfigPatch, "image", o.ConfigPatch, "A JSON patch that will be used with the output image data.")
 	flag.StringVar(&o.MetaPatch, "meta", o.MetaPatch, "A JSON patch that will be used with image base metadata (advanced config).")
+	flag.StringArrayVar(&o.Labels, "label", o.Labels, "A label to set on the output image in the form KEY=VALUE. May be specified multiple times.")
+	flag.StringArrayVar(&o.RemoveLabels, "remove-label", o.RemoveLabels, "The key of a label to remove from the output image. May be specified multiple times.")
+	flag.StringArrayVar(&o.Env, "env", o.Env, "An environment variable to set on the output image in the form KEY=VALUE. May be specified multiple times.")
 	flag.BoolVar(&o.DropHistory, "drop-history", o.DropHistory, "Fields on the image that relate to the history of how the image was created will be removed.")
 	flag.StringVar(&o.CreatedAt, "created-at", o.CreatedAt, "The creation date for this image, in RFC3339 format or milliseconds from the Unix epoch.")
 
@@ -195,7 +215,54 @@ func (o *AppendImageOptions) Complete(cmd *cobra.Command, args []string) error {
 }
 
 func (o *AppendImageOptions) Validate() error {
-	return o.FilterOptions.Validate()
+	if err := o.FilterOptions.Validate(); err != nil {
+		return err
+	}
+
+	o.labels = make(map[string]string)
+	for _, label := range o.Labels {
+		parts := strings.SplitN(label, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("--label must be of the form KEY=VALUE, but is %q", label)
+		}
+		if err := validateLabelKey(parts[0]); err != nil {
+			return fmt.Errorf("--label %q is invalid: %v", label, err)
+		}
+		o.labels[parts[0]] = parts[1]
+	}
+	for _, key := range o.RemoveLabels {
+		if err := validateLabelKey(key); err != nil {
+			return fmt.Errorf("--remove-label %q is invalid: %v", key, err)
+		}
+		if _, ok := o.labels[key]; ok {
+			return fmt.Errorf("--label and --remove-label may not both be specified for %q", key)
+		}
+	}
+
+	o.env = nil
+	for _, env := range o.Env {
+		if !strings.Contains(env, "=") {
+			return fmt.Errorf("--env must be of the form KEY=VALUE, but is %q", env)
+		}
+	}
+	env, _, err := utilenv.ParseEnv(o.Env, nil)
+	if err != nil {
+		return err
+	}
+	o.env = env
+
+	return nil
+}
+
+// validateLabelKey returns an error if key may not be used as an image label.
+func validateLabelKey(key string) error {
+	switch {
+	case len(key) == 0:
+		return fmt.Errorf("the key may not be empty")
+	case strings.ContainsAny(key, " \t\n="):
+		return fmt.Errorf("the key may not contain whitespace or '='")
+	}
+	return nil
 }
 
 func (o *AppendImageOptions) Run() error {
@@ -419,6 +486,7 @@ func (o *AppendImageOptions) append(ctx context.Context, createdAt *time.Time,
 				return fmt.Errorf("unable to patch image from --meta: %v", err)
 			}
 		}
+		updateConfig(base.Config, o.labels, o.RemoveLabels, o.env)
 	}
 
 	if klog.V(4).Enabled() {
@@ -560,6 +628,33 @@ func (o *AppendImageOptions) append(ctx context.Context, createdAt *time.Time,
 	return nil
 }
 
+// updateConfig sets and removes the provided labels and sets the provided environment
+// variables on config, replacing any existing variable with the same name.
+func updateConfig(config *docker10.DockerConfig, labels map[string]string, removeLabels []string, env []corev1.EnvVar) {
+	if len(labels) > 0 && config.Labels == nil {
+		config.Labels = make(map[string]string)
+	}
+	for k, v := range labels {
+		config.Labels[k] = v
+	}
+	for _, k := range removeLabels {
+		delete(config.Labels, k)
+	}
+	for _, e := range env {
+		value := e.Name + "=" + e.Value
+		replaced := false
+		for i, existing := range config.Env {
+			if strings.SplitN(existing, "=", 2)[0] == e.Name {
+				config.Env[i] = value
+				replaced = true
+			}
+		}
+		if !replaced {
+			config.Env = append(config.Env, value)
+		}
+	}
+}
+
 // copyBlob attempts to mirror a blob from one repo to another, mounting it if possible, and calculating the
 // layerDigest if needLayerDigest is true (mounting is not possible if we need to calculate a layerDigest).
 func copyBlob(ctx context.Context, fromBlobs, toBlobs distribution.BlobService, layer distribution.Descriptor, out io.Writer, needLayerDigest bool, mountFrom reference.Named) (distribution.Descriptor, digest.Digest, error) {
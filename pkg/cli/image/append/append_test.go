@@ -0,0 +1,75 @@
+package append
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/api/image/docker10"
+)
+
+func TestUpdateConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       docker10.DockerConfig
+		labels       map[string]string
+		removeLabels []string
+		env          []corev1.EnvVar
+		expected     docker10.DockerConfig
+	}{
+		{
+			name:     "no changes",
+			config:   docker10.DockerConfig{Labels: map[string]string{"a": "1"}, Env: []string{"A=1"}},
+			expected: docker10.DockerConfig{Labels: map[string]string{"a": "1"}, Env: []string{"A=1"}},
+		},
+		{
+			name:     "add labels without existing labels",
+			labels:   map[string]string{"a": "1", "b": ""},
+			expected: docker10.DockerConfig{Labels: map[string]string{"a": "1", "b": ""}},
+		},
+		{
+			name:     "replace label",
+			config:   docker10.DockerConfig{Labels: map[string]string{"a": "1", "b": "2"}},
+			labels:   map[string]string{"a": "3"},
+			expected: docker10.DockerConfig{Labels: map[string]string{"a": "3", "b": "2"}},
+		},
+		{
+			name:         "remove labels",
+			config:       docker10.DockerConfig{Labels: map[string]string{"a": "1", "b": "2"}},
+			removeLabels: []string{"a", "missing"},
+			expected:     docker10.DockerConfig{Labels: map[string]string{"b": "2"}},
+		},
+		{
+			name:         "remove label without existing labels",
+			removeLabels: []string{"a"},
+		},
+		{
+			name:     "add env",
+			config:   docker10.DockerConfig{Env: []string{"PATH=/usr/bin"}},
+			env:      []corev1.EnvVar{{Name: "A", Value: "1"}, {Name: "B"}},
+			expected: docker10.DockerConfig{Env: []string{"PATH=/usr/bin", "A=1", "B="}},
+		},
+		{
+			name:     "replace env in place",
+			config:   docker10.DockerConfig{Env: []string{"A=1", "PATH=/usr/bin", "AB=2"}},
+			env:      []corev1.EnvVar{{Name: "A", Value: "x=y"}},
+			expected: docker10.DockerConfig{Env: []string{"A=x=y", "PATH=/usr/bin", "AB=2"}},
+		},
+		{
+			name:     "replace env without value",
+			config:   docker10.DockerConfig{Env: []string{"A"}},
+			env:      []corev1.EnvVar{{Name: "A", Value: "1"}},
+			expected: docker10.DockerConfig{Env: []string{"A=1"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := test.config
+			updateConfig(&config, test.labels, test.removeLabels, test.env)
+			if !reflect.DeepEqual(config, test.expected) {
+				t.Errorf("unexpected config:\n%#v\nexpected:\n%#v", config, test.expected)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -19,6 +20,7 @@ import (
 	dockerarchive "github.com/docker/docker/pkg/archive"
 	digest "github.com/opencontainers/go-digest"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/klog/v2"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
@@ -50,6 +52,12 @@ var (
 		current operating system will be chosen. Otherwise you must pass --filter-by-os to
 		select the desired image.
 
+		Layers that fail to download are retried up to --max-retries times, waiting longer between
+		each attempt. An interrupted extraction into a directory can be resumed by running the
+		command again with --confirm and --resume, which skips the files that were already fully
+		extracted. Only the size and modification time of existing files are compared, so do not
+		use --resume to update a directory that holds the contents of a different image.
+
 		You may further qualify the image by adding a layer selector to the end of the image
 		string to only extract specific layers within an image. The supported selectors are:
 
@@ -93,6 +101,9 @@ var (
 		# Extract an image stored on disk in a directory other than $(pwd)/v2 into a designated directory (must exist)
 		oc image extract file://busybox:local --dir busybox-mirror-dir --path /:/tmp/busybox
 
+		# Resume an interrupted extraction of a large image, retrying each layer up to 5 times
+		oc image extract quay.io/example/large:latest --path /:/tmp/large --confirm --resume --max-retries=5
+
 		# Extract the last layer in the image
 		oc image extract docker.io/library/centos:7[-1]
 
@@ -129,6 +140,11 @@ type ExtractOptions struct {
 
 	Confirm bool
 	DryRun  bool
+	// Resume skips the files that an earlier extraction into the same directory completed.
+	Resume bool
+
+	// MaxRetries is the number of times fetching a layer is retried before giving up.
+	MaxRetries int
 
 	FileDir string
 
@@ -152,6 +168,7 @@ func NewExtractOptions(streams genericclioptions.IOStreams) *ExtractOptions {
 
 		IOStreams:       streams,
 		ParallelOptions: imagemanifest.ParallelOptions{MaxPerRegistry: 1},
+		MaxRetries:      3,
 	}
 }
 
@@ -176,6 +193,7 @@ func NewExtract(streams genericclioptions.IOStreams) *cobra.Command {
 	o.FilterOptions.Bind(flag)
 
 	flag.BoolVar(&o.Confirm, "confirm", o.Confirm, "Pass to allow extracting to non-empty directories.")
+	flag.BoolVar(&o.Resume, "resume", o.Resume, "Skip files that already exist in the destination directory with the size and modification time of the file in the image, to resume an interrupted extraction.")
 	flag.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Print the actions that would be taken and exit without writing any contents.")
 
 	flag.StringSliceVar(&o.Files, "file", o.Files, "Extract the specified files to the current directory.")
@@ -184,6 +202,7 @@ func NewExtract(streams genericclioptions.IOStreams) *cobra.Command {
 	flag.BoolVar(&o.OnlyFiles, "only-files", o.OnlyFiles, "Only extract regular files and directories from the image.")
 	flag.BoolVar(&o.AllLayers, "all-layers", o.AllLayers, "For dry-run mode, process from lowest to highest layer and don't omit duplicate files.")
 	flag.StringVar(&o.FileDir, "dir", o.FileDir, "The directory on disk that file:// images will be extracted from.")
+	flag.IntVar(&o.MaxRetries, "max-retries", o.MaxRetries, "The number of times to retry fetching a layer that failed to download, with an increasing delay between attempts.")
 
 	return cmd
 }
@@ -332,6 +351,9 @@ func (o *ExtractOptions) Validate() error {
 	if len(o.Mappings) == 0 {
 		return fmt.Errorf("you must specify one or more paths or files")
 	}
+	if o.MaxRetries < 0 {
+		return fmt.Errorf("--max-retries must be zero or greater")
+	}
 	return o.FilterOptions.Validate()
 }
 
@@ -463,10 +485,15 @@ func (o *ExtractOptions) Run() error {
 					}
 				}
 
+				// files extracted completely by an earlier run are not written again
+				if byEntry == nil && o.Resume {
+					alter = append(alter, skipExtracted{dir: mapping.To})
+				}
+
 				for _, info := range layerInfos {
 					layer := info.Descriptor
 
-					cont, err := func() (bool, error) {
+					cont, err := retryLayer(o.ErrOut, o.MaxRetries, func() (bool, bool, error) {
 						fromBlobs := repo.Blobs(ctx)
 
 						klog.V(5).Infof("Extracting from layer: %#v", layer)
@@ -474,7 +501,7 @@ func (o *ExtractOptions) Run() error {
 						// source
 						r, err := fromBlobs.Open(ctx, layer.Digest)
 						if err != nil {
-							return false, fmt.Errorf("unable to access the source layer %s: %v", layer.Digest, err)
+							return false, true, fmt.Errorf("unable to access the source layer %s: %v", layer.Digest, err)
 						}
 						defer r.Close()
 
@@ -484,19 +511,22 @@ func (o *ExtractOptions) Run() error {
 						}
 
 						if byEntry != nil {
+							// entries already passed to the callback cannot be taken back, so the
+							// layer is not fetched again
 							cont, err := layerByEntry(r, options, info, byEntry, o.AllLayers, alreadySeen)
 							if err != nil {
 								err = fmt.Errorf("unable to iterate over layer %s from %s: %v", layer.Digest, from, err)
 							}
-							return cont, err
+							return cont, false, err
 						}
 
 						klog.V(4).Infof("Extracting layer %s with options %#v", layer.Digest, options)
-						if _, err := archive.ApplyLayer(mapping.To, r, options); err != nil {
-							return false, fmt.Errorf("unable to extract layer %s from %s: %v", layer.Digest, from, err)
+						fetch := &fetchReader{Reader: r}
+						if _, err := archive.ApplyLayer(mapping.To, fetch, options); err != nil {
+							return false, fetch.err != nil, fmt.Errorf("unable to extract layer %s from %s: %v", layer.Digest, from, err)
 						}
-						return true, nil
-					}()
+						return true, false, nil
+					})
 					if err != nil {
 						return err
 					}
@@ -558,6 +588,40 @@ func layerByEntry(r io.Reader, options *archive.TarOptions, layerInfo LayerInfo,
 	}
 }
 
+// layerRetryBackoff is the delay before fetching a layer again, doubled after every attempt.
+var layerRetryBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Cap: time.Minute}
+
+// retryLayer invokes fn until it succeeds, returns an error that is not retriable, or has been
+// retried maxRetries times.
+func retryLayer(errOut io.Writer, maxRetries int, fn func() (cont bool, retriable bool, err error)) (bool, error) {
+	backoff := layerRetryBackoff
+	backoff.Steps = maxRetries
+	for {
+		cont, retriable, err := fn()
+		if err == nil || !retriable || backoff.Steps == 0 {
+			return cont, err
+		}
+		delay := backoff.Step()
+		fmt.Fprintf(errOut, "warning: %v, retrying in %s\n", err, delay.Round(time.Millisecond))
+		time.Sleep(delay)
+	}
+}
+
+// fetchReader records the last error reading a layer, which distinguishes a layer that could
+// not be downloaded from one that could not be written to disk.
+type fetchReader struct {
+	io.Reader
+	err error
+}
+
+func (r *fetchReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
 type alterations []archive.AlterHeader
 
 func (a alterations) Alter(hdr *tar.Header) (bool, error) {
@@ -585,6 +649,30 @@ func (_ removePermissions) Alter(hdr *tar.Header) (bool, error) {
 	return true, nil
 }
 
+// skipExtracted excludes regular files that already exist in dir with the size and modification
+// time of the entry. Extracted files are given the modification time of their entry only once
+// all their contents were written, so a partially written file is always extracted again.
+type skipExtracted struct {
+	dir string
+}
+
+func (s skipExtracted) Alter(hdr *tar.Header) (bool, error) {
+	switch hdr.Typeflag {
+	case tar.TypeReg, tar.TypeRegA:
+	default:
+		return true, nil
+	}
+	fi, err := os.Lstat(filepath.Join(s.dir, filepath.Clean(hdr.Name)))
+	if err != nil || !fi.Mode().IsRegular() {
+		return true, nil
+	}
+	if fi.Size() != hdr.Size || !fi.ModTime().Equal(hdr.ModTime) {
+		return true, nil
+	}
+	klog.V(5).Infof("Skipping %s, it was already extracted", hdr.Name)
+	return false, nil
+}
+
 type writableDirectories struct{}
 
 func (_ writableDirectories) Alter(hdr *tar.Header) (bool, error) {
@@ -0,0 +1,96 @@
+package extract
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRetryLayer(t *testing.T) {
+	backoff := layerRetryBackoff
+	defer func() { layerRetryBackoff = backoff }()
+	layerRetryBackoff.Duration = time.Millisecond
+
+	tests := []struct {
+		name       string
+		maxRetries int
+		failures   int
+		retriable  bool
+		wantCalls  int
+		wantErr    bool
+	}{
+		{name: "succeeds", maxRetries: 3, wantCalls: 1},
+		{name: "succeeds after retries", maxRetries: 3, failures: 2, retriable: true, wantCalls: 3},
+		{name: "gives up", maxRetries: 2, failures: 5, retriable: true, wantCalls: 3, wantErr: true},
+		{name: "no retries", maxRetries: 0, failures: 1, retriable: true, wantCalls: 1, wantErr: true},
+		{name: "not retriable", maxRetries: 3, failures: 1, wantCalls: 1, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			errOut := &bytes.Buffer{}
+			cont, err := retryLayer(errOut, test.maxRetries, func() (bool, bool, error) {
+				calls++
+				if calls <= test.failures {
+					return false, test.retriable, fmt.Errorf("unexpected EOF")
+				}
+				return true, false, nil
+			})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cont == test.wantErr {
+				t.Errorf("unexpected cont: %t", cont)
+			}
+			if calls != test.wantCalls {
+				t.Errorf("expected %d attempts, got %d: %s", test.wantCalls, calls, errOut.String())
+			}
+		})
+	}
+}
+
+func TestSkipExtracted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "extract")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	modTime := time.Unix(1600000000, 0)
+	write := func(name, contents string, modTime time.Time) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0640); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filepath.Join(dir, name), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("complete", "12345", modTime)
+	write("partial", "12", time.Now())
+	write("modified", "12345", time.Now())
+
+	tests := []struct {
+		hdr  tar.Header
+		want bool
+	}{
+		{hdr: tar.Header{Name: "complete", Typeflag: tar.TypeReg, Size: 5, ModTime: modTime}, want: false},
+		{hdr: tar.Header{Name: "partial", Typeflag: tar.TypeReg, Size: 5, ModTime: modTime}, want: true},
+		{hdr: tar.Header{Name: "modified", Typeflag: tar.TypeReg, Size: 5, ModTime: modTime}, want: true},
+		{hdr: tar.Header{Name: "missing", Typeflag: tar.TypeReg, Size: 5, ModTime: modTime}, want: true},
+		{hdr: tar.Header{Name: "complete", Typeflag: tar.TypeSymlink, Linkname: "other", ModTime: modTime}, want: true},
+	}
+	for _, test := range tests {
+		ok, err := skipExtracted{dir: dir}.Alter(&test.hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != test.want {
+			t.Errorf("%s %c: expected extract=%t, got %t", test.hdr.Name, test.hdr.Typeflag, test.want, ok)
+		}
+	}
+}
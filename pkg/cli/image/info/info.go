@@ -53,6 +53,11 @@ func NewInfo(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Com
 
 			Images in manifest list format will be shown for your current operating system.
 			To see the image for a particular OS use the --filter-by-os=OS/ARCH flag.
+
+			Pass --as-release-component to find the component of an OpenShift release that
+			the image belongs to. The image is looked up by digest in the release the
+			cluster is running, or in the release image given with --release, so images
+			mirrored to another registry are found as well.
 		`),
 		Example: templates.Examples(`
 			# Show information about an image
@@ -67,6 +72,11 @@ func NewInfo(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Com
 			# Select which image from a multi-OS image to show
 			oc image info library/busybox:latest --filter-by-os=linux/arm64
 
+			# Show the component of the cluster's release that the image of a pod belongs to
+			oc image info --as-release-component quay.io/openshift-release-dev/ocp-v4.0-art-dev@sha256:...
+
+			# Show the component of a given release that an image belongs to
+			oc image info --as-release-component --release=quay.io/openshift-release-dev/ocp-release:4.10.0-x86_64 IMAGE
 		`),
 		Run: func(cmd *cobra.Command, args []string) {
 			kcmdutil.CheckErr(o.Complete(f, cmd, args))
@@ -80,6 +90,8 @@ func NewInfo(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Com
 	flags.StringVarP(&o.Output, "output", "o", o.Output, "Print the image in an alternative format: json")
 	flags.StringVar(&o.FileDir, "dir", o.FileDir, "The directory on disk that file:// images will be read from.")
 	flags.StringVar(&o.ICSPFile, "icsp-file", o.ICSPFile, "Path to an ImageContentSourcePolicy file.  If set, data from this file will be used to find alternative locations for images.")
+	flags.BoolVar(&o.AsReleaseComponent, "as-release-component", o.AsReleaseComponent, "Show the component of the release that the image belongs to. Uses the release of the cluster unless --release is given.")
+	flags.StringVar(&o.Release, "release", o.Release, "With --as-release-component, the release image to look the image up in instead of the release of the cluster.")
 
 	return cmd
 }
@@ -94,6 +106,9 @@ type InfoOptions struct {
 	FileDir  string
 	Output   string
 	ICSPFile string
+
+	AsReleaseComponent bool
+	Release            string
 }
 
 func (o *InfoOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
@@ -102,6 +117,13 @@ func (o *InfoOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []st
 	}
 	o.Images = args
 
+	if o.AsReleaseComponent && len(o.Release) == 0 {
+		release, err := clusterReleaseImage(f)
+		if err != nil {
+			return err
+		}
+		o.Release = release
+	}
 	return nil
 }
 
@@ -109,6 +131,9 @@ func (o *InfoOptions) Validate(cmd *cobra.Command) error {
 	if len(o.Images) == 0 {
 		return fmt.Errorf("must specify one or more images as arguments")
 	}
+	if len(o.Release) > 0 && !o.AsReleaseComponent {
+		return fmt.Errorf("--release may only be used with --as-release-component")
+	}
 	return o.FilterOptions.Validate()
 }
 
@@ -127,6 +152,13 @@ func (o *InfoOptions) Run() error {
 		RegistryContext: registryContext,
 	}
 
+	var release *releaseComponents
+	if o.AsReleaseComponent {
+		if release, err = o.loadReleaseComponents(); err != nil {
+			return err
+		}
+	}
+
 	hadError := false
 	icspWarned := false
 	for _, location := range o.Images {
@@ -182,10 +214,22 @@ func (o *InfoOptions) Run() error {
 				return err
 			}
 
+			var component *ReleaseComponent
+			if release != nil {
+				component = release.Find(image)
+			}
+
 			switch o.Output {
 			case "":
 			case "json":
-				data, err := json.MarshalIndent(image, "", "  ")
+				var obj interface{} = image
+				if release != nil {
+					obj = struct {
+						*Image
+						ReleaseComponent *ReleaseComponent `json:"releaseComponent"`
+					}{image, component}
+				}
+				data, err := json.MarshalIndent(obj, "", "  ")
 				if err != nil {
 					return err
 				}
@@ -195,7 +239,7 @@ func (o *InfoOptions) Run() error {
 				return fmt.Errorf("unrecognized --output, only 'json' is supported")
 			}
 
-			if err := describeImage(o.Out, image); err != nil {
+			if err := describeImage(o.Out, image, release, component); err != nil {
 				hadError = true
 				if err != kcmdutil.ErrExit {
 					fmt.Fprintf(o.ErrOut, "error: %v", err)
@@ -222,7 +266,18 @@ type Image struct {
 	Manifest distribution.Manifest `json:"-"`
 }
 
-func describeImage(out io.Writer, image *Image) error {
+// describeReleaseComponent prints the component of the release that an image belongs to, if any.
+func describeReleaseComponent(w io.Writer, release *releaseComponents, component *ReleaseComponent) {
+	if component == nil {
+		fmt.Fprintf(w, "Release Component:\t<none>, the image is not part of release %s (%s)\n", release.Version, release.Release)
+		return
+	}
+	fmt.Fprintf(w, "Release Component:\t%s\n", component.Name)
+	fmt.Fprintf(w, "Release Version:\t%s\n", component.Version)
+	fmt.Fprintf(w, "Release Image:\t%s\n", component.Release)
+}
+
+func describeImage(out io.Writer, image *Image, release *releaseComponents, component *ReleaseComponent) error {
 	var err error
 
 	w := tabwriter.NewWriter(out, 0, 4, 1, ' ', 0)
@@ -238,6 +293,9 @@ func describeImage(out io.Writer, image *Image) error {
 		fmt.Fprintf(w, "Content Digest:\t%s\n\tERROR: the image contents do not match the requested digest, this image has been tampered with\n", image.ContentDigest)
 		err = kcmdutil.ErrExit
 	}
+	if release != nil {
+		describeReleaseComponent(w, release, component)
+	}
 
 	fmt.Fprintf(w, "Media Type:\t%s\n", image.MediaType)
 	if image.Config.Created.IsZero() {
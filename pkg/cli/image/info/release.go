@@ -0,0 +1,148 @@
+package info
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+	digest "github.com/opencontainers/go-digest"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	configv1client "github.com/openshift/client-go/config/clientset/versioned"
+	imagereference "github.com/openshift/library-go/pkg/image/reference"
+	"github.com/openshift/oc/pkg/cli/image/extract"
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+)
+
+// ReleaseComponent identifies the component of a release payload an image belongs to.
+type ReleaseComponent struct {
+	// Name is the name of the component in the image references of the release.
+	Name string `json:"name"`
+	// Release is the pull spec of the release image.
+	Release string `json:"release"`
+	// Version is the version of the release.
+	Version string `json:"version"`
+}
+
+// releaseComponents holds the components of a release by the digests of their images.
+type releaseComponents struct {
+	Release    string
+	Version    string
+	Components map[digest.Digest]string
+}
+
+// Find returns the component of the release that image belongs to, or nil if it is not part
+// of the release. Images are matched by digest, so mirrored images are found as well.
+func (r *releaseComponents) Find(image *Image) *ReleaseComponent {
+	for _, dgst := range []digest.Digest{image.Digest, image.ListDigest, digest.Digest(image.Ref.Ref.ID)} {
+		if len(dgst) == 0 {
+			continue
+		}
+		if name, ok := r.Components[dgst]; ok {
+			return &ReleaseComponent{Name: name, Release: r.Release, Version: r.Version}
+		}
+	}
+	return nil
+}
+
+// newReleaseComponents indexes the images of the image references of a release by digest.
+func newReleaseComponents(release string, is *imagev1.ImageStream) *releaseComponents {
+	components := &releaseComponents{
+		Release:    release,
+		Version:    is.Name,
+		Components: make(map[digest.Digest]string),
+	}
+	for _, tag := range is.Spec.Tags {
+		if tag.From == nil || tag.From.Kind != "DockerImage" {
+			continue
+		}
+		ref, err := imagereference.Parse(tag.From.Name)
+		if err != nil || len(ref.ID) == 0 {
+			klog.V(4).Infof("Ignoring release component %q without a digest: %s", tag.Name, tag.From.Name)
+			continue
+		}
+		components.Components[digest.Digest(ref.ID)] = tag.Name
+	}
+	return components
+}
+
+// clusterReleaseImage returns the pull spec of the release the cluster is running or updating to.
+func clusterReleaseImage(f kcmdutil.Factory) (string, error) {
+	cfg, err := f.ToRESTConfig()
+	if err != nil {
+		return "", fmt.Errorf("--as-release-component requires --release or a connection to an OpenShift 4.x server: %v", err)
+	}
+	client, err := configv1client.NewForConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+	cv, err := client.ConfigV1().ClusterVersions().Get(context.TODO(), "version", metav1.GetOptions{})
+	if err != nil {
+		if kapierrors.IsNotFound(err) {
+			return "", fmt.Errorf("the server has no cluster version, specify the release image with --release")
+		}
+		return "", fmt.Errorf("unable to find the release of the cluster, specify the release image with --release: %v", err)
+	}
+	if len(cv.Status.Desired.Image) == 0 {
+		return "", fmt.Errorf("the server is not reporting a release image at this time, specify the release image with --release")
+	}
+	return cv.Status.Desired.Image, nil
+}
+
+// loadReleaseComponents reads the image references of the release image.
+func (o *InfoOptions) loadReleaseComponents() (*releaseComponents, error) {
+	ref, err := imagesource.ParseReference(o.Release)
+	if err != nil {
+		return nil, fmt.Errorf("--release is not a valid image reference: %v", err)
+	}
+
+	opts := extract.NewExtractOptions(genericclioptions.IOStreams{Out: o.Out, ErrOut: o.ErrOut})
+	opts.SecurityOptions = o.SecurityOptions
+	opts.FilterOptions = o.FilterOptions
+	opts.FileDir = o.FileDir
+	opts.OnlyFiles = true
+	opts.Mappings = []extract.Mapping{
+		{
+			ImageRef: ref,
+
+			From:        "release-manifests/",
+			To:          ".",
+			LayerFilter: extract.NewPositionLayerFilter(-1),
+		},
+	}
+	var data []byte
+	opts.TarEntryCallback = func(hdr *tar.Header, _ extract.LayerInfo, r io.Reader) (bool, error) {
+		if hdr.Name != "image-references" {
+			return true, nil
+		}
+		var err error
+		if data, err = ioutil.ReadAll(r); err != nil {
+			return false, fmt.Errorf("unable to read release image-references: %v", err)
+		}
+		return false, nil
+	}
+	if err := opts.Run(); err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("release image %s did not contain an image-references file", o.Release)
+	}
+
+	is := &imagev1.ImageStream{}
+	if err := yaml.Unmarshal(data, is); err != nil {
+		return nil, fmt.Errorf("unable to load release image-references: %v", err)
+	}
+	if is.Kind != "ImageStream" || is.APIVersion != "image.openshift.io/v1" {
+		return nil, fmt.Errorf("unrecognized image-references in release payload")
+	}
+	return newReleaseComponents(o.Release, is), nil
+}
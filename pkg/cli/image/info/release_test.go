@@ -0,0 +1,60 @@
+package info
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	imagereference "github.com/openshift/library-go/pkg/image/reference"
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+)
+
+func TestReleaseComponentsFind(t *testing.T) {
+	const (
+		cliDigest     = "sha256:0000000000000000000000000000000000000000000000000000000000000001"
+		consoleDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000002"
+		otherDigest   = "sha256:0000000000000000000000000000000000000000000000000000000000000003"
+	)
+	is := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{Name: "4.10.3"},
+		Spec: imagev1.ImageStreamSpec{
+			Tags: []imagev1.TagReference{
+				{Name: "cli", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + cliDigest}},
+				{Name: "console", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift-release-dev/ocp-v4.0-art-dev@" + consoleDigest}},
+				{Name: "tagged", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/openshift/origin-tagged:latest"}},
+			},
+		},
+	}
+	release := newReleaseComponents("quay.io/openshift-release-dev/ocp-release:4.10.3-x86_64", is)
+
+	tests := []struct {
+		name     string
+		image    *Image
+		expected *ReleaseComponent
+	}{
+		{
+			name:     "mirrored image by digest",
+			image:    &Image{Digest: cliDigest, Ref: imagesource.TypedImageReference{Ref: imagereference.DockerImageReference{Registry: "mirror.example.com", Name: "ocp", ID: cliDigest}}},
+			expected: &ReleaseComponent{Name: "cli", Release: "quay.io/openshift-release-dev/ocp-release:4.10.3-x86_64", Version: "4.10.3"},
+		},
+		{
+			name:     "image of a manifest list",
+			image:    &Image{Digest: otherDigest, ListDigest: consoleDigest},
+			expected: &ReleaseComponent{Name: "console", Release: "quay.io/openshift-release-dev/ocp-release:4.10.3-x86_64", Version: "4.10.3"},
+		},
+		{
+			name:  "image not in the release",
+			image: &Image{Digest: otherDigest},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := release.Find(test.image); !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("expected %#v, got %#v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	godigest "github.com/opencontainers/go-digest"
+)
+
+// pushedManifest is a manifest that was stored in a destination, with the digest the
+// destination reported for it. The digest may differ from the source digest when the
+// manifest had to be converted to a schema the destination accepts.
+type pushedManifest struct {
+	Destination string          `json:"destination"`
+	Source      godigest.Digest `json:"sourceDigest"`
+	Digest      godigest.Digest `json:"digest"`
+}
+
+// printPushedManifests writes one "DESTINATION DIGEST" line per pushed manifest, or a
+// JSON list of the manifests if output is json.
+func printPushedManifests(out io.Writer, pushed []pushedManifest, output string) error {
+	if output == "json" {
+		if pushed == nil {
+			pushed = []pushedManifest{}
+		}
+		data, err := json.MarshalIndent(pushed, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	for _, m := range pushed {
+		fmt.Fprintf(out, "%s %s\n", m.Destination, m.Digest)
+	}
+	return nil
+}
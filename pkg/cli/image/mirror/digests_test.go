@@ -0,0 +1,124 @@
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/distribution/reference"
+	godigest "github.com/opencontainers/go-digest"
+
+	imagereference "github.com/openshift/library-go/pkg/image/reference"
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+)
+
+// fakeManifestService stores manifests by the digest of their payload, as a registry does.
+type fakeManifestService struct {
+	stored map[godigest.Digest][]byte
+	tags   map[string]godigest.Digest
+}
+
+func (s *fakeManifestService) Exists(ctx context.Context, dgst godigest.Digest) (bool, error) {
+	_, ok := s.stored[dgst]
+	return ok, nil
+}
+
+func (s *fakeManifestService) Get(ctx context.Context, dgst godigest.Digest, options ...distribution.ManifestServiceOption) (distribution.Manifest, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (s *fakeManifestService) Put(ctx context.Context, manifest distribution.Manifest, options ...distribution.ManifestServiceOption) (godigest.Digest, error) {
+	_, payload, err := manifest.Payload()
+	if err != nil {
+		return "", err
+	}
+	dgst := godigest.FromBytes(payload)
+	s.stored[dgst] = payload
+	for _, option := range options {
+		if tag, ok := option.(distribution.WithTagOption); ok {
+			s.tags[tag.Tag] = dgst
+		}
+	}
+	return dgst, nil
+}
+
+func (s *fakeManifestService) Delete(ctx context.Context, dgst godigest.Digest) error {
+	return fmt.Errorf("not implemented")
+}
+
+func TestPrintPushedManifests(t *testing.T) {
+	srcManifest, err := schema2.FromStruct(schema2.Manifest{
+		Versioned: schema2.SchemaVersion,
+		Config:    distribution.Descriptor{MediaType: schema2.MediaTypeImageConfig, Digest: godigest.FromString("config"), Size: 6},
+		Layers: []distribution.Descriptor{
+			{MediaType: schema2.MediaTypeLayer, Digest: godigest.FromString("layer"), Size: 5},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, payload, _ := srcManifest.Payload()
+	srcDigest := godigest.FromBytes(payload)
+
+	to := &fakeManifestService{stored: make(map[godigest.Digest][]byte), tags: make(map[string]godigest.Digest)}
+	toRef := imagesource.TypedImageReference{Type: imagesource.DestinationRegistry, Ref: imagereference.DockerImageReference{Registry: "registry.example.com", Namespace: "ns", Name: "repo"}}
+
+	p := newPlan()
+	op := p.RegistryPlan(toRef).RepositoryPlan(toRef.Ref.RepositoryName()).Manifests()
+	op.Copy(srcDigest, srcManifest, []string{"latest", "stable"}, to, nil)
+	ref, err := reference.WithName(op.toRef.Ref.RepositoryName())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := &bytes.Buffer{}
+	if errs := copyManifestToTags(context.Background(), ref, srcDigest, []string{"latest", "stable"}, op, progress, progress); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if err := copyManifest(context.Background(), ref, srcDigest, op, progress, progress); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pushed := p.PushedManifests()
+	if len(pushed) != 3 {
+		t.Fatalf("expected 3 pushed manifests, got %#v", pushed)
+	}
+	for _, m := range pushed {
+		if _, ok := to.stored[m.Digest]; !ok {
+			t.Errorf("printed digest %s for %s was not stored by the destination", m.Digest, m.Destination)
+		}
+		if m.Source != srcDigest {
+			t.Errorf("unexpected source digest for %s: %s", m.Destination, m.Source)
+		}
+	}
+	for _, tag := range []string{"latest", "stable"} {
+		if to.tags[tag] != srcDigest {
+			t.Errorf("expected tag %s to point to %s, got %s", tag, srcDigest, to.tags[tag])
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := printPushedManifests(out, pushed, ""); err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("registry.example.com/ns/repo %[1]s\nregistry.example.com/ns/repo:latest %[1]s\nregistry.example.com/ns/repo:stable %[1]s\n", srcDigest)
+	if out.String() != expected {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	out.Reset()
+	if err := printPushedManifests(out, pushed, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []pushedManifest
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("unable to decode output: %v\n%s", err, out.String())
+	}
+	if len(decoded) != 3 || decoded[1].Destination != "registry.example.com/ns/repo:latest" || decoded[1].Digest != to.tags["latest"] {
+		t.Errorf("unexpected json output: %s", out.String())
+	}
+}
@@ -63,6 +63,11 @@ var (
 
 		Images in manifest list format will be copied as-is unless you use --filter-by-os to restrict
 		the allowed images to copy in a manifest list. This flag has no effect on regular images.
+
+		Pass --dry-run to plan a mirror without writing to the destinations. The source manifests
+		are resolved and destination registries are checked for the manifests and blobs they
+		already have. The plan lists the blobs each mapping would copy with their total size,
+		followed by the total size that would be transferred.
 	`)
 
 	mirrorExample = templates.Examples(`
@@ -111,6 +116,13 @@ var (
 		# Note the above command is equivalent to
 		oc image mirror myregistry.com/myimage:latest=myregistry.com/other:test \
 			--filter-by-os=.*
+
+		# Estimate how much data mirroring images to another registry would transfer
+		oc image mirror myregistry.com/myimage:latest docker.io/myrepository/myimage:stable --dry-run
+
+		# Copy an image and print the digest of each pushed manifest as JSON
+		oc image mirror myregistry.com/myimage:latest docker.io/myrepository/myimage:stable \
+			--print-digests -o json
 	`)
 )
 
@@ -127,6 +139,8 @@ type MirrorImageOptions struct {
 	Force              bool
 	KeepManifestList   bool
 	ContinueOnError    bool
+	PrintDigests       bool
+	Output             string
 
 	MaxRegistry     int
 	ParallelOptions imagemanifest.ParallelOptions
@@ -171,13 +185,15 @@ func NewCmdMirrorImage(streams genericclioptions.IOStreams) *cobra.Command {
 	o.FilterOptions.Bind(flag)
 	o.ParallelOptions.Bind(flag)
 
-	flag.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Print the actions that would be taken and exit without writing to the destinations.")
+	flag.BoolVar(&o.DryRun, "dry-run", o.DryRun, "Print the actions that would be taken and the size of the blobs that would be copied, and exit without writing to the destinations.")
 	flag.BoolVar(&o.ContinueOnError, "continue-on-error", o.ContinueOnError, "If an error occurs, keep going and attempt to mirror as much as possible.")
 	flag.BoolVar(&o.SkipMissing, "skip-missing", o.SkipMissing, "If an input image is not found, skip them.")
 	flag.BoolVar(&o.SkipMount, "skip-mount", o.SkipMount, "Always push layers instead of cross-mounting them")
 	flag.BoolVar(&o.SkipMultipleScopes, "skip-multiple-scopes", o.SkipMultipleScopes, "Some registries do not support multiple scopes passed to the registry login.")
 	flag.BoolVar(&o.Force, "force", o.Force, "Attempt to write all layers and manifests even if they exist in the remote repository.")
 	flag.BoolVar(&o.KeepManifestList, "keep-manifest-list", o.KeepManifestList, "If an image is part of a manifest list, always mirror the list even if only one image is found. The default is to mirror the specific image unless unless --filter-by-os is passed. This flag is equivalent to setting --filter-by-os to '.*' since you cannot preserve the manifest list digest while filtering out any of the manifests included in the list.")
+	flag.BoolVar(&o.PrintDigests, "print-digests", o.PrintDigests, "After mirroring, print each destination with the digest of the manifest the destination stored.")
+	flag.StringVarP(&o.Output, "output", "o", o.Output, "Print the digests from --print-digests in an alternative format: json")
 	flag.IntVar(&o.MaxRegistry, "max-registry", o.MaxRegistry, "Number of concurrent registries to connect to at any one time.")
 	flag.StringSliceVar(&o.AttemptS3BucketCopy, "s3-source-bucket", o.AttemptS3BucketCopy, "A list of bucket/path locations on S3 that may contain already uploaded blobs. Add [store] to the end to use the container image registry path convention.")
 	flag.StringSliceVarP(&o.Filenames, "filename", "f", o.Filenames, "One or more files to read SRC=DST or SRC DST [DST ...] mappings from.")
@@ -262,6 +278,18 @@ func (o *MirrorImageOptions) Validate() error {
 	if o.KeepManifestList && len(o.FilterOptions.FilterByOS) > 0 && !o.FilterOptions.IsWildcardFilter() {
 		return fmt.Errorf("--keep-manifest-list=true cannot be passed with --filter-by-os, unless --filter-by-os=.*")
 	}
+	if o.PrintDigests && o.DryRun {
+		return fmt.Errorf("--print-digests cannot be used with --dry-run, no manifests are pushed")
+	}
+	switch o.Output {
+	case "":
+	case "json":
+		if !o.PrintDigests {
+			return fmt.Errorf("--output may only be specified with --print-digests")
+		}
+	default:
+		return fmt.Errorf("--output only supports 'json'")
+	}
 	return o.FilterOptions.Validate()
 }
 
@@ -292,10 +320,18 @@ func (o *MirrorImageOptions) Run() error {
 	fmt.Fprintf(o.ErrOut, "info: Planning completed in %s\n", time.Now().Sub(start).Round(10*time.Millisecond))
 
 	if o.DryRun {
+		p.PrintDryRunSummary(o.ErrOut, o.SkipMount)
+		fmt.Fprintln(o.ErrOut)
 		fmt.Fprintf(o.ErrOut, "info: Dry run complete\n")
 		return nil
 	}
 
+	// when digests are printed at the end, keep the progress of each push off stdout
+	out := o.Out
+	if o.PrintDigests {
+		out = o.ErrOut
+	}
+
 	// we must have a client available for accessing referential URLs
 	referentialClient, err := o.SecurityOptions.ReferentialHTTPClient()
 	if err != nil {
@@ -377,7 +413,7 @@ func (o *MirrorImageOptions) Run() error {
 								srcDigest := digest
 								tags := op.digestsToTags[srcDigest].List()
 								w.Parallel(func() {
-									if errs := copyManifestToTags(ctx, ref, srcDigest, tags, op, o.Out, o.ErrOut); len(errs) > 0 {
+									if errs := copyManifestToTags(ctx, ref, srcDigest, tags, op, out, o.ErrOut); len(errs) > 0 {
 										phase.ExecutionFailure(errs...)
 									}
 								})
@@ -393,7 +429,7 @@ func (o *MirrorImageOptions) Run() error {
 
 								srcDigest := godigest.Digest(digest)
 								w.Parallel(func() {
-									if err := copyManifest(ctx, ref, srcDigest, op, o.Out, o.ErrOut); err != nil {
+									if err := copyManifest(ctx, ref, srcDigest, op, out, o.ErrOut); err != nil {
 										phase.ExecutionFailure(err)
 									}
 								})
@@ -421,6 +457,12 @@ func (o *MirrorImageOptions) Run() error {
 		}
 	}
 
+	if o.PrintDigests {
+		if err := printPushedManifests(o.Out, p.PushedManifests(), o.Output); err != nil {
+			return err
+		}
+	}
+
 	if o.ManifestUpdateCallback != nil {
 		for _, reg := range p.registries {
 			klog.V(4).Infof("Manifests mapped %#v", reg.manifestConversions)
@@ -452,6 +494,8 @@ func (o *MirrorImageOptions) plan() (*plan, error) {
 	}
 	fromContext := context.Copy()
 	toContext := context.Copy().WithActions("pull", "push")
+	// a dry run only reads from the destinations to find the content they already have
+	dryRunContext := context.Copy()
 	toContexts := make(map[contextKey]*registryclient.Context)
 
 	tree := buildTargetTree(o.Mappings)
@@ -550,9 +594,12 @@ func (o *MirrorImageOptions) plan() (*plan, error) {
 						for _, dst := range pushTargets {
 							var toRepo distribution.Repository
 							var err error
-							if o.DryRun {
+							switch {
+							case o.DryRun && dst.ref.Type == imagesource.DestinationRegistry:
+								toRepo, err = o.Repository(ctx, dryRunContext, dst.ref, false)
+							case o.DryRun:
 								toRepo, err = imagesource.NewDryRun(dst.ref)
-							} else {
+							default:
 								toRepo, err = o.Repository(ctx, toContexts[contextKeyForReference(dst.ref)], dst.ref, false)
 							}
 							if err != nil {
@@ -592,6 +639,8 @@ func (o *MirrorImageOptions) plan() (*plan, error) {
 							if mustCopyLayers {
 								// upload all the blobs
 								srcBlobs := srcRepo.Blobs(ctx)
+								// a dry run skips the blobs the destination already has, until it fails to answer
+								checkBlobs := o.DryRun && !o.Force
 
 								// upload each manifest
 								for _, srcManifest := range srcManifests {
@@ -610,6 +659,15 @@ func (o *MirrorImageOptions) plan() (*plan, error) {
 										if src.ref.EqualRegistry(dst.ref) {
 											registryPlan.AssociateBlob(canonicalFrom.String(), blob)
 										}
+										if checkBlobs {
+											if _, err := toBlobs.Stat(ctx, blob.Digest); err == nil {
+												blobPlan.AlreadyExists(blob)
+												continue
+											} else if err != distribution.ErrBlobUnknown {
+												klog.V(4).Infof("Unable to check whether blob %s exists in %s, assuming all blobs must be copied: %v", blob.Digest, dst.ref, err)
+												checkBlobs = false
+											}
+										}
 										blobPlan.Copy(blob, srcBlobs, toBlobs)
 									}
 								}
@@ -871,6 +929,7 @@ func copyManifestToTags(
 			plan.parent.parent.AssociateBlob(plan.parent.name, desc)
 		}
 		plan.parent.parent.SavedManifest(srcDigest, toDigest)
+		plan.parent.parent.parent.PushedManifest(fmt.Sprintf("%s:%s", plan.toRef, tag), srcDigest, toDigest)
 		fmt.Fprintf(out, "%s %s:%s\n", toDigest, plan.toRef, tag)
 	}
 	return errs
@@ -896,6 +955,7 @@ func copyManifest(
 		plan.parent.parent.AssociateBlob(plan.parent.name, desc)
 	}
 	plan.parent.parent.SavedManifest(srcDigest, toDigest)
+	plan.parent.parent.parent.PushedManifest(plan.toRef.String(), srcDigest, toDigest)
 	fmt.Fprintf(out, "%s %s\n", toDigest, plan.toRef)
 	return nil
 }
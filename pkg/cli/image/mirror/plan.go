@@ -128,6 +128,7 @@ type plan struct {
 	errs       []error
 	blobs      map[godigest.Digest]distribution.Descriptor
 	manifests  map[godigest.Digest]distribution.Manifest
+	pushed     []pushedManifest
 
 	work *workPlan
 
@@ -180,6 +181,30 @@ func (p *plan) CacheManifest(digest godigest.Digest, manifest distribution.Manif
 	p.manifests[digest] = manifest
 }
 
+// PushedManifest records that the manifest srcDigest was stored at destination as toDigest.
+func (p *plan) PushedManifest(destination string, srcDigest, toDigest godigest.Digest) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.pushed = append(p.pushed, pushedManifest{Destination: destination, Source: srcDigest, Digest: toDigest})
+}
+
+// PushedManifests returns the manifests recorded by PushedManifest, ordered by destination.
+func (p *plan) PushedManifests() []pushedManifest {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	pushed := make([]pushedManifest, len(p.pushed))
+	copy(pushed, p.pushed)
+	sort.Slice(pushed, func(i, j int) bool {
+		if pushed[i].Destination != pushed[j].Destination {
+			return pushed[i].Destination < pushed[j].Destination
+		}
+		return pushed[i].Digest < pushed[j].Digest
+	})
+	return pushed
+}
+
 func (p *plan) GetManifest(digest godigest.Digest) (distribution.Manifest, bool) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -305,6 +330,34 @@ func (p *plan) Print(w io.Writer) {
 	}
 }
 
+// PrintDryRunSummary writes the number and size of the blobs each mapping copies to its
+// destination and the total size transferred. A blob copied to several repositories of a
+// registry is counted once, since it is mounted into the others unless skipMount is set.
+func (p *plan) PrintDryRunSummary(w io.Writer, skipMount bool) {
+	tabw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tabw, "SOURCE\tDESTINATION\tBLOBS\tSIZE\n")
+	var count int
+	var size int64
+	for _, name := range p.RegistryNames().List() {
+		r := p.registries[name]
+		for _, repoName := range r.RepositoryNames().List() {
+			for _, blob := range r.repositories[repoName].blobs {
+				fmt.Fprintf(tabw, "%s\t%s\t%d\t%s\n", blob.fromRef, blob.toRef, len(blob.blobs), units.BytesSize(float64(blob.stats.size)))
+				if skipMount {
+					count += len(blob.blobs)
+					size += blob.stats.size
+				}
+			}
+		}
+		if !skipMount {
+			count += int(r.stats.uniqueCount + r.stats.sharedCount)
+			size += r.stats.uniqueSize + r.stats.sharedSize
+		}
+	}
+	tabw.Flush()
+	fmt.Fprintf(w, "total: %d blobs to copy, %s\n", count, units.BytesSize(float64(size)))
+}
+
 func (p *plan) trim() {
 	for name, registry := range p.registries {
 		if registry.trim() {
@@ -0,0 +1,58 @@
+package mirror
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/docker/distribution"
+	godigest "github.com/opencontainers/go-digest"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	imagereference "github.com/openshift/library-go/pkg/image/reference"
+	"github.com/openshift/oc/pkg/cli/image/imagesource"
+)
+
+func TestPrintDryRunSummary(t *testing.T) {
+	newPlanWithBlobs := func() *plan {
+		shared := distribution.Descriptor{Digest: godigest.FromString("shared"), Size: 1024 * 1024}
+		unique := distribution.Descriptor{Digest: godigest.FromString("unique"), Size: 1024}
+		existing := distribution.Descriptor{Digest: godigest.FromString("existing"), Size: 4096}
+
+		p := newPlan()
+		source := imagesource.TypedImageReference{Type: imagesource.DestinationRegistry, Ref: imagereference.DockerImageReference{Registry: "quay.io", Namespace: "example", Name: "app", Tag: "latest"}}
+		registry := p.RegistryPlan(imagesource.TypedImageReference{Type: imagesource.DestinationRegistry, Ref: imagereference.DockerImageReference{Registry: "mirror.local"}})
+		for _, name := range []string{"a/app", "b/app"} {
+			repo := registry.RepositoryPlan(name)
+			repo.Manifests().digestsToTags[godigest.FromString("manifest")] = sets.NewString("latest")
+			blobs := repo.Blobs(source, "manifest")
+			blobs.Copy(shared, nil, nil)
+			blobs.Copy(existing, nil, nil)
+			blobs.AlreadyExists(existing)
+			if name == "a/app" {
+				blobs.Copy(unique, nil, nil)
+			}
+		}
+		p.trim()
+		p.calculateStats()
+		return p
+	}
+
+	out := &bytes.Buffer{}
+	newPlanWithBlobs().PrintDryRunSummary(out, false)
+	for _, expected := range []string{
+		"quay.io/example/app:latest  mirror.local/a/app  2      1.001MiB",
+		"quay.io/example/app:latest  mirror.local/b/app  1      1MiB",
+		"total: 2 blobs to copy, 1.001MiB",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+		}
+	}
+
+	out.Reset()
+	newPlanWithBlobs().PrintDryRunSummary(out, true)
+	if !strings.Contains(out.String(), "total: 3 blobs to copy, 2.001MiB") {
+		t.Errorf("expected shared blobs to be counted for every repository, got:\n%s", out.String())
+	}
+}
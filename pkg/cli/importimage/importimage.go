@@ -3,7 +3,10 @@ package importimage
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
@@ -36,6 +39,13 @@ var (
 		entries. When importing an image, only the image metadata is copied, not the
 		image contents.
 
+		When importing all tags with --all, a summary of the tags that were imported and
+		the error for each tag that failed is printed, and the command fails if any tag
+		could not be imported. Combine --all with --dry-run to list the tags that would be
+		imported and where they would be imported from without changing the image stream.
+		Unless --reference-policy is given, tags that already exist keep their reference
+		policy.
+
 		If you want to change the image stream tag or provide more advanced options,
 		see the 'tag' command.`)
 
@@ -52,6 +62,9 @@ var (
 		# Update imported data for all tags in an existing image stream
 		oc import-image mystream --all
 
+		# List the tags that would be updated by importing all tags of an existing image stream
+		oc import-image mystream --all --dry-run
+
 		# Import all tags into a new image stream
 		oc import-image mystream --from=registry.io/repo/image --all --confirm
 
@@ -197,10 +210,9 @@ func (o *ImportImageOptions) Validate() error {
 		return fmt.Errorf("you must specify the name of an image stream")
 	}
 
+	// an empty policy keeps the policy of existing tags and defaults to source for new tags
 	switch strings.ToLower(o.ReferencePolicy) {
-	case tag.SourceReferencePolicy:
-	case "":
-		o.ReferencePolicy = tag.SourceReferencePolicy
+	case "", tag.SourceReferencePolicy:
 	case tag.LocalReferencePolicy:
 		o.ReferencePolicy = tag.LocalReferencePolicy
 	default:
@@ -279,7 +291,105 @@ func (o *ImportImageOptions) Run() error {
 		return err
 	}
 
-	return printer.PrintObj(stream, o.Out)
+	if err := printer.PrintObj(stream, o.Out); err != nil {
+		return err
+	}
+
+	if !o.All {
+		return nil
+	}
+	// keep structured output parseable by writing the summary to stderr
+	out := o.Out
+	if o.PrintFlags.OutputFormat != nil && len(*o.PrintFlags.OutputFormat) > 0 {
+		out = o.ErrOut
+	}
+	results := tagImportResults(isi, result)
+	printTagImportSummary(out, results, o.DryRun)
+	if failed := failedTagImports(results); failed > 0 && !o.DryRun {
+		return fmt.Errorf("%d of %d tags failed to import", failed, len(results))
+	}
+	return nil
+}
+
+// tagImportResult is the outcome of importing a single tag.
+type tagImportResult struct {
+	Tag  string
+	From string
+	Err  string
+}
+
+// tagImportResults pairs the tags requested by isi with their import status in result. Tags
+// discovered in a repository are reported with the repository they are imported from.
+func tagImportResults(isi, result *imagev1.ImageStreamImport) []tagImportResult {
+	var results []tagImportResult
+	for i, spec := range isi.Spec.Images {
+		r := tagImportResult{From: spec.From.Name}
+		if spec.To != nil {
+			r.Tag = spec.To.Name
+		}
+		if i < len(result.Status.Images) {
+			if status := result.Status.Images[i]; status.Status.Status == metav1.StatusFailure || status.Image == nil {
+				r.Err = status.Status.Message
+			}
+		} else {
+			r.Err = "no import status was returned"
+		}
+		results = append(results, r)
+	}
+	if isi.Spec.Repository != nil && result.Status.Repository != nil {
+		repo := result.Status.Repository
+		for _, image := range repo.Images {
+			r := tagImportResult{Tag: image.Tag, From: fmt.Sprintf("%s:%s", isi.Spec.Repository.From.Name, image.Tag)}
+			if image.Status.Status == metav1.StatusFailure || image.Image == nil {
+				r.Err = image.Status.Message
+			}
+			results = append(results, r)
+		}
+		if repo.Status.Status == metav1.StatusFailure {
+			results = append(results, tagImportResult{From: isi.Spec.Repository.From.Name, Err: repo.Status.Message})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Tag < results[j].Tag })
+	return results
+}
+
+func failedTagImports(results []tagImportResult) int {
+	failed := 0
+	for _, r := range results {
+		if len(r.Err) > 0 {
+			failed++
+		}
+	}
+	return failed
+}
+
+// printTagImportSummary writes one line per tag with the source it is imported from, followed
+// by the number of tags that succeeded and failed.
+func printTagImportSummary(out io.Writer, results []tagImportResult, dryRun bool) {
+	w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	fmt.Fprintf(w, "\nTAG\tFROM\tSTATUS\n")
+	for _, r := range results {
+		tag := r.Tag
+		if len(tag) == 0 {
+			tag = "<repository>"
+		}
+		status := "imported"
+		switch {
+		case len(r.Err) > 0:
+			status = fmt.Sprintf("failed: %s", r.Err)
+		case dryRun:
+			status = "would import"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", tag, r.From, status)
+	}
+	w.Flush()
+
+	failed := failedTagImports(results)
+	if dryRun {
+		fmt.Fprintf(out, "\n%d tag(s) would be imported, %d tag(s) would fail (dry run)\n", len(results)-failed, failed)
+		return
+	}
+	fmt.Fprintf(out, "\n%d tag(s) imported, %d tag(s) failed\n", len(results)-failed, failed)
 }
 
 func wasError(isi *imagev1.ImageStreamImport) bool {
@@ -561,9 +671,13 @@ func (o *ImportImageOptions) newImageStreamImportTags(stream *imagev1.ImageStrea
 			}
 		}
 
+		referencePolicy := o.getReferencePolicy()
 		if oldTagFound {
 			insecure = insecure || oldTag.ImportPolicy.Insecure
 			scheduled = scheduled || oldTag.ImportPolicy.Scheduled
+			if len(o.ReferencePolicy) == 0 {
+				referencePolicy = oldTag.ReferencePolicy
+			}
 		}
 		isi.Spec.Images = append(isi.Spec.Images, imagev1.ImageImportSpec{
 			From: corev1.ObjectReference{
@@ -575,7 +689,7 @@ func (o *ImportImageOptions) newImageStreamImportTags(stream *imagev1.ImageStrea
 				Insecure:  insecure,
 				Scheduled: scheduled,
 			},
-			ReferencePolicy: o.getReferencePolicy(),
+			ReferencePolicy: referencePolicy,
 		})
 	}
 	return isi
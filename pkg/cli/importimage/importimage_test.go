@@ -1,6 +1,8 @@
 package importimage
 
 import (
+	"bytes"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -444,6 +446,30 @@ func TestCreateImageImport(t *testing.T) {
 				},
 			},
 		},
+		"import all from .spec.tags keeps existing referencePolicy": {
+			name: "testis",
+			all:  true,
+			stream: &imagev1.ImageStream{
+				ObjectMeta: metav1.ObjectMeta{Name: "testis", Namespace: "other"},
+				Spec: imagev1.ImageStreamSpec{
+					Tags: []imagev1.TagReference{
+						{Name: "mytag", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "repo.com/somens/someimage:mytag"}, ReferencePolicy: imagev1.TagReferencePolicy{Type: imagev1.LocalTagReferencePolicy}},
+						{Name: "other", From: &corev1.ObjectReference{Kind: "DockerImage", Name: "repo.com/somens/someimage:other"}},
+					},
+				},
+			},
+			expectedImages: []imagev1.ImageImportSpec{
+				{
+					From:            corev1.ObjectReference{Kind: "DockerImage", Name: "repo.com/somens/someimage:mytag"},
+					To:              &corev1.LocalObjectReference{Name: "mytag"},
+					ReferencePolicy: imagev1.TagReferencePolicy{Type: imagev1.LocalTagReferencePolicy},
+				},
+				{
+					From: corev1.ObjectReference{Kind: "DockerImage", Name: "repo.com/somens/someimage:other"},
+					To:   &corev1.LocalObjectReference{Name: "other"},
+				},
+			},
+		},
 		"import all from .spec.dockerImageRepository setting referencePolicy": {
 			name:            "testis",
 			all:             true,
@@ -665,6 +691,64 @@ func TestWasError(t *testing.T) {
 	}
 }
 
+func TestTagImportSummary(t *testing.T) {
+	isi := &imagev1.ImageStreamImport{
+		Spec: imagev1.ImageStreamImportSpec{
+			Images: []imagev1.ImageImportSpec{
+				{From: corev1.ObjectReference{Kind: "DockerImage", Name: "repo.com/ns/image:b"}, To: &corev1.LocalObjectReference{Name: "b"}},
+				{From: corev1.ObjectReference{Kind: "DockerImage", Name: "repo.com/ns/image:a"}, To: &corev1.LocalObjectReference{Name: "a"}},
+			},
+		},
+	}
+	result := &imagev1.ImageStreamImport{
+		Status: imagev1.ImageStreamImportStatus{
+			Images: []imagev1.ImageImportStatus{
+				{Status: metav1.Status{Status: metav1.StatusFailure, Message: "manifest unknown"}, Tag: "b"},
+				{Status: metav1.Status{Status: metav1.StatusSuccess}, Image: &imagev1.Image{}, Tag: "a"},
+			},
+		},
+	}
+
+	results := tagImportResults(isi, result)
+	expected := []tagImportResult{
+		{Tag: "a", From: "repo.com/ns/image:a"},
+		{Tag: "b", From: "repo.com/ns/image:b", Err: "manifest unknown"},
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+
+	out := &bytes.Buffer{}
+	printTagImportSummary(out, results, false)
+	for _, s := range []string{"repo.com/ns/image:a  imported", "failed: manifest unknown", "1 tag(s) imported, 1 tag(s) failed"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("expected %q in summary:\n%s", s, out.String())
+		}
+	}
+
+	out.Reset()
+	printTagImportSummary(out, results[:1], true)
+	if !strings.Contains(out.String(), "would import") || !strings.Contains(out.String(), "1 tag(s) would be imported, 0 tag(s) would fail (dry run)") {
+		t.Errorf("unexpected dry run summary:\n%s", out.String())
+	}
+
+	repoISI := &imagev1.ImageStreamImport{
+		Spec: imagev1.ImageStreamImportSpec{
+			Repository: &imagev1.RepositoryImportSpec{From: corev1.ObjectReference{Kind: "DockerImage", Name: "repo.com/ns/image"}},
+		},
+	}
+	repoResult := &imagev1.ImageStreamImport{
+		Status: imagev1.ImageStreamImportStatus{
+			Repository: &imagev1.RepositoryImportStatus{
+				Images: []imagev1.ImageImportStatus{{Tag: "latest", Image: &imagev1.Image{}}},
+			},
+		},
+	}
+	if results := tagImportResults(repoISI, repoResult); len(results) != 1 || results[0].From != "repo.com/ns/image:latest" || len(results[0].Err) > 0 {
+		t.Errorf("unexpected repository results: %#v", results)
+	}
+}
+
 func listEqual(actual, expected []imagev1.ImageImportSpec) bool {
 	if len(actual) != len(expected) {
 		return false
@@ -0,0 +1,274 @@
+package kubectlwrappers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/kubectl/pkg/cmd/exec"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const verifyChecksumExample = `
+  # Copy a database dump out of a pod and verify it was not truncated or corrupted
+  oc cp mypod:/var/lib/db/dump.sql /tmp/dump.sql --verify-checksum`
+
+// copySpec is one side of a copy, a local path when Pod is empty.
+type copySpec struct {
+	Namespace string
+	Pod       string
+	Path      string
+}
+
+// CopyChecksumOptions compares the SHA-256 checksums of the files on both sides of a copy once
+// it has completed. The checksums of files in a container are computed with sha256sum.
+type CopyChecksumOptions struct {
+	Container string
+	Namespace string
+
+	Source      copySpec
+	Destination copySpec
+
+	// ExecFn runs command in a container of pod and returns its output.
+	ExecFn func(namespace, pod string, command []string) (string, error)
+
+	Config    *restclient.Config
+	PodClient corev1client.PodsGetter
+
+	genericclioptions.IOStreams
+}
+
+// withVerifyChecksum adds the --verify-checksum flag to the cp command.
+func withVerifyChecksum(cmd *cobra.Command, f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	var verify bool
+	cmd.Flags().BoolVar(&verify, "verify-checksum", verify, "If true, once the copy completes compare the SHA-256 checksum of every copied file with its source and fail on any difference. Requires find and sha256sum in the container.")
+	cmd.Example += "\n" + verifyChecksumExample
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if !verify {
+			run(c, args)
+			return
+		}
+		o := &CopyChecksumOptions{IOStreams: streams}
+		kcmdutil.CheckErr(o.Complete(f, c, args))
+		run(c, args)
+		kcmdutil.CheckErr(o.Run())
+	}
+	return cmd
+}
+
+func (o *CopyChecksumOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return kcmdutil.UsageErrorf(cmd, "source and destination are required")
+	}
+	var err error
+	if o.Source, err = parseCopySpec(args[0]); err != nil {
+		return err
+	}
+	if o.Destination, err = parseCopySpec(args[1]); err != nil {
+		return err
+	}
+	if (len(o.Source.Pod) == 0) == (len(o.Destination.Pod) == 0) {
+		return fmt.Errorf("one of src or dest must be a local file specification")
+	}
+
+	o.Container = kcmdutil.GetFlagString(cmd, "container")
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	o.Config, err = f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.PodClient = client.CoreV1()
+	o.ExecFn = o.execInContainer
+
+	// like the copy itself, a local path copied to an existing directory is copied into it
+	if len(o.Destination.Pod) > 0 {
+		if _, err := o.ExecFn(o.Destination.Namespace, o.Destination.Pod, []string{"test", "-d", o.Destination.Path}); err == nil {
+			o.Destination.Path = path.Join(o.Destination.Path, filepath.Base(o.Source.Path))
+		}
+	}
+	return nil
+}
+
+// Run compares the checksums of the source and the destination of the copy.
+func (o *CopyChecksumOptions) Run() error {
+	source, err := o.checksums(o.Source)
+	if err != nil {
+		return fmt.Errorf("unable to compute the checksums of %s: %v", o.Source.Path, err)
+	}
+	if len(source) == 0 {
+		return fmt.Errorf("no files were found to verify in %s", o.Source.Path)
+	}
+	destination, err := o.checksums(o.Destination)
+	if err != nil {
+		return fmt.Errorf("unable to compute the checksums of %s: %v", o.Destination.Path, err)
+	}
+	if errs := compareChecksums(source, destination); len(errs) > 0 {
+		return fmt.Errorf(kcmdutil.MultipleErrors("checksum verification failed: ", errs))
+	}
+	fmt.Fprintf(o.ErrOut, "Verified the SHA-256 checksums of %d copied files\n", len(source))
+	return nil
+}
+
+// checksums returns the SHA-256 checksum of every regular file under spec, by path relative to
+// spec. If spec is a file, its checksum is returned for the empty path.
+func (o *CopyChecksumOptions) checksums(spec copySpec) (map[string]string, error) {
+	if len(spec.Pod) == 0 {
+		return localChecksums(spec.Path)
+	}
+	root := path.Clean(spec.Path)
+	out, err := o.ExecFn(spec.Namespace, spec.Pod, []string{"find", root, "-type", "f", "-exec", "sha256sum", "{}", "+"})
+	if err != nil {
+		return nil, err
+	}
+	return parseChecksums(out, root)
+}
+
+func (o *CopyChecksumOptions) execInContainer(namespace, pod string, command []string) (string, error) {
+	if len(namespace) == 0 {
+		namespace = o.Namespace
+	}
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	options := &exec.ExecOptions{
+		StreamOptions: exec.StreamOptions{
+			IOStreams:     genericclioptions.IOStreams{Out: out, ErrOut: errOut},
+			Namespace:     namespace,
+			PodName:       pod,
+			ContainerName: o.Container,
+			Quiet:         true,
+		},
+		Command:   command,
+		Executor:  &exec.DefaultRemoteExecutor{},
+		Config:    o.Config,
+		PodClient: o.PodClient,
+	}
+	if err := options.Validate(); err != nil {
+		return "", err
+	}
+	if err := options.Run(); err != nil {
+		if msg := strings.TrimSpace(errOut.String()); len(msg) > 0 {
+			return "", fmt.Errorf("%v: %s", err, msg)
+		}
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// parseCopySpec splits a [[namespace/]pod:]path argument of oc cp.
+func parseCopySpec(arg string) (copySpec, error) {
+	i := strings.Index(arg, ":")
+	switch {
+	case i == 0:
+		return copySpec{}, fmt.Errorf("filespec must match the canonical format: [[namespace/]pod:]file/path")
+	case i == -1:
+		return copySpec{Path: arg}, nil
+	}
+	spec := copySpec{Pod: arg[:i], Path: arg[i+1:]}
+	if j := strings.Index(spec.Pod, "/"); j != -1 {
+		spec.Namespace, spec.Pod = spec.Pod[:j], spec.Pod[j+1:]
+	}
+	return spec, nil
+}
+
+// parseChecksums reads the output of sha256sum for the files under root.
+func parseChecksums(out, root string) (map[string]string, error) {
+	checksums := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if len(line) == 0 {
+			continue
+		}
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) != 2 || len(parts[1]) < 2 {
+			return nil, fmt.Errorf("unexpected sha256sum output: %s", line)
+		}
+		// the file name is preceded by a space, or by '*' in binary mode
+		name := strings.TrimPrefix(parts[1][1:], root)
+		checksums[strings.TrimPrefix(name, "/")] = parts[0]
+	}
+	return checksums, nil
+}
+
+func localChecksums(root string) (map[string]string, error) {
+	checksums := map[string]string{}
+	err := filepath.Walk(root, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			rel = ""
+		}
+		sum, err := fileChecksum(name)
+		if err != nil {
+			return err
+		}
+		checksums[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	return checksums, err
+}
+
+func fileChecksum(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// compareChecksums returns an error for every file of source that is missing or different in
+// destination.
+func compareChecksums(source, destination map[string]string) []error {
+	names := make([]string, 0, len(source))
+	for name := range source {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		display := name
+		if len(display) == 0 {
+			display = "file"
+		}
+		actual, ok := destination[name]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("%s was not copied", display))
+		case actual != source[name]:
+			errs = append(errs, fmt.Errorf("%s has checksum %s, expected %s", display, actual, source[name]))
+		}
+	}
+	return errs
+}
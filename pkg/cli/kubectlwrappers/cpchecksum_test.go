@@ -0,0 +1,123 @@
+package kubectlwrappers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestParseCopySpec(t *testing.T) {
+	tests := map[string]copySpec{
+		"/tmp/foo":             {Path: "/tmp/foo"},
+		"mypod:/tmp/foo":       {Pod: "mypod", Path: "/tmp/foo"},
+		"test/mypod:/tmp/foo":  {Namespace: "test", Pod: "mypod", Path: "/tmp/foo"},
+		"mypod:relative/f.txt": {Pod: "mypod", Path: "relative/f.txt"},
+	}
+	for arg, expected := range tests {
+		spec, err := parseCopySpec(arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spec != expected {
+			t.Errorf("%s: expected %#v, got %#v", arg, expected, spec)
+		}
+	}
+	if _, err := parseCopySpec(":/tmp/foo"); err == nil {
+		t.Errorf("expected an error for a missing pod name")
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	out := "aaa  /data/dump/a.sql\nbbb */data/dump/sub/b.sql\n"
+	checksums, err := parseChecksums(out, "/data/dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"a.sql": "aaa", "sub/b.sql": "bbb"}
+	if !reflect.DeepEqual(checksums, expected) {
+		t.Errorf("expected %v, got %v", expected, checksums)
+	}
+
+	checksums, err = parseChecksums("ccc  /data/dump.sql\n", "/data/dump.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"": "ccc"}; !reflect.DeepEqual(checksums, expected) {
+		t.Errorf("expected %v, got %v", expected, checksums)
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.sql"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "sub", "b.sql"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	const (
+		sumA = "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"
+		sumB = "3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d"
+	)
+
+	tests := []struct {
+		name   string
+		remote string
+		err    string
+	}{
+		{
+			name:   "identical",
+			remote: fmt.Sprintf("%s  /backup/a.sql\n%s  /backup/sub/b.sql\n", sumA, sumB),
+		},
+		{
+			name:   "truncated",
+			remote: fmt.Sprintf("%s  /backup/a.sql\n%s  /backup/sub/b.sql\n", sumA, sumA),
+			err:    "sub/b.sql has checksum " + sumA + ", expected " + sumB,
+		},
+		{
+			name:   "missing",
+			remote: fmt.Sprintf("%s  /backup/sub/b.sql\n", sumB),
+			err:    "a.sql was not copied",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var executed []string
+			o := &CopyChecksumOptions{
+				Source:      copySpec{Path: dir},
+				Destination: copySpec{Pod: "mypod", Path: "/backup"},
+				ExecFn: func(namespace, pod string, command []string) (string, error) {
+					executed = append(executed, strings.Join(command, " "))
+					return tc.remote, nil
+				},
+				IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+			}
+			err := o.Run()
+			if expected := []string{"find /backup -type f -exec sha256sum {} +"}; !reflect.DeepEqual(executed, expected) {
+				t.Errorf("expected %v to be executed, got %v", expected, executed)
+			}
+			if len(tc.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package kubectlwrappers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	imagev1 "github.com/openshift/api/image/v1"
+	"github.com/openshift/library-go/pkg/image/imageutil"
+)
+
+var timeNowFn = time.Now
+
+const showTagsExample = `
+  # List every tag of the image streams in the current project with its resolved image
+  oc get imagestreams --show-tags
+
+  # Show the tags of a single image stream as JSON
+  oc get imagestream/ruby --show-tags -o json`
+
+// ImageStreamTagsOptions lists the tags of image streams, one row per tag.
+type ImageStreamTagsOptions struct {
+	Namespace     string
+	AllNamespaces bool
+	LabelSelector string
+	Output        string
+
+	Builder func() *resource.Builder
+
+	genericclioptions.IOStreams
+}
+
+// imageStreamTagRow is a single tag of an image stream, correlated between spec and status.
+type imageStreamTagRow struct {
+	Namespace            string       `json:"namespace"`
+	ImageStream          string       `json:"imageStream"`
+	Tag                  string       `json:"tag"`
+	Image                string       `json:"image,omitempty"`
+	DockerImageReference string       `json:"dockerImageReference,omitempty"`
+	Updated              *metav1.Time `json:"updated,omitempty"`
+	Scheduled            bool         `json:"scheduled"`
+}
+
+// withShowTags adds the --show-tags flag to the get command. When it is set, the
+// requested image streams are listed with one row per tag instead of one row per stream.
+func withShowTags(cmd *cobra.Command, f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	var showTags bool
+	cmd.Flags().BoolVar(&showTags, "show-tags", showTags, "If true, list each tag of the requested image streams with its resolved image, last update and whether it is imported periodically. Only supports -o json and -o wide output.")
+	cmd.Example += "\n" + showTagsExample
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if !showTags {
+			run(c, args)
+			return
+		}
+		o := &ImageStreamTagsOptions{IOStreams: streams}
+		kcmdutil.CheckErr(o.Complete(f, c, args))
+		kcmdutil.CheckErr(o.Validate())
+		kcmdutil.CheckErr(o.Run(args))
+	}
+	return cmd
+}
+
+func (o *ImageStreamTagsOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return kcmdutil.UsageErrorf(cmd, "--show-tags requires the image streams to list, e.g. 'imagestreams'")
+	}
+
+	var err error
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	o.AllNamespaces = kcmdutil.GetFlagBool(cmd, "all-namespaces")
+	o.LabelSelector = kcmdutil.GetFlagString(cmd, "selector")
+	o.Output = kcmdutil.GetFlagString(cmd, "output")
+	o.Builder = f.NewBuilder
+	return nil
+}
+
+func (o *ImageStreamTagsOptions) Validate() error {
+	switch o.Output {
+	case "", "wide", "json":
+		return nil
+	default:
+		return fmt.Errorf("--show-tags does not support output format %q, only json and wide are supported", o.Output)
+	}
+}
+
+func (o *ImageStreamTagsOptions) Run(args []string) error {
+	infos, err := o.Builder().
+		Unstructured().
+		NamespaceParam(o.Namespace).DefaultNamespace().AllNamespaces(o.AllNamespaces).
+		LabelSelectorParam(o.LabelSelector).
+		ResourceTypeOrNameArgs(true, args...).
+		Latest().
+		Flatten().
+		Do().
+		Infos()
+	if err != nil {
+		return err
+	}
+
+	var imageStreams []*imagev1.ImageStream
+	for _, info := range infos {
+		if gr := info.Mapping.Resource.GroupResource(); gr != imagev1.Resource("imagestreams") {
+			return fmt.Errorf("--show-tags is only supported for image streams, not %s", gr.String())
+		}
+		u, ok := info.Object.(*unstructured.Unstructured)
+		if !ok {
+			return fmt.Errorf("unexpected object %T for image stream %s", info.Object, info.Name)
+		}
+		stream := &imagev1.ImageStream{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, stream); err != nil {
+			return err
+		}
+		imageStreams = append(imageStreams, stream)
+	}
+
+	rows := imageStreamTagRows(imageStreams)
+	if o.Output == "json" {
+		data, err := json.MarshalIndent(rows, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s\n", data)
+		return nil
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(o.ErrOut, "No image stream tags found.")
+		return nil
+	}
+	return printImageStreamTagRows(o.Out, rows, o.AllNamespaces)
+}
+
+// imageStreamTagRows returns a row for every tag that appears in the spec or the status
+// of the given image streams, ordered the same way oc describe orders them.
+func imageStreamTagRows(imageStreams []*imagev1.ImageStream) []imageStreamTagRow {
+	rows := []imageStreamTagRow{}
+	for _, stream := range imageStreams {
+		names := sets.NewString()
+		for _, tag := range stream.Spec.Tags {
+			names.Insert(tag.Name)
+		}
+		for _, tag := range stream.Status.Tags {
+			names.Insert(tag.Tag)
+		}
+		tags := names.List()
+		imageutil.PrioritizeTags(tags)
+
+		for _, tag := range tags {
+			row := imageStreamTagRow{
+				Namespace:   stream.Namespace,
+				ImageStream: stream.Name,
+				Tag:         tag,
+			}
+			if tagRef, ok := imageutil.SpecHasTag(stream, tag); ok && tagRef.ImportPolicy.Scheduled {
+				row.Scheduled = true
+			}
+			if event := imageutil.LatestTaggedImage(stream, tag); event != nil {
+				row.Image = event.Image
+				row.DockerImageReference = event.DockerImageReference
+				created := event.Created
+				row.Updated = &created
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func printImageStreamTagRows(out io.Writer, rows []imageStreamTagRow, withNamespace bool) error {
+	w := printers.GetNewTabWriter(out)
+	if withNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tTAG\tIMAGE\tUPDATED\tSCHEDULED")
+	for _, row := range rows {
+		image, updated := "<pending>", "<none>"
+		if len(row.Image) > 0 {
+			image = row.Image
+		}
+		if row.Updated != nil && !row.Updated.IsZero() {
+			updated = units.HumanDuration(timeNowFn().Sub(row.Updated.Time)) + " ago"
+		}
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", row.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", row.ImageStream, row.Tag, image, updated, row.Scheduled)
+	}
+	return w.Flush()
+}
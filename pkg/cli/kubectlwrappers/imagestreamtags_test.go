@@ -0,0 +1,83 @@
+package kubectlwrappers
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	imagev1 "github.com/openshift/api/image/v1"
+)
+
+func TestImageStreamTagRows(t *testing.T) {
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	timeNowFn = func() time.Time { return now }
+	defer func() { timeNowFn = time.Now }()
+
+	stream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ruby"},
+		Spec: imagev1.ImageStreamSpec{
+			Tags: []imagev1.TagReference{
+				{
+					Name:         "latest",
+					From:         &corev1.ObjectReference{Kind: "DockerImage", Name: "quay.io/ruby:latest"},
+					ImportPolicy: imagev1.TagImportPolicy{Scheduled: true},
+				},
+				{Name: "next"},
+			},
+		},
+		Status: imagev1.ImageStreamStatus{
+			Tags: []imagev1.NamedTagEventList{
+				{
+					Tag: "latest",
+					Items: []imagev1.TagEvent{
+						{Image: "sha256:2", DockerImageReference: "quay.io/ruby@sha256:2", Created: metav1.NewTime(now.Add(-2 * time.Hour))},
+						{Image: "sha256:1", DockerImageReference: "quay.io/ruby@sha256:1", Created: metav1.NewTime(now.Add(-48 * time.Hour))},
+					},
+				},
+				{
+					Tag: "2.7",
+					Items: []imagev1.TagEvent{
+						{Image: "sha256:1", DockerImageReference: "quay.io/ruby@sha256:1", Created: metav1.NewTime(now.Add(-10 * time.Minute))},
+					},
+				},
+			},
+		},
+	}
+
+	rows := imageStreamTagRows([]*imagev1.ImageStream{stream})
+
+	out := &bytes.Buffer{}
+	if err := printImageStreamTagRows(out, rows, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `NAME   TAG      IMAGE       UPDATED          SCHEDULED
+ruby   latest   sha256:2    2 hours ago      true
+ruby   2.7      sha256:1    10 minutes ago   false
+ruby   next     <pending>   <none>           false
+`
+	if out.String() != expected {
+		t.Errorf("unexpected table output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(decoded) != 3 {
+		t.Fatalf("expected 3 tags, got %d: %s", len(decoded), data)
+	}
+	if decoded[0]["tag"] != "latest" || decoded[0]["scheduled"] != true || decoded[0]["dockerImageReference"] != "quay.io/ruby@sha256:2" {
+		t.Errorf("unexpected scheduled tag: %v", decoded[0])
+	}
+	if _, ok := decoded[2]["image"]; ok {
+		t.Errorf("expected no image for a tag that was not imported yet: %v", decoded[2])
+	}
+}
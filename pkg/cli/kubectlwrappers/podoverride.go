@@ -0,0 +1,76 @@
+package kubectlwrappers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+	"k8s.io/kubectl/pkg/scheme"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+)
+
+// withPodOverride adds the --pod flag to a command that accepts a TYPE/NAME as its first
+// argument and picks one of its pods, such as exec or attach. When it is set, the named pod
+// is used instead, after checking it is selected by the requested object.
+func withPodOverride(cmd *cobra.Command, f kcmdutil.Factory) *cobra.Command {
+	var podName string
+	cmd.Flags().StringVar(&podName, "pod", podName, "When a TYPE/NAME is given, use this pod of it instead of selecting one, for example a specific completed pod of a job.")
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if len(podName) == 0 {
+			run(c, args)
+			return
+		}
+		if len(args) == 0 || !strings.Contains(args[0], "/") {
+			kcmdutil.CheckErr(kcmdutil.UsageErrorf(c, "--pod requires a TYPE/NAME argument"))
+		}
+
+		namespace, _, err := f.ToRawKubeConfigLoader().Namespace()
+		kcmdutil.CheckErr(err)
+		obj, err := f.NewBuilder().
+			WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
+			NamespaceParam(namespace).DefaultNamespace().
+			ResourceNames("pods", args[0]).
+			SingleResourceType().
+			Do().Object()
+		kcmdutil.CheckErr(err)
+		client, err := f.KubernetesClientSet()
+		kcmdutil.CheckErr(err)
+		pod, err := client.CoreV1().Pods(namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+		kcmdutil.CheckErr(err)
+		kcmdutil.CheckErr(podSelectedBy(obj, pod, args[0]))
+
+		args[0] = "pod/" + pod.Name
+		run(c, args)
+	}
+	return cmd
+}
+
+// podSelectedBy returns an error unless pod is one of the pods selected by obj.
+func podSelectedBy(obj runtime.Object, pod *corev1.Pod, name string) error {
+	var namespace string
+	var selector labels.Selector
+	if dc, ok := obj.(*appsv1.DeploymentConfig); ok {
+		namespace, selector = dc.Namespace, labels.SelectorFromSet(dc.Spec.Selector)
+	} else {
+		var err error
+		namespace, selector, err = polymorphichelpers.SelectorsForObject(obj)
+		if err != nil {
+			return fmt.Errorf("cannot select a pod of %s: %v", name, err)
+		}
+	}
+	if pod.Namespace != namespace || !selector.Matches(labels.Set(pod.Labels)) {
+		return fmt.Errorf("pod %s does not belong to %s", pod.Name, name)
+	}
+	return nil
+}
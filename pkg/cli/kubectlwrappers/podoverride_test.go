@@ -0,0 +1,46 @@
+package kubectlwrappers
+
+import (
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+)
+
+func TestPodSelectedBy(t *testing.T) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "test"},
+		Spec:       batchv1.JobSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "report"}}},
+	}
+	dc := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+		Spec:       appsv1.DeploymentConfigSpec{Selector: map[string]string{"deploymentconfig": "web"}},
+	}
+	pod := func(namespace string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespace, Labels: labels}}
+	}
+
+	tests := []struct {
+		name      string
+		obj       runtime.Object
+		pod       *corev1.Pod
+		expectErr bool
+	}{
+		{name: "job pod", obj: job, pod: pod("test", map[string]string{"job-name": "report"})},
+		{name: "other job", obj: job, pod: pod("test", map[string]string{"job-name": "cleanup"}), expectErr: true},
+		{name: "other namespace", obj: job, pod: pod("other", map[string]string{"job-name": "report"}), expectErr: true},
+		{name: "deployment config pod", obj: dc, pod: pod("test", map[string]string{"deploymentconfig": "web", "deployment": "web-3"})},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := podSelectedBy(tc.obj, tc.pod, "TYPE/NAME")
+			if tc.expectErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
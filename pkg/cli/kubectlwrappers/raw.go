@@ -0,0 +1,118 @@
+package kubectlwrappers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+const rawPagesExample = `
+  # Stream a large list endpoint one page of 500 items at a time
+  oc get --raw /api/v1/pods --limit=500 --paginate`
+
+// RawPagesOptions requests a list endpoint given with --raw one page at a time, following
+// the continue token of each page until the list is complete.
+type RawPagesOptions struct {
+	Raw      string
+	Limit    int64
+	Paginate bool
+
+	RESTClient rest.Interface
+
+	genericclioptions.IOStreams
+}
+
+// withRawPages adds the --limit and --paginate flags to the get command, which are rejected
+// without --raw. Without them --raw streams the response body to the output as it arrives.
+func withRawPages(cmd *cobra.Command, f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &RawPagesOptions{IOStreams: streams}
+	cmd.Flags().Int64Var(&o.Limit, "limit", o.Limit, "With --raw, the maximum number of items to request from a list endpoint in a single page.")
+	cmd.Flags().BoolVar(&o.Paginate, "paginate", o.Paginate, "With --raw and --limit, follow the continue token of each page and print every page of the list, one after another.")
+	cmd.Example += "\n" + rawPagesExample
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if !c.Flags().Changed("limit") && !c.Flags().Changed("paginate") {
+			run(c, args)
+			return
+		}
+		kcmdutil.CheckErr(o.Complete(f, c, args))
+		kcmdutil.CheckErr(o.Validate())
+		kcmdutil.CheckErr(o.Run())
+	}
+	return cmd
+}
+
+func (o *RawPagesOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	o.Raw = kcmdutil.GetFlagString(cmd, "raw")
+	if len(o.Raw) == 0 {
+		return kcmdutil.UsageErrorf(cmd, "--limit and --paginate may only be used with --raw")
+	}
+	if len(args) > 0 {
+		return kcmdutil.UsageErrorf(cmd, "arguments may not be passed when --raw is specified")
+	}
+
+	var err error
+	o.RESTClient, err = f.RESTClient()
+	return err
+}
+
+func (o *RawPagesOptions) Validate() error {
+	if o.Paginate && o.Limit == 0 {
+		return fmt.Errorf("--paginate requires --limit to be set")
+	}
+	if o.Limit <= 0 {
+		return fmt.Errorf("--limit must be a positive number")
+	}
+	return nil
+}
+
+// Run prints the bytes of each page as they were returned by the server. Only a single page
+// is held in memory at a time.
+func (o *RawPagesOptions) Run() error {
+	continueToken := ""
+	for {
+		request := o.RESTClient.Get().RequestURI(o.Raw).Param("limit", strconv.FormatInt(o.Limit, 10))
+		if len(continueToken) > 0 {
+			request = request.Param("continue", continueToken)
+		}
+		page, err := request.DoRaw(context.TODO())
+		if err != nil {
+			return err
+		}
+		if _, err := o.Out.Write(page); err != nil {
+			return err
+		}
+		if !o.Paginate {
+			return nil
+		}
+
+		continueToken, err = continueTokenFor(page)
+		if err != nil {
+			return fmt.Errorf("unable to read the continue token of %s: %v", o.Raw, err)
+		}
+		if len(continueToken) == 0 {
+			return nil
+		}
+	}
+}
+
+// continueTokenFor returns metadata.continue of a list, or an empty string on the last page.
+func continueTokenFor(page []byte) (string, error) {
+	list := struct {
+		Metadata struct {
+			Continue string `json:"continue"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(page, &list); err != nil {
+		return "", err
+	}
+	return list.Metadata.Continue, nil
+}
@@ -0,0 +1,102 @@
+package kubectlwrappers
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+func TestRawPages(t *testing.T) {
+	pages := map[string]string{
+		"":  `{"kind":"PodList","metadata":{"continue":"a"},"items":[{"metadata":{"name":"one"}}]}` + "\n",
+		"a": `{"kind":"PodList","metadata":{"continue":"b"} ,"items":[{"metadata":{"name":"two"}}]}` + "\n",
+		"b": `{"kind":"PodList","metadata":{},"items":[]}` + "\n",
+	}
+	var requested []string
+	client := &fake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			requested = append(requested, req.URL.Path+"?"+query.Encode())
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       ioutil.NopCloser(bytes.NewBufferString(pages[query.Get("continue")])),
+			}, nil
+		}),
+	}
+
+	tests := []struct {
+		name      string
+		paginate  bool
+		expected  string
+		requested []string
+	}{
+		{
+			name:      "single page",
+			expected:  pages[""],
+			requested: []string{"/api/v1/pods?labelSelector=app%3Dweb&limit=1"},
+		},
+		{
+			name:     "paginate",
+			paginate: true,
+			expected: pages[""] + pages["a"] + pages["b"],
+			requested: []string{
+				"/api/v1/pods?labelSelector=app%3Dweb&limit=1",
+				"/api/v1/pods?continue=a&labelSelector=app%3Dweb&limit=1",
+				"/api/v1/pods?continue=b&labelSelector=app%3Dweb&limit=1",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			requested = nil
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := &RawPagesOptions{
+				Raw:        "/api/v1/pods?labelSelector=app%3Dweb",
+				Limit:      1,
+				Paginate:   tc.paginate,
+				RESTClient: client,
+				IOStreams:  streams,
+			}
+			if err := o.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if err := o.Run(); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("expected the exact bytes of each page, got:\n%s", out.String())
+			}
+			if len(requested) != len(tc.requested) {
+				t.Fatalf("unexpected requests: %v", requested)
+			}
+			for i := range requested {
+				if requested[i] != tc.requested[i] {
+					t.Errorf("expected request %s, got %s", tc.requested[i], requested[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRawPagesRequiresRaw(t *testing.T) {
+	cmd := withRawPages(&cobra.Command{Use: "get", Run: func(*cobra.Command, []string) {}}, nil, genericclioptions.NewTestIOStreamsDiscard())
+	cmd.Flags().String("raw", "", "")
+	if err := cmd.ParseFlags([]string{"--limit=5"}); err != nil {
+		t.Fatal(err)
+	}
+	o := &RawPagesOptions{}
+	err := o.Complete(nil, cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "may only be used with --raw") {
+		t.Errorf("expected --limit to be rejected without --raw, got %v", err)
+	}
+}
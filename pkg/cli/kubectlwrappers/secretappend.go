@@ -0,0 +1,217 @@
+package kubectlwrappers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	kcreate "k8s.io/kubectl/pkg/cmd/create"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+const secretAppendExample = `
+  # Add the credentials of another registry to the existing pull secret my-secret
+  oc create secret docker-registry my-secret --append --docker-server=quay.io --docker-username=user --docker-password=password
+
+  # Add the credentials of a registry to the global pull secret of the cluster
+  oc create secret docker-registry pull-secret -n openshift-config --append --docker-server=registry.example.com --docker-username=user --docker-password=password`
+
+// SecretAppendOptions merges registry credentials into the .dockerconfigjson of an existing
+// pull secret instead of replacing it.
+type SecretAppendOptions struct {
+	Name      string
+	Namespace string
+	Username  string
+	Password  string
+	Email     string
+	Server    string
+
+	DryRunStrategy kcmdutil.DryRunStrategy
+	// Existing is the secret to merge into, or nil when it does not exist yet.
+	Existing *corev1.Secret
+
+	Client   corev1client.SecretsGetter
+	PrintObj func(obj runtime.Object) error
+
+	genericclioptions.IOStreams
+}
+
+// withSecretAppend adds the --append flag to the create secret docker-registry command. When
+// it is set and the named secret exists, the credentials are merged into it. Otherwise the
+// secret is created as usual.
+func withSecretAppend(cmd *cobra.Command, f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	var appendCredentials bool
+	cmd.Flags().BoolVar(&appendCredentials, "append", appendCredentials, "If true and the secret exists, merge the credentials into its .dockerconfigjson, replacing those of the same registry host, instead of failing.")
+	cmd.Example += "\n" + secretAppendExample
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if !appendCredentials {
+			run(c, args)
+			return
+		}
+		o := &SecretAppendOptions{IOStreams: streams}
+		kcmdutil.CheckErr(o.Complete(f, c, args))
+		if o.Existing == nil {
+			run(c, args)
+			return
+		}
+		kcmdutil.CheckErr(o.Validate(c))
+		kcmdutil.CheckErr(o.Run())
+	}
+	return cmd
+}
+
+func (o *SecretAppendOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	o.Name, err = kcreate.NameFromCommandArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+	o.Username = kcmdutil.GetFlagString(cmd, "docker-username")
+	o.Password = kcmdutil.GetFlagString(cmd, "docker-password")
+	o.Email = kcmdutil.GetFlagString(cmd, "docker-email")
+	o.Server = kcmdutil.GetFlagString(cmd, "docker-server")
+
+	o.Namespace, _, err = f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	o.DryRunStrategy, err = kcmdutil.GetDryRunStrategy(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.Client = client.CoreV1()
+
+	o.Existing, err = o.Client.Secrets(o.Namespace).Get(context.TODO(), o.Name, metav1.GetOptions{})
+	switch {
+	case kapierrors.IsNotFound(err):
+		o.Existing = nil
+	case err != nil:
+		return err
+	}
+
+	printFlags := genericclioptions.NewPrintFlags("configured").WithTypeSetter(scheme.Scheme)
+	output := kcmdutil.GetFlagString(cmd, "output")
+	printFlags.OutputFormat = &output
+	kcmdutil.PrintFlagsWithDryRunStrategy(printFlags, o.DryRunStrategy)
+	printer, err := printFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+	o.PrintObj = func(obj runtime.Object) error {
+		return printer.PrintObj(obj, o.Out)
+	}
+	return nil
+}
+
+func (o *SecretAppendOptions) Validate(cmd *cobra.Command) error {
+	if len(kcmdutil.GetFlagStringSlice(cmd, "from-file")) > 0 {
+		return kcmdutil.UsageErrorf(cmd, "--append may not be used with --from-file")
+	}
+	if kcmdutil.GetFlagBool(cmd, "append-hash") {
+		return kcmdutil.UsageErrorf(cmd, "--append may not be used with --append-hash")
+	}
+	if len(o.Username) == 0 || len(o.Password) == 0 || len(o.Server) == 0 {
+		return fmt.Errorf("--append requires --docker-username, --docker-password and --docker-server")
+	}
+	if o.Existing.Type != corev1.SecretTypeDockerConfigJson {
+		return fmt.Errorf("secret %q is of type %s, only %s secrets can be appended to", o.Name, o.Existing.Type, corev1.SecretTypeDockerConfigJson)
+	}
+	return nil
+}
+
+// Run merges the credentials into the existing secret and updates it, or only prints the
+// merged secret with --dry-run=client.
+func (o *SecretAppendOptions) Run() error {
+	secret := o.Existing.DeepCopy()
+	data, err := mergeDockerConfigJSON(secret.Data[corev1.DockerConfigJsonKey], o.Server, kcreate.DockerConfigEntry{
+		Username: o.Username,
+		Password: o.Password,
+		Email:    o.Email,
+		Auth:     base64.StdEncoding.EncodeToString([]byte(o.Username + ":" + o.Password)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to merge the credentials into secret %q: %v", o.Name, err)
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[corev1.DockerConfigJsonKey] = data
+
+	if o.DryRunStrategy != kcmdutil.DryRunClient {
+		updateOptions := metav1.UpdateOptions{}
+		if o.DryRunStrategy == kcmdutil.DryRunServer {
+			updateOptions.DryRun = []string{metav1.DryRunAll}
+		}
+		secret, err = o.Client.Secrets(o.Namespace).Update(context.TODO(), secret, updateOptions)
+		if err != nil {
+			return err
+		}
+	}
+	return o.PrintObj(secret)
+}
+
+// mergeDockerConfigJSON sets the credentials of server in the auths of a .dockerconfigjson,
+// replacing any entry for the same registry host. Other fields are preserved.
+func mergeDockerConfigJSON(data []byte, server string, entry kcreate.DockerConfigEntry) ([]byte, error) {
+	config := map[string]json.RawMessage{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, err
+		}
+	}
+	auths := map[string]json.RawMessage{}
+	if raw, ok := config["auths"]; ok {
+		if err := json.Unmarshal(raw, &auths); err != nil {
+			return nil, err
+		}
+	}
+
+	for key := range auths {
+		if registryHost(key) == registryHost(server) {
+			delete(auths, key)
+		}
+	}
+	rawEntry, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	auths[server] = rawEntry
+
+	rawAuths, err := json.Marshal(auths)
+	if err != nil {
+		return nil, err
+	}
+	config["auths"] = rawAuths
+	return json.Marshal(config)
+}
+
+// registryHost returns the host of a registry as it is written in a .dockerconfigjson, which
+// may include a scheme and a path such as https://index.docker.io/v1/.
+func registryHost(server string) string {
+	host := server
+	if i := strings.Index(host, "://"); i != -1 {
+		host = host[i+3:]
+	}
+	if i := strings.Index(host, "/"); i != -1 {
+		host = host[:i]
+	}
+	return strings.ToLower(host)
+}
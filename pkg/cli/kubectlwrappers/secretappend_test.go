@@ -0,0 +1,108 @@
+package kubectlwrappers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	kcreate "k8s.io/kubectl/pkg/cmd/create"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+)
+
+func TestMergeDockerConfigJSON(t *testing.T) {
+	existing := `{"auths":{"https://index.docker.io/v1/":{"auth":"b2xkOm9sZA=="},"quay.io":{"auth":"cXVheTpxdWF5"}},"credsStore":"desktop"}`
+	tests := []struct {
+		name     string
+		data     string
+		server   string
+		expected string
+	}{
+		{
+			name:     "empty",
+			server:   "registry.example.com",
+			expected: `{"auths":{"registry.example.com":{"auth":"bmV3Om5ldw=="}}}`,
+		},
+		{
+			name:     "add a registry",
+			data:     existing,
+			server:   "registry.example.com",
+			expected: `{"auths":{"https://index.docker.io/v1/":{"auth":"b2xkOm9sZA=="},"quay.io":{"auth":"cXVheTpxdWF5"},"registry.example.com":{"auth":"bmV3Om5ldw=="}},"credsStore":"desktop"}`,
+		},
+		{
+			name:     "replace the same host",
+			data:     existing,
+			server:   "index.docker.io",
+			expected: `{"auths":{"index.docker.io":{"auth":"bmV3Om5ldw=="},"quay.io":{"auth":"cXVheTpxdWF5"}},"credsStore":"desktop"}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := mergeDockerConfigJSON([]byte(tc.data), tc.server, kcreate.DockerConfigEntry{Auth: "bmV3Om5ldw=="})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actual, expected interface{}
+			if err := json.Unmarshal(data, &actual); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.expected), &expected); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected %s, got %s", tc.expected, data)
+			}
+		})
+	}
+}
+
+func TestSecretAppendRun(t *testing.T) {
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "pull-secret", Namespace: "openshift-config"},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"quay.io":{"auth":"cXVheTpxdWF5"}}}`),
+		},
+	}
+	expected := `{"auths":{"quay.io":{"auth":"cXVheTpxdWF5"},"registry.example.com":{"username":"new","password":"new","auth":"bmV3Om5ldw=="}}}`
+
+	for name, dryRun := range map[string]kcmdutil.DryRunStrategy{"none": kcmdutil.DryRunNone, "client": kcmdutil.DryRunClient} {
+		client := fake.NewSimpleClientset(existing)
+		var printed *corev1.Secret
+		o := &SecretAppendOptions{
+			Name:           "pull-secret",
+			Namespace:      "openshift-config",
+			Username:       "new",
+			Password:       "new",
+			Server:         "registry.example.com",
+			DryRunStrategy: dryRun,
+			Existing:       existing,
+			Client:         client.CoreV1(),
+			PrintObj: func(obj runtime.Object) error {
+				printed = obj.(*corev1.Secret)
+				return nil
+			},
+			IOStreams: genericclioptions.NewTestIOStreamsDiscard(),
+		}
+		if err := o.Run(); err != nil {
+			t.Fatal(err)
+		}
+		if actual := string(printed.Data[corev1.DockerConfigJsonKey]); actual != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, actual)
+		}
+
+		updated := 0
+		for _, action := range client.Actions() {
+			if action.GetVerb() == "update" {
+				updated++
+			}
+		}
+		if (dryRun == kcmdutil.DryRunClient) != (updated == 0) {
+			t.Errorf("%s: unexpected number of updates: %d", name, updated)
+		}
+	}
+}
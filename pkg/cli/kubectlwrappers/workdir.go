@@ -0,0 +1,51 @@
+package kubectlwrappers
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/openshift/oc/pkg/cli/rsh"
+)
+
+const execWorkdirExample = `
+  # Run 'ls' in the /var/lib/data directory of the first container of pod mypod
+  oc exec mypod --workdir=/var/lib/data -- ls`
+
+// withWorkdir adds the --workdir flag to the exec command, which runs the command through a shell
+// in the given directory of the container.
+func withWorkdir(cmd *cobra.Command) *cobra.Command {
+	var workdir string
+	cmd.Flags().StringVar(&workdir, "workdir", workdir, "The directory in the container to run the command in, instead of its working directory. Requires /bin/sh in the container.")
+	cmd.Example += "\n" + execWorkdirExample
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if len(workdir) == 0 {
+			run(c, args)
+			return
+		}
+		args, err := workdirArgs(args, c.ArgsLenAtDash(), workdir)
+		if err != nil {
+			kcmdutil.CheckErr(kcmdutil.UsageErrorf(c, err.Error()))
+		}
+		run(c, args)
+	}
+	return cmd
+}
+
+// workdirArgs wraps the command in args, which starts at argsLenAtDash or after the pod name
+// when no '--' was given, so that it is run in workdir.
+func workdirArgs(args []string, argsLenAtDash int, workdir string) ([]string, error) {
+	start := argsLenAtDash
+	if start == -1 {
+		start = 1
+	}
+	if start >= len(args) {
+		return nil, fmt.Errorf("--workdir requires a command to run")
+	}
+	result := append([]string{}, args[:start]...)
+	return append(result, rsh.WorkdirCommand(rsh.DefaultShell, workdir, args[start:])...), nil
+}
@@ -0,0 +1,33 @@
+package kubectlwrappers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWorkdirArgs(t *testing.T) {
+	wrapped := []string{"/bin/sh", "-c", `cd -- "$1" || exit; shift; exec "$@"`, "/bin/sh", "/data"}
+	tests := []struct {
+		name          string
+		args          []string
+		argsLenAtDash int
+		expected      []string
+		expectErr     bool
+	}{
+		{name: "command after dash", args: []string{"mypod", "ls", "-l"}, argsLenAtDash: 1, expected: append([]string{"mypod"}, append(wrapped, "ls", "-l")...)},
+		{name: "command without dash", args: []string{"mypod", "ls"}, argsLenAtDash: -1, expected: append([]string{"mypod"}, append(wrapped, "ls")...)},
+		{name: "pod from a file", args: []string{"ls"}, argsLenAtDash: 0, expected: append(append([]string{}, wrapped...), "ls")},
+		{name: "no command", args: []string{"mypod"}, argsLenAtDash: -1, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			args, err := workdirArgs(test.args, test.argsLenAtDash, "/data")
+			if test.expectErr != (err != nil) {
+				t.Fatalf("expected error %t, got %v", test.expectErr, err)
+			}
+			if !reflect.DeepEqual(args, test.expected) {
+				t.Errorf("expected %q, got %q", test.expected, args)
+			}
+		})
+	}
+}
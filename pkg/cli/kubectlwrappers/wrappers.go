@@ -16,7 +16,7 @@ import (
 	"k8s.io/kubectl/pkg/cmd/autoscale"
 	"k8s.io/kubectl/pkg/cmd/clusterinfo"
 	"k8s.io/kubectl/pkg/cmd/completion"
-	"k8s.io/kubectl/pkg/cmd/config"
+	kconfig "k8s.io/kubectl/pkg/cmd/config"
 	"k8s.io/kubectl/pkg/cmd/cp"
 	kcreate "k8s.io/kubectl/pkg/cmd/create"
 	"k8s.io/kubectl/pkg/cmd/delete"
@@ -24,7 +24,6 @@ import (
 	"k8s.io/kubectl/pkg/cmd/diff"
 	"k8s.io/kubectl/pkg/cmd/edit"
 	"k8s.io/kubectl/pkg/cmd/exec"
-	"k8s.io/kubectl/pkg/cmd/explain"
 	kget "k8s.io/kubectl/pkg/cmd/get"
 	"k8s.io/kubectl/pkg/cmd/kustomize"
 	"k8s.io/kubectl/pkg/cmd/label"
@@ -39,6 +38,7 @@ import (
 	kwait "k8s.io/kubectl/pkg/cmd/wait"
 	"k8s.io/kubectl/pkg/util/templates"
 
+	"github.com/openshift/oc/pkg/cli/config"
 	"github.com/openshift/oc/pkg/cli/create"
 	cmdutil "github.com/openshift/oc/pkg/helpers/cmd"
 )
@@ -59,7 +59,7 @@ func adjustCmdExamples(cmd *cobra.Command, name string) {
 
 // NewCmdGet is a wrapper for the Kubernetes cli get command
 func NewCmdGet(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	return cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(kget.NewCmdGet("oc", f, streams)))
+	return withRawPages(withShowTags(cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(kget.NewCmdGet("oc", f, streams))), f, streams), f, streams)
 }
 
 // NewCmdReplace is a wrapper for the Kubernetes cli replace command
@@ -97,6 +97,10 @@ func NewCmdCreate(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 	cmd.AddCommand(create.NewCmdCreateImageStreamTag(f, streams))
 	cmd.AddCommand(create.NewCmdCreateBuild(f, streams))
 
+	if secretCmd, _, err := cmd.Find([]string{"secret", "docker-registry"}); err == nil && secretCmd.Name() == "docker-registry" {
+		withSecretAppend(secretCmd, f, streams)
+	}
+
 	adjustCmdExamples(cmd, "create")
 
 	return cmd
@@ -120,7 +124,7 @@ func NewCmdCompletion(streams genericclioptions.IOStreams) *cobra.Command {
 
 // NewCmdExec is a wrapper for the Kubernetes cli exec command
 func NewCmdExec(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	return cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(exec.NewCmdExec(f, streams)))
+	return withWorkdir(withPodOverride(cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(exec.NewCmdExec(f, streams))), f))
 }
 
 // NewCmdPortForward is a wrapper for the Kubernetes cli port-forward command
@@ -161,7 +165,7 @@ func NewCmdRun(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.C
 
 // NewCmdAttach is a wrapper for the Kubernetes cli attach command
 func NewCmdAttach(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	return cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(attach.NewCmdAttach(f, streams)))
+	return withPodOverride(cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(attach.NewCmdAttach(f, streams))), f)
 }
 
 // NewCmdAnnotate is a wrapper for the Kubernetes cli annotate command
@@ -179,11 +183,6 @@ func NewCmdApply(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 	return cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(apply.NewCmdApply("oc", f, streams)))
 }
 
-// NewCmdExplain is a wrapper for the Kubernetes cli explain command
-func NewCmdExplain(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	return cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(explain.NewCmdExplain("oc", f, streams)))
-}
-
 // NewCmdEdit is a wrapper for the Kubernetes cli edit command
 func NewCmdEdit(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
 	return cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(edit.NewCmdEdit(f, streams)))
@@ -193,12 +192,14 @@ func NewCmdEdit(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.
 func NewCmdConfig(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
 	pathOptions := kclientcmd.NewDefaultPathOptions()
 
-	return cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(config.NewCmdConfig(pathOptions, streams)))
+	cmd := cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(kconfig.NewCmdConfig(pathOptions, streams)))
+	cmd.AddCommand(config.NewCmdSwitch(pathOptions, streams))
+	return cmd
 }
 
 // NewCmdCp is a wrapper for the Kubernetes cli cp command
 func NewCmdCp(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	return cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(cp.NewCmdCp(f, streams)))
+	return withVerifyChecksum(cmdutil.ReplaceCommandName("kubectl", "oc", templates.Normalize(cp.NewCmdCp(f, streams))), f, streams)
 }
 
 func NewCmdWait(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
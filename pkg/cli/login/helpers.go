@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -31,6 +33,60 @@ func getMatchingClusters(clientConfig restclient.Config, kubeconfig clientcmdapi
 	return ret
 }
 
+// selectServer lists the servers of the clusters in kubeconfig and prompts for one of them.
+// It returns an empty string if the user chooses to enter a new server instead.
+func selectServer(reader io.Reader, out io.Writer, kubeconfig *clientcmdapi.Config) string {
+	clusters := map[string][]string{}
+	for name, cluster := range kubeconfig.Clusters {
+		if len(cluster.Server) > 0 {
+			clusters[cluster.Server] = append(clusters[cluster.Server], name)
+		}
+	}
+	if len(clusters) == 0 {
+		fmt.Fprintln(out, "No clusters found in the configuration file.")
+		return ""
+	}
+	servers := make([]string, 0, len(clusters))
+	for server := range clusters {
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+
+	currentServer := ""
+	if context, ok := kubeconfig.Contexts[kubeconfig.CurrentContext]; ok {
+		if cluster, ok := kubeconfig.Clusters[context.Cluster]; ok {
+			currentServer = cluster.Server
+		}
+	}
+
+	choice := 1
+	fmt.Fprintln(out, "Select the cluster to log in to:")
+	for i, server := range servers {
+		names := clusters[server]
+		sort.Strings(names)
+		current := ""
+		if server == currentServer {
+			choice = i + 1
+			current = " (current)"
+		}
+		fmt.Fprintf(out, "  %d) %s [%s]%s\n", i+1, server, strings.Join(names, ", "), current)
+	}
+	fmt.Fprintf(out, "  %d) Enter a new server URL\n", len(servers)+1)
+
+	for {
+		input := term.PromptForStringWithDefault(reader, out, strconv.Itoa(choice), "Cluster [%d]: ", choice)
+		i, err := strconv.Atoi(input)
+		if err != nil || i < 1 || i > len(servers)+1 {
+			fmt.Fprintf(out, "Please enter a number between 1 and %d.\n", len(servers)+1)
+			continue
+		}
+		if i > len(servers) {
+			return ""
+		}
+		return servers[i-1]
+	}
+}
+
 // findExistingClientCA returns *either* the existing client CA file name as a string,
 // *or* data in a []byte for a given host, and true if it exists in the given config
 func findExistingClientCA(host string, kubeconfig clientcmdapi.Config) (string, []byte, bool) {
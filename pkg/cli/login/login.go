@@ -32,6 +32,9 @@ var (
 		The information required to login -- like username and password, a session token, or
 		the server details -- can be provided through flags. If not provided, the command will
 		prompt for user input as needed.
+
+		Pass --select to choose the server from a menu of the clusters in your configuration
+		file, or to enter the URL of a new one. This requires an interactive terminal.
 	`)
 
 	loginExample = templates.Examples(`
@@ -43,6 +46,9 @@ var (
 
 		# Log in to the given server with the given credentials (will not prompt interactively)
 		oc login localhost:8443 --username=myuser --password=mypass
+
+		# Choose one of the clusters you have logged in to before, or enter a new server
+		oc login --select
 	`)
 )
 
@@ -82,6 +88,7 @@ func NewCmdLogin(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 	// Login is the only command that can negotiate a session token against the auth server using basic auth
 	cmds.Flags().StringVarP(&o.Username, "username", "u", o.Username, "Username for server")
 	cmds.Flags().StringVarP(&o.Password, "password", "p", o.Password, "Password for server")
+	cmds.Flags().BoolVar(&o.Select, "select", o.Select, "If true, choose the server from the clusters in the configuration file or enter a new one. Requires a terminal.")
 
 	return cmds
 }
@@ -122,7 +129,7 @@ func (o *LoginOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []s
 		}
 		o.Server = addr.String()
 
-	} else if len(o.Server) == 0 {
+	} else if len(o.Server) == 0 && !o.Select {
 		if defaultContext, defaultContextExists := o.StartingKubeConfig.Contexts[o.StartingKubeConfig.CurrentContext]; defaultContextExists {
 			if cluster, exists := o.StartingKubeConfig.Clusters[defaultContext.Cluster]; exists {
 				o.Server = cluster.Server
@@ -155,6 +162,15 @@ func (o LoginOptions) Validate(cmd *cobra.Command, serverFlag string, args []str
 		return errors.New("--server and passing the server URL as an argument are mutually exclusive")
 	}
 
+	if o.Select {
+		if (len(serverFlag) > 0) || (len(args) == 1) {
+			return errors.New("--select and specifying the server URL are mutually exclusive")
+		}
+		if !term.IsTerminal(o.In) {
+			return errors.New("--select requires an interactive terminal, specify the server URL instead")
+		}
+	}
+
 	if (len(o.Server) == 0) && !term.IsTerminal(o.In) {
 		return errors.New("A server URL must be specified")
 	}
@@ -51,6 +51,9 @@ type LoginOptions struct {
 	Password string
 	Project  string
 
+	// Select lets the user choose the server from the clusters in StartingKubeConfig
+	Select bool
+
 	// infra
 	StartingKubeConfig *kclientcmdapi.Config
 	DefaultNamespace   string
@@ -101,6 +104,9 @@ func (o *LoginOptions) getClientConfig() (*restclient.Config, error) {
 	if len(o.Server) == 0 {
 		// we need to have a server to talk to
 		if kterm.IsTerminal(o.In) {
+			if o.Select {
+				o.Server = selectServer(o.In, o.Out, o.StartingKubeConfig)
+			}
 			for !o.serverProvided() {
 				defaultServer := defaultClusterURL
 				promptMsg := fmt.Sprintf("Server [%s]: ", defaultServer)
@@ -1,6 +1,7 @@
 package login
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/json"
 	"encoding/pem"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/MakeNowJust/heredoc"
@@ -386,3 +388,40 @@ func newTLSServer(certString, keyString string) (*httptest.Server, error) {
 	}
 	return server, nil
 }
+
+func TestSelectServer(t *testing.T) {
+	kubeconfig := &kclientcmdapi.Config{
+		Clusters: map[string]*kclientcmdapi.Cluster{
+			"b-example-com:6443":     {Server: "https://b.example.com:6443"},
+			"b-example-com:6443-alt": {Server: "https://b.example.com:6443"},
+			"a-example-com:6443":     {Server: "https://a.example.com:6443"},
+		},
+		Contexts: map[string]*kclientcmdapi.Context{
+			"default/b-example-com:6443/user": {Cluster: "b-example-com:6443"},
+		},
+		CurrentContext: "default/b-example-com:6443/user",
+	}
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "current cluster by default", input: "", expected: "https://b.example.com:6443"},
+		{name: "first cluster", input: "1\n", expected: "https://a.example.com:6443"},
+		{name: "new server", input: "3\n", expected: ""},
+		{name: "invalid choice is asked again", input: "9\nfoo\n1\n", expected: "https://a.example.com:6443"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			server := selectServer(strings.NewReader(tc.input), out, kubeconfig)
+			if server != tc.expected {
+				t.Errorf("expected %q, got %q\n%s", tc.expected, server, out.String())
+			}
+			if !strings.Contains(out.String(), "2) https://b.example.com:6443 [b-example-com:6443, b-example-com:6443-alt] (current)") {
+				t.Errorf("unexpected menu:\n%s", out.String())
+			}
+		})
+	}
+}
@@ -28,7 +28,9 @@ var (
 		Supported resources are builds, build configs (bc), deployment configs (dc), and pods.
 		When a pod is specified and has more than one container, the container name should be
 		specified via -c. When a build config or deployment config is specified, you can view
-		the logs for a particular version of it via --version.
+		the logs for a particular version of it via --version. Use --all-pods with a deployment
+		config to print the logs of every pod of its latest deployment together, each line
+		prefixed with the name of its pod.
 
 		If your pod is failing to start, you may need to use the --previous option to see the
 		logs of the last attempt.
@@ -46,6 +48,9 @@ var (
 		# or due to deployment pruning or manual deletion of the deployment
 		oc logs --version=1 dc/mysql
 
+		# Follow the logs of all the pods of the latest deployment of the mysql deployment config
+		oc logs -f dc/mysql --all-pods
+
 		# Return a snapshot of ruby-container logs from pod backend
 		oc logs backend -c ruby-container
 
@@ -62,6 +67,10 @@ type LogsOptions struct {
 
 	Version int64
 
+	// AllPods requests the logs of every pod of the latest deployment of a
+	// deployment config instead of the logs of its deployer pod.
+	AllPods bool
+
 	// Embed kubectl's LogsOptions directly.
 	*logs.LogsOptions
 }
@@ -90,6 +99,7 @@ func NewCmdLogs(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.
 
 	o.LogsOptions.AddFlags(cmd)
 	cmd.Flags().Int64Var(&o.Version, "version", o.Version, "View the logs of a particular build or deployment by version if greater than zero")
+	cmd.Flags().BoolVar(&o.AllPods, "all-pods", o.AllPods, "When viewing a deployment config, print the logs of all the pods of its latest deployment, prefixed by pod name")
 
 	return cmd
 }
@@ -112,6 +122,14 @@ func (o *LogsOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []st
 // Validate runs the upstream validation for the logs command and then it
 // will validate any OpenShift-specific log options.
 func (o *LogsOptions) Validate(args []string) error {
+	if o.AllPods {
+		if _, ok := o.LogsOptions.Object.(*appsv1.DeploymentConfig); !ok {
+			return fmt.Errorf("--all-pods can only be used with a deployment config")
+		}
+		if o.Version != 0 {
+			return fmt.Errorf("--all-pods cannot be used with --version")
+		}
+	}
 	return o.LogsOptions.Validate()
 }
 
@@ -141,6 +159,11 @@ func (o *LogsOptions) RunLog() error {
 		o.LogsOptions.Options = o.buildLogOptions(podLogOptions)
 
 	case *appsv1.DeploymentConfig:
+		if o.AllPods {
+			// the pod log options make the logs of each pod of the latest deployment be requested
+			o.LogsOptions.Prefix = true
+			break
+		}
 		o.LogsOptions.Options = o.deployLogOptions(podLogOptions)
 	}
 
@@ -87,9 +87,15 @@ var (
 		# Create an application based on the source code in the current git repository (with a public remote) and a container image
 		oc new-app . --image=registry/repo/langimage
 
+		# Create an application deployed by a deployment config instead of a deployment
+		oc new-app centos/ruby-25-centos7~https://github.com/sclorg/ruby-ex.git --as-deployment=false
+
 		# Create an application myapp with Docker based build strategy expecting binary input
 		oc new-app  --strategy=docker --binary --name myapp
 
+		# Create an application with a Docker build, passing a build argument to the Dockerfile
+		oc new-app https://github.com/youruser/yourgitrepo --strategy=docker --build-arg=HTTP_PROXY=http://10.0.0.1:3128
+
 		# Create a Ruby application based on the provided [image]~[source code] combination
 		oc new-app centos/ruby-25-centos7~https://github.com/sclorg/ruby-ex.git
 
@@ -102,6 +108,9 @@ var (
 		# Create an application from a remote repository using its beta4 branch
 		oc new-app https://github.com/openshift/ruby-hello-world#beta4
 
+		# Deploy from an image stream tag that does not exist yet, for example one that a build will produce
+		oc new-app --image-stream=myproject/frontend:v2 --allow-missing-imagestream-tags
+
 		# Create an application based on a stored template, explicitly setting a parameter value
 		oc new-app --template=ruby-helloworld-sample --param=MYSQL_USER=admin
 
@@ -111,6 +120,9 @@ var (
 		# Create an application from a remote private repository and specify which existing secret to use
 		oc new-app https://github.com/youruser/yourgitrepo --source-secret=yoursecret
 
+		# Create an application whose containers take their environment from a secret and a config map
+		oc new-app mysql --env-from=secret/mysql-credentials,configmap/mysql-settings
+
 		# Create an application based on a template file, explicitly setting a parameter value
 		oc new-app --file=./example/myapp/template.json --param=MYSQL_USER=admin
 
@@ -163,6 +175,9 @@ type ObjectGeneratorOptions struct {
 type AppOptions struct {
 	*ObjectGeneratorOptions
 
+	// AsDeployment is the inverse of --as-deployment-config, it is only used when set explicitly.
+	AsDeployment bool
+
 	RESTClientGetter genericclioptions.RESTClientGetter
 
 	genericclioptions.IOStreams
@@ -266,6 +281,7 @@ func NewCmdNewApplication(f kcmdutil.Factory, streams genericclioptions.IOStream
 
 	cmd.Flags().BoolVar(&o.Config.AsTestDeployment, "as-test", o.Config.AsTestDeployment, "If true create this application as a test deployment, which validates that the deployment succeeds and then scales down.")
 	cmd.Flags().BoolVar(&o.Config.DeploymentConfig, "as-deployment-config", o.Config.DeploymentConfig, "If true create this application as a deployment config, which allows for hooks and custom strategies.")
+	cmd.Flags().BoolVar(&o.AsDeployment, "as-deployment", o.AsDeployment, "If true create this application as a deployment, which is the default. If false create it as a deployment config.")
 	cmd.Flags().StringSliceVar(&o.Config.SourceRepositories, "code", o.Config.SourceRepositories, "Source code to use to build this application.")
 	cmd.Flags().StringVar(&o.Config.ContextDir, "context-dir", o.Config.ContextDir, "Context directory to be used for the build.")
 	cmd.Flags().StringSliceVarP(&o.Config.ImageStreams, "image-stream", "i", o.Config.ImageStreams, "Name of an existing image stream to use to deploy an app.")
@@ -282,9 +298,11 @@ func NewCmdNewApplication(f kcmdutil.Factory, streams genericclioptions.IOStream
 	cmd.Flags().StringArrayVarP(&o.Config.Environment, "env", "e", o.Config.Environment, "Specify a key-value pair for an environment variable to set into each container.")
 	cmd.Flags().StringArrayVar(&o.Config.EnvironmentFiles, "env-file", o.Config.EnvironmentFiles, "File containing key-value pairs of environment variables to set into each container.")
 	cmd.MarkFlagFilename("env-file")
+	cmd.Flags().StringSliceVar(&o.Config.EnvironmentFrom, "env-from", o.Config.EnvironmentFrom, "Populate the environment of each container from the keys of a secret or config map, given as secret/NAME or configmap/NAME. May be specified multiple times.")
 	cmd.Flags().StringArrayVar(&o.Config.BuildEnvironment, "build-env", o.Config.BuildEnvironment, "Specify a key-value pair for an environment variable to set into each build image.")
 	cmd.Flags().StringArrayVar(&o.Config.BuildEnvironmentFiles, "build-env-file", o.Config.BuildEnvironmentFiles, "File containing key-value pairs of environment variables to set into each build image.")
 	cmd.MarkFlagFilename("build-env-file")
+	cmd.Flags().StringArrayVar(&o.Config.BuildArgs, "build-arg", o.Config.BuildArgs, "Specify a key-value pair to pass to Docker during the build. Only valid with the docker build strategy.")
 	cmd.Flags().StringVar(&o.Config.Name, "name", o.Config.Name, "Set name to use for generated application artifacts")
 	cmd.Flags().Var(&o.Config.Strategy, "strategy", "Specify the build strategy to use if you don't want to detect (docker|pipeline|source). NOTICE: the pipeline strategy is deprecated; consider using Jenkinsfiles directly on Jenkins or OpenShift Pipelines.")
 	cmd.Flags().StringP("labels", "l", "", "Label to set in all resources for this application.")
@@ -293,7 +311,7 @@ func NewCmdNewApplication(f kcmdutil.Factory, streams genericclioptions.IOStream
 	cmd.Flags().BoolVarP(&o.Config.AsList, "list", "L", o.Config.AsList, "List all local templates and image streams that can be used to create.")
 	cmd.Flags().BoolVarP(&o.Config.AsSearch, "search", "S", o.Config.AsSearch, "Search all templates, image streams, and container images that match the arguments provided. Note: the container images search is run on the OpenShift cluster via the ImageStreamImport API.")
 	cmd.Flags().BoolVar(&o.Config.AllowMissingImages, "allow-missing-images", o.Config.AllowMissingImages, "If true, indicates that referenced container images that cannot be found locally or in a registry should still be used.")
-	cmd.Flags().BoolVar(&o.Config.AllowMissingImageStreamTags, "allow-missing-imagestream-tags", o.Config.AllowMissingImageStreamTags, "If true, indicates that image stream tags that don't exist should still be used.")
+	cmd.Flags().BoolVar(&o.Config.AllowMissingImageStreamTags, "allow-missing-imagestream-tags", o.Config.AllowMissingImageStreamTags, "If true, indicates that image stream tags that don't exist should still be used. Generated triggers will react once the tag is available.")
 	cmd.Flags().BoolVar(&o.Config.AllowSecretUse, "grant-install-rights", o.Config.AllowSecretUse, "If true, a component that requires access to your account may use your token to install software into your project. Only grant images you trust the right to run with your token.")
 	cmd.Flags().StringVar(&o.Config.SourceSecret, "source-secret", o.Config.SourceSecret, "The name of an existing secret that should be used for cloning a private git repository.")
 	cmd.Flags().BoolVar(&o.Config.SkipGeneration, "no-install", o.Config.SkipGeneration, "Do not attempt to run images that describe themselves as being installable")
@@ -310,6 +328,9 @@ func (o *AppOptions) Complete(f kcmdutil.Factory, c *cobra.Command, args []strin
 	o.RESTClientGetter = f
 
 	cmdutil.WarnAboutCommaSeparation(o.ErrOut, o.ObjectGeneratorOptions.Config.TemplateParameters, "--param")
+	if err := o.completeDeploymentType(c); err != nil {
+		return err
+	}
 	err := o.ObjectGeneratorOptions.Complete(f, c, args)
 	if err != nil {
 		return err
@@ -318,6 +339,20 @@ func (o *AppOptions) Complete(f kcmdutil.Factory, c *cobra.Command, args []strin
 	return nil
 }
 
+// completeDeploymentType chooses between a deployment and a deployment config when
+// --as-deployment is set. Without it, --as-deployment-config decides and deployments
+// are generated by default.
+func (o *AppOptions) completeDeploymentType(c *cobra.Command) error {
+	if !c.Flags().Changed("as-deployment") {
+		return nil
+	}
+	if c.Flags().Changed("as-deployment-config") && o.AsDeployment == o.Config.DeploymentConfig {
+		return kcmdutil.UsageErrorf(c, "--as-deployment and --as-deployment-config are mutually exclusive.")
+	}
+	o.Config.DeploymentConfig = !o.AsDeployment
+	return nil
+}
+
 // RunNewApp contains all the necessary functionality for the OpenShift cli new-app command
 func (o *AppOptions) RunNewApp() error {
 	config := o.Config
@@ -755,7 +790,7 @@ func CompleteAppConfig(config *newcmd.AppConfig, f kcmdutil.Factory, c *cobra.Co
 	}
 
 	if len(config.BuildArgs) > 0 && config.Strategy != newapp.StrategyUnspecified && config.Strategy != newapp.StrategyDocker {
-		return kcmdutil.UsageErrorf(c, "Cannot use '--build-arg' without a Docker build")
+		return kcmdutil.UsageErrorf(c, "Cannot use '--build-arg' with the %s strategy, build arguments are only passed to Docker builds", config.Strategy)
 	}
 	return nil
 }
@@ -5,6 +5,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
+
 	"github.com/openshift/oc/pkg/helpers/newapp/app"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,6 +30,10 @@ func TestNewAppDefaultFlags(t *testing.T) {
 		flagName   string
 		defaultVal string
 	}{
+		"as deployment": {
+			flagName:   "as-deployment",
+			defaultVal: strconv.FormatBool(false),
+		},
 		"as test": {
 			flagName:   "as-test",
 			defaultVal: strconv.FormatBool(config.AsTestDeployment),
@@ -73,6 +79,10 @@ func TestNewAppDefaultFlags(t *testing.T) {
 			flagName:   "build-env",
 			defaultVal: "[" + strings.Join(config.BuildEnvironment, ",") + "]",
 		},
+		"build-arg": {
+			flagName:   "build-arg",
+			defaultVal: "[" + strings.Join(config.BuildArgs, ",") + "]",
+		},
 		"name": {
 			flagName:   "name",
 			defaultVal: config.Name,
@@ -133,6 +143,47 @@ func TestNewAppDefaultFlags(t *testing.T) {
 	}
 }
 
+func TestNewAppDeploymentType(t *testing.T) {
+	tests := []struct {
+		name             string
+		args             []string
+		deploymentConfig bool
+		expectErr        bool
+	}{
+		{name: "default", deploymentConfig: false},
+		{name: "as deployment config", args: []string{"--as-deployment-config"}, deploymentConfig: true},
+		{name: "as deployment", args: []string{"--as-deployment"}, deploymentConfig: false},
+		{name: "not as deployment", args: []string{"--as-deployment=false"}, deploymentConfig: true},
+		{name: "consistent flags", args: []string{"--as-deployment=false", "--as-deployment-config"}, deploymentConfig: true},
+		{name: "conflicting flags", args: []string{"--as-deployment", "--as-deployment-config"}, expectErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := NewAppOptions(genericclioptions.NewTestIOStreamsDiscard())
+			cmd := &cobra.Command{}
+			cmd.Flags().BoolVar(&o.Config.DeploymentConfig, "as-deployment-config", o.Config.DeploymentConfig, "")
+			cmd.Flags().BoolVar(&o.AsDeployment, "as-deployment", o.AsDeployment, "")
+			if err := cmd.Flags().Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := o.completeDeploymentType(cmd)
+			if test.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.Config.DeploymentConfig != test.deploymentConfig {
+				t.Errorf("expected deployment config %t, got %t", test.deploymentConfig, o.Config.DeploymentConfig)
+			}
+		})
+	}
+}
+
 // TestNewAppRunFailure test failures.
 func TestNewAppRunFailure(t *testing.T) {
 	tests := map[string]struct {
@@ -114,6 +114,14 @@ var (
 		server, and any node in the inventory file that no longer exists will trigger
 		a call to remove_from_inventory.sh with the name of the node.
 
+		To consume the changes from another program, pass --output=json. Each added,
+		updated, deleted or resynced object is then written to standard output as a
+		single line of JSON containing the change type, the object's resourceVersion
+		and the object itself when it is known. Deletions are reported without
+		requiring --delete or --names, and the output of any commands that are
+		invoked is written to standard error instead. Use --resync-period to control
+		how often every object is reported again as a Sync.
+
 		Important: when handling deletes, the previous state of the object may not be
 		available and only the name/namespace of the object will be passed to	your
 		--delete command as arguments (all custom arguments are omitted).
@@ -134,6 +142,9 @@ var (
 
 		# Observe changes to services filtered by a label selector
 		oc observe namespaces -l regist-dns=true --template '{ .spec.clusterIP }' -- register_dns.sh
+
+		# Stream every change to pods as JSON, resyncing all pods every 10 minutes
+		oc observe pods --output=json --resync-period=10m
 	`)
 )
 
@@ -180,6 +191,7 @@ type ObserveOptions struct {
 	templates       stringSliceFlag
 	printer         printerWrapper
 	strictTemplates bool
+	jsonOutput      bool
 
 	argumentStore *objectArgumentsStore
 	// knownObjects is nil if we do not need to track deletions
@@ -312,6 +324,12 @@ func (o *ObserveOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args [
 		*o.PrintFlags.OutputFormat = "go-template"
 	}
 
+	// json reports each change as a record rather than selecting a template format for the arguments
+	if o.PrintFlags.OutputFormat != nil && *o.PrintFlags.OutputFormat == "json" {
+		o.jsonOutput = true
+		*o.PrintFlags.OutputFormat = "jsonpath"
+	}
+
 	// TODO: Remove in the next release
 	// support backwards compatibility with incorrect flag --strict-templates
 	if o.strictTemplates {
@@ -382,7 +400,7 @@ func (o *ObserveOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args [
 			return outputNames, nil
 		}
 		o.knownObjects = o.argumentStore
-	case len(o.deleteCommand) > 0, o.resyncPeriod > 0:
+	case len(o.deleteCommand) > 0, o.resyncPeriod > 0, o.jsonOutput:
 		o.knownObjects = o.argumentStore
 	}
 
@@ -605,8 +623,8 @@ func (o *ObserveOptions) calculateArguments(delta cache.Delta) (runtime.Object,
 			o.argumentStore.Remove(key)
 		} else {
 			saved := objectArguments{key: key, arguments: arguments}
-			// only cache the object data if the commands will be using it.
-			if len(o.objectEnvVar) > 0 {
+			// only cache the object data if the commands or the output will be using it.
+			if len(o.objectEnvVar) > 0 || o.jsonOutput {
 				saved.output = output
 			}
 			o.argumentStore.Put(key, saved)
@@ -636,10 +654,16 @@ func (o *ObserveOptions) calculateArguments(delta cache.Delta) (runtime.Object,
 }
 
 func (o *ObserveOptions) startSync() error {
+	if o.jsonOutput {
+		return nil
+	}
 	fmt.Fprintf(o.debugOut, "# %s Sync started\n", time.Now().Format(time.RFC3339))
 	return nil
 }
 func (o *ObserveOptions) finishSync() error {
+	if o.jsonOutput {
+		return nil
+	}
 	fmt.Fprintf(o.debugOut, "# %s Sync ended\n", time.Now().Format(time.RFC3339))
 	return nil
 }
@@ -674,14 +698,28 @@ func (o *ObserveOptions) next(deltaType cache.DeltaType, obj runtime.Object, out
 
 	args = append(args, arguments...)
 
-	if len(command) == 0 {
+	if o.jsonOutput {
+		// without a template the printer produces a single empty argument
+		recordArguments := arguments
+		if len(recordArguments) == 1 && len(recordArguments[0]) == 0 {
+			recordArguments = nil
+		}
+		if err := writeObserveRecord(o.Out, time.Now(), outType, m, recordArguments, output); err != nil {
+			return err
+		}
+	} else {
 		fmt.Fprintf(o.debugOut, "# %s %s %s\t%s\n", time.Now().Format(time.RFC3339), outType, resourceVersion, printCommandLine(command, args...))
-		return nil
 	}
 
-	fmt.Fprintf(o.debugOut, "# %s %s %s\t%s\n", time.Now().Format(time.RFC3339), outType, resourceVersion, printCommandLine(command, args...))
+	if len(command) == 0 {
+		return nil
+	}
 
+	// keep standard output a stream of records when printing json
 	out, errOut := &newlineTrailingWriter{w: o.Out}, &newlineTrailingWriter{w: o.ErrOut}
+	if o.jsonOutput {
+		out.w = o.ErrOut
+	}
 
 	err = retryCommandError(o.retryExitStatus, o.retryCount, func() error {
 		cmd := exec.Command(command, args...)
@@ -2,9 +2,12 @@ package observe
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/kubectl/pkg/scheme"
@@ -27,6 +30,39 @@ func (p printerWrapper) PrintObj(obj runtime.Object) ([]string, []byte, error) {
 	return []string{out.String()}, data, nil
 }
 
+// observeRecord is a single observed change, written as a line of JSON with --output=json.
+type observeRecord struct {
+	Time            string          `json:"time"`
+	Type            string          `json:"type"`
+	Namespace       string          `json:"namespace,omitempty"`
+	Name            string          `json:"name"`
+	ResourceVersion string          `json:"resourceVersion,omitempty"`
+	Arguments       []string        `json:"arguments,omitempty"`
+	Object          json.RawMessage `json:"object,omitempty"`
+}
+
+// writeObserveRecord writes a record of the change to obj to out. data is the serialized object,
+// and is omitted from the record if it is not known.
+func writeObserveRecord(out io.Writer, now time.Time, changeType string, obj metav1.Object, arguments []string, data []byte) error {
+	record := observeRecord{
+		Time:            now.UTC().Format(time.RFC3339),
+		Type:            changeType,
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		ResourceVersion: obj.GetResourceVersion(),
+		Arguments:       arguments,
+	}
+	if len(data) > 0 {
+		record.Object = json.RawMessage(bytes.TrimSpace(data))
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(out, "%s\n", line)
+	return err
+}
+
 type newlineTrailingWriter struct {
 	w        io.Writer
 	openLine bool
@@ -0,0 +1,57 @@
+package observe
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+func TestWriteObserveRecord(t *testing.T) {
+	pod := &corev1.Pod{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test", ResourceVersion: "42"},
+	}
+	data, err := runtime.Encode(scheme.DefaultJSONEncoder(), pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := &bytes.Buffer{}
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	if err := writeObserveRecord(out, now, "Updated", pod, []string{"10.0.0.1"}, data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeObserveRecord(out, now, "Deleted", &pod.ObjectMeta, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("expected one record per line, got:\n%s", out.String())
+	}
+
+	var updated map[string]interface{}
+	if err := json.Unmarshal(lines[0], &updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated["type"] != "Updated" || updated["resourceVersion"] != "42" || updated["namespace"] != "test" || updated["name"] != "web" || updated["time"] != "2021-06-01T12:00:00Z" {
+		t.Errorf("unexpected record: %s", lines[0])
+	}
+	if object, ok := updated["object"].(map[string]interface{}); !ok || object["kind"] != "Pod" {
+		t.Errorf("expected the object in the record: %s", lines[0])
+	}
+
+	var deleted map[string]interface{}
+	if err := json.Unmarshal(lines[1], &deleted); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := deleted["object"]; ok || deleted["type"] != "Deleted" {
+		t.Errorf("unexpected delete record: %s", lines[1])
+	}
+}
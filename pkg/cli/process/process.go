@@ -1,10 +1,17 @@
 package process
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
 	"reflect"
 	"strings"
 	"time"
@@ -52,6 +59,28 @@ var (
 		Process resolves the template on the server, but you may pass --local to parameterize the template
 		locally. When running locally be aware that the version of your client tools will determine what
 		template transformations are supported, rather than the server.
+
+		The template file may be given as an http or https URL, in which case it is downloaded using
+		the proxy settings from the environment. Downloads that take longer than 30 seconds or are
+		larger than 10MB are rejected. Pass --insecure-skip-tls-verify to skip verifying the
+		certificate of the server hosting the template.
+
+		Parameter values may be read from one or more files with --param-file. Each line of a file
+		is KEY=VALUE, optionally prefixed with 'export' as in a shell script; blank lines and lines
+		starting with '#' are ignored. Values in later files override those in earlier files, and
+		values given with --param or as arguments override all files. With --param-file-expand,
+		references to environment variables such as $HOME or ${HOME} in file values are expanded.
+
+		By default the processed objects are printed as a single List. With -o yaml and
+		--as-individual-documents, each object is printed as its own YAML document separated by
+		'---' instead, in the order of the template, as expected by tools such as kustomize.
+
+		To prepare the output for 'oc apply --server-side', pass --clean to remove the fields of the
+		processed objects that are set by the server, such as a null creationTimestamp, an empty
+		status, or the uid and resourceVersion of exported objects. Empty values that are part of the
+		desired state, such as an empty ConfigMap data or zero replicas, are kept. Pass
+		--field-manager to record the field manager the objects should be applied with in the
+		template.openshift.io/field-manager annotation of every object.
 	`)
 
 	processExample = templates.Examples(`
@@ -70,9 +99,21 @@ var (
 		# Convert a stored template into a resource list by setting/overriding parameter values
 		oc process foo PARM1=VALUE1 PARM2=VALUE2
 
+		# Set parameter values from a base file, overridden by an environment specific file and a flag
+		oc process foo --param-file=base.env --param-file=prod.env -p REPLICAS=3
+
+		# Print each processed object as its own YAML document for use with kustomize
+		oc process -f template.yaml -l app=myapp -o yaml --as-individual-documents > resources.yaml
+
+		# Apply a template with server-side apply without fields set by the server
+		oc process -f template.yaml --clean --field-manager=my-app | oc apply --server-side --field-manager=my-app -f -
+
 		# Convert a template stored in different namespace into a resource list
 		oc process openshift//foo
 
+		# Process a template shared by URL
+		oc process -f https://raw.githubusercontent.com/example/templates/main/app.yaml -p NAME=app
+
 		# Convert template.json into a resource list
 		cat template.json | oc process -f -
 	`)
@@ -89,15 +130,21 @@ type ProcessOptions struct {
 	filename            string
 	local               bool
 	raw                 bool
+	individualDocuments bool
+	clean               bool
+	fieldManager        string
 	parameters          bool
 	ignoreUnknownParams bool
 	templateName        string
 	paramFile           []string
+	expandParamFiles    bool
 	templateParams      []string
 	namespace           string
 	explicitNamespace   bool
 	paramValuesProvided bool
 
+	insecureSkipTLSVerify bool
+
 	templateClient    *templatev1client.TemplateV1Client
 	templateProcessor func(*templatev1.Template) (*templatev1.Template, error)
 
@@ -150,14 +197,18 @@ func NewCmdProcess(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cob
 	cmd.Flags().StringVarP(&o.filename, "filename", "f", o.filename, "Filename or URL to file to read a template")
 	cmd.MarkFlagFilename("filename", "yaml", "yml", "json")
 	cmd.Flags().StringArrayVarP(&o.templateParams, "param", "p", o.templateParams, "Specify a key-value pair (eg. -p FOO=BAR) to set/override a parameter value in the template.")
-	cmd.Flags().StringArrayVar(&o.paramFile, "param-file", o.paramFile, "File containing template parameter values to set/override in the template.")
+	cmd.Flags().StringArrayVar(&o.paramFile, "param-file", o.paramFile, "File containing template parameter values to set/override in the template. May be specified multiple times; values in later files override those in earlier files.")
 	cmd.MarkFlagFilename("param-file")
+	cmd.Flags().BoolVar(&o.expandParamFiles, "param-file-expand", o.expandParamFiles, "If true, expand references to environment variables in values read from --param-file.")
 	cmd.Flags().BoolVar(&o.ignoreUnknownParams, "ignore-unknown-parameters", o.ignoreUnknownParams, "If true, will not stop processing if a provided parameter does not exist in the template.")
 	cmd.Flags().BoolVarP(&o.local, "local", "", o.local, "If true process the template locally instead of contacting the server.")
 	cmd.Flags().BoolVarP(&o.parameters, "parameters", "", o.parameters, "If true, do not process but only print available parameters")
 	cmd.Flags().StringVarP(&o.labels, "labels", "l", o.labels, "Label to set in all resources for this template")
 
 	cmd.Flags().BoolVar(&o.raw, "raw", o.raw, "If true, output the processed template instead of the template's objects. Implied by -o describe")
+	cmd.Flags().BoolVar(&o.individualDocuments, "as-individual-documents", o.individualDocuments, "If true, print each processed object as its own YAML document instead of a List. Requires -o yaml.")
+	cmd.Flags().BoolVar(&o.clean, "clean", o.clean, "If true, remove fields set by the server, such as a null creationTimestamp or an empty status, from the processed objects.")
+	cmd.Flags().StringVar(&o.fieldManager, "field-manager", o.fieldManager, "If set, record this field manager in the "+fieldManagerAnnotation+" annotation of the processed objects.")
 
 	return cmd
 }
@@ -166,6 +217,9 @@ func NewCmdProcess(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cob
 type processPrinter struct {
 	printFlags   *genericclioptions.PrintFlags
 	outputFormat string
+
+	// printer is reused so that the YAML printer separates the objects it prints
+	printer printers.ResourcePrinter
 }
 
 func (p *processPrinter) PrintObj(obj runtime.Object, out io.Writer) error {
@@ -189,12 +243,15 @@ func (p *processPrinter) PrintObj(obj runtime.Object, out io.Writer) error {
 		return nil
 	}
 
-	printer, err := p.printFlags.ToPrinter()
-	if err != nil {
-		return err
+	if p.printer == nil {
+		printer, err := p.printFlags.ToPrinter()
+		if err != nil {
+			return err
+		}
+		p.printer = printer
 	}
 
-	return printer.PrintObj(obj, out)
+	return p.printer.PrintObj(obj, out)
 }
 
 func (o *ProcessOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
@@ -210,6 +267,10 @@ func (o *ProcessOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args [
 	}
 
 	o.paramValuesProvided = cmd.Flag("param").Changed
+	// the global flag also applies to fetching a template from an https URL
+	if f := cmd.Flag("insecure-skip-tls-verify"); f != nil {
+		o.insecureSkipTLSVerify = f.Value.String() == "true"
+	}
 
 	templateName, templateParams := "", []string{}
 	for _, s := range args {
@@ -284,13 +345,17 @@ func (o *ProcessOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args [
 
 func (o *ProcessOptions) Validate(cmd *cobra.Command) error {
 	if o.parameters {
-		for _, flag := range []string{"param", "labels", "output", "output-version", "raw", "template"} {
+		for _, flag := range []string{"param", "labels", "output", "output-version", "raw", "as-individual-documents", "template", "clean", "field-manager"} {
 			if f := cmd.Flags().Lookup(flag); f != nil && f.Changed {
 				return kcmdutil.UsageErrorf(cmd, "The --parameters flag does not process the template, can't be used with --%v", flag)
 			}
 		}
 	}
 
+	if o.individualDocuments && o.outputFormat != "yaml" {
+		return kcmdutil.UsageErrorf(cmd, "--as-individual-documents requires -o yaml")
+	}
+
 	if len(o.templateName) > 0 && o.local {
 		return kcmdutil.UsageErrorf(cmd, "You may only specify a local template file via -f when running this command with --local")
 	}
@@ -300,16 +365,8 @@ func (o *ProcessOptions) Validate(cmd *cobra.Command) error {
 
 // RunProcess contains all the necessary functionality for the OpenShift cli process command
 func (o *ProcessOptions) RunProcess() error {
-	duplicatedKeys := sets.NewString()
-	params, paramErr := app.ParseAndCombineEnvironment(o.templateParams, o.paramFile, o.In, func(key, file string) error {
-		if file == "" {
-			duplicatedKeys.Insert(key)
-		} else {
-			fmt.Fprintf(o.ErrOut, "warning: Template parameter %q already defined, ignoring value from file %q\n", key, file)
-		}
-		return nil
-	})
-	if len(duplicatedKeys) != 0 {
+	params, duplicates, paramErr := o.parseParameters()
+	if duplicatedKeys := sets.NewString(duplicates...); len(duplicatedKeys) != 0 {
 		return o.usageErrorFn(fmt.Sprintf("The following parameters were provided more than once: %s", strings.Join(duplicatedKeys.List(), ", ")))
 	}
 
@@ -353,12 +410,19 @@ func (o *ProcessOptions) RunProcess() error {
 		infos = append(infos, &resource.Info{Object: templateObj})
 	} else {
 		var err error
-		infos, err = o.builderFn().
+		b := o.builderFn().
 			WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
-			LocalParam(o.local).
-			FilenameParam(o.explicitNamespace, &resource.FilenameOptions{Recursive: false, Filenames: []string{o.filename}}).
-			Do().
-			Infos()
+			LocalParam(o.local)
+		if isTemplateURL(o.filename) {
+			data, err := fetchTemplateURL(o.filename, o.insecureSkipTLSVerify)
+			if err != nil {
+				return err
+			}
+			b = b.Stream(bytes.NewReader(data), o.filename)
+		} else {
+			b = b.FilenameParam(o.explicitNamespace, &resource.FilenameOptions{Recursive: false, Filenames: []string{o.filename}})
+		}
+		infos, err = b.Do().Infos()
 		if err != nil {
 			return fmt.Errorf("failed to read input object (not a Template?): %v", err)
 		}
@@ -419,9 +483,18 @@ func (o *ProcessOptions) RunProcess() error {
 		return o.Printer.PrintObj(resultObj, o.Out)
 	}
 
+	if o.clean || len(o.fieldManager) > 0 {
+		for i := range resultObj.Objects {
+			if err := cleanObject(&resultObj.Objects[i], o.clean, o.fieldManager); err != nil {
+				return err
+			}
+		}
+	}
+
 	// the name printer does not accept object lists, so re-use
-	// the print loop used for --raw printing instead.
-	if o.outputFormat == "name" || o.raw {
+	// the print loop used for --raw printing instead. The same loop
+	// prints each object as its own document when requested.
+	if o.outputFormat == "name" || o.raw || o.individualDocuments {
 		for _, obj := range resultObj.Objects {
 			objToPrint := obj.Object
 
@@ -448,6 +521,119 @@ func (o *ProcessOptions) RunProcess() error {
 	}, o.Out)
 }
 
+// fieldManagerAnnotation records the field manager the processed objects are meant to be
+// applied with.
+const fieldManagerAnnotation = "template.openshift.io/field-manager"
+
+// serverSetMetadataFields are the metadata fields of exported objects that are owned by the
+// server and make applying them conditional or non-idempotent.
+var serverSetMetadataFields = []string{"uid", "resourceVersion", "selfLink", "generation", "managedFields"}
+
+// cleanObject removes the fields set by the server from a processed object when clean is true,
+// and records fieldManager in its annotations when it is set. The object is stored back as Raw.
+func cleanObject(obj *runtime.RawExtension, clean bool, fieldManager string) error {
+	var content map[string]interface{}
+	switch {
+	case obj.Object == nil:
+		if err := json.Unmarshal(obj.Raw, &content); err != nil {
+			return err
+		}
+	default:
+		var err error
+		content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(obj.Object)
+		if err != nil {
+			return err
+		}
+	}
+
+	if clean {
+		metadata, _ := content["metadata"].(map[string]interface{})
+		for _, field := range serverSetMetadataFields {
+			delete(metadata, field)
+		}
+		if status, ok := content["status"].(map[string]interface{}); ok && len(status) == 0 {
+			delete(content, "status")
+		}
+		removeNullCreationTimestamps(content)
+	}
+	if len(fieldManager) > 0 {
+		u := &unstructured.Unstructured{Object: content}
+		annotations := u.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[fieldManagerAnnotation] = fieldManager
+		u.SetAnnotations(annotations)
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	obj.Raw, obj.Object = data, nil
+	return nil
+}
+
+// removeNullCreationTimestamps removes the null creationTimestamp of every metadata in value,
+// including those of nested pod templates.
+func removeNullCreationTimestamps(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if metadata, ok := v["metadata"].(map[string]interface{}); ok {
+			if timestamp, ok := metadata["creationTimestamp"]; ok && timestamp == nil {
+				delete(metadata, "creationTimestamp")
+			}
+		}
+		for _, child := range v {
+			removeNullCreationTimestamps(child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			removeNullCreationTimestamps(child)
+		}
+	}
+}
+
+// parseParameters combines the values from the parameter files with those given on the
+// command line. Later files override earlier ones and command line values override all
+// files. Keys given more than once on the command line are returned separately.
+func (o *ProcessOptions) parseParameters() (app.Environment, []string, error) {
+	params, duplicates, errs := app.ParseEnvironment(o.templateParams...)
+	if len(errs) > 0 {
+		return nil, nil, errs[0]
+	}
+	fileParams, err := loadParamFiles(o.paramFile, o.In, o.expandParamFiles)
+	if err != nil {
+		return nil, duplicates, err
+	}
+	for key, value := range fileParams {
+		if _, ok := params[key]; !ok {
+			params[key] = value
+		}
+	}
+	return params, duplicates, nil
+}
+
+// loadParamFiles reads the parameter files in order, letting values in later files replace
+// values from earlier ones. If expand is true, environment variable references in the
+// values are expanded.
+func loadParamFiles(filenames []string, stdin io.Reader, expand bool) (app.Environment, error) {
+	params := make(app.Environment)
+	for _, filename := range filenames {
+		values, err := app.LoadEnvironmentFile(filename, stdin)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range values {
+			if expand {
+				value = os.ExpandEnv(value)
+			}
+			params[key] = value
+		}
+	}
+	return params, nil
+}
+
 // injectUserVars injects user specified variables into the Template
 func injectUserVars(values app.Environment, t *templatev1.Template, ignoreUnknownParameters bool) []error {
 	var errors []error
@@ -463,6 +649,47 @@ func injectUserVars(values app.Environment, t *templatev1.Template, ignoreUnknow
 	return errors
 }
 
+var (
+	// templateURLTimeout bounds how long downloading a template may take.
+	templateURLTimeout = 30 * time.Second
+	// maxTemplateURLSize is the largest template that will be downloaded.
+	maxTemplateURLSize int64 = 10 * 1024 * 1024
+)
+
+// isTemplateURL returns true if filename is an http or https URL.
+func isTemplateURL(filename string) bool {
+	u, err := url.Parse(filename)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && len(u.Host) > 0
+}
+
+// fetchTemplateURL downloads the template at location, honoring the proxy settings from the
+// environment and failing if the download is too slow or too large.
+func fetchTemplateURL(location string, insecureSkipTLSVerify bool) ([]byte, error) {
+	client := &http.Client{
+		Timeout: templateURLTimeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipTLSVerify},
+		},
+	}
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to download template from %s: %v", location, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to download template from %s: %s", location, resp.Status)
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxTemplateURLSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("unable to download template from %s: %v", location, err)
+	}
+	if int64(len(data)) > maxTemplateURLSize {
+		return nil, fmt.Errorf("the template at %s is larger than the maximum of %d bytes", location, maxTemplateURLSize)
+	}
+	return data, nil
+}
+
 // processTemplateLocally applies the same logic that a remote call would make but makes no
 // connection to the server.
 func processTemplateLocally(tpl *templatev1.Template) (*templatev1.Template, error) {
@@ -2,8 +2,24 @@ package process
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kcmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	"k8s.io/kubectl/pkg/scheme"
+
+	"github.com/openshift/api/template"
 	templatev1 "github.com/openshift/api/template/v1"
 )
 
@@ -41,3 +57,219 @@ func TestInjectUserVars(t *testing.T) {
 			"parameter_foo_bar_2", "value_foo_bar_2", template.Parameters[1].Name, template.Parameters[1].Value)
 	}
 }
+
+func TestParseParameters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "process-params")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	base := filepath.Join(dir, "base.env")
+	if err := ioutil.WriteFile(base, []byte("# defaults\n\nNAME=base\nexport REPLICAS=1\nexported=yes\nHOME_DIR=$PARAM_TEST_HOME\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	prod := filepath.Join(dir, "prod.env")
+	if err := ioutil.WriteFile(prod, []byte("export NAME=prod\nREPLICAS=2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("PARAM_TEST_HOME", "/home/test")
+	defer os.Unsetenv("PARAM_TEST_HOME")
+
+	o := &ProcessOptions{
+		templateParams: []string{"REPLICAS=3", "LABEL=a", "LABEL=b"},
+		paramFile:      []string{base, prod},
+	}
+	params, duplicates, err := o.parseParameters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(duplicates) != 1 || duplicates[0] != "LABEL" {
+		t.Errorf("expected LABEL to be reported as a duplicate, got %v", duplicates)
+	}
+	expected := map[string]string{
+		"NAME":     "prod",
+		"REPLICAS": "3",
+		"LABEL":    "a",
+		"exported": "yes",
+		"HOME_DIR": "$PARAM_TEST_HOME",
+	}
+	for key, value := range expected {
+		if params[key] != value {
+			t.Errorf("expected %s=%q, got %q", key, value, params[key])
+		}
+	}
+	if len(params) != len(expected) {
+		t.Errorf("unexpected parameters: %v", params)
+	}
+
+	o.expandParamFiles = true
+	params, _, err = o.parseParameters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["HOME_DIR"] != "/home/test" {
+		t.Errorf("expected HOME_DIR to be expanded, got %q", params["HOME_DIR"])
+	}
+}
+
+func TestFetchTemplateURL(t *testing.T) {
+	template := `{"kind":"Template","apiVersion":"template.openshift.io/v1","metadata":{"name":"test"}}`
+	mux := http.NewServeMux()
+	mux.HandleFunc("/template.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, template)
+	})
+	mux.HandleFunc("/large.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Repeat(" ", int(maxTemplateURLSize)+1))
+	})
+	mux.HandleFunc("/slow.json", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, template)
+	})
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	defer func(timeout time.Duration) { templateURLTimeout = timeout }(templateURLTimeout)
+	templateURLTimeout = 100 * time.Millisecond
+
+	if !isTemplateURL(server.URL+"/template.json") || isTemplateURL("template.json") || isTemplateURL("/tmp/template.json") {
+		t.Errorf("unexpected URL detection")
+	}
+
+	if _, err := fetchTemplateURL(server.URL+"/template.json", false); err == nil {
+		t.Errorf("expected an error for an untrusted certificate")
+	}
+	data, err := fetchTemplateURL(server.URL+"/template.json", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != template {
+		t.Errorf("unexpected template: %s", data)
+	}
+	if _, err := fetchTemplateURL(server.URL+"/missing.json", true); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if _, err := fetchTemplateURL(server.URL+"/large.json", true); err == nil || !strings.Contains(err.Error(), "larger than the maximum") {
+		t.Errorf("expected a size error, got %v", err)
+	}
+	if _, err := fetchTemplateURL(server.URL+"/slow.json", true); err == nil {
+		t.Errorf("expected a timeout error")
+	}
+}
+
+func TestRunProcessIndividualDocuments(t *testing.T) {
+	utilruntime.Must(template.Install(scheme.Scheme))
+
+	dir, err := ioutil.TempDir("", "process")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "template.yaml")
+	if err := ioutil.WriteFile(filename, []byte(`kind: Template
+apiVersion: template.openshift.io/v1
+metadata:
+  name: test
+objects:
+- kind: Service
+  apiVersion: v1
+  metadata:
+    name: first
+- kind: ConfigMap
+  apiVersion: v1
+  metadata:
+    name: second
+`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tf := kcmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	o := NewProcessOptions(streams)
+	o.outputFormat = "yaml"
+	o.PrintFlags.OutputFormat = &o.outputFormat
+	o.Printer = &processPrinter{printFlags: o.PrintFlags, outputFormat: o.outputFormat}
+	o.filename, o.local, o.labels, o.individualDocuments = filename, true, "app=test", true
+	o.builderFn = tf.NewBuilder
+	o.templateProcessor = processTemplateLocally
+	cmd := NewCmdProcess(tf, streams)
+	if err := o.Validate(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.RunProcess(); err != nil {
+		t.Fatal(err)
+	}
+
+	documents := strings.Split(out.String(), "---\n")
+	if len(documents) != 2 {
+		t.Fatalf("expected 2 documents, got:\n%s", out.String())
+	}
+	for i, name := range []string{"first", "second"} {
+		if strings.Contains(documents[i], "kind: List") || !strings.Contains(documents[i], "name: "+name) || !strings.Contains(documents[i], "app: test") {
+			t.Errorf("unexpected document %d:\n%s", i, documents[i])
+		}
+	}
+
+	o.outputFormat = "json"
+	if err := o.Validate(cmd); err == nil || !strings.Contains(err.Error(), "requires -o yaml") {
+		t.Errorf("expected -o json to be rejected, got %v", err)
+	}
+}
+
+func TestCleanObject(t *testing.T) {
+	tests := []struct {
+		name         string
+		object       string
+		clean        bool
+		fieldManager string
+		expected     string
+	}{
+		{
+			name:     "server set fields",
+			object:   `{"kind":"Service","apiVersion":"v1","metadata":{"name":"web","creationTimestamp":null,"uid":"1234","resourceVersion":"5"},"spec":{},"status":{"loadBalancer":{}}}`,
+			clean:    true,
+			expected: `{"apiVersion":"v1","kind":"Service","metadata":{"name":"web"},"spec":{},"status":{"loadBalancer":{}}}`,
+		},
+		{
+			name:     "nested pod template and empty status",
+			object:   `{"kind":"Deployment","apiVersion":"apps/v1","metadata":{"name":"web","creationTimestamp":null},"spec":{"replicas":0,"template":{"metadata":{"creationTimestamp":null,"labels":{}},"spec":{"containers":[{"name":"web","image":"web","env":[{"name":"EMPTY","value":""}],"resources":{}}]}}},"status":{}}`,
+			clean:    true,
+			expected: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web"},"spec":{"replicas":0,"template":{"metadata":{"labels":{}},"spec":{"containers":[{"env":[{"name":"EMPTY","value":""}],"image":"web","name":"web","resources":{}}]}}}}`,
+		},
+		{
+			name:     "meaningful empty values are kept",
+			object:   `{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"empty","creationTimestamp":"2021-01-01T00:00:00Z","annotations":{}},"data":{}}`,
+			clean:    true,
+			expected: `{"apiVersion":"v1","data":{},"kind":"ConfigMap","metadata":{"annotations":{},"creationTimestamp":"2021-01-01T00:00:00Z","name":"empty"}}`,
+		},
+		{
+			name:         "field manager",
+			object:       `{"kind":"ConfigMap","apiVersion":"v1","metadata":{"name":"config","creationTimestamp":null}}`,
+			fieldManager: "my-app",
+			expected:     `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"annotations":{"template.openshift.io/field-manager":"my-app"},"creationTimestamp":null,"name":"config"}}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			obj := &runtime.RawExtension{Raw: []byte(test.object)}
+			if err := cleanObject(obj, test.clean, test.fieldManager); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(obj.Raw) != test.expected {
+				t.Errorf("expected\n%s\ngot\n%s", test.expected, obj.Raw)
+			}
+		})
+	}
+
+	obj := &runtime.RawExtension{Object: &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "typed"},
+	}}
+	if err := cleanObject(obj, true, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obj.Object != nil || string(obj.Raw) != `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"typed"}}` {
+		t.Errorf("unexpected typed object: %s", obj.Raw)
+	}
+}
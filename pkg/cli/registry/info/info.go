@@ -2,6 +2,7 @@ package info
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -9,14 +10,19 @@ import (
 	"github.com/docker/distribution/registry/client/transport"
 	"github.com/spf13/cobra"
 
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apirequest "k8s.io/apiserver/pkg/endpoints/request"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/util/templates"
 
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	imageclient "github.com/openshift/client-go/image/clientset/versioned"
+	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	"github.com/openshift/library-go/pkg/image/reference"
 	"github.com/openshift/library-go/pkg/image/registryclient"
 )
@@ -30,12 +36,21 @@ var (
 		has not configured a public host name for the registry then this command may fail when
 		run outside of the server.
 
+		Use --output=json to print the internal and public hostnames together. The internal
+		hostname is read from the cluster image configuration and the public hostname from the
+		route that exposes the registry, falling back to the image streams in the current
+		namespace if you do not have access to them. If the registry is not exposed the public
+		hostname is empty and a note explains why.
+
 		Experimental: This command is under active development and may change without notice.
 	`)
 
 	example = templates.Examples(`
 		# Display information about the integrated registry
 		oc registry info
+
+		# Display the internal and public hostnames of the integrated registry as JSON
+		oc registry info -o json
 	`)
 )
 
@@ -44,9 +59,12 @@ type Options struct {
 	Quiet        bool
 	ShowInternal bool
 	ShowPublic   bool
+	Output       string
 
-	Namespaces []string
-	Client     imageclient.Interface
+	Namespaces   []string
+	Client       imageclient.Interface
+	ConfigClient configclient.Interface
+	RouteClient  routeclient.Interface
 
 	genericclioptions.IOStreams
 }
@@ -78,6 +96,7 @@ func NewRegistryInfoCmd(f kcmdutil.Factory, streams genericclioptions.IOStreams)
 	flag.BoolVarP(&o.Check, "quiet", "q", o.Quiet, "Suppress normal output and only print status.")
 	flag.BoolVar(&o.ShowInternal, "internal", o.ShowInternal, "Only check the internal registry hostname.")
 	flag.BoolVar(&o.ShowPublic, "public", o.ShowPublic, "Only check the public registry hostname.")
+	flag.StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: json. With json, the internal and public hostnames are printed together.")
 
 	return cmd
 }
@@ -92,6 +111,16 @@ func (o *Options) Complete(f kcmdutil.Factory, args []string) error {
 		return err
 	}
 	o.Client = client
+	if o.Output == "json" {
+		o.ConfigClient, err = configclient.NewForConfig(cfg)
+		if err != nil {
+			return err
+		}
+		o.RouteClient, err = routeclient.NewForConfig(cfg)
+		if err != nil {
+			return err
+		}
+	}
 
 	ns, _, err := f.ToRawKubeConfigLoader().Namespace()
 	if err != nil {
@@ -149,14 +178,110 @@ func findRegistryInfo(client imageclient.Interface, namespaces ...string) (*Regi
 	return nil, fmt.Errorf("no image streams could be located to retrieve registry info, please specify a namespace with image streams")
 }
 
+const (
+	registryNamespace = "openshift-image-registry"
+	registryRouteName = "default-route"
+)
+
+// registryHostnames is printed by --output=json.
+type registryHostnames struct {
+	InternalHostname string `json:"internalHostname"`
+	PublicHostname   string `json:"publicHostname"`
+	// TLS is true if the public route terminates TLS.
+	TLS  bool   `json:"tls"`
+	Note string `json:"note,omitempty"`
+}
+
+// findRegistryHostnames reads the internal hostname of the registry service from the cluster image
+// configuration, if it could be read, and the public hostname from the default route of the image
+// registry operator. Hostnames that cannot be read that way are taken from the image streams in namespaces.
+func findRegistryHostnames(config *configv1.Image, routeClient routeclient.Interface, client imageclient.Interface, namespaces ...string) (*registryHostnames, error) {
+	hostnames := &registryHostnames{}
+	routeFound := false
+
+	if config != nil {
+		hostnames.InternalHostname = config.Status.InternalRegistryHostname
+	}
+
+	route, err := routeClient.RouteV1().Routes(registryNamespace).Get(context.TODO(), registryRouteName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		routeFound = true
+		hostnames.PublicHostname = route.Spec.Host
+		for _, ingress := range route.Status.Ingress {
+			if len(ingress.Host) > 0 {
+				hostnames.PublicHostname = ingress.Host
+				break
+			}
+		}
+		hostnames.TLS = route.Spec.TLS != nil
+	case kerrors.IsNotFound(err):
+		routeFound = true
+	default:
+		klog.V(4).Infof("Unable to read the registry route: %v", err)
+	}
+
+	if len(hostnames.InternalHostname) == 0 || !routeFound {
+		info, err := findRegistryInfo(client, namespaces...)
+		if err != nil {
+			if len(hostnames.InternalHostname) == 0 {
+				return nil, err
+			}
+			klog.V(4).Infof("Unable to read registry info from image streams: %v", err)
+		} else {
+			if len(hostnames.InternalHostname) == 0 {
+				hostnames.InternalHostname = info.Internal
+			}
+			if !routeFound {
+				hostnames.PublicHostname = info.Public
+			}
+		}
+	}
+
+	switch {
+	case len(hostnames.PublicHostname) == 0:
+		hostnames.Note = fmt.Sprintf("the registry is not exposed by a public route, set spec.defaultRoute in the image registry operator configuration to create %s/%s", registryNamespace, registryRouteName)
+	case !routeFound:
+		hostnames.Note = fmt.Sprintf("the public hostname was read from an image stream because the route %s/%s could not be read, tls is unknown", registryNamespace, registryRouteName)
+	}
+	return hostnames, nil
+}
+
 func (o *Options) Validate() error {
 	if o.ShowInternal && o.ShowPublic {
 		return fmt.Errorf("only one of --internal or --public may be specified at a time")
 	}
+	switch o.Output {
+	case "":
+	case "json":
+		if o.ShowInternal || o.ShowPublic || o.Check {
+			return fmt.Errorf("--output=json cannot be combined with --internal, --public or --check")
+		}
+	default:
+		return fmt.Errorf("--output must be 'json'")
+	}
 	return nil
 }
 
 func (o *Options) Run() error {
+	if o.Output == "json" {
+		config, err := o.ConfigClient.ConfigV1().Images().Get(context.TODO(), "cluster", metav1.GetOptions{})
+		if err != nil {
+			klog.V(4).Infof("Unable to read the cluster image configuration: %v", err)
+			config = nil
+		}
+		hostnames, err := findRegistryHostnames(config, o.RouteClient, o.Client, o.Namespaces...)
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(hostnames, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s\n", data)
+		return nil
+	}
+
 	info, err := findRegistryInfo(o.Client, o.Namespaces...)
 	if err != nil {
 		return err
@@ -0,0 +1,81 @@
+package info
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1 "github.com/openshift/api/config/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	imagefake "github.com/openshift/client-go/image/clientset/versioned/fake"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+)
+
+func TestFindRegistryHostnames(t *testing.T) {
+	config := &configv1.Image{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status:     configv1.ImageStatus{InternalRegistryHostname: "image-registry.openshift-image-registry.svc:5000"},
+	}
+	route := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: registryRouteName, Namespace: registryNamespace},
+		Spec: routev1.RouteSpec{
+			Host: "default-route-openshift-image-registry.apps.example.com",
+			TLS:  &routev1.TLSConfig{Termination: routev1.TLSTerminationReencrypt},
+		},
+	}
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{Name: "ruby", Namespace: "test"},
+		Status: imagev1.ImageStreamStatus{
+			DockerImageRepository:       "172.30.0.10:5000/test/ruby",
+			PublicDockerImageRepository: "registry.example.com/test/ruby",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		config   *configv1.Image
+		routes   []runtime.Object
+		expected registryHostnames
+		note     string
+	}{
+		{
+			name:   "exposed",
+			config: config,
+			routes: []runtime.Object{route},
+			expected: registryHostnames{
+				InternalHostname: "image-registry.openshift-image-registry.svc:5000",
+				PublicHostname:   "default-route-openshift-image-registry.apps.example.com",
+				TLS:              true,
+			},
+		},
+		{
+			name:     "not exposed",
+			config:   config,
+			expected: registryHostnames{InternalHostname: "image-registry.openshift-image-registry.svc:5000"},
+			note:     "not exposed by a public route",
+		},
+		{
+			name:     "no access to configuration",
+			routes:   []runtime.Object{route},
+			expected: registryHostnames{InternalHostname: "172.30.0.10:5000", PublicHostname: "default-route-openshift-image-registry.apps.example.com", TLS: true},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hostnames, err := findRegistryHostnames(tc.config, routefake.NewSimpleClientset(tc.routes...), imagefake.NewSimpleClientset(imageStream), "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(hostnames.Note, tc.note) || (len(tc.note) == 0 && len(hostnames.Note) > 0) {
+				t.Errorf("unexpected note: %q", hostnames.Note)
+			}
+			hostnames.Note = ""
+			if *hostnames != tc.expected {
+				t.Errorf("expected %#v, got %#v", tc.expected, *hostnames)
+			}
+		})
+	}
+}
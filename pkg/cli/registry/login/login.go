@@ -71,6 +71,9 @@ var (
 
 		# Log in to different registry using BASIC auth credentials
 		oc registry login --registry quay.io/myregistry --auth-basic=USER:PASS
+
+		# Log in to the integrated registry and save the credentials to a separate auth file
+		oc registry login --to=./auth.json
 	`)
 )
 
@@ -331,6 +334,8 @@ func (o *LoginOptions) Run() error {
 			return err
 		}
 		fmt.Fprintln(o.Out, string(bytes))
+	} else if o.ConfigFile != "" {
+		fmt.Fprintf(o.Out, "Saved credentials for %s into %s\n", o.HostPort, o.ConfigFile)
 	} else {
 		fmt.Fprintf(o.Out, "Saved credentials for %s\n", o.HostPort)
 	}
@@ -0,0 +1,66 @@
+package login
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestRunMergesIntoConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "registry-login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	existing := filepath.Join(dir, "existing.json")
+	if err := ioutil.WriteFile(existing, []byte(`{"auths":{"quay.io":{"auth":"b3RoZXI6c2VjcmV0"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		configFile string
+		expected   []string
+	}{
+		{name: "new file", configFile: filepath.Join(dir, "nested", "auth.json"), expected: []string{"registry.example.com:5000"}},
+		{name: "existing file", configFile: existing, expected: []string{"registry.example.com:5000", "quay.io"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := NewRegistryLoginOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.ConfigFile = tc.configFile
+			o.HostPort = "registry.example.com:5000"
+			o.Credentials = newCredentials("user", "token")
+			o.SkipCheck = true
+			if err := o.Run(); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := ioutil.ReadFile(tc.configFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config := struct {
+				Auths map[string]Credentials `json:"auths"`
+			}{}
+			if err := json.Unmarshal(data, &config); err != nil {
+				t.Fatal(err)
+			}
+			if len(config.Auths) != len(tc.expected) {
+				t.Errorf("unexpected auths: %s", data)
+			}
+			for _, registry := range tc.expected {
+				if _, ok := config.Auths[registry]; !ok {
+					t.Errorf("missing auth for %s: %s", registry, data)
+				}
+			}
+			if auth := config.Auths["registry.example.com:5000"].Auth; string(auth) != "user:token" {
+				t.Errorf("unexpected auth: %q", auth)
+			}
+		})
+	}
+}
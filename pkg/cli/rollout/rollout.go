@@ -145,41 +145,36 @@ var (
     If you would like to review the outcome of the rollback, pass '--dry-run' to print
     a human-readable representation of the updated deployment configuration instead of
     executing the rollback. This is useful if you're not quite sure what the outcome
-    will be.`)
+    will be.
+
+    Use '--to-label' to roll back to the revision whose pod template carries a label,
+    such as a release version, instead of looking up its revision number. Exactly one
+    revision in the history must match.`)
 
 	rolloutUndoExample = templates.Examples(`
     # Roll back to the previous deployment
     oc rollout undo dc/nginx
 
     # Roll back to deployment revision 3. The replication controller for that version must exist
-    oc rollout undo dc/nginx --to-revision=3`)
-)
+    oc rollout undo dc/nginx --to-revision=3
 
-// NewCmdRolloutUndo is a wrapper for the Kubernetes cli rollout undo command
-func NewCmdRolloutUndo(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	cmd := rollout.NewCmdRolloutUndo(f, streams)
-	cmd.Long = rolloutUndoLong
-	cmd.Example = rolloutUndoExample
-	validArgs := []string{"deployment", "replicaset", "replicationcontroller", "statefulset", "deploymentconfig"}
-	cmd.ValidArgsFunction = completion.SpecifiedResourceTypeAndNameCompletionFunc(f, validArgs)
-	return cmd
-}
+    # Roll back to the revision whose pod template is labeled release=1.4.2
+    oc rollout undo deployment/nginx --to-label=release=1.4.2`)
+)
 
 var (
 	rolloutStatusLong = templates.LongDesc(`
-		Watch the status of the latest rollout, until it's done.`)
+		Watch the status of the latest rollout, until it's done.
+
+		Pass '--output=json' to print one JSON object per status transition, with the replica
+		counts and conditions of the resource, followed by a final record whose phase is either
+		Complete or Failed. The command exits with a non-zero status if the rollout fails, for
+		example when its progress deadline is exceeded.`)
 
 	rolloutStatusExample = templates.Examples(`
 		# Watch the status of the latest rollout
-		oc rollout status dc/nginx`)
-)
+		oc rollout status dc/nginx
 
-// NewCmdRolloutStatus is a wrapper for the Kubernetes cli rollout status command
-func NewCmdRolloutStatus(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	cmd := rollout.NewCmdRolloutStatus(f, streams)
-	cmd.Long = rolloutStatusLong
-	cmd.Example = rolloutStatusExample
-	validArgs := []string{"deployment", "replicaset", "replicationcontroller", "statefulset", "deploymentconfig"}
-	cmd.ValidArgsFunction = completion.SpecifiedResourceTypeAndNameCompletionFunc(f, validArgs)
-	return cmd
-}
+		# Watch the rollout of a deployment, printing each status transition as JSON
+		oc rollout status deployment/nginx -o json`)
+)
@@ -0,0 +1,271 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+	"k8s.io/kubectl/pkg/cmd/rollout"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/kubectl/pkg/util/completion"
+	"k8s.io/kubectl/pkg/util/interrupt"
+)
+
+const (
+	rolloutPhaseProgressing = "Progressing"
+	rolloutPhaseComplete    = "Complete"
+	rolloutPhaseFailed      = "Failed"
+)
+
+// StatusOptions extends the upstream rollout status options with a structured output
+// that reports each status transition as a JSON object.
+type StatusOptions struct {
+	*rollout.RolloutStatusOptions
+
+	Output string
+}
+
+func NewRolloutStatusOptions(streams genericclioptions.IOStreams) *StatusOptions {
+	return &StatusOptions{
+		RolloutStatusOptions: rollout.NewRolloutStatusOptions(streams),
+	}
+}
+
+// NewCmdRolloutStatus is a wrapper for the Kubernetes cli rollout status command
+func NewCmdRolloutStatus(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewRolloutStatusOptions(streams)
+
+	validArgs := []string{"deployment", "replicaset", "replicationcontroller", "statefulset", "deploymentconfig"}
+	cmd := &cobra.Command{
+		Use:                   "status (TYPE NAME | TYPE/NAME) [flags]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Show the status of the rollout",
+		Long:                  rolloutStatusLong,
+		Example:               rolloutStatusExample,
+		ValidArgsFunction:     completion.SpecifiedResourceTypeAndNameCompletionFunc(f, validArgs),
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Complete(f, args))
+			kcmdutil.CheckErr(o.Validate())
+			kcmdutil.CheckErr(o.Run())
+		},
+	}
+
+	usage := "identifying the resource to get from a server."
+	kcmdutil.AddFilenameOptionFlags(cmd, o.FilenameOptions, usage)
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "Watch the status of the rollout until it's done.")
+	cmd.Flags().Int64Var(&o.Revision, "revision", o.Revision, "Pin to a specific revision for showing its status. Defaults to 0 (last revision).")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "The length of time to wait before ending watch, zero means never. Any other values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output format. One of: json. With json, one object is printed per status transition, followed by a final Complete or Failed record.")
+	kcmdutil.AddLabelSelectorFlagVar(cmd, &o.LabelSelector)
+
+	return cmd
+}
+
+func (o *StatusOptions) Validate() error {
+	if err := o.RolloutStatusOptions.Validate(); err != nil {
+		return err
+	}
+	switch o.Output {
+	case "", "json":
+	default:
+		return fmt.Errorf("--output must be 'json'")
+	}
+	return nil
+}
+
+func (o *StatusOptions) Run() error {
+	if len(o.Output) == 0 {
+		return o.RolloutStatusOptions.Run()
+	}
+
+	r := o.Builder().
+		WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		LabelSelectorParam(o.LabelSelector).
+		FilenameParam(o.EnforceNamespace, o.FilenameOptions).
+		ResourceTypeOrNameArgs(true, o.BuilderArgs...).
+		SingleResourceType().
+		Latest().
+		Do()
+	if err := r.Err(); err != nil {
+		return err
+	}
+	infos, err := r.Infos()
+	if err != nil {
+		return err
+	}
+	if len(infos) != 1 {
+		return fmt.Errorf("rollout status is only supported on individual resources and resource collections - %d resources were found", len(infos))
+	}
+	info := infos[0]
+
+	statusViewer, err := o.StatusViewerFn(info.ResourceMapping())
+	if err != nil {
+		return err
+	}
+
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", info.Name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return o.DynamicClient.Resource(info.Mapping.Resource).Namespace(info.Namespace).Watch(context.TODO(), options)
+		},
+	}
+
+	printer := &rolloutEventPrinter{out: o.Out}
+	ctx, cancel := watchtools.ContextWithOptionalTimeout(context.Background(), o.Timeout)
+	intr := interrupt.New(nil, cancel)
+	return intr.Run(func() error {
+		_, err := watchtools.UntilWithSync(ctx, lw, &unstructured.Unstructured{}, nil, func(e watch.Event) (bool, error) {
+			switch e.Type {
+			case watch.Added, watch.Modified:
+				obj := e.Object.(*unstructured.Unstructured)
+				message, done, statusErr := statusViewer.Status(obj, o.Revision)
+				if err := printer.print(newRolloutEvent(obj, message, done, statusErr)); err != nil {
+					return false, err
+				}
+				if statusErr != nil {
+					return false, statusErr
+				}
+				return done || !o.Watch, nil
+
+			case watch.Deleted:
+				err := fmt.Errorf("object has been deleted")
+				printer.print(&rolloutEvent{Kind: info.Mapping.GroupVersionKind.Kind, Name: info.Name, Namespace: info.Namespace, Phase: rolloutPhaseFailed, Message: err.Error()})
+				return true, err
+
+			default:
+				return true, fmt.Errorf("internal error: unexpected event %#v", e)
+			}
+		})
+		return err
+	})
+}
+
+// rolloutEvent is a single status transition of a rollout, printed with --output=json.
+type rolloutEvent struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+
+	Phase   string `json:"phase"`
+	Message string `json:"message,omitempty"`
+
+	ObservedGeneration  int64 `json:"observedGeneration,omitempty"`
+	Replicas            int64 `json:"replicas"`
+	UpdatedReplicas     int64 `json:"updatedReplicas"`
+	AvailableReplicas   int64 `json:"availableReplicas"`
+	UnavailableReplicas int64 `json:"unavailableReplicas"`
+
+	Conditions []rolloutCondition `json:"conditions,omitempty"`
+}
+
+type rolloutCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// newRolloutEvent describes obj and the result of its status viewer. The replica counts are
+// read from the fields used by deployments, deployment configs and stateful sets, falling
+// back to the daemon set equivalents.
+func newRolloutEvent(obj *unstructured.Unstructured, message string, done bool, statusErr error) *rolloutEvent {
+	content := obj.UnstructuredContent()
+	event := &rolloutEvent{
+		Kind:      obj.GetKind(),
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Phase:     rolloutPhaseProgressing,
+		Message:   strings.TrimSpace(message),
+
+		ObservedGeneration:  nestedInt64(content, "status", "observedGeneration"),
+		Replicas:            nestedInt64(content, "spec", "replicas"),
+		UpdatedReplicas:     nestedInt64(content, "status", "updatedReplicas"),
+		AvailableReplicas:   nestedInt64(content, "status", "availableReplicas"),
+		UnavailableReplicas: nestedInt64(content, "status", "unavailableReplicas"),
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(content, "status", "desiredNumberScheduled"); found {
+		event.Replicas = nestedInt64(content, "status", "desiredNumberScheduled")
+		event.UpdatedReplicas = nestedInt64(content, "status", "updatedNumberScheduled")
+		event.AvailableReplicas = nestedInt64(content, "status", "numberAvailable")
+		event.UnavailableReplicas = nestedInt64(content, "status", "numberUnavailable")
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(content, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		event.Conditions = append(event.Conditions, rolloutCondition{
+			Type:    nestedString(condition, "type"),
+			Status:  nestedString(condition, "status"),
+			Reason:  nestedString(condition, "reason"),
+			Message: nestedString(condition, "message"),
+		})
+	}
+
+	switch {
+	case statusErr != nil:
+		event.Phase = rolloutPhaseFailed
+		event.Message = statusErr.Error()
+	case done:
+		event.Phase = rolloutPhaseComplete
+	}
+	return event
+}
+
+func nestedInt64(obj map[string]interface{}, fields ...string) int64 {
+	v, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	switch t := v.(type) {
+	case int64:
+		return t
+	case float64:
+		return int64(t)
+	}
+	return 0
+}
+
+func nestedString(obj map[string]interface{}, fields ...string) string {
+	s, _, _ := unstructured.NestedString(obj, fields...)
+	return s
+}
+
+// rolloutEventPrinter prints each rollout event as a single line of JSON, skipping events
+// that are identical to the last one printed.
+type rolloutEventPrinter struct {
+	out  io.Writer
+	last *rolloutEvent
+}
+
+func (p *rolloutEventPrinter) print(event *rolloutEvent) error {
+	if p.last != nil && reflect.DeepEqual(p.last, event) {
+		return nil
+	}
+	p.last = event
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(p.out, "%s\n", data)
+	return err
+}
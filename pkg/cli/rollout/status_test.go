@@ -0,0 +1,79 @@
+package rollout
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRolloutEvents(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "web", "namespace": "test"},
+		"spec":       map[string]interface{}{"replicas": int64(3)},
+		"status": map[string]interface{}{
+			"observedGeneration":  int64(2),
+			"updatedReplicas":     int64(1),
+			"availableReplicas":   int64(2),
+			"unavailableReplicas": int64(1),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "True", "reason": "ReplicaSetUpdated"},
+			},
+		},
+	}}
+	daemonSet := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "DaemonSet",
+		"metadata":   map[string]interface{}{"name": "agent"},
+		"status": map[string]interface{}{
+			"desiredNumberScheduled": int64(4),
+			"updatedNumberScheduled": int64(4),
+			"numberAvailable":        int64(4),
+		},
+	}}
+
+	out := &bytes.Buffer{}
+	printer := &rolloutEventPrinter{out: out}
+	events := []*rolloutEvent{
+		newRolloutEvent(deployment, "Waiting for rollout to finish...\n", false, nil),
+		newRolloutEvent(deployment, "Waiting for rollout to finish...\n", false, nil),
+		newRolloutEvent(deployment, "", false, errors.New(`deployment "web" exceeded its progress deadline`)),
+		newRolloutEvent(daemonSet, "daemon set \"agent\" successfully rolled out\n", true, nil),
+	}
+	for _, event := range events {
+		if err := printer.print(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected repeated events to be skipped, got:\n%s", out.String())
+	}
+	var printed []rolloutEvent
+	for _, line := range lines {
+		var event rolloutEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("unable to decode %q: %v", line, err)
+		}
+		printed = append(printed, event)
+	}
+
+	if e := printed[0]; e.Phase != rolloutPhaseProgressing || e.Replicas != 3 || e.UpdatedReplicas != 1 || e.AvailableReplicas != 2 || e.UnavailableReplicas != 1 || e.ObservedGeneration != 2 || e.Message != "Waiting for rollout to finish..." {
+		t.Errorf("unexpected progressing event: %#v", e)
+	}
+	if e := printed[0]; len(e.Conditions) != 1 || e.Conditions[0].Reason != "ReplicaSetUpdated" {
+		t.Errorf("unexpected conditions: %#v", e.Conditions)
+	}
+	if e := printed[1]; e.Phase != rolloutPhaseFailed || !strings.Contains(e.Message, "progress deadline") {
+		t.Errorf("unexpected failed event: %#v", e)
+	}
+	if e := printed[2]; e.Phase != rolloutPhaseComplete || e.Kind != "DaemonSet" || e.Replicas != 4 || e.AvailableReplicas != 4 {
+		t.Errorf("unexpected complete event: %#v", e)
+	}
+}
@@ -0,0 +1,247 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	kappsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kubectl/pkg/cmd/rollout"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/kubectl/pkg/util/completion"
+	deploymentutil "k8s.io/kubectl/pkg/util/deployment"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
+)
+
+// UndoOptions extends the upstream rollout undo options with the ability to select
+// the revision to roll back to by a label on its pod template.
+type UndoOptions struct {
+	*rollout.UndoOptions
+
+	ToLabel string
+
+	KubeClient kubernetes.Interface
+
+	toLabelSelector labels.Selector
+}
+
+func NewRolloutUndoOptions(streams genericclioptions.IOStreams) *UndoOptions {
+	return &UndoOptions{
+		UndoOptions: rollout.NewRolloutUndoOptions(streams),
+	}
+}
+
+// NewCmdRolloutUndo is a wrapper for the Kubernetes cli rollout undo command
+func NewCmdRolloutUndo(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := NewRolloutUndoOptions(streams)
+
+	validArgs := []string{"deployment", "replicaset", "replicationcontroller", "statefulset", "deploymentconfig"}
+	cmd := &cobra.Command{
+		Use:                   "undo (TYPE NAME | TYPE/NAME) [flags]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Undo a previous rollout",
+		Long:                  rolloutUndoLong,
+		Example:               rolloutUndoExample,
+		ValidArgsFunction:     completion.SpecifiedResourceTypeAndNameCompletionFunc(f, validArgs),
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Validate())
+			kcmdutil.CheckErr(o.Run())
+		},
+	}
+
+	cmd.Flags().Int64Var(&o.ToRevision, "to-revision", o.ToRevision, "The revision to rollback to. Default to 0 (last revision).")
+	cmd.Flags().StringVar(&o.ToLabel, "to-label", o.ToLabel, "Roll back to the revision whose pod template matches this label selector, for example release=1.4.2. Exactly one revision must match.")
+	usage := "identifying the resource to get from a server."
+	kcmdutil.AddFilenameOptionFlags(cmd, &o.FilenameOptions, usage)
+	kcmdutil.AddDryRunFlag(cmd)
+	kcmdutil.AddLabelSelectorFlagVar(cmd, &o.LabelSelector)
+	o.PrintFlags.AddFlags(cmd)
+	return cmd
+}
+
+func (o *UndoOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if err := o.UndoOptions.Complete(f, cmd, args); err != nil {
+		return err
+	}
+	if len(o.ToLabel) == 0 {
+		return nil
+	}
+
+	var err error
+	o.toLabelSelector, err = labels.Parse(o.ToLabel)
+	if err != nil {
+		return fmt.Errorf("invalid --to-label: %v", err)
+	}
+	o.KubeClient, err = f.KubernetesClientSet()
+	return err
+}
+
+func (o *UndoOptions) Validate() error {
+	if err := o.UndoOptions.Validate(); err != nil {
+		return err
+	}
+	if len(o.ToLabel) > 0 && o.ToRevision != 0 {
+		return fmt.Errorf("--to-revision and --to-label cannot be used together")
+	}
+	return nil
+}
+
+func (o *UndoOptions) Run() error {
+	if len(o.ToLabel) == 0 {
+		return o.RunUndo()
+	}
+
+	r := o.Builder().
+		WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
+		NamespaceParam(o.Namespace).DefaultNamespace().
+		LabelSelectorParam(o.LabelSelector).
+		FilenameParam(o.EnforceNamespace, &o.FilenameOptions).
+		ResourceTypeOrNameArgs(true, o.Resources...).
+		ContinueOnError().
+		Latest().
+		Flatten().
+		Do()
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	return r.Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			return err
+		}
+		history, err := templateRevisionsFor(o.KubeClient, info.Object)
+		if err != nil {
+			return err
+		}
+		revision, err := revisionForSelector(history, o.toLabelSelector)
+		if err != nil {
+			return fmt.Errorf("%s/%s: %v", info.Mapping.Resource.Resource, info.Name, err)
+		}
+
+		rollbacker, err := polymorphichelpers.RollbackerFn(o.RESTClientGetter, info.ResourceMapping())
+		if err != nil {
+			return err
+		}
+		if o.DryRunStrategy == kcmdutil.DryRunServer {
+			if err := o.DryRunVerifier.HasSupport(info.Mapping.GroupVersionKind); err != nil {
+				return err
+			}
+		}
+		result, err := rollbacker.Rollback(info.Object, nil, revision, o.DryRunStrategy)
+		if err != nil {
+			return err
+		}
+
+		printer, err := o.ToPrinter(result)
+		if err != nil {
+			return err
+		}
+		return printer.PrintObj(info.Object, o.Out)
+	})
+}
+
+// templateRevision is a single revision in the rollout history of a deployment or
+// deployment config, along with the labels on its pod template.
+type templateRevision struct {
+	Revision int64
+	Name     string
+	Labels   map[string]string
+}
+
+// templateRevisionsFor returns the rollout history of obj, read from the replica sets of a
+// deployment or the replication controllers of a deployment config, ordered by revision.
+func templateRevisionsFor(client kubernetes.Interface, obj runtime.Object) ([]templateRevision, error) {
+	var history []templateRevision
+	switch t := obj.(type) {
+	case *kappsv1.Deployment:
+		_, replicaSets, newRS, err := deploymentutil.GetAllReplicaSets(t, client.AppsV1())
+		if err != nil {
+			return nil, err
+		}
+		if newRS != nil {
+			replicaSets = append(replicaSets, newRS)
+		}
+		for _, rs := range replicaSets {
+			revision, err := deploymentutil.Revision(rs)
+			if err != nil || revision <= 0 {
+				continue
+			}
+			history = append(history, templateRevision{Revision: revision, Name: rs.Name, Labels: rs.Spec.Template.Labels})
+		}
+	case *appsv1.DeploymentConfig:
+		rcs, err := client.CoreV1().ReplicationControllers(t.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: appsutil.ConfigSelector(t.Name).String()})
+		if err != nil {
+			return nil, err
+		}
+		for i := range rcs.Items {
+			rc := &rcs.Items[i]
+			revision := appsutil.DeploymentVersionFor(rc)
+			if revision <= 0 || rc.Spec.Template == nil {
+				continue
+			}
+			history = append(history, templateRevision{Revision: revision, Name: rc.Name, Labels: rc.Spec.Template.Labels})
+		}
+	default:
+		return nil, fmt.Errorf("--to-label is only supported for deployments and deployment configs")
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Revision < history[j].Revision })
+	return history, nil
+}
+
+// revisionForSelector returns the only revision in history whose pod template labels match
+// selector, or an error listing the candidates if there is not exactly one.
+func revisionForSelector(history []templateRevision, selector labels.Selector) (int64, error) {
+	var matches []templateRevision
+	for _, r := range history {
+		if selector.Matches(labels.Set(r.Labels)) {
+			matches = append(matches, r)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0].Revision, nil
+	case 0:
+		if len(history) == 0 {
+			return 0, fmt.Errorf("no revisions were found in the rollout history")
+		}
+		return 0, fmt.Errorf("no revision matches %q, found: %s", selector, describeRevisions(history, selector))
+	default:
+		return 0, fmt.Errorf("%d revisions match %q, use a more specific label or --to-revision: %s", len(matches), selector, describeRevisions(matches, selector))
+	}
+}
+
+// describeRevisions lists each revision with the values of the labels the selector refers to.
+func describeRevisions(history []templateRevision, selector labels.Selector) string {
+	var keys []string
+	if requirements, selectable := selector.Requirements(); selectable {
+		for _, r := range requirements {
+			keys = append(keys, r.Key())
+		}
+	}
+
+	var descriptions []string
+	for _, r := range history {
+		details := []string{r.Name}
+		for _, key := range keys {
+			if value, ok := r.Labels[key]; ok {
+				details = append(details, fmt.Sprintf("%s=%s", key, value))
+			}
+		}
+		descriptions = append(descriptions, fmt.Sprintf("revision %d (%s)", r.Revision, strings.Join(details, ", ")))
+	}
+	return strings.Join(descriptions, "; ")
+}
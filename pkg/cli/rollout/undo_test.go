@@ -0,0 +1,77 @@
+package rollout
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+)
+
+func deploymentConfigRC(name, version, release string) *corev1.ReplicationController {
+	return &corev1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "test",
+			Labels:      map[string]string{appsv1.DeploymentConfigAnnotation: "web"},
+			Annotations: map[string]string{appsv1.DeploymentVersionAnnotation: version},
+		},
+		Spec: corev1.ReplicationControllerSpec{
+			Template: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web", "release": release}},
+			},
+		},
+	}
+}
+
+func TestRevisionForLabel(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		deploymentConfigRC("web-3", "3", "1.4.2"),
+		deploymentConfigRC("web-1", "1", "1.4.0"),
+		deploymentConfigRC("web-2", "2", "1.4.1"),
+		deploymentConfigRC("web-4", "4", "1.4.2-hotfix"),
+	)
+	config := &appsv1.DeploymentConfig{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"}}
+
+	history, err := templateRevisionsFor(client, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 4 || history[0].Revision != 1 || history[3].Revision != 4 {
+		t.Fatalf("unexpected history: %#v", history)
+	}
+
+	tests := []struct {
+		selector string
+		revision int64
+		err      string
+	}{
+		{selector: "release=1.4.1", revision: 2},
+		{selector: "release in (1.4.2, 1.4.2-hotfix)", err: "2 revisions match"},
+		{selector: "release=1.5.0", err: "revision 1 (web-1, release=1.4.0)"},
+	}
+	for _, tc := range tests {
+		selector, err := labels.Parse(tc.selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		revision, err := revisionForSelector(history, selector)
+		if len(tc.err) > 0 {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s: expected error containing %q, got %v", tc.selector, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.selector, err)
+			continue
+		}
+		if revision != tc.revision {
+			t.Errorf("%s: expected revision %d, got %d", tc.selector, tc.revision, revision)
+		}
+	}
+}
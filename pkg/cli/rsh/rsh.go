@@ -18,6 +18,10 @@ import (
 const (
 	DefaultShell         = "/bin/sh"
 	defaultPodRshTimeout = 60 * time.Second
+
+	// workdirScript changes to the directory passed as its first argument and replaces the shell
+	// with the remaining arguments. If the directory does not exist the shell reports it and exits.
+	workdirScript = `cd -- "$1" || exit; shift; exec "$@"`
 )
 
 var (
@@ -34,11 +38,16 @@ var (
 		It will default to the first container if none is specified, and will attempt to use
 		'/bin/sh' as the default shell. You may pass any flags supported by this command before
 		the resource name, and an optional command after the resource name, which will be executed
-		instead of a login shell. A TTY will be automatically allocated if standard input is
-		interactive - use -t and -T to override. A TERM variable is sent to the environment where
+		instead of a login shell. A TTY will be automatically allocated if both standard input and
+		standard output are terminals, so the output of a command piped to another program is not
+		altered - use -t and -T to override. A TERM variable is sent to the environment where
 		the shell (or command) will be executed. By default its value is the same as the TERM
 		variable from the local environment; if not set, 'xterm' is used.
 
+		Pass --workdir to start the shell or command in another directory than the working
+		directory of the container. If the directory does not exist, the error of the shell is
+		printed and the command fails.
+
 		Note, some containers may not include a shell - use 'oc exec' if you need to run commands
 		directly.`)
 
@@ -53,11 +62,17 @@ var (
 		# Run the command 'cat /etc/resolv.conf' inside pod 'foo'
 		oc rsh foo cat /etc/resolv.conf
 
+		# Compress a file of pod 'foo' locally, no TTY is allocated when the output is piped
+		oc rsh foo cat /var/log/app.log | gzip > app.log.gz
+
 		# See the configuration of your internal registry
 		oc rsh dc/docker-registry cat config.yml
 
 		# Open a shell session on the container named 'index' inside a pod of your job
 		oc rsh -c index job/sheduled
+
+		# Open a shell session in the /var/lib/data directory of pod 'foo'
+		oc rsh --workdir=/var/lib/data foo
 	`)
 )
 
@@ -66,6 +81,7 @@ type RshOptions struct {
 	ForceTTY   bool
 	DisableTTY bool
 	Executable string
+	Workdir    string
 	*exec.ExecOptions
 }
 
@@ -107,7 +123,9 @@ func NewCmdRsh(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.C
 	cmd.Flags().BoolVarP(&o.ForceTTY, "tty", "t", o.ForceTTY, "Force a pseudo-terminal to be allocated")
 	cmd.Flags().BoolVarP(&o.DisableTTY, "no-tty", "T", o.DisableTTY, "Disable pseudo-terminal allocation")
 	cmd.Flags().StringVar(&o.Executable, "shell", o.Executable, "Path to the shell command")
-	cmd.Flags().StringVarP(&o.ContainerName, "container", "c", o.ContainerName, "Container name; defaults to first container")
+	cmd.Flags().StringVar(&o.Workdir, "workdir", o.Workdir, "The directory in the container to run the shell or command in, instead of its working directory")
+	kcmdutil.AddContainerVarFlags(cmd, &o.ContainerName, o.ContainerName)
+	kcmdutil.CheckErr(cmd.RegisterFlagCompletionFunc("container", completion.ContainerCompletionFunc(f)))
 	// For consistencty with rsh API (https://linux.die.net/man/1/rsh) we don't
 	// allow '--' and we need this flag enabled explicitly, otherwise two things
 	// will break:
@@ -134,16 +152,10 @@ func (o *RshOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []str
 		return kcmdutil.UsageErrorf(cmd, "%s", rshUsageErrStr)
 	}
 
-	switch {
-	case o.ForceTTY && o.DisableTTY:
+	if o.ForceTTY && o.DisableTTY {
 		return kcmdutil.UsageErrorf(cmd, "you may not specify -t and -T together")
-	case o.ForceTTY:
-		o.TTY = true
-	case o.DisableTTY:
-		o.TTY = false
-	default:
-		o.TTY = term.IsTerminal(o.In)
 	}
+	o.TTY = o.allocateTTY()
 
 	// Value of argsLenAtDash is -1 since cmd.ArgsLenAtDash() assumes all the flags
 	// of flag.FlagSet were parsed. The opposite is true. Thus, it needs to be computed manually.
@@ -172,6 +184,20 @@ func (o *RshOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []str
 	return nil
 }
 
+// allocateTTY returns whether a TTY should be allocated. Unless -t or -T is given, one is only
+// allocated when both standard input and output are terminals, since a TTY translates line
+// endings and would corrupt output that is redirected or piped to another program.
+func (o *RshOptions) allocateTTY() bool {
+	switch {
+	case o.ForceTTY:
+		return true
+	case o.DisableTTY:
+		return false
+	default:
+		return term.IsTerminal(o.In) && term.IsTerminal(o.Out)
+	}
+}
+
 // Validate ensures that RshOptions are valid
 func (o *RshOptions) Validate() error {
 	return o.ExecOptions.Validate()
@@ -188,5 +214,13 @@ func (o *RshOptions) Run() error {
 		termsh := fmt.Sprintf("TERM=%q %s", term, DefaultShell)
 		o.Command = append(o.Command, "-c", termsh)
 	}
+	if len(o.Workdir) > 0 {
+		o.Command = WorkdirCommand(o.Executable, o.Workdir, o.Command)
+	}
 	return o.ExecOptions.Run()
 }
+
+// WorkdirCommand returns command wrapped to be run by shell in the directory dir.
+func WorkdirCommand(shell, dir string, command []string) []string {
+	return append([]string{shell, "-c", workdirScript, shell, dir}, command...)
+}
@@ -0,0 +1,74 @@
+package rsh
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestAllocateTTY(t *testing.T) {
+	tests := []struct {
+		name       string
+		forceTTY   bool
+		disableTTY bool
+		expected   bool
+	}{
+		{name: "not a terminal"},
+		{name: "forced", forceTTY: true, expected: true},
+		{name: "disabled", disableTTY: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			streams, _, _, _ := genericclioptions.NewTestIOStreams()
+			o := NewRshOptions(streams)
+			o.ForceTTY, o.DisableTTY = test.forceTTY, test.disableTTY
+			if got := o.allocateTTY(); got != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestWorkdirCommand(t *testing.T) {
+	got := WorkdirCommand("/bin/bash", "/var/lib/data", []string{"/bin/sh", "-c", `TERM="xterm" /bin/sh`})
+	expected := []string{"/bin/bash", "-c", `cd -- "$1" || exit; shift; exec "$@"`, "/bin/bash", "/var/lib/data", "/bin/sh", "-c", `TERM="xterm" /bin/sh`}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	// the script must run the command in the directory and fail for a missing one
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	dir, err := ioutil.TempDir("", "workdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	command := WorkdirCommand("sh", dir, []string{"pwd"})
+	out, err := exec.Command(command[0], command[1:]...).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out))); actual != mustEvalSymlinks(t, dir) {
+		t.Errorf("expected the command to run in %s, got %s", dir, out)
+	}
+	command = WorkdirCommand("sh", filepath.Join(dir, "missing"), []string{"pwd"})
+	if out, err := exec.Command(command[0], command[1:]...).CombinedOutput(); err == nil || !strings.Contains(string(out), "missing") {
+		t.Errorf("expected the missing directory to be reported, got %v: %s", err, out)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
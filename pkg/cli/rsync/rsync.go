@@ -4,6 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,6 +15,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -26,6 +31,10 @@ const (
 
 	noRsyncUnixWarning    = "WARNING: rsync command not found in path. Please use your package manager to install it.\n"
 	noRsyncWindowsWarning = "WARNING: rsync command not found in path. Download cwRsync for Windows and add it to your PATH.\n"
+
+	// maxIncrementalPaths is the number of changed paths above which watch mode
+	// synchronizes the whole source directory instead of the individual paths.
+	maxIncrementalPaths = 20
 )
 
 var (
@@ -43,6 +52,13 @@ var (
 
 		The following flags are passed to rsync by default:
 		--archive --no-owner --no-group --omit-dir-times --numeric-ids
+
+		With --watch, the local source directory is watched for changes after the
+		initial copy. Changes are synchronized once no further change happened for
+		--watch-interval, and only the files and directories that changed are copied.
+		With --delete, files removed locally are also removed from the pod. When
+		--include or --exclude is given, or when many paths change at once, the whole
+		directory is synchronized again instead.
 	`)
 
 	rsyncExample = templates.Examples(`
@@ -51,6 +67,9 @@ var (
 
 		# Synchronize a pod directory with a local directory
 		oc rsync POD:/remote/dir/ ./local/dir
+
+		# Keep a pod directory in sync with a local directory, waiting for 5 seconds without changes between syncs
+		oc rsync ./local/dir/ POD:/remote/dir --watch --watch-interval=5s
 	`)
 
 	rsyncDefaultFlags = []string{"--archive", "--no-owner", "--no-group", "--omit-dir-times", "--numeric-ids"}
@@ -89,6 +108,7 @@ type RsyncOptions struct {
 	Quiet                   bool
 	Delete                  bool
 	Watch                   bool
+	WatchInterval           time.Duration
 	Compress                bool
 	EnableSuggestedCmdUsage bool
 
@@ -105,7 +125,8 @@ type RsyncOptions struct {
 
 func NewRsyncOptions(streams genericclioptions.IOStreams) *RsyncOptions {
 	return &RsyncOptions{
-		IOStreams: streams,
+		WatchInterval: 2 * time.Second,
+		IOStreams:     streams,
 	}
 }
 
@@ -136,6 +157,7 @@ func NewCmdRsync(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 	cmd.Flags().BoolVar(&o.RsyncProgress, "progress", false, "If true, show progress during transfer")
 	cmd.Flags().BoolVar(&o.RsyncNoPerms, "no-perms", false, "If true, do not transfer permissions")
 	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", false, "Watch directory for changes and resync automatically")
+	cmd.Flags().DurationVar(&o.WatchInterval, "watch-interval", o.WatchInterval, "With --watch, the time without further changes to wait for before synchronizing them")
 	cmd.Flags().BoolVar(&o.Compress, "compress", false, "compress file data during the transfer")
 
 	return cmd
@@ -251,6 +273,9 @@ func (o *RsyncOptions) Validate() error {
 	if o.Destination.Local() && o.Watch {
 		return errors.New("\"--watch\" can only be used with a local source directory")
 	}
+	if o.Watch && o.WatchInterval <= 0 {
+		return errors.New("\"--watch-interval\" must be greater than zero")
+	}
 	if err := o.Strategy.Validate(); err != nil {
 		return err
 	}
@@ -271,7 +296,7 @@ func (o *RsyncOptions) RunRsync() error {
 }
 
 // WatchAndSync sets up a recursive filesystem watch on the sync path
-// and invokes rsync each time the path changes.
+// and copies the changed paths each time the path changes.
 func (o *RsyncOptions) WatchAndSync() error {
 
 	// these variables must be accessed while holding the changeLock
@@ -279,7 +304,7 @@ func (o *RsyncOptions) WatchAndSync() error {
 	// sync state/events.
 	var (
 		changeLock sync.Mutex
-		dirty      bool
+		changed    = sets.NewString()
 		lastChange time.Time
 		watchError error
 	)
@@ -297,7 +322,9 @@ func (o *RsyncOptions) WatchAndSync() error {
 				changeLock.Lock()
 				klog.V(5).Infof("filesystem watch event: %s", event)
 				lastChange = time.Now()
-				dirty = true
+				// directories are watched rather than files, so editors that save by
+				// renaming a temporary file over the original are seen as a create
+				changed.Insert(event.Name)
 				if event.Op&fsnotify.Remove == fsnotify.Remove {
 					if e := watcher.Remove(event.Name); e != nil {
 						klog.V(5).Infof("error removing watch for %s: %v", event.Name, e)
@@ -321,33 +348,134 @@ func (o *RsyncOptions) WatchAndSync() error {
 		return fmt.Errorf("error watching source path %s: %v", o.Source.Path, err)
 	}
 
-	delay := 2 * time.Second
+	remoteExecutor := newRemoteExecutor(o)
+	delay := o.WatchInterval
 	ticker := time.NewTicker(delay)
 	defer ticker.Stop()
 	for {
 		changeLock.Lock()
 		if watchError != nil {
+			changeLock.Unlock()
 			return watchError
 		}
-		// if a change happened more than 'delay' seconds ago, sync it now.
-		// if a change happened less than 'delay' seconds ago, sleep for 'delay' seconds
+		// if a change happened more than 'delay' ago, sync it now.
+		// if a change happened less than 'delay' ago, sleep for 'delay'
 		// and see if more changes happen, we don't want to sync when
 		// the filesystem is in the middle of changing due to a massive
 		// set of changes (such as a local build in progress).
-		if dirty && time.Now().After(lastChange.Add(delay)) {
+		var paths []string
+		if changed.Len() > 0 && time.Now().After(lastChange.Add(delay)) {
+			paths = changed.List()
+			changed = sets.NewString()
+		}
+		changeLock.Unlock()
+
+		if len(paths) > 0 {
 			klog.V(1).Info("Synchronizing filesystem changes...")
-			err = o.Strategy.Copy(o.Source, o.Destination, o.Out, o.ErrOut)
-			if err != nil {
+			if err := o.syncChanges(remoteExecutor, paths); err != nil {
 				return err
 			}
 			klog.V(1).Info("Done.")
-			dirty = false
 		}
-		changeLock.Unlock()
 		<-ticker.C
 	}
 }
 
+// syncChanges copies the given changed local paths to the destination. Paths that no
+// longer exist are removed from the destination if --delete was specified. The whole
+// source is copied again when the changes cannot be copied individually.
+func (o *RsyncOptions) syncChanges(remoteExecutor executor, paths []string) error {
+	// include and exclude patterns are matched relative to the copied path, so they
+	// only apply as expected when copying the whole source
+	if len(o.RsyncInclude) > 0 || len(o.RsyncExclude) > 0 {
+		return o.Strategy.Copy(o.Source, o.Destination, o.Out, o.ErrOut)
+	}
+	copies, deletes, ok := changedPaths(o.Source.Path, paths)
+	if !ok || len(copies)+len(deletes) > maxIncrementalPaths {
+		klog.V(3).Infof("Synchronizing all of %s", o.Source.Path)
+		return o.Strategy.Copy(o.Source, o.Destination, o.Out, o.ErrOut)
+	}
+
+	destinationRoot := o.destinationRoot()
+	if o.Delete && len(deletes) > 0 {
+		cmd := []string{"rm", "-rf", "--"}
+		for _, rel := range deletes {
+			cmd = append(cmd, path.Join(destinationRoot, filepath.ToSlash(rel)))
+		}
+		klog.V(3).Infof("Removing deleted paths from the pod: %v", deletes)
+		if err := executeWithLogging(remoteExecutor, cmd); err != nil {
+			return fmt.Errorf("unable to delete files in destination: %v", err)
+		}
+	}
+	for _, rel := range copies {
+		klog.V(3).Infof("Synchronizing %s", rel)
+		source := &PathSpec{Path: filepath.Join(o.Source.Path, rel)}
+		destination := &PathSpec{
+			PodName: o.Destination.PodName,
+			Path:    path.Join(destinationRoot, path.Dir(filepath.ToSlash(rel))),
+		}
+		if err := o.Strategy.Copy(source, destination, o.Out, o.ErrOut); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// destinationRoot returns the directory of the destination that the contents of the
+// source directory are copied to. Like rsync, a source without a trailing separator
+// is copied into a directory of the same name.
+func (o *RsyncOptions) destinationRoot() string {
+	if strings.HasSuffix(o.Source.Path, "/") || strings.HasSuffix(o.Source.Path, string(filepath.Separator)) {
+		return o.Destination.Path
+	}
+	return path.Join(o.Destination.Path, filepath.Base(o.Source.Path))
+}
+
+// changedPaths sorts the changed paths below root into the paths to copy, because
+// they exist, and the paths to delete, because they do not exist anymore. The paths
+// are returned relative to root, without the paths that are covered by a changed
+// parent directory. It returns false if root itself changed.
+func changedPaths(root string, paths []string) ([]string, []string, bool) {
+	root = filepath.Clean(root)
+	existing, removed := sets.NewString(), sets.NewString()
+	for _, name := range paths {
+		rel, err := filepath.Rel(root, name)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, nil, false
+		}
+		_, err = os.Lstat(name)
+		switch {
+		case err == nil:
+			existing.Insert(rel)
+		case os.IsNotExist(err):
+			removed.Insert(rel)
+		default:
+			return nil, nil, false
+		}
+	}
+
+	covered := func(rel string) bool {
+		for dir := filepath.Dir(rel); dir != "."; dir = filepath.Dir(dir) {
+			if existing.Has(dir) || removed.Has(dir) {
+				return true
+			}
+		}
+		return false
+	}
+	var copies, deletes []string
+	for _, rel := range existing.List() {
+		if !covered(rel) {
+			copies = append(copies, rel)
+		}
+	}
+	for _, rel := range removed.List() {
+		if !covered(rel) {
+			deletes = append(deletes, rel)
+		}
+	}
+	return copies, deletes, true
+}
+
 // PodName returns the name of the pod as specified in either the
 // the source or destination arguments
 func (o *RsyncOptions) PodName() string {
@@ -0,0 +1,141 @@
+package rsync
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+type fakeCopyStrategy struct {
+	copies []PathSpec
+}
+
+func (s *fakeCopyStrategy) Copy(source, destination *PathSpec, out, errOut io.Writer) error {
+	s.copies = append(s.copies, *source, *destination)
+	return nil
+}
+
+func (s *fakeCopyStrategy) Validate() error { return nil }
+func (s *fakeCopyStrategy) String() string  { return "fake" }
+
+type fakeExecutor struct {
+	commands [][]string
+}
+
+func (e *fakeExecutor) Execute(command []string, in io.Reader, out, err io.Writer) error {
+	e.commands = append(e.commands, command)
+	return nil
+}
+
+func TestSyncChanges(t *testing.T) {
+	root, err := ioutil.TempDir("", "rsync-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	for _, dir := range []string{"src", "src/new"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"main.go", "src/lib.go", "src/new/file.go"} {
+		if err := ioutil.WriteFile(filepath.Join(root, file), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	changed := func(names ...string) []string {
+		var paths []string
+		for _, name := range names {
+			paths = append(paths, filepath.Join(root, name))
+		}
+		return paths
+	}
+
+	tests := []struct {
+		name             string
+		source           string
+		delete           bool
+		exclude          []string
+		paths            []string
+		expectedCopies   []PathSpec
+		expectedCommands [][]string
+	}{
+		{
+			name:   "changed files and a new directory are copied",
+			source: root + "/",
+			paths:  changed("main.go", "src/lib.go", "src/new", "src/new/file.go"),
+			expectedCopies: []PathSpec{
+				{Path: filepath.Join(root, "main.go")}, {PodName: "pod", Path: "/app"},
+				{Path: filepath.Join(root, "src/lib.go")}, {PodName: "pod", Path: "/app/src"},
+				{Path: filepath.Join(root, "src/new")}, {PodName: "pod", Path: "/app/src"},
+			},
+		},
+		{
+			name:   "source without a trailing separator is copied into a directory",
+			source: root,
+			paths:  changed("src/lib.go"),
+			expectedCopies: []PathSpec{
+				{Path: filepath.Join(root, "src/lib.go")}, {PodName: "pod", Path: "/app/" + filepath.Base(root) + "/src"},
+			},
+		},
+		{
+			name:   "removed files are deleted with --delete",
+			source: root + "/",
+			delete: true,
+			paths:  changed("main.go", ".main.go.swp", "src/old"),
+			expectedCopies: []PathSpec{
+				{Path: filepath.Join(root, "main.go")}, {PodName: "pod", Path: "/app"},
+			},
+			expectedCommands: [][]string{{"rm", "-rf", "--", "/app/.main.go.swp", "/app/src/old"}},
+		},
+		{
+			name:   "removed files are kept without --delete",
+			source: root + "/",
+			paths:  changed("src/old"),
+		},
+		{
+			name:    "whole source is copied with --exclude",
+			source:  root + "/",
+			exclude: []string{"*.swp"},
+			paths:   changed("main.go"),
+			expectedCopies: []PathSpec{
+				{Path: root + "/"}, {PodName: "pod", Path: "/app"},
+			},
+		},
+		{
+			name:   "whole source is copied when the source changed",
+			source: root + "/",
+			paths:  []string{root, filepath.Join(root, "main.go")},
+			expectedCopies: []PathSpec{
+				{Path: root + "/"}, {PodName: "pod", Path: "/app"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			strategy := &fakeCopyStrategy{}
+			executor := &fakeExecutor{}
+			o := NewRsyncOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.Source = &PathSpec{Path: test.source}
+			o.Destination = &PathSpec{PodName: "pod", Path: "/app"}
+			o.Strategy = strategy
+			o.Delete = test.delete
+			o.RsyncExclude = test.exclude
+
+			if err := o.syncChanges(executor, test.paths); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(test.expectedCopies, strategy.copies) {
+				t.Errorf("expected copies %v, got %v", test.expectedCopies, strategy.copies)
+			}
+			if !reflect.DeepEqual(test.expectedCommands, executor.commands) {
+				t.Errorf("expected commands %v, got %v", test.expectedCommands, executor.commands)
+			}
+		})
+	}
+}
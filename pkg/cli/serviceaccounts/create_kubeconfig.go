@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -32,11 +33,19 @@ var (
 		Service account API tokens are used by service accounts to authenticate to the API.
 		Client actions using a service account token will be executed as if the service account
 		itself were making the actions.
+
+		If a duration is provided, a time-bound token that expires after the requested duration
+		is requested from the TokenRequest API and embedded in the kubeconfig file instead of an
+		existing token. If the server does not support the TokenRequest API, the existing token
+		is used.
 	`)
 
 	createKubeconfigExamples = templates.Examples(`
 		# Create a kubeconfig file for service account 'default'
 		oc serviceaccounts create-kubeconfig 'default' > default.kubeconfig
+
+		# Create a kubeconfig file for service account 'default' whose token expires after 8 hours
+		oc serviceaccounts create-kubeconfig 'default' --duration=8h > default.kubeconfig
 	`)
 )
 
@@ -48,6 +57,9 @@ type CreateKubeconfigOptions struct {
 	ContextNamespace string
 	Context          string
 
+	Duration  time.Duration
+	Audiences []string
+
 	genericclioptions.IOStreams
 }
 
@@ -74,6 +86,8 @@ func NewCommandCreateKubeconfig(f cmdutil.Factory, streams genericclioptions.IOS
 		},
 	}
 	cmd.Flags().StringVar(&options.ContextNamespace, "with-namespace", "", "Namespace for this context in .kubeconfig.")
+	cmd.Flags().DurationVar(&options.Duration, "duration", options.Duration, "If set, request a token that expires after this duration from the TokenRequest API instead of using an existing token.")
+	cmd.Flags().StringArrayVar(&options.Audiences, "audience", options.Audiences, "Intended audience of the token requested with --duration. May be repeated.")
 	return cmd
 }
 
@@ -127,6 +141,10 @@ func (o *CreateKubeconfigOptions) Validate() error {
 		return errors.New("API clients must not be nil in order to create a new service account token")
 	}
 
+	if err := validateBoundTokenOptions(o.Duration, o.Audiences); err != nil {
+		return err
+	}
+
 	if o.Out == nil || o.ErrOut == nil {
 		return errors.New("cannot proceed if output or error writers are nil")
 	}
@@ -140,6 +158,16 @@ func (o *CreateKubeconfigOptions) Run() error {
 		return err
 	}
 
+	if o.Duration > 0 {
+		token, err := requestBoundToken(o.SAClient, o.SAName, o.Duration, o.Audiences, o.ErrOut)
+		if err != nil {
+			return err
+		}
+		if len(token) > 0 {
+			return o.writeKubeconfig(token)
+		}
+	}
+
 	for _, reference := range serviceAccount.Secrets {
 		secret, err := o.SecretsClient.Get(context.TODO(), reference.Name, metav1.GetOptions{})
 		if err != nil {
@@ -152,39 +180,44 @@ func (o *CreateKubeconfigOptions) Run() error {
 				return fmt.Errorf("service account token %q for service account %q did not contain token data", secret.Name, serviceAccount.Name)
 			}
 
-			// It's assumed o.RawConfig is only read here since MinifyConfig modifies
-			// the list of contexts and reduces it to a single context given by CurrentContext
-			// field. Thus, it's safe to modify cfg.CurrentContext here.
-			cfg := &o.RawConfig
-			if o.Context != "" {
-				cfg.CurrentContext = o.Context
-			}
-
-			if err := clientcmdapi.MinifyConfig(cfg); err != nil {
-				return fmt.Errorf("invalid configuration, unable to create new config file: %v", err)
-			}
-
-			ctx := cfg.Contexts[cfg.CurrentContext]
-			ctx.Namespace = o.ContextNamespace
-			// rename the current context
-			cfg.CurrentContext = o.SAName
-			cfg.Contexts = map[string]*clientcmdapi.Context{
-				cfg.CurrentContext: ctx,
-			}
-			// use the server name
-			ctx.AuthInfo = o.SAName
-			cfg.AuthInfos = map[string]*clientcmdapi.AuthInfo{
-				ctx.AuthInfo: {
-					Token: string(token),
-				},
-			}
-			out, err := kclientcmd.Write(*cfg)
-			if err != nil {
-				return err
-			}
-			fmt.Fprintf(o.Out, string(out))
-			return nil
+			return o.writeKubeconfig(string(token))
 		}
 	}
 	return fmt.Errorf("could not find a service account token for service account %q", serviceAccount.Name)
 }
+
+// writeKubeconfig prints a minified copy of the current configuration that authenticates with token
+func (o *CreateKubeconfigOptions) writeKubeconfig(token string) error {
+	// It's assumed o.RawConfig is only read here since MinifyConfig modifies
+	// the list of contexts and reduces it to a single context given by CurrentContext
+	// field. Thus, it's safe to modify cfg.CurrentContext here.
+	cfg := &o.RawConfig
+	if o.Context != "" {
+		cfg.CurrentContext = o.Context
+	}
+
+	if err := clientcmdapi.MinifyConfig(cfg); err != nil {
+		return fmt.Errorf("invalid configuration, unable to create new config file: %v", err)
+	}
+
+	ctx := cfg.Contexts[cfg.CurrentContext]
+	ctx.Namespace = o.ContextNamespace
+	// rename the current context
+	cfg.CurrentContext = o.SAName
+	cfg.Contexts = map[string]*clientcmdapi.Context{
+		cfg.CurrentContext: ctx,
+	}
+	// use the server name
+	ctx.AuthInfo = o.SAName
+	cfg.AuthInfos = map[string]*clientcmdapi.AuthInfo{
+		ctx.AuthInfo: {
+			Token: token,
+		},
+	}
+	out, err := kclientcmd.Write(*cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(o.Out, string(out))
+	return nil
+}
@@ -1,10 +1,18 @@
 package serviceaccounts
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
+	"io"
+	"time"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 const (
@@ -98,3 +106,53 @@ func isServiceAccountToken(secret *corev1.Secret, sa *corev1.ServiceAccount) boo
 
 	return true
 }
+
+// validateBoundTokenOptions checks the values of the --duration and --audience flags.
+func validateBoundTokenOptions(duration time.Duration, audiences []string) error {
+	if duration < 0 {
+		return errors.New("--duration must be positive")
+	}
+	if duration%time.Second != 0 {
+		return errors.New("--duration cannot be expressed in units less than seconds")
+	}
+	if len(audiences) > 0 && duration == 0 {
+		return errors.New("--audience can only be set if --duration is provided")
+	}
+	for _, audience := range audiences {
+		if len(audience) == 0 {
+			return errors.New("--audience must not be an empty string")
+		}
+	}
+	return nil
+}
+
+// requestBoundToken uses the TokenRequest API to create a token for the service account that
+// expires after duration. If the server does not support the TokenRequest API, a warning is
+// printed to errOut and an empty token is returned so the caller can fall back to a token secret.
+func requestBoundToken(client corev1client.ServiceAccountInterface, name string, duration time.Duration, audiences []string, errOut io.Writer) (string, error) {
+	expirationSeconds := int64(duration / time.Second)
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         audiences,
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	response, err := client.CreateToken(context.TODO(), name, request, metav1.CreateOptions{})
+	if kerrors.IsNotFound(err) {
+		// the same error is returned when the service account does not exist
+		if _, getErr := client.Get(context.TODO(), name, metav1.GetOptions{}); getErr != nil {
+			return "", getErr
+		}
+	}
+	if kerrors.IsNotFound(err) || kerrors.IsMethodNotSupported(err) {
+		fmt.Fprintf(errOut, "warning: the server does not support the TokenRequest API, falling back to a token secret that does not expire\n")
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create token: %v", err)
+	}
+	if len(response.Status.Token) == 0 {
+		return "", errors.New("failed to create token: no token in server response")
+	}
+	return response.Status.Token, nil
+}
@@ -40,6 +40,11 @@ var (
 		account actions by executing them with distinct tokens, to rotate out pre-existing token
 		on the service account, or for use by an external client. If a label is provided, it will
 		be applied to any created token so that tokens created with this command can be idenitifed.
+
+		If a duration is provided, a time-bound token is requested from the TokenRequest API
+		instead of creating a token secret. The token expires after the requested duration and
+		is not stored on the server, so labels are not applied to it. If the server does not
+		support the TokenRequest API, a token secret is created instead.
 	`)
 
 	newServiceAccountTokenExamples = templates.Examples(`
@@ -49,6 +54,9 @@ var (
 		# Generate a new token for service account 'default' and apply
 		# labels 'foo' and 'bar' to the new token for identification
 		oc serviceaccounts new-token 'default' --labels foo=foo-value,bar=bar-value
+
+		# Generate a token for service account 'default' that expires after one hour
+		oc serviceaccounts new-token 'default' --duration=1h --audience=https://example.com
 	`)
 )
 
@@ -61,6 +69,9 @@ type ServiceAccountTokenOptions struct {
 
 	Timeout time.Duration
 
+	Duration  time.Duration
+	Audiences []string
+
 	genericclioptions.IOStreams
 }
 
@@ -91,6 +102,8 @@ func NewCommandNewServiceAccountToken(f cmdutil.Factory, streams genericclioptio
 
 	newServiceAccountTokenCommand.Flags().DurationVar(&options.Timeout, "timeout", 30*time.Second, "the maximum time allowed to generate a token")
 	newServiceAccountTokenCommand.Flags().StringVarP(&requestedLabels, "labels", "l", "", "labels to set in all resources for this application, given as a comma-delimited list of key-value pairs")
+	newServiceAccountTokenCommand.Flags().DurationVar(&options.Duration, "duration", options.Duration, "If set, request a token that expires after this duration from the TokenRequest API instead of creating a token secret.")
+	newServiceAccountTokenCommand.Flags().StringArrayVar(&options.Audiences, "audience", options.Audiences, "Intended audience of the token requested with --duration. May be repeated.")
 	return newServiceAccountTokenCommand
 }
 
@@ -141,6 +154,10 @@ func (o *ServiceAccountTokenOptions) Validate() error {
 		return errors.New("a positive amount of time must be allotted for the timeout")
 	}
 
+	if err := validateBoundTokenOptions(o.Duration, o.Audiences); err != nil {
+		return err
+	}
+
 	if o.Out == nil || o.ErrOut == nil {
 		return errors.New("cannot proceed if output or error writers are nil")
 	}
@@ -148,8 +165,23 @@ func (o *ServiceAccountTokenOptions) Validate() error {
 	return nil
 }
 
-// Run creates a new token secret, waits for the service account token controller to fulfill it, then adds the token to the service account
+// Run requests a time-bound token when a duration is set, otherwise it creates a new token secret
 func (o *ServiceAccountTokenOptions) Run() error {
+	if o.Duration > 0 {
+		token, err := requestBoundToken(o.SAClient, o.SAName, o.Duration, o.Audiences, o.ErrOut)
+		if err != nil {
+			return err
+		}
+		if len(token) > 0 {
+			o.printToken(token)
+			return nil
+		}
+	}
+	return o.createTokenSecret()
+}
+
+// createTokenSecret creates a new token secret, waits for the service account token controller to fulfill it, then adds the token to the service account
+func (o *ServiceAccountTokenOptions) createTokenSecret() error {
 	serviceAccount, err := o.SAClient.Get(context.TODO(), o.SAName, metav1.GetOptions{})
 	if err != nil {
 		return err
@@ -184,12 +216,16 @@ func (o *ServiceAccountTokenOptions) Run() error {
 		return fmt.Errorf("service account token %q did not contain token data", tokenSecret.Name)
 	}
 
-	fmt.Fprintf(o.Out, string(token))
+	o.printToken(string(token))
+	return nil
+}
+
+func (o *ServiceAccountTokenOptions) printToken(token string) {
+	fmt.Fprint(o.Out, token)
 	if term.IsTerminalWriter(o.Out) {
 		// pretty-print for a TTY
 		fmt.Fprintf(o.Out, "\n")
 	}
-	return nil
 }
 
 // waitForToken uses `cmd.Until` to wait for the service account controller to fulfill the token request
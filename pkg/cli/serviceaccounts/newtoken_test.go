@@ -0,0 +1,119 @@
+package serviceaccounts
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestNewBoundServiceAccountToken(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "test"}})
+	var requested *authenticationv1.TokenRequest
+	client.PrependReactor("create", "serviceaccounts", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		requested = action.(clienttesting.CreateAction).GetObject().(*authenticationv1.TokenRequest)
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: "bound-token"}}, nil
+	})
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	o := NewServiceAccountTokenOptions(streams)
+	o.SAName = "builder"
+	o.SAClient = client.CoreV1().ServiceAccounts("test")
+	o.SecretsClient = client.CoreV1().Secrets("test")
+	o.Timeout = time.Second
+	o.Duration = time.Hour
+	o.Audiences = []string{"https://example.com"}
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "bound-token" {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+	if requested == nil || *requested.Spec.ExpirationSeconds != 3600 || len(requested.Spec.Audiences) != 1 || requested.Spec.Audiences[0] != "https://example.com" {
+		t.Errorf("unexpected token request: %#v", requested)
+	}
+	secrets, err := client.CoreV1().Secrets("test").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets.Items) != 0 {
+		t.Errorf("expected no token secret to be created, got %d", len(secrets.Items))
+	}
+}
+
+func TestRequestBoundTokenUnsupported(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "test"}})
+	client.PrependReactor("create", "serviceaccounts", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kerrors.NewNotFound(schema.GroupResource{Resource: "serviceaccounts/token"}, "builder")
+	})
+
+	errOut := &bytes.Buffer{}
+	token, err := requestBoundToken(client.CoreV1().ServiceAccounts("test"), "builder", time.Hour, nil, errOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(token) != 0 {
+		t.Errorf("expected no token, got %q", token)
+	}
+	if !strings.Contains(errOut.String(), "does not support the TokenRequest API") {
+		t.Errorf("expected a warning, got %q", errOut.String())
+	}
+}
+
+func TestRequestBoundTokenMissingServiceAccount(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "serviceaccounts", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kerrors.NewNotFound(schema.GroupResource{Resource: "serviceaccounts"}, "builder")
+	})
+
+	errOut := &bytes.Buffer{}
+	_, err := requestBoundToken(client.CoreV1().ServiceAccounts("test"), "builder", time.Hour, nil, errOut)
+	if !kerrors.IsNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+	if errOut.Len() > 0 {
+		t.Errorf("expected no warning, got %q", errOut.String())
+	}
+}
+
+func TestValidateBoundTokenOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		duration  time.Duration
+		audiences []string
+		expectErr bool
+	}{
+		{name: "legacy"},
+		{name: "duration", duration: time.Hour, audiences: []string{"api"}},
+		{name: "negative", duration: -time.Hour, expectErr: true},
+		{name: "fractional seconds", duration: 1500 * time.Millisecond, expectErr: true},
+		{name: "audience without duration", audiences: []string{"api"}, expectErr: true},
+		{name: "empty audience", duration: time.Hour, audiences: []string{""}, expectErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateBoundTokenOptions(tc.duration, tc.audiences)
+			if tc.expectErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
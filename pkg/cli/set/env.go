@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -58,6 +59,9 @@ var (
 		# List the environment variables defined on all pods
 		oc set env pods --all --list
 
+		# List the environment variables of the containers named app or starting with sidecar- in a deployment config
+		oc set env dc/myapp --list -c 'app,sidecar-*'
+
 		# Output modified build config in YAML
 		oc set env bc/sample-build STORAGE_DIR=/data -o yaml
 
@@ -70,6 +74,9 @@ var (
 		# Import environment from a config map with a prefix
 		oc set env --from=configmap/myconfigmap --prefix=MYSQL_ dc/myapp
 
+		# Import only the user and password keys of a secret, with a prefix
+		oc set env --from=secret/mysecret --keys=user,password --prefix=DB_ dc/myapp
+
 		# Remove the environment variable ENV from container 'c1' in all deployment configs
 		oc set env dc --all --containers="c1" ENV-
 
@@ -102,6 +109,7 @@ type EnvOptions struct {
 	Selector          string
 	From              string
 	Prefix            string
+	Keys              []string
 
 	UpdatePodSpecForObject polymorphichelpers.UpdatePodSpecForObjectFunc
 	Builder                func() *resource.Builder
@@ -143,9 +151,10 @@ func NewCmdEnv(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.C
 	}
 	usage := "to use to edit the resource"
 	kcmdutil.AddFilenameOptionFlags(cmd, &o.FilenameOptions, usage)
-	cmd.Flags().StringVarP(&o.ContainerSelector, "containers", "c", o.ContainerSelector, "The names of containers in the selected pod templates to change - may use wildcards")
+	cmd.Flags().StringVarP(&o.ContainerSelector, "containers", "c", o.ContainerSelector, "The names of containers in the selected pod templates to change or list, separated by commas - may use wildcards")
 	cmd.Flags().StringVar(&o.From, "from", o.From, "The name of a resource from which to inject environment variables")
 	cmd.Flags().StringVar(&o.Prefix, "prefix", o.Prefix, "Prefix to append to variable names")
+	cmd.Flags().StringSliceVar(&o.Keys, "keys", o.Keys, "Comma-separated list of keys to import from the resource given in --from. Defaults to all keys.")
 	cmd.Flags().StringArrayVarP(&o.EnvParams, "env", "e", o.EnvParams, "Specify a key-value pair for an environment variable to set into each container.")
 	cmd.Flags().BoolVar(&o.List, "list", o.List, "If true, display the environment and any changes in the standard format")
 	cmd.Flags().BoolVar(&o.Resolve, "resolve", o.Resolve, "If true, show secret or configmap references when listing variables")
@@ -234,11 +243,69 @@ func (o *EnvOptions) Validate() error {
 		return fmt.Errorf("cannot specify --local and --dry-run=server - did you mean --dry-run=client?")
 	}
 
+	if len(o.Keys) > 0 && len(o.From) == 0 {
+		return fmt.Errorf("--keys may only be specified with --from")
+	}
+
 	cmdutil.WarnAboutCommaSeparation(o.ErrOut, o.EnvParams, "--env")
 
 	return nil
 }
 
+// envFromSource returns an environment variable referencing each of the given keys in a secret
+// or config map, or every key in it if no keys are given. An error is returned if a requested
+// key is absent.
+func envFromSource(obj runtime.Object, keys []string) ([]corev1.EnvVar, error) {
+	var name, kind string
+	available := sets.NewString()
+	switch from := obj.(type) {
+	case *corev1.Secret:
+		name, kind = from.Name, "secret"
+		for key := range from.Data {
+			available.Insert(key)
+		}
+	case *corev1.ConfigMap:
+		name, kind = from.Name, "configmap"
+		for key := range from.Data {
+			available.Insert(key)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported resource specified in --from: %T", from)
+	}
+
+	selected := available.List()
+	if len(keys) > 0 {
+		for _, key := range keys {
+			if !available.Has(key) {
+				return nil, fmt.Errorf("key %q not found in %s/%s", key, kind, name)
+			}
+		}
+		selected = sets.NewString(keys...).List()
+	}
+
+	env := make([]corev1.EnvVar, 0, len(selected))
+	for _, key := range selected {
+		envVar := corev1.EnvVar{Name: keyToEnvName(key), ValueFrom: &corev1.EnvVarSource{}}
+		if kind == "secret" {
+			envVar.ValueFrom.SecretKeyRef = &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: name,
+				},
+				Key: key,
+			}
+		} else {
+			envVar.ValueFrom.ConfigMapKeyRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: name,
+				},
+				Key: key,
+			}
+		}
+		env = append(env, envVar)
+	}
+	return env, nil
+}
+
 // RunEnv contains all the necessary functionality for the OpenShift cli env command
 // TODO: refactor to share the common "patch resource" pattern of probe
 // TODO: figure out how we can replace this with upstream counterpart
@@ -271,40 +338,11 @@ func (o *EnvOptions) RunEnv() error {
 		}
 
 		for _, info := range infos {
-			switch from := info.Object.(type) {
-			case *corev1.Secret:
-				for key := range from.Data {
-					envVar := corev1.EnvVar{
-						Name: keyToEnvName(key),
-						ValueFrom: &corev1.EnvVarSource{
-							SecretKeyRef: &corev1.SecretKeySelector{
-								LocalObjectReference: corev1.LocalObjectReference{
-									Name: from.Name,
-								},
-								Key: key,
-							},
-						},
-					}
-					env = append(env, envVar)
-				}
-			case *corev1.ConfigMap:
-				for key := range from.Data {
-					envVar := corev1.EnvVar{
-						Name: keyToEnvName(key),
-						ValueFrom: &corev1.EnvVarSource{
-							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
-								LocalObjectReference: corev1.LocalObjectReference{
-									Name: from.Name,
-								},
-								Key: key,
-							},
-						},
-					}
-					env = append(env, envVar)
-				}
-			default:
-				return fmt.Errorf("unsupported resource specified in --from: %T", from)
+			fromEnv, err := envFromSource(info.Object, o.Keys)
+			if err != nil {
+				return err
 			}
+			env = append(env, fromEnv...)
 		}
 	}
 
@@ -352,6 +390,72 @@ func (o *EnvOptions) RunEnv() error {
 		oldData[i] = old
 	}
 
+	skipped, errored := o.updateInfos(infos, env, remove)
+	if singleItemImplied && skipped == len(infos) {
+		name := getObjectName(infos[0])
+		return fmt.Errorf("%s is not a pod or does not have a pod template", name)
+	}
+	if len(errored) == len(infos) {
+		return kcmdutil.ErrExit
+	}
+
+	if o.List {
+		return nil
+	}
+
+	allErrs := []error{}
+updates:
+	for i, info := range infos {
+		for _, erroredInfo := range errored {
+			if info == erroredInfo {
+				continue updates
+			}
+		}
+
+		if o.Local || o.DryRunStrategy == kcmdutil.DryRunClient {
+			if err := o.Printer.PrintObj(info.Object, o.Out); err != nil {
+				allErrs = append(allErrs, err)
+			}
+			continue
+		}
+
+		newData, err := json.Marshal(infos[i].Object)
+		if err != nil {
+			allErrs = append(allErrs, err)
+			continue
+		}
+		patchBytes, err := strategicpatch.CreateTwoWayMergePatch(oldData[i], newData, infos[i].Object)
+		if err != nil {
+			allErrs = append(allErrs, err)
+			continue
+		}
+
+		actual, err := resource.NewHelper(info.Client, info.Mapping).
+			DryRun(o.DryRunStrategy == kcmdutil.DryRunServer).
+			WithFieldManager(o.FieldManager).
+			Patch(info.Namespace, info.Name, types.StrategicMergePatchType, patchBytes, &metav1.PatchOptions{})
+		if err != nil {
+			allErrs = append(allErrs, fmt.Errorf("failed to set env: %v\n", err))
+			continue
+		}
+
+		// make sure arguments to set or replace environment variables are set
+		// before returning a successful message
+		if len(env) == 0 && len(o.EnvArgs) == 0 && len(remove) == 0 {
+			return fmt.Errorf("at least one environment variable must be provided")
+		}
+
+		if err := o.Printer.PrintObj(actual, o.Out); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return utilerrors.NewAggregate(allErrs)
+}
+
+// updateInfos applies the environment changes to the containers selected by ContainerSelector in
+// each object, or lists their environment if List is set. It returns the number of objects
+// without a pod template or environment, and the objects that could not be updated.
+func (o *EnvOptions) updateInfos(infos []*resource.Info, env []corev1.EnvVar, remove []string) (int, []*resource.Info) {
 	skipped := 0
 	errored := []*resource.Info{}
 	for _, info := range infos {
@@ -454,65 +558,7 @@ func (o *EnvOptions) RunEnv() error {
 			continue
 		}
 	}
-	if singleItemImplied && skipped == len(infos) {
-		name := getObjectName(infos[0])
-		return fmt.Errorf("%s is not a pod or does not have a pod template", name)
-	}
-	if len(errored) == len(infos) {
-		return kcmdutil.ErrExit
-	}
-
-	if o.List {
-		return nil
-	}
-
-	allErrs := []error{}
-updates:
-	for i, info := range infos {
-		for _, erroredInfo := range errored {
-			if info == erroredInfo {
-				continue updates
-			}
-		}
-
-		if o.Local || o.DryRunStrategy == kcmdutil.DryRunClient {
-			if err := o.Printer.PrintObj(info.Object, o.Out); err != nil {
-				allErrs = append(allErrs, err)
-			}
-			continue
-		}
-
-		newData, err := json.Marshal(infos[i].Object)
-		if err != nil {
-			allErrs = append(allErrs, err)
-			continue
-		}
-		patchBytes, err := strategicpatch.CreateTwoWayMergePatch(oldData[i], newData, infos[i].Object)
-		if err != nil {
-			allErrs = append(allErrs, err)
-			continue
-		}
-
-		actual, err := resource.NewHelper(info.Client, info.Mapping).
-			DryRun(o.DryRunStrategy == kcmdutil.DryRunServer).
-			WithFieldManager(o.FieldManager).
-			Patch(info.Namespace, info.Name, types.StrategicMergePatchType, patchBytes, &metav1.PatchOptions{})
-		if err != nil {
-			allErrs = append(allErrs, fmt.Errorf("failed to set env: %v\n", err))
-			continue
-		}
-
-		// make sure arguments to set or replace environment variables are set
-		// before returning a successful message
-		if len(env) == 0 && len(o.EnvArgs) == 0 && len(remove) == 0 {
-			return fmt.Errorf("at least one environment variable must be provided")
-		}
-
-		if err := o.Printer.PrintObj(actual, o.Out); err != nil {
-			allErrs = append(allErrs, err)
-		}
-	}
-	return utilerrors.NewAggregate(allErrs)
+	return skipped, errored
 }
 
 // UpdateObjectEnvironment update the environment variables in object specification.
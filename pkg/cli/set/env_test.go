@@ -0,0 +1,162 @@
+package set
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	"github.com/openshift/oc/pkg/helpers/originpolymorphichelpers"
+)
+
+func TestEnvFromSource(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db"},
+		Data:       map[string][]byte{"user": []byte("admin"), "password": []byte("secret"), "ca.crt": []byte("cert")},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings"},
+		Data:       map[string]string{"log-level": "debug", "mode": "prod"},
+	}
+	secretRef := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: key}}}
+	}
+	configMapRef := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}, Key: key}}}
+	}
+
+	tests := []struct {
+		name        string
+		obj         runtime.Object
+		keys        []string
+		expected    []corev1.EnvVar
+		expectedErr string
+	}{
+		{
+			name:     "all secret keys",
+			obj:      secret,
+			expected: []corev1.EnvVar{secretRef("CA_CRT", "ca.crt"), secretRef("PASSWORD", "password"), secretRef("USER", "user")},
+		},
+		{
+			name:     "selected secret keys",
+			obj:      secret,
+			keys:     []string{"user", "password", "user"},
+			expected: []corev1.EnvVar{secretRef("PASSWORD", "password"), secretRef("USER", "user")},
+		},
+		{
+			name:     "selected config map keys",
+			obj:      configMap,
+			keys:     []string{"log-level"},
+			expected: []corev1.EnvVar{configMapRef("LOG_LEVEL", "log-level")},
+		},
+		{
+			name:        "missing key",
+			obj:         configMap,
+			keys:        []string{"mode", "region"},
+			expectedErr: `key "region" not found in configmap/settings`,
+		},
+		{
+			name:        "unsupported resource",
+			obj:         &corev1.Pod{},
+			expectedErr: "unsupported resource specified in --from: *v1.Pod",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			env, err := envFromSource(test.obj, test.keys)
+			if len(test.expectedErr) > 0 {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(env, test.expected) {
+				t.Errorf("unexpected env:\n%#v\nexpected:\n%#v", env, test.expected)
+			}
+		})
+	}
+}
+
+func TestEnvListContainers(t *testing.T) {
+	dc := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: "default"},
+		Spec: appsv1.DeploymentConfigSpec{
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "app", Env: []corev1.EnvVar{{Name: "MODE", Value: "prod"}}},
+						{Name: "sidecar-proxy", Env: []corev1.EnvVar{{Name: "PROXY_PORT", Value: "8080"}}},
+						{Name: "sidecar-logs", Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		selector  string
+		expected  string
+		expectErr string
+	}{
+		{
+			name:     "single container",
+			selector: "app",
+			expected: "# deploymentconfigs/myapp, container app\nMODE=prod\n",
+		},
+		{
+			name:     "pattern",
+			selector: "sidecar-*",
+			expected: "# deploymentconfigs/myapp, container sidecar-proxy\nPROXY_PORT=8080\n# deploymentconfigs/myapp, container sidecar-logs\nLOG_LEVEL=info\n",
+		},
+		{
+			name:     "list of names and patterns",
+			selector: "sidecar-l*, app",
+			expected: "# deploymentconfigs/myapp, container app\nMODE=prod\n# deploymentconfigs/myapp, container sidecar-logs\nLOG_LEVEL=info\n",
+		},
+		{
+			name:      "no match",
+			selector:  "db",
+			expectErr: "warning: deploymentconfigs/myapp does not have any containers matching \"db\"\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+			o := &EnvOptions{
+				List:              true,
+				ContainerSelector: test.selector,
+				// set the way it is set in pkg/cli/shim_kubectl.go
+				UpdatePodSpecForObject: originpolymorphichelpers.NewUpdatePodSpecForObjectFn(polymorphichelpers.UpdatePodSpecForObjectFn),
+				IOStreams:              genericclioptions.IOStreams{Out: out, ErrOut: errOut},
+			}
+			infos := []*resource.Info{{
+				Mapping:   &meta.RESTMapping{Resource: appsv1.SchemeGroupVersion.WithResource("deploymentconfigs")},
+				Namespace: "default",
+				Name:      "myapp",
+				Object:    dc.DeepCopy(),
+			}}
+			skipped, errored := o.updateInfos(infos, nil, nil)
+			if skipped != 0 || len(errored) != 0 {
+				t.Fatalf("unexpected skipped %d or errored %v", skipped, errored)
+			}
+			if out.String() != test.expected {
+				t.Errorf("unexpected output:\n%s\nexpected:\n%s", out.String(), test.expected)
+			}
+			if errOut.String() != test.expectErr {
+				t.Errorf("unexpected error output: %q", errOut.String())
+			}
+		})
+	}
+}
@@ -12,11 +12,14 @@ import (
 	"k8s.io/kubectl/pkg/scheme"
 )
 
+// selectContainers returns the containers whose name matches spec and the ones that are skipped.
+// spec may be a comma-separated list of names or wildcard patterns.
 func selectContainers(containers []corev1.Container, spec string) ([]*corev1.Container, []*corev1.Container) {
 	out := []*corev1.Container{}
 	skipped := []*corev1.Container{}
+	specs := strings.Split(spec, ",")
 	for i, c := range containers {
-		if selectString(c.Name, spec) {
+		if selectAnyString(c.Name, specs) {
 			out = append(out, &containers[i])
 		} else {
 			skipped = append(skipped, &containers[i])
@@ -25,6 +28,16 @@ func selectContainers(containers []corev1.Container, spec string) ([]*corev1.Con
 	return out, skipped
 }
 
+// selectAnyString returns true if the provided string matches any of specs.
+func selectAnyString(s string, specs []string) bool {
+	for _, spec := range specs {
+		if selectString(s, strings.TrimSpace(spec)) {
+			return true
+		}
+	}
+	return false
+}
+
 // selectString returns true if the provided string matches spec, where spec is a string with
 // a non-greedy '*' wildcard operator.
 // TODO: turn into a regex and handle greedy matches and backtracking.
@@ -110,11 +123,16 @@ type Patch struct {
 // the changes in the object. Encoder must be able to encode the info into the appropriate destination type. If mutateFn
 // returns false, the object is not included in the final list of patches.
 func CalculatePatchesExternal(infos []*resource.Info, mutateFn func(*resource.Info) (bool, error)) []*Patch {
+	return CalculatePatches(infos, scheme.DefaultJSONEncoder(), mutateFn)
+}
+
+// CalculatePatches is like CalculatePatchesExternal, but encodes the objects with encoder.
+func CalculatePatches(infos []*resource.Info, encoder runtime.Encoder, mutateFn func(*resource.Info) (bool, error)) []*Patch {
 	var patches []*Patch
 	for _, info := range infos {
 		patch := &Patch{Info: info}
 
-		patch.Before, patch.Err = runtime.Encode(scheme.DefaultJSONEncoder(), info.Object)
+		patch.Before, patch.Err = runtime.Encode(encoder, info.Object)
 
 		ok, err := mutateFn(info)
 		if !ok {
@@ -128,7 +146,7 @@ func CalculatePatchesExternal(infos []*resource.Info, mutateFn func(*resource.In
 			continue
 		}
 
-		patch.After, patch.Err = runtime.Encode(scheme.DefaultJSONEncoder(), info.Object)
+		patch.After, patch.Err = runtime.Encode(encoder, info.Object)
 		if patch.Err != nil {
 			continue
 		}
@@ -0,0 +1,319 @@
+package set
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/kubectl/pkg/cmd/set"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
+	"k8s.io/kubectl/pkg/scheme"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	setImageLong = ktemplates.LongDesc(`
+Update existing container image(s) of resources.
+
+The wildcard container name * updates every container of the pod template. Init containers are
+only updated by * when --init-containers is passed, use --init-container-name to update a single
+init container.`)
+
+	setImageExample = ktemplates.Examples(`
+	  # Set a deployment configs's nginx container image to 'nginx:1.9.1', and its busybox container image to 'busybox'.
+	  oc set image dc/nginx busybox=busybox nginx=nginx:1.9.1
+
+	  # Set a deployment configs's app container image to the image referenced by the imagestream tag 'openshift/ruby:2.3'.
+	  oc set image dc/myapp app=openshift/ruby:2.3 --source=imagestreamtag
+
+	  # Update all deployments' and rc's nginx container's image to 'nginx:1.9.1'
+	  oc set image deployments,rc nginx=nginx:1.9.1 --all
+
+	  # Update image of all containers of daemonset abc to 'nginx:1.9.1'
+	  oc set image daemonset abc *=nginx:1.9.1
+
+	  # Update image of all containers and init containers of deployment abc to 'nginx:1.9.1'
+	  oc set image deploy/abc '*=nginx:1.9.1' --init-containers
+
+	  # Update the image of the init container 'setup' of deployment abc to 'busybox'
+	  oc set image deploy/abc --init-container-name=setup=busybox
+
+	  # Print result (in yaml format) of updating nginx container image from local file, without hitting the server
+	  oc set image -f path/to/file.yaml nginx=nginx:1.9.1 --local -o yaml`)
+)
+
+type SetImageOptions struct {
+	PrintFlags  *genericclioptions.PrintFlags
+	RecordFlags *genericclioptions.RecordFlags
+
+	Resources           []string
+	ContainerImages     map[string]string
+	InitContainerImages map[string]string
+	InitContainerPairs  []string
+	InitContainers      bool
+
+	Selector       string
+	All            bool
+	Local          bool
+	DryRunStrategy kcmdutil.DryRunStrategy
+	DryRunVerifier *resource.QueryParamVerifier
+	FieldManager   string
+
+	Infos                  []*resource.Info
+	ResolveImage           set.ImageResolverFunc
+	UpdatePodSpecForObject polymorphichelpers.UpdatePodSpecForObjectFunc
+	Printer                printers.ResourcePrinter
+	Recorder               genericclioptions.Recorder
+
+	resource.FilenameOptions
+	genericclioptions.IOStreams
+}
+
+func NewSetImageOptions(streams genericclioptions.IOStreams) *SetImageOptions {
+	return &SetImageOptions{
+		PrintFlags:  genericclioptions.NewPrintFlags("image updated").WithTypeSetter(scheme.Scheme),
+		RecordFlags: genericclioptions.NewRecordFlags(),
+		Recorder:    genericclioptions.NoopRecorder{},
+		IOStreams:   streams,
+	}
+}
+
+// NewCmdImage implements the set image command
+func NewCmdImage(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	return newCmdImage(f, NewSetImageOptions(streams))
+}
+
+func newCmdImage(f kcmdutil.Factory, o *SetImageOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "image (-f FILENAME | TYPE NAME) CONTAINER_NAME_1=CONTAINER_IMAGE_1 ... CONTAINER_NAME_N=CONTAINER_IMAGE_N",
+		DisableFlagsInUseLine: true,
+		Short:                 "Update the image of a pod template",
+		Long:                  setImageLong,
+		Example:               setImageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			kcmdutil.CheckErr(o.Complete(f, cmd, args))
+			kcmdutil.CheckErr(o.Validate())
+			kcmdutil.CheckErr(o.Run())
+		},
+	}
+
+	o.PrintFlags.AddFlags(cmd)
+	o.RecordFlags.AddFlags(cmd)
+
+	usage := "identifying the resource to get from a server."
+	kcmdutil.AddFilenameOptionFlags(cmd, &o.FilenameOptions, usage)
+	cmd.Flags().BoolVar(&o.All, "all", o.All, "Select all resources, in the namespace of the specified resource types")
+	cmd.Flags().BoolVar(&o.Local, "local", o.Local, "If true, set image will NOT contact api-server but run locally.")
+	cmd.Flags().String("source", "docker", "The image source type; valid types are 'imagestreamtag', 'istag', 'imagestreamimage', 'isimage', and 'docker'")
+	cmd.Flags().BoolVar(&o.InitContainers, "init-containers", o.InitContainers, "If true, the wildcard container name * also updates the init containers.")
+	cmd.Flags().StringArrayVar(&o.InitContainerPairs, "init-container-name", o.InitContainerPairs, "An INIT_CONTAINER_NAME=IMAGE pair setting the image of a single init container. May be repeated.")
+	kcmdutil.AddDryRunFlag(cmd)
+	kcmdutil.AddFieldManagerFlagVar(cmd, &o.FieldManager, "kubectl-set")
+	kcmdutil.AddLabelSelectorFlagVar(cmd, &o.Selector)
+
+	return cmd
+}
+
+func (o *SetImageOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+
+	o.RecordFlags.Complete(cmd)
+	o.Recorder, err = o.RecordFlags.ToRecorder()
+	if err != nil {
+		return err
+	}
+
+	o.UpdatePodSpecForObject = polymorphichelpers.UpdatePodSpecForObjectFn
+	o.ResolveImage = resolveImageFactory(f, cmd)
+	o.DryRunStrategy, err = kcmdutil.GetDryRunStrategy(cmd)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := f.DynamicClient()
+	if err != nil {
+		return err
+	}
+	o.DryRunVerifier = resource.NewQueryParamVerifier(dynamicClient, f.OpenAPIGetter(), resource.QueryParamDryRun)
+
+	kcmdutil.PrintFlagsWithDryRunStrategy(o.PrintFlags, o.DryRunStrategy)
+	o.Printer, err = o.PrintFlags.ToPrinter()
+	if err != nil {
+		return err
+	}
+
+	var imageArgs []string
+	o.Resources, imageArgs, err = kcmdutil.GetResourcesAndPairs(args, "image")
+	if err != nil {
+		return err
+	}
+	o.ContainerImages, _, err = kcmdutil.ParsePairs(imageArgs, "image", false)
+	if err != nil {
+		return err
+	}
+	o.InitContainerImages, _, err = kcmdutil.ParsePairs(o.InitContainerPairs, "init container image", false)
+	if err != nil {
+		return err
+	}
+
+	namespace, enforceNamespace, err := f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+
+	builder := f.NewBuilder().
+		WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
+		LocalParam(o.Local).
+		ContinueOnError().
+		NamespaceParam(namespace).DefaultNamespace().
+		FilenameParam(enforceNamespace, &o.FilenameOptions).
+		Flatten()
+
+	if !o.Local {
+		builder.LabelSelectorParam(o.Selector).
+			ResourceTypeOrNameArgs(o.All, o.Resources...).
+			Latest()
+	} else if len(o.Resources) > 0 {
+		// --local cannot query the api server for the specified resource
+		return resource.LocalResourceError
+	}
+
+	o.Infos, err = builder.Do().Infos()
+	return err
+}
+
+func (o *SetImageOptions) Validate() error {
+	errs := []error{}
+	if o.All && len(o.Selector) > 0 {
+		errs = append(errs, fmt.Errorf("cannot set --all and --selector at the same time"))
+	}
+	if len(o.Resources) < 1 && kcmdutil.IsFilenameSliceEmpty(o.Filenames, o.Kustomize) {
+		errs = append(errs, fmt.Errorf("one or more resources must be specified as <resource> <name> or <resource>/<name>"))
+	}
+	_, wildcard := o.ContainerImages["*"]
+	switch {
+	case len(o.ContainerImages) == 0 && len(o.InitContainerImages) == 0:
+		errs = append(errs, fmt.Errorf("at least one image update is required"))
+	case len(o.ContainerImages) > 1 && wildcard:
+		errs = append(errs, fmt.Errorf("all containers are already specified by *, but saw more than one container_name=container_image pairs"))
+	}
+	if o.InitContainers && !wildcard {
+		errs = append(errs, fmt.Errorf("--init-containers may only be used with the wildcard container name, e.g. '*=IMAGE'"))
+	}
+	if o.InitContainers && len(o.InitContainerImages) > 0 {
+		errs = append(errs, fmt.Errorf("--init-containers and --init-container-name may not be used together"))
+	}
+	if o.Local && o.DryRunStrategy == kcmdutil.DryRunServer {
+		errs = append(errs, fmt.Errorf("cannot specify --local and --dry-run=server - did you mean --dry-run=client?"))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (o *SetImageOptions) Run() error {
+	allErrs := []error{}
+
+	patches := CalculatePatchesExternal(o.Infos, func(info *resource.Info) (bool, error) {
+		_, err := o.UpdatePodSpecForObject(info.Object, func(spec *corev1.PodSpec) error {
+			allErrs = append(allErrs, o.setPodSpecImages(spec)...)
+			return nil
+		})
+		if err != nil {
+			return true, err
+		}
+		// record this change (for rollout history)
+		if err := o.Recorder.Record(info.Object); err != nil {
+			klog.V(4).Infof("error recording current command: %v", err)
+		}
+		return true, nil
+	})
+
+	for _, patch := range patches {
+		info := patch.Info
+		if patch.Err != nil {
+			allErrs = append(allErrs, fmt.Errorf("error: %s %v\n", getObjectName(info), patch.Err))
+			continue
+		}
+
+		// no changes
+		if string(patch.Patch) == "{}" || len(patch.Patch) == 0 {
+			continue
+		}
+
+		if o.Local || o.DryRunStrategy == kcmdutil.DryRunClient {
+			if err := o.Printer.PrintObj(info.Object, o.Out); err != nil {
+				allErrs = append(allErrs, err)
+			}
+			continue
+		}
+
+		if o.DryRunStrategy == kcmdutil.DryRunServer {
+			if err := o.DryRunVerifier.HasSupport(info.Mapping.GroupVersionKind); err != nil {
+				return err
+			}
+		}
+		actual, err := resource.NewHelper(info.Client, info.Mapping).
+			DryRun(o.DryRunStrategy == kcmdutil.DryRunServer).
+			WithFieldManager(o.FieldManager).
+			Patch(info.Namespace, info.Name, types.StrategicMergePatchType, patch.Patch, &metav1.PatchOptions{})
+		if err != nil {
+			allErrs = append(allErrs, fmt.Errorf("failed to patch image update to pod template: %v", err))
+			continue
+		}
+
+		if err := o.Printer.PrintObj(actual, o.Out); err != nil {
+			allErrs = append(allErrs, err)
+		}
+	}
+	return utilerrors.NewAggregate(allErrs)
+}
+
+// setPodSpecImages applies the requested image changes to the containers of the pod spec. A
+// named container is looked up in both the containers and the init containers, the wildcard
+// only reaches the init containers if InitContainers is set.
+func (o *SetImageOptions) setPodSpecImages(spec *corev1.PodSpec) []error {
+	var errs []error
+	for name, image := range o.ContainerImages {
+		resolved, err := o.ResolveImage(image)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error: unable to resolve image %q for container %q: %v", image, name, err))
+			continue
+		}
+		found := setContainerImage(spec.Containers, name, resolved)
+		if name != "*" || o.InitContainers {
+			found = setContainerImage(spec.InitContainers, name, resolved) || found
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("error: unable to find container named %q", name))
+		}
+	}
+	for name, image := range o.InitContainerImages {
+		resolved, err := o.ResolveImage(image)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error: unable to resolve image %q for init container %q: %v", image, name, err))
+			continue
+		}
+		if !setContainerImage(spec.InitContainers, name, resolved) {
+			errs = append(errs, fmt.Errorf("error: unable to find init container named %q", name))
+		}
+	}
+	return errs
+}
+
+func setContainerImage(containers []corev1.Container, name, image string) bool {
+	found := false
+	for i := range containers {
+		if containers[i].Name == name || name == "*" {
+			containers[i].Image = image
+			found = true
+		}
+	}
+	return found
+}
@@ -0,0 +1,185 @@
+package set
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kcmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+)
+
+func newImageTestPodSpec() *corev1.PodSpec {
+	return &corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "setup", Image: "setup:1"}, {Name: "migrate", Image: "migrate:1"}},
+		Containers:     []corev1.Container{{Name: "app", Image: "app:1"}, {Name: "sidecar", Image: "sidecar:1"}},
+	}
+}
+
+func podSpecImages(containers []corev1.Container) []string {
+	var images []string
+	for _, c := range containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+func TestSetPodSpecImages(t *testing.T) {
+	tests := []struct {
+		name               string
+		options            SetImageOptions
+		expectedInit       []string
+		expectedContainers []string
+		expectedErr        string
+	}{
+		{
+			name:               "wildcard skips init containers",
+			options:            SetImageOptions{ContainerImages: map[string]string{"*": "new:2"}},
+			expectedInit:       []string{"setup:1", "migrate:1"},
+			expectedContainers: []string{"new:2", "new:2"},
+		},
+		{
+			name:               "wildcard with init containers",
+			options:            SetImageOptions{ContainerImages: map[string]string{"*": "new:2"}, InitContainers: true},
+			expectedInit:       []string{"new:2", "new:2"},
+			expectedContainers: []string{"new:2", "new:2"},
+		},
+		{
+			name:               "named container and init container",
+			options:            SetImageOptions{ContainerImages: map[string]string{"app": "app:2"}, InitContainerImages: map[string]string{"migrate": "migrate:2"}},
+			expectedInit:       []string{"setup:1", "migrate:2"},
+			expectedContainers: []string{"app:2", "sidecar:1"},
+		},
+		{
+			name:               "init container name does not match containers",
+			options:            SetImageOptions{InitContainerImages: map[string]string{"app": "app:2"}},
+			expectedInit:       []string{"setup:1", "migrate:1"},
+			expectedContainers: []string{"app:1", "sidecar:1"},
+			expectedErr:        `error: unable to find init container named "app"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := test.options
+			o.ResolveImage = func(in string) (string, error) { return in, nil }
+			spec := newImageTestPodSpec()
+			errs := o.setPodSpecImages(spec)
+			if len(test.expectedErr) > 0 {
+				if len(errs) != 1 || errs[0].Error() != test.expectedErr {
+					t.Errorf("expected error %q, got %v", test.expectedErr, errs)
+				}
+			} else if len(errs) > 0 {
+				t.Errorf("unexpected errors: %v", errs)
+			}
+			if images := podSpecImages(spec.InitContainers); !reflect.DeepEqual(images, test.expectedInit) {
+				t.Errorf("unexpected init container images: %v", images)
+			}
+			if images := podSpecImages(spec.Containers); !reflect.DeepEqual(images, test.expectedContainers) {
+				t.Errorf("unexpected container images: %v", images)
+			}
+		})
+	}
+}
+
+func TestSetImageValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     SetImageOptions
+		expectedErr string
+	}{
+		{
+			name:    "init container only",
+			options: SetImageOptions{Resources: []string{"deploy/foo"}, InitContainerImages: map[string]string{"setup": "busybox"}},
+		},
+		{
+			name:        "no images",
+			options:     SetImageOptions{Resources: []string{"deploy/foo"}},
+			expectedErr: "at least one image update is required",
+		},
+		{
+			name:        "init containers without wildcard",
+			options:     SetImageOptions{Resources: []string{"deploy/foo"}, ContainerImages: map[string]string{"app": "app:2"}, InitContainers: true},
+			expectedErr: "--init-containers may only be used with the wildcard container name, e.g. '*=IMAGE'",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.options.Validate()
+			if len(test.expectedErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("expected error %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestSetImageLocal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "set-image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "deployment.yaml")
+	if err := ioutil.WriteFile(filename, []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+spec:
+  selector:
+    matchLabels:
+      app: foo
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      initContainers:
+      - name: setup
+        image: setup:1
+      containers:
+      - name: app
+        image: app:1
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]string{
+		"local":           {"--local", "-f", filename, "-o", "yaml", "--init-container-name=setup=setup:2", "app=app:2"},
+		"client dry run":  {"--local", "--dry-run=client", "-f", filename, "-o", "yaml", "--init-container-name=setup=setup:2", "app=app:2"},
+		"init containers": {"--local", "-f", filename, "-o", "yaml", "--init-containers", "*=app:2"},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			tf := kcmdtesting.NewTestFactory().WithNamespace("test")
+			defer tf.Cleanup()
+
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := NewSetImageOptions(streams)
+			cmd := newCmdImage(tf, o)
+			if err := cmd.ParseFlags(args); err != nil {
+				t.Fatal(err)
+			}
+			if err := o.Complete(tf, cmd, cmd.Flags().Args()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := o.Validate(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := o.Run(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(out.String(), "image: app:2") || strings.Contains(out.String(), "image: setup:1") {
+				t.Errorf("unexpected output:\n%s", out.String())
+			}
+		})
+	}
+}
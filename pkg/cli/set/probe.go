@@ -56,6 +56,9 @@ var (
 		# Set an exec action as a liveness probe to run 'echo ok'
 		oc set probe dc/myapp --liveness -- echo ok
 
+		# Set an exec action as a readiness probe running a command that takes its own flags
+		oc set probe deploy/myapp --readiness --exec -- curl -f http://localhost:8080/healthz
+
 		# Set a readiness probe to try to open a TCP socket on 3306
 		oc set probe rc/mysql --readiness --open-tcp=3306
 
@@ -86,6 +89,7 @@ type ProbeOptions struct {
 	Local             bool
 	OpenTCPSocket     string
 	HTTPGet           string
+	Exec              bool
 
 	Printer                printers.ResourcePrinter
 	Builder                func() *resource.Builder
@@ -128,9 +132,12 @@ func NewProbeOptions(streams genericclioptions.IOStreams) *ProbeOptions {
 
 // NewCmdProbe implements the set probe command
 func NewCmdProbe(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	o := NewProbeOptions(streams)
+	return newCmdProbe(f, NewProbeOptions(streams))
+}
+
+func newCmdProbe(f kcmdutil.Factory, o *ProbeOptions) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "probe RESOURCE/NAME --readiness|--liveness [flags] (--get-url=URL|--open-tcp=PORT|-- CMD)",
+		Use:     "probe RESOURCE/NAME --readiness|--liveness [flags] (--get-url=URL|--open-tcp=PORT|[--exec] -- CMD)",
 		Short:   "Update a probe on a pod template",
 		Long:    probeLong,
 		Example: probeExample,
@@ -152,6 +159,7 @@ func NewCmdProbe(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra
 	cmd.Flags().BoolVar(&o.Local, "local", o.Local, "If true, set image will NOT contact api-server but run locally.")
 	cmd.Flags().StringVar(&o.OpenTCPSocket, "open-tcp", o.OpenTCPSocket, "A port number or port name to attempt to open via TCP.")
 	cmd.Flags().StringVar(&o.HTTPGet, "get-url", o.HTTPGet, "A URL to perform an HTTP GET on (you can omit the host, have a string port, or omit the scheme.")
+	cmd.Flags().BoolVar(&o.Exec, "exec", o.Exec, "If true, run the command given after -- in the container. Everything after -- is passed as the command's arguments, including flags.")
 
 	o.InitialDelaySeconds = cmd.Flags().Int("initial-delay-seconds", 0, "The time in seconds to wait before the probe begins checking")
 	o.SuccessThreshold = cmd.Flags().Int("success-threshold", 0, "The number of successes required before the probe is considered successful")
@@ -240,6 +248,12 @@ func (o *ProbeOptions) Validate() error {
 	if !o.Readiness && !o.Liveness && !o.Startup {
 		return fmt.Errorf("you must specify at least one of --readiness, --liveness, --startup")
 	}
+	if o.Exec && o.Command == nil {
+		return fmt.Errorf("--exec requires the command to run after --, e.g. '--exec -- cat /tmp/healthy'")
+	}
+	if o.Command != nil && len(o.Command) == 0 {
+		return fmt.Errorf("you must specify the command to run after --")
+	}
 	count := 0
 	if o.Command != nil {
 		count++
@@ -255,7 +269,7 @@ func (o *ProbeOptions) Validate() error {
 	case o.Remove && count != 0:
 		return fmt.Errorf("--remove may not be used with any flag except --readiness or --liveness")
 	case count > 1:
-		return fmt.Errorf("you may only set one of --get-url, --open-tcp, or command")
+		return fmt.Errorf("you may only set one of --get-url, --open-tcp, or --exec")
 	case len(o.OpenTCPSocket) > 0 && intOrString(o.OpenTCPSocket).IntVal > 65535:
 		return fmt.Errorf("--open-tcp must be a port number between 1 and 65535 or an IANA port name")
 	}
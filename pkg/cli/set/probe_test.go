@@ -0,0 +1,79 @@
+package set
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kcmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+)
+
+func TestProbeExecCommand(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              []string
+		expectedResources []string
+		expectedCommand   []string
+		expectedErr       string
+	}{
+		{
+			name:              "exec with flags after dash",
+			args:              []string{"deploy/foo", "--readiness", "--exec", "--", "curl", "-f", "--max-time=5", "http://localhost:8080/healthz"},
+			expectedResources: []string{"deploy/foo"},
+			expectedCommand:   []string{"curl", "-f", "--max-time=5", "http://localhost:8080/healthz"},
+		},
+		{
+			name:              "command without exec",
+			args:              []string{"dc/foo", "--liveness", "--", "echo", "ok"},
+			expectedResources: []string{"dc/foo"},
+			expectedCommand:   []string{"echo", "ok"},
+		},
+		{
+			name:        "exec without dash",
+			args:        []string{"deploy/foo", "--readiness", "--exec"},
+			expectedErr: "--exec requires the command to run after --, e.g. '--exec -- cat /tmp/healthy'",
+		},
+		{
+			name:        "exec with empty command",
+			args:        []string{"deploy/foo", "--readiness", "--exec", "--"},
+			expectedErr: "you must specify the command to run after --",
+		},
+		{
+			name:        "exec and get-url",
+			args:        []string{"deploy/foo", "--readiness", "--get-url=http://:8080/healthz", "--exec", "--", "true"},
+			expectedErr: "you may only set one of --get-url, --open-tcp, or --exec",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tf := kcmdtesting.NewTestFactory().WithNamespace("test")
+			defer tf.Cleanup()
+
+			o := NewProbeOptions(genericclioptions.NewTestIOStreamsDiscard())
+			cmd := newCmdProbe(tf, o)
+			if err := cmd.ParseFlags(test.args); err != nil {
+				t.Fatalf("unexpected error parsing flags: %v", err)
+			}
+			if err := o.Complete(tf, cmd, cmd.Flags().Args()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err := o.Validate()
+			if len(test.expectedErr) > 0 {
+				if err == nil || err.Error() != test.expectedErr {
+					t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(o.Resources, test.expectedResources) {
+				t.Errorf("unexpected resources: %v", o.Resources)
+			}
+			if !reflect.DeepEqual(o.Command, test.expectedCommand) {
+				t.Errorf("unexpected command: %v", o.Command)
+			}
+		})
+	}
+}
@@ -1,27 +1,36 @@
 package set
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"k8s.io/kubectl/pkg/scheme"
 	"k8s.io/kubectl/pkg/util/templates"
 
 	routev1 "github.com/openshift/api/route/v1"
+	routev1client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
 )
 
 var (
@@ -47,6 +56,22 @@ var (
 		relative to either the primary or the first alternate (if you specify the primary).
 		If there are other backends their weights will be kept proportional to the changed.
 
+		The --equal flag sets the weight of every backend to 100. If services are provided after
+		the route name as service/NAME, the existing backends are replaced by those services and
+		traffic is split evenly between them with weights that add up to 100. Any remainder goes
+		to the services listed first. Unless --local is set, each service must exist.
+
+		The --zero flag sets the weight of every backend to zero. The --zero-backend flag sets the
+		weight of a single backend to zero, and the backend remains attached to the route so it can
+		be restored later. The weights of the other backends are left unchanged.
+
+		The --canary flag gradually shifts the traffic of a route from its primary backend to its
+		first alternate backend, the canary. Every --interval the share of the canary grows by
+		--step percent until it reaches --max percent. The route and the endpoints of the canary
+		service are watched during each interval, and if a router rejects the route or less than
+		--min-ready percent of the endpoints of the canary are ready, the original backends are
+		restored. They are also restored when the command is interrupted.
+
 		Not all routers may support multiple or weighted backends.`)
 
 	backendsExample = templates.Examples(`
@@ -65,8 +90,20 @@ var (
 		# Set weight of b to 10
 		oc set route-backends web --adjust b=10
 
+		# Split traffic evenly between services a, b, and c, replacing any other backends
+		oc set route-backends web --equal service/a service/b service/c
+
 		# Set the weight to all backends to zero
 		oc set route-backends web --zero
+
+		# Drain traffic from backend b without removing it from the route
+		oc set route-backends web --zero-backend=b
+
+		# Shift traffic from the primary backend of route 'web' to its canary by 10%% every 2 minutes, up to 50%%
+		oc set route-backends web --canary --step=10 --interval=2m --max=50
+
+		# Split the traffic of the route in route.yaml between a and b without contacting the server
+		oc set route-backends --local -f route.yaml a=90 b=10 -o yaml
 	`)
 )
 
@@ -79,8 +116,14 @@ type BackendsOptions struct {
 	PrintTable bool
 	Transform  BackendTransform
 
+	// Canary progressively shifts traffic to the first alternate backend of the route.
+	Canary      CanaryOptions
+	canary      bool
+	canaryFlags []string
+
 	Printer           printers.ResourcePrinter
 	Builder           func() *resource.Builder
+	ServiceClient     corev1client.ServicesGetter
 	Namespace         string
 	ExplicitNamespace bool
 	DryRunStrategy    kcmdutil.DryRunStrategy
@@ -89,20 +132,34 @@ type BackendsOptions struct {
 
 	resource.FilenameOptions
 	genericclioptions.IOStreams
+
+	// scheme decodes the routes read from files and from the server.
+	scheme *runtime.Scheme
 }
 
 func NewBackendsOptions(streams genericclioptions.IOStreams) *BackendsOptions {
 	return &BackendsOptions{
 		PrintFlags: genericclioptions.NewPrintFlags("backends updated").WithTypeSetter(scheme.Scheme),
-		IOStreams:  streams,
+		Canary: CanaryOptions{
+			Step:        10,
+			Interval:    2 * time.Minute,
+			Max:         50,
+			MinReady:    80,
+			CheckPeriod: 5 * time.Second,
+		},
+		IOStreams: streams,
+		scheme:    scheme.Scheme,
 	}
 }
 
 // NewCmdRouteBackends implements the set route-backends command
 func NewCmdRouteBackends(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	o := NewBackendsOptions(streams)
+	return newCmdRouteBackends(f, NewBackendsOptions(streams))
+}
+
+func newCmdRouteBackends(f kcmdutil.Factory, o *BackendsOptions) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "route-backends ROUTENAME [--zero|--equal] [--adjust] SERVICE=WEIGHT[%] [...]",
+		Use:     "route-backends ROUTENAME [--zero|--zero-backend=SERVICE|--equal [service/NAME ...]|--canary] [--adjust] SERVICE=WEIGHT[%] [...]",
 		Short:   "Update the backends for a route",
 		Long:    backendsLong,
 		Example: backendsExample,
@@ -116,10 +173,16 @@ func NewCmdRouteBackends(f kcmdutil.Factory, streams genericclioptions.IOStreams
 	kcmdutil.AddFilenameOptionFlags(cmd, &o.FilenameOptions, usage)
 	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Selector (label query) to filter on")
 	cmd.Flags().BoolVar(&o.All, "all", o.All, "If true, select all resources in the namespace of the specified resource types")
-	cmd.Flags().BoolVar(&o.Local, "local", o.Local, "If true, set image will NOT contact api-server but run locally.")
+	cmd.Flags().BoolVar(&o.Local, "local", o.Local, "If true, set route-backends will NOT contact api-server but run locally on the routes passed with -f.")
 	cmd.Flags().BoolVar(&o.Transform.Adjust, "adjust", o.Transform.Adjust, "Adjust a single backend using an absolute or relative weight. If the primary backend is selected and there is more than one alternate an error will be returned.")
 	cmd.Flags().BoolVar(&o.Transform.Zero, "zero", o.Transform.Zero, "If true, set the weight of all backends to zero.")
-	cmd.Flags().BoolVar(&o.Transform.Equal, "equal", o.Transform.Equal, "If true, set the weight of all backends to 100.")
+	cmd.Flags().StringVar(&o.Transform.ZeroBackend, "zero-backend", o.Transform.ZeroBackend, "Set the weight of the backend for this service to zero, leaving it attached to the route.")
+	cmd.Flags().BoolVar(&o.Transform.Equal, "equal", o.Transform.Equal, "If true, set the weight of all backends to 100. If services are listed after the route name as service/NAME, replace the backends with those services and split traffic evenly between them.")
+	cmd.Flags().BoolVar(&o.canary, "canary", o.canary, "If true, gradually shift traffic from the primary backend of the route to its first alternate backend, aborting if the canary becomes unhealthy.")
+	cmd.Flags().Int32Var(&o.Canary.Step, "step", o.Canary.Step, "The percentage of traffic shifted to the canary at each step. Requires --canary.")
+	cmd.Flags().DurationVar(&o.Canary.Interval, "interval", o.Canary.Interval, "How long the canary is watched after each step. Requires --canary.")
+	cmd.Flags().Int32Var(&o.Canary.Max, "max", o.Canary.Max, "The percentage of traffic at which the canary progression stops. Requires --canary.")
+	cmd.Flags().Int32Var(&o.Canary.MinReady, "min-ready", o.Canary.MinReady, "The percentage of the endpoints of the canary that must be ready for it to be healthy. Requires --canary.")
 
 	o.PrintFlags.AddFlags(cmd)
 	kcmdutil.AddDryRunFlag(cmd)
@@ -148,7 +211,48 @@ func (o *BackendsOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args
 		o.Transform.Inputs = append(o.Transform.Inputs, *input)
 	}
 
-	o.PrintTable = o.Transform.Empty()
+	// with --equal, any arguments after the route are the services to split traffic between. With
+	// --local the routes are read from files, so every argument is a service.
+	if o.Transform.Equal && len(o.Resources) > 0 {
+		routes := 1
+		if o.Local {
+			routes = 0
+		}
+		for _, arg := range o.Resources[routes:] {
+			name, err := parseBackendService(arg)
+			if err != nil {
+				return fmt.Errorf("invalid argument %q: %v", arg, err)
+			}
+			o.Transform.Services = append(o.Transform.Services, name)
+		}
+		o.Resources = o.Resources[:routes]
+	}
+
+	o.PrintTable = o.Transform.Empty() && !o.canary
+
+	for _, name := range []string{"step", "interval", "max", "min-ready"} {
+		if cmd.Flags().Changed(name) {
+			o.canaryFlags = append(o.canaryFlags, "--"+name)
+		}
+	}
+	if o.canary || (len(o.Transform.Services) > 0 && !o.Local) {
+		config, err := f.ToRESTConfig()
+		if err != nil {
+			return err
+		}
+		kubeClient, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return err
+		}
+		o.ServiceClient = kubeClient.CoreV1()
+		if o.canary {
+			o.Canary.RouteClient, err = routev1client.NewForConfig(config)
+			if err != nil {
+				return err
+			}
+			o.Canary.EndpointsClient = kubeClient.CoreV1()
+		}
+	}
 
 	o.DryRunStrategy, err = kcmdutil.GetDryRunStrategy(cmd)
 	if err != nil {
@@ -170,14 +274,44 @@ func (o *BackendsOptions) Validate() error {
 	if o.Local && o.DryRunStrategy == kcmdutil.DryRunServer {
 		return fmt.Errorf("cannot specify --local and --dry-run=server - did you mean --dry-run=client?")
 	}
+	if o.Local {
+		if kcmdutil.IsFilenameSliceEmpty(o.Filenames, o.Kustomize) {
+			return fmt.Errorf("--local requires the routes to be passed with -f")
+		}
+		if len(o.Resources) > 0 {
+			return fmt.Errorf("route names may not be given with --local, the routes are read from the files passed with -f")
+		}
+	}
+	if !o.canary {
+		if len(o.canaryFlags) > 0 {
+			return fmt.Errorf("%s may only be specified with --canary", strings.Join(o.canaryFlags, ", "))
+		}
+	} else {
+		if !o.Transform.Empty() {
+			return fmt.Errorf("--canary may not be specified with other changes to the backends")
+		}
+		if o.Local || !kcmdutil.IsFilenameSliceEmpty(o.Filenames, o.Kustomize) || len(o.Selector) > 0 || o.All || len(o.Resources) != 1 {
+			return fmt.Errorf("--canary requires the name of a single route")
+		}
+		if o.DryRunStrategy != kcmdutil.DryRunNone {
+			return fmt.Errorf("--canary may not be specified with --dry-run")
+		}
+		if err := o.Canary.Validate(); err != nil {
+			return err
+		}
+	}
 
 	return o.Transform.Validate()
 }
 
 // Run executes the BackendOptions or returns an error.
 func (o *BackendsOptions) Run() error {
+	if o.canary {
+		return o.runCanary()
+	}
+
 	b := o.Builder().
-		WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
+		WithScheme(o.scheme, o.scheme.PrioritizedVersionsAllGroups()...).
 		LocalParam(o.Local).
 		ContinueOnError().
 		NamespaceParam(o.Namespace).DefaultNamespace().
@@ -203,8 +337,12 @@ func (o *BackendsOptions) Run() error {
 	if o.PrintTable {
 		return o.printBackends(infos)
 	}
+	if err := o.checkServicesExist(infos); err != nil {
+		return err
+	}
 
-	patches := CalculatePatchesExternal(infos, func(info *resource.Info) (bool, error) {
+	encoder := unstructured.NewJSONFallbackEncoder(serializer.NewCodecFactory(o.scheme).LegacyCodec(o.scheme.PrioritizedVersionsAllGroups()...))
+	patches := CalculatePatches(infos, encoder, func(info *resource.Info) (bool, error) {
 		return UpdateBackendsForObject(info.Object, o.Transform.Apply)
 	})
 	if singleItemImplied && len(patches) == 0 {
@@ -212,7 +350,7 @@ func (o *BackendsOptions) Run() error {
 		if infos[0].Mapping != nil {
 			name = fmt.Sprintf("%s/%s", infos[0].Mapping.Resource.Resource, infos[0].Name)
 		}
-		return fmt.Errorf("%s is not a deployment config or build config", name)
+		return fmt.Errorf("%s is not a route", name)
 	}
 
 	allErrs := []error{}
@@ -252,6 +390,33 @@ func (o *BackendsOptions) Run() error {
 	return utilerrors.NewAggregate(allErrs)
 }
 
+// checkServicesExist returns an error if a service the backends are replaced with does not exist
+// in the namespace of one of the routes.
+func (o *BackendsOptions) checkServicesExist(infos []*resource.Info) error {
+	if o.ServiceClient == nil {
+		return nil
+	}
+	allErrs := []error{}
+	checked := sets.NewString()
+	for _, info := range infos {
+		for _, name := range o.Transform.Services {
+			key := info.Namespace + "/" + name
+			if checked.Has(key) {
+				continue
+			}
+			checked.Insert(key)
+			_, err := o.ServiceClient.Services(info.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+			switch {
+			case kapierrors.IsNotFound(err):
+				allErrs = append(allErrs, fmt.Errorf("service %q does not exist in namespace %q", name, info.Namespace))
+			case err != nil:
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+	return utilerrors.NewAggregate(allErrs)
+}
+
 // printBackends displays a tabular output of the backends for each object.
 func (o *BackendsOptions) printBackends(infos []*resource.Info) error {
 	w := tabwriter.NewWriter(o.Out, 0, 2, 2, ' ', 0)
@@ -290,20 +455,39 @@ type BackendTransform struct {
 	Adjust bool
 	// Zero sets all backend weights to zero.
 	Zero bool
+	// ZeroBackend is the name of a single backend whose weight will be set to zero.
+	ZeroBackend string
 	// Equal means backends will be set to equal weights.
 	Equal bool
+	// Services, if set with Equal, replaces the backends with these services and splits
+	// a total weight of 100 evenly between them.
+	Services []string
 	// Inputs is the desired backends.
 	Inputs []BackendInput
 }
 
 // Empty returns true if no transformations have been specified.
 func (t BackendTransform) Empty() bool {
-	return !(t.Zero || t.Equal || len(t.Inputs) > 0)
+	return !(t.Zero || t.Equal || len(t.ZeroBackend) > 0 || len(t.Inputs) > 0)
 }
 
 // Validate returns an error if the transformations are not internally consistent.
 func (t BackendTransform) Validate() error {
 	switch {
+	case len(t.ZeroBackend) > 0:
+		if t.Adjust {
+			return fmt.Errorf("--adjust and --zero-backend may not be specified together")
+		}
+		if t.Zero {
+			return fmt.Errorf("--zero and --zero-backend may not be specified together")
+		}
+		if t.Equal {
+			return fmt.Errorf("--zero-backend and --equal may not be specified together")
+		}
+		if len(t.Inputs) > 0 {
+			return fmt.Errorf("arguments may not be provided when --zero-backend is specified")
+		}
+
 	case t.Adjust:
 		if t.Zero {
 			return fmt.Errorf("--adjust and --zero may not be specified together")
@@ -322,8 +506,21 @@ func (t BackendTransform) Validate() error {
 		if len(t.Inputs) > 0 {
 			return fmt.Errorf("arguments may not be provided when --zero or --equal is specified")
 		}
+		if len(t.Services) > maxRouteBackends {
+			return fmt.Errorf("a route may have at most %d backends, %d services were provided", maxRouteBackends, len(t.Services))
+		}
+		names := sets.NewString()
+		for _, name := range t.Services {
+			if names.Has(name) {
+				return fmt.Errorf("service %q may only be specified once", name)
+			}
+			names.Insert(name)
+		}
 
 	default:
+		if len(t.Inputs) > maxRouteBackends {
+			return fmt.Errorf("a route may have at most %d backends, %d were provided", maxRouteBackends, len(t.Inputs))
+		}
 		percent := false
 		names := sets.NewString()
 		for i, input := range t.Inputs {
@@ -340,6 +537,9 @@ func (t BackendTransform) Validate() error {
 			if input.Value < 0 {
 				return fmt.Errorf("negative percentages are not allowed")
 			}
+			if !input.Percentage && input.Value > maxRouteBackendWeight {
+				return fmt.Errorf("the weight of backend %q must be between 0 and %d", input.Name, maxRouteBackendWeight)
+			}
 		}
 	}
 	return nil
@@ -348,12 +548,39 @@ func (t BackendTransform) Validate() error {
 // Apply transforms the provided backends or returns an error.
 func (t BackendTransform) Apply(b *Backends) error {
 	switch {
+	case len(t.ZeroBackend) > 0:
+		for i := range b.Backends {
+			if b.Backends[i].Name != t.ZeroBackend {
+				continue
+			}
+			zero := int32(0)
+			b.Backends[i].Weight = &zero
+			return nil
+		}
+		return fmt.Errorf("backend %q is not in the list of backends (%s)", t.ZeroBackend, strings.Join(b.Names(), ", "))
+
 	case t.Zero:
 		zero := int32(0)
 		for i := range b.Backends {
 			b.Backends[i].Weight = &zero
 		}
 
+	case t.Equal && len(t.Services) > 0:
+		// distribute the remainder one at a time to the first services so the total is 100
+		count := int32(len(t.Services))
+		b.Backends = nil
+		for i, name := range t.Services {
+			weight := 100 / count
+			if int32(i) < 100%count {
+				weight++
+			}
+			b.Backends = append(b.Backends, routev1.RouteTargetReference{
+				Kind:   "Service",
+				Name:   name,
+				Weight: &weight,
+			})
+		}
+
 	case t.Equal:
 		equal := int32(100)
 		for i := range b.Backends {
@@ -505,6 +732,32 @@ func (input *BackendInput) Apply(ref, to *routev1.RouteTargetReference, backends
 	}
 }
 
+// maxRouteBackends is the primary backend plus the three alternates a route allows.
+const maxRouteBackends = 4
+
+// maxRouteBackendWeight is the largest weight the API accepts for a route backend.
+const maxRouteBackendWeight = 256
+
+// parseBackendService returns the service name referenced by s, which must be service/NAME so
+// that services cannot be mistaken for route names. Routes can only target services, so other
+// kinds are rejected.
+func parseBackendService(s string) (string, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("services must be given as service/%s", s)
+	}
+	switch strings.ToLower(parts[0]) {
+	case "service", "services", "svc":
+	default:
+		return "", fmt.Errorf("routes may only send traffic to services, not %s", parts[0])
+	}
+	name := parts[1]
+	if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+		return "", fmt.Errorf("%q is not a valid service name: %s", name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
 // ParseBackendInput turns the provided input into a BackendInput or returns an error.
 func ParseBackendInput(s string) (*BackendInput, error) {
 	parts := strings.SplitN(s, "=", 2)
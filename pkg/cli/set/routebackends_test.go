@@ -0,0 +1,449 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/cli-runtime/pkg/resource"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	kcmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/openshift/api/route"
+	routev1 "github.com/openshift/api/route/v1"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestBackendTransformZeroBackend(t *testing.T) {
+	route := &routev1.Route{
+		Spec: routev1.RouteSpec{
+			To: routev1.RouteTargetReference{Kind: "Service", Name: "prod", Weight: int32Ptr(90)},
+			AlternateBackends: []routev1.RouteTargetReference{
+				{Kind: "Service", Name: "canary", Weight: int32Ptr(10)},
+				{Kind: "Service", Name: "other", Weight: int32Ptr(5)},
+			},
+		},
+	}
+	transform := BackendTransform{ZeroBackend: "canary"}
+	if err := transform.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if _, err := UpdateBackendsForObject(route, transform.Apply); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if route.Spec.To.Name != "prod" || *route.Spec.To.Weight != 90 {
+		t.Errorf("unexpected primary backend: %#v", route.Spec.To)
+	}
+	if len(route.Spec.AlternateBackends) != 2 {
+		t.Fatalf("expected zeroed backend to remain attached: %#v", route.Spec.AlternateBackends)
+	}
+	if b := route.Spec.AlternateBackends[0]; b.Name != "canary" || *b.Weight != 0 {
+		t.Errorf("expected canary to have zero weight: %#v", b)
+	}
+	if b := route.Spec.AlternateBackends[1]; b.Name != "other" || *b.Weight != 5 {
+		t.Errorf("unexpected alternate backend: %#v", b)
+	}
+}
+
+func TestBackendTransformZeroBackendMissing(t *testing.T) {
+	route := &routev1.Route{
+		Spec: routev1.RouteSpec{
+			To: routev1.RouteTargetReference{Kind: "Service", Name: "prod", Weight: int32Ptr(100)},
+		},
+	}
+	transform := BackendTransform{ZeroBackend: "canary"}
+	if _, err := UpdateBackendsForObject(route, transform.Apply); err == nil {
+		t.Fatalf("expected an error for a backend not on the route")
+	}
+	if *route.Spec.To.Weight != 100 {
+		t.Errorf("unexpected change to primary backend: %#v", route.Spec.To)
+	}
+}
+
+func TestBackendTransformZeroBackendValidate(t *testing.T) {
+	tests := []BackendTransform{
+		{ZeroBackend: "a", Adjust: true, Inputs: []BackendInput{{Name: "a", Value: 1}}},
+		{ZeroBackend: "a", Equal: true},
+		{ZeroBackend: "a", Inputs: []BackendInput{{Name: "b", Value: 1}}},
+	}
+	for _, test := range tests {
+		if err := test.Validate(); err == nil {
+			t.Errorf("expected validation error for %#v", test)
+		}
+	}
+}
+
+func TestBackendTransformEqualServices(t *testing.T) {
+	route := &routev1.Route{
+		Spec: routev1.RouteSpec{
+			To: routev1.RouteTargetReference{Kind: "Service", Name: "prod", Weight: int32Ptr(90)},
+			AlternateBackends: []routev1.RouteTargetReference{
+				{Kind: "Service", Name: "canary", Weight: int32Ptr(10)},
+			},
+			TLS: &routev1.TLSConfig{Termination: routev1.TLSTerminationEdge},
+		},
+	}
+	transform := BackendTransform{Equal: true, Services: []string{"a", "b", "c"}}
+	if err := transform.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+	if _, err := UpdateBackendsForObject(route, transform.Apply); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	backends := append([]routev1.RouteTargetReference{route.Spec.To}, route.Spec.AlternateBackends...)
+	expected := []struct {
+		name   string
+		weight int32
+	}{{"a", 34}, {"b", 33}, {"c", 33}}
+	if len(backends) != len(expected) {
+		t.Fatalf("expected existing backends to be replaced: %#v", backends)
+	}
+	for i, e := range expected {
+		if b := backends[i]; b.Kind != "Service" || b.Name != e.name || b.Weight == nil || *b.Weight != e.weight {
+			t.Errorf("expected %s=%d, got %#v", e.name, e.weight, b)
+		}
+	}
+	if route.Spec.TLS == nil || route.Spec.TLS.Termination != routev1.TLSTerminationEdge {
+		t.Errorf("expected TLS config to be preserved: %#v", route.Spec.TLS)
+	}
+}
+
+func TestBackendTransformEqualServicesValidate(t *testing.T) {
+	tests := []BackendTransform{
+		{Equal: true, Services: []string{"a", "a"}},
+		{Equal: true, Services: []string{"a", "b", "c", "d", "e"}},
+		{Equal: true, Services: []string{"a"}, Inputs: []BackendInput{{Name: "b", Value: 1}}},
+	}
+	for _, test := range tests {
+		if err := test.Validate(); err == nil {
+			t.Errorf("expected validation error for %#v", test)
+		}
+	}
+}
+
+func TestParseBackendService(t *testing.T) {
+	tests := map[string]string{
+		"svc/web":     "web",
+		"service/web": "web",
+	}
+	for in, expected := range tests {
+		name, err := parseBackendService(in)
+		if err != nil || name != expected {
+			t.Errorf("%s: expected %q, got %q (%v)", in, expected, name, err)
+		}
+	}
+	for _, in := range []string{"web", "deployment/web", "pod/web", "service/Web_1", "service/"} {
+		if _, err := parseBackendService(in); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
+
+func TestCheckServicesExist(t *testing.T) {
+	client := kubefake.NewSimpleClientset(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "a"}})
+	infos := []*resource.Info{{Namespace: "test", Name: "web"}, {Namespace: "other", Name: "web"}}
+
+	o := &BackendsOptions{ServiceClient: client.CoreV1(), Transform: BackendTransform{Equal: true, Services: []string{"a"}}}
+	if err := o.checkServicesExist(infos[:1]); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := o.checkServicesExist(infos); err == nil || err.Error() != `service "a" does not exist in namespace "other"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+	o.Transform.Services = []string{"a", "b"}
+	if err := o.checkServicesExist(infos[:1]); err == nil || err.Error() != `service "b" does not exist in namespace "test"` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRouteBackendsLocal(t *testing.T) {
+	routeScheme := runtime.NewScheme()
+	utilruntime.Must(route.Install(routeScheme))
+	dir, err := ioutil.TempDir("", "route-backends")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "route.yaml")
+	if err := ioutil.WriteFile(filename, []byte(`apiVersion: route.openshift.io/v1
+kind: Route
+metadata:
+  name: web
+spec:
+  to:
+    kind: Service
+    name: prod
+    weight: 100
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		err      string
+	}{
+		{name: "weights", args: []string{"prod=90", "canary=10"}, expected: []string{"name: prod\n    weight: 90", "name: canary\n    weight: 10"}},
+		{name: "equal services", args: []string{"--equal", "service/a", "svc/b"}, expected: []string{"name: a\n    weight: 50", "name: b\n    weight: 50"}},
+		{name: "equal without service kind", args: []string{"--equal", "a"}, err: "services must be given as service/a"},
+		{name: "missing backend", args: []string{"--zero-backend=missing"}, err: `backend "missing" is not in the list of backends (prod)`},
+		{name: "weight too large", args: []string{"prod=300"}, err: `the weight of backend "prod" must be between 0 and 256`},
+		{name: "route name", args: []string{"web", "prod=1"}, err: "route names may not be given with --local"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tf := kcmdtesting.NewTestFactory().WithNamespace("test")
+			defer tf.Cleanup()
+
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := NewBackendsOptions(streams)
+			o.scheme = routeScheme
+			o.PrintFlags.TypeSetterPrinter = printers.NewTypeSetter(routeScheme)
+			cmd := newCmdRouteBackends(tf, o)
+			if err := cmd.ParseFlags(append([]string{"--local", "-f", filename, "-o", "yaml"}, test.args...)); err != nil {
+				t.Fatal(err)
+			}
+
+			err := o.Complete(tf, cmd, cmd.Flags().Args())
+			if err == nil {
+				err = o.Validate()
+			}
+			if err == nil {
+				err = o.Run()
+			}
+			if len(test.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected error containing %q, got %v", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, expected := range test.expected {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRouteBackendsCanary(t *testing.T) {
+	newRoute := func() *routev1.Route {
+		return &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "web"},
+			Spec: routev1.RouteSpec{
+				To:                routev1.RouteTargetReference{Kind: "Service", Name: "prod", Weight: int32Ptr(100)},
+				AlternateBackends: []routev1.RouteTargetReference{{Kind: "Service", Name: "canary", Weight: int32Ptr(0)}},
+			},
+		}
+	}
+	endpoints := func(ready, notReady int) *corev1.Endpoints {
+		subset := corev1.EndpointSubset{}
+		for i := 0; i < ready; i++ {
+			subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: fmt.Sprintf("10.0.0.%d", i)})
+		}
+		for i := 0; i < notReady; i++ {
+			subset.NotReadyAddresses = append(subset.NotReadyAddresses, corev1.EndpointAddress{IP: fmt.Sprintf("10.0.1.%d", i)})
+		}
+		return &corev1.Endpoints{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test", Name: "canary"},
+			Subsets:    []corev1.EndpointSubset{subset},
+		}
+	}
+
+	unavailable := kapierrors.NewServiceUnavailable("unavailable")
+	tests := []struct {
+		name string
+		// check returns the endpoints of the canary at the nth health check, starting from 1
+		check func(n int) (*corev1.Endpoints, error)
+		// cancelAfter interrupts the canary after that many health checks
+		cancelAfter     int
+		expectedWeights []int32
+		expectedErr     string
+	}{
+		{
+			name:            "progresses to max",
+			check:           func(n int) (*corev1.Endpoints, error) { return endpoints(1, 0), nil },
+			expectedWeights: []int32{20, 40, 50},
+		},
+		{
+			name:            "enough ready endpoints",
+			check:           func(n int) (*corev1.Endpoints, error) { return endpoints(4, 1), nil },
+			expectedWeights: []int32{20, 40, 50},
+		},
+		{
+			name:        "unhealthy before the first step",
+			check:       func(n int) (*corev1.Endpoints, error) { return endpoints(1, 1), nil },
+			expectedErr: "no traffic was shifted",
+		},
+		{
+			name: "aborts and restores",
+			check: func(n int) (*corev1.Endpoints, error) {
+				if n > 2 {
+					return endpoints(3, 1), nil
+				}
+				return endpoints(1, 0), nil
+			},
+			expectedWeights: []int32{20, 0},
+			expectedErr:     "canary aborted: 3 of 4 endpoints of service canary are ready, at least 80% must be",
+		},
+		{
+			name: "retries transient errors",
+			check: func(n int) (*corev1.Endpoints, error) {
+				if n == 2 {
+					return nil, unavailable
+				}
+				return endpoints(1, 0), nil
+			},
+			expectedWeights: []int32{20, 40, 50},
+		},
+		{
+			name: "aborts on repeated errors",
+			check: func(n int) (*corev1.Endpoints, error) {
+				if n > 1 {
+					return nil, unavailable
+				}
+				return endpoints(1, 0), nil
+			},
+			expectedWeights: []int32{20, 0},
+			expectedErr:     "canary aborted: unable to check the health of the canary",
+		},
+		{
+			name: "aborts on permanent errors",
+			check: func(n int) (*corev1.Endpoints, error) {
+				if n > 1 {
+					return nil, kapierrors.NewForbidden(corev1.Resource("endpoints"), "canary", fmt.Errorf("denied"))
+				}
+				return endpoints(1, 0), nil
+			},
+			expectedWeights: []int32{20, 0},
+			expectedErr:     "canary aborted: unable to check the health of the canary",
+		},
+		{
+			name:            "restores when interrupted",
+			check:           func(n int) (*corev1.Endpoints, error) { return endpoints(1, 0), nil },
+			cancelAfter:     3,
+			expectedWeights: []int32{20, 0},
+			expectedErr:     "canary aborted: interrupted",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			routeClient := routefake.NewSimpleClientset(newRoute())
+			kubeClient := kubefake.NewSimpleClientset()
+			checks := 0
+			kubeClient.PrependReactor("get", "endpoints", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				checks++
+				if checks == test.cancelAfter {
+					cancel()
+				}
+				ep, err := test.check(checks)
+				if err != nil {
+					return true, nil, err
+				}
+				return true, ep, nil
+			})
+
+			streams, _, out, _ := genericclioptions.NewTestIOStreams()
+			o := NewBackendsOptions(streams)
+			o.canary = true
+			o.Namespace = "test"
+			o.Resources = []string{"web"}
+			o.Canary.Step, o.Canary.Max = 20, 50
+			o.Canary.Interval, o.Canary.CheckPeriod = 10*time.Millisecond, time.Millisecond
+			o.Canary.RouteClient = routeClient.RouteV1()
+			o.Canary.EndpointsClient = kubeClient.CoreV1()
+			o.Printer = printers.NewDiscardingPrinter()
+
+			if err := o.Validate(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err := o.progressCanary(ctx)
+			if len(test.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.expectedErr) {
+					t.Fatalf("expected error containing %q, got %v", test.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v\n%s", err, out.String())
+			}
+
+			var weights []int32
+			for _, action := range routeClient.Actions() {
+				update, ok := action.(clienttesting.UpdateAction)
+				if !ok {
+					continue
+				}
+				route := update.GetObject().(*routev1.Route)
+				canary := *route.Spec.AlternateBackends[0].Weight
+				if *route.Spec.To.Weight+canary != 100 {
+					t.Errorf("unexpected weights: %d and %d", *route.Spec.To.Weight, canary)
+				}
+				weights = append(weights, canary)
+			}
+			if !reflect.DeepEqual(weights, test.expectedWeights) {
+				t.Errorf("expected canary weights %v, got %v", test.expectedWeights, weights)
+			}
+		})
+	}
+}
+
+func TestRouteBackendsCanaryValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(o *BackendsOptions)
+		err    string
+	}{
+		{name: "valid", modify: func(o *BackendsOptions) {}},
+		{name: "canary flags without --canary", modify: func(o *BackendsOptions) { o.canary, o.canaryFlags = false, []string{"--step"} }, err: "--step may only be specified with --canary"},
+		{name: "with weights", modify: func(o *BackendsOptions) { o.Transform.Inputs = []BackendInput{{Name: "a", Value: 1}} }, err: "--canary may not be specified with other changes"},
+		{name: "several routes", modify: func(o *BackendsOptions) { o.Resources = append(o.Resources, "api") }, err: "--canary requires the name of a single route"},
+		{name: "dry run", modify: func(o *BackendsOptions) { o.DryRunStrategy = kcmdutil.DryRunClient }, err: "--canary may not be specified with --dry-run"},
+		{name: "step too large", modify: func(o *BackendsOptions) { o.Canary.Step = 101 }, err: "--step must be between 1 and 100"},
+		{name: "no interval", modify: func(o *BackendsOptions) { o.Canary.Interval = 0 }, err: "--interval must be greater than 0"},
+		{name: "min ready too small", modify: func(o *BackendsOptions) { o.Canary.MinReady = 0 }, err: "--min-ready must be between 1 and 100"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o := NewBackendsOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.canary = true
+			o.Resources = []string{"web"}
+			test.modify(o)
+			err := o.Validate()
+			if len(test.err) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected error containing %q, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/kubectl/pkg/util/interrupt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	routev1client "github.com/openshift/client-go/route/clientset/versioned/typed/route/v1"
+)
+
+// CanaryOptions describe how traffic is shifted from the primary backend of a route to its first
+// alternate backend.
+type CanaryOptions struct {
+	// Step is the share of the traffic, in percent, moved to the canary at each step.
+	Step int32
+	// Interval is how long the canary is watched after each step.
+	Interval time.Duration
+	// Max is the share of the traffic, in percent, at which the progression stops.
+	Max int32
+	// MinReady is the share of the endpoints of the canary, in percent, that must be ready.
+	MinReady int32
+
+	// CheckPeriod is how often the health of the canary is checked during an interval.
+	CheckPeriod time.Duration
+
+	RouteClient     routev1client.RoutesGetter
+	EndpointsClient corev1client.EndpointsGetter
+}
+
+// Validate returns an error if the steps of the progression are invalid.
+func (c *CanaryOptions) Validate() error {
+	if c.Step < 1 || c.Step > 100 {
+		return fmt.Errorf("--step must be between 1 and 100")
+	}
+	if c.Max < 1 || c.Max > 100 {
+		return fmt.Errorf("--max must be between 1 and 100")
+	}
+	if c.MinReady < 1 || c.MinReady > 100 {
+		return fmt.Errorf("--min-ready must be between 1 and 100")
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("--interval must be greater than 0")
+	}
+	return nil
+}
+
+// canaryMaxAPIErrors is how many consecutive health checks of the canary may fail because the
+// server could not be reached before the canary is aborted.
+const canaryMaxAPIErrors = 3
+
+// canaryBackoff is used to retry changing the backends of the route on transient errors.
+var canaryBackoff = wait.Backoff{Steps: 5, Duration: time.Second, Factor: 2, Jitter: 0.1}
+
+// runCanary runs the progression of the canary. An interrupt stops the progression and restores
+// the original backends instead of exiting.
+func (o *BackendsOptions) runCanary() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return interrupt.New(func(os.Signal) {}, cancel).Run(func() error {
+		return o.progressCanary(ctx)
+	})
+}
+
+// progressCanary shifts the traffic of the route to its canary until it reaches Max, watching the
+// health of the route and of the canary for an interval after each step. If the canary becomes
+// unhealthy or ctx is cancelled the original backends are restored.
+func (o *BackendsOptions) progressCanary(ctx context.Context) error {
+	c := o.Canary
+	var route *routev1.Route
+	err := retry.OnError(canaryBackoff, isTransientError, func() (err error) {
+		route, err = c.RouteClient.Routes(o.Namespace).Get(context.TODO(), o.Resources[0], metav1.GetOptions{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(route.Spec.AlternateBackends) == 0 {
+		return fmt.Errorf("route/%s has no alternate backend to shift traffic to", route.Name)
+	}
+	primary, canary := route.Spec.To, route.Spec.AlternateBackends[0]
+	if canary.Kind != "Service" {
+		return fmt.Errorf("the canary of route/%s must be a service, not a %s", route.Name, canary.Kind)
+	}
+	original := route.DeepCopy()
+
+	current := int32(0)
+	if total := backendWeight(primary) + backendWeight(canary); total > 0 {
+		current = backendWeight(canary) * 100 / total
+	}
+	if current >= c.Max {
+		fmt.Fprintf(o.Out, "route/%s already sends %d%% of the traffic to %s\n", route.Name, current, canary.Name)
+		return nil
+	}
+	endpoints, err := o.getCanaryEndpoints(canary.Name)
+	if err != nil {
+		return err
+	}
+	if err := o.checkCanary(route, endpoints, canary.Name); err != nil {
+		return fmt.Errorf("the canary is not healthy, no traffic was shifted: %v", err)
+	}
+
+	abort := func(err error) error {
+		if restoreErr := o.restoreBackends(original); restoreErr != nil {
+			return fmt.Errorf("canary aborted: %v, and the backends of route/%s could not be restored: %v", err, route.Name, restoreErr)
+		}
+		return fmt.Errorf("canary aborted: %v, the backends of route/%s were restored", err, route.Name)
+	}
+	for current < c.Max {
+		if ctx.Err() != nil {
+			return abort(fmt.Errorf("interrupted"))
+		}
+		current += c.Step
+		if current > c.Max {
+			current = c.Max
+		}
+		route, err = o.updateCanaryWeights(route.Name, primary.Name, canary.Name, current)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "route/%s sends %d%% of the traffic to %s\n", route.Name, current, canary.Name)
+
+		if err := o.watchCanary(ctx, route.Name, canary.Name); err != nil {
+			return abort(err)
+		}
+	}
+	return o.Printer.PrintObj(route, o.Out)
+}
+
+// backendWeight returns the weight of a backend, which defaults to 100 when unset.
+func backendWeight(ref routev1.RouteTargetReference) int32 {
+	if ref.Weight == nil {
+		return 100
+	}
+	return *ref.Weight
+}
+
+// updateCanaryWeights splits a weight of 100 between the primary backend and the canary, which
+// receives percent of it. The weights of the other alternate backends are left unchanged.
+func (o *BackendsOptions) updateCanaryWeights(name, primary, canary string, percent int32) (*routev1.Route, error) {
+	var updated *routev1.Route
+	err := retry.OnError(canaryBackoff, isTransientError, func() error {
+		route, err := o.Canary.RouteClient.Routes(o.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if route.Spec.To.Name != primary || len(route.Spec.AlternateBackends) == 0 || route.Spec.AlternateBackends[0].Name != canary {
+			return fmt.Errorf("the backends of route/%s were changed during the canary", name)
+		}
+		primaryWeight, canaryWeight := 100-percent, percent
+		route.Spec.To.Weight = &primaryWeight
+		route.Spec.AlternateBackends[0].Weight = &canaryWeight
+		updated, err = o.Canary.RouteClient.Routes(o.Namespace).Update(context.TODO(), route, metav1.UpdateOptions{FieldManager: o.FieldManager})
+		return err
+	})
+	return updated, err
+}
+
+// restoreBackends sets the backends of the route back to those of original.
+func (o *BackendsOptions) restoreBackends(original *routev1.Route) error {
+	return retry.OnError(canaryBackoff, isTransientError, func() error {
+		route, err := o.Canary.RouteClient.Routes(o.Namespace).Get(context.TODO(), original.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		route.Spec.To = original.Spec.To
+		route.Spec.AlternateBackends = original.Spec.AlternateBackends
+		_, err = o.Canary.RouteClient.Routes(o.Namespace).Update(context.TODO(), route, metav1.UpdateOptions{FieldManager: o.FieldManager})
+		return err
+	})
+}
+
+// isTransientError returns true if the request may succeed when it is retried.
+func isTransientError(err error) bool {
+	return kapierrors.IsConflict(err) || kapierrors.IsServerTimeout(err) || kapierrors.IsTimeout(err) ||
+		kapierrors.IsTooManyRequests(err) || kapierrors.IsServiceUnavailable(err) || kapierrors.IsInternalError(err) ||
+		utilnet.IsConnectionReset(err) || utilnet.IsConnectionRefused(err) || utilnet.IsProbableEOF(err)
+}
+
+// watchCanary checks the health of the route and of the canary until the interval elapses, and
+// returns the first problem found. Transient errors reaching the server are retried at the next
+// check, up to canaryMaxAPIErrors in a row.
+func (o *BackendsOptions) watchCanary(ctx context.Context, name, canary string) error {
+	intervalCtx, cancel := context.WithTimeout(ctx, o.Canary.Interval)
+	defer cancel()
+	apiErrors := 0
+	err := wait.PollImmediateUntil(o.Canary.CheckPeriod, func() (bool, error) {
+		route, err := o.Canary.RouteClient.Routes(o.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		var endpoints *corev1.Endpoints
+		if err == nil {
+			endpoints, err = o.getCanaryEndpoints(canary)
+		}
+		if err != nil {
+			apiErrors++
+			if !isTransientError(err) || apiErrors >= canaryMaxAPIErrors {
+				return false, fmt.Errorf("unable to check the health of the canary: %v", err)
+			}
+			fmt.Fprintf(o.ErrOut, "warning: unable to check the health of the canary, retrying: %v\n", err)
+			return false, nil
+		}
+		apiErrors = 0
+		// the canary is healthy as long as no problem is found, so the poll is expected to time out
+		return false, o.checkCanary(route, endpoints, canary)
+	}, intervalCtx.Done())
+	switch {
+	case err != wait.ErrWaitTimeout:
+		return err
+	case ctx.Err() != nil:
+		return fmt.Errorf("interrupted")
+	}
+	return nil
+}
+
+// getCanaryEndpoints returns the endpoints of the canary service, or nil if it has none.
+func (o *BackendsOptions) getCanaryEndpoints(canary string) (*corev1.Endpoints, error) {
+	endpoints, err := o.Canary.EndpointsClient.Endpoints(o.Namespace).Get(context.TODO(), canary, metav1.GetOptions{})
+	if kapierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return endpoints, err
+}
+
+// checkCanary returns an error if a router rejected the route, or if less than MinReady percent
+// of the endpoints of the canary service are ready.
+func (o *BackendsOptions) checkCanary(route *routev1.Route, endpoints *corev1.Endpoints, canary string) error {
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type == routev1.RouteAdmitted && condition.Status == corev1.ConditionFalse {
+				return fmt.Errorf("router %s rejected route/%s: %s", ingress.RouterName, route.Name, condition.Message)
+			}
+		}
+	}
+
+	if endpoints == nil {
+		return fmt.Errorf("service %s has no endpoints", canary)
+	}
+	ready, notReady := 0, 0
+	for _, subset := range endpoints.Subsets {
+		ready += len(subset.Addresses)
+		notReady += len(subset.NotReadyAddresses)
+	}
+	switch {
+	case ready == 0:
+		return fmt.Errorf("service %s has no ready endpoints", canary)
+	case ready*100 < int(o.Canary.MinReady)*(ready+notReady):
+		return fmt.Errorf("%d of %d endpoints of service %s are ready, at least %d%% must be", ready, ready+notReady, canary, o.Canary.MinReady)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package set
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	kcmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+// ServiceAccountValidationOptions checks the service account set on workloads before they are
+// changed, so that pods are not created with a service account that is missing or that cannot
+// pull images.
+type ServiceAccountValidationOptions struct {
+	ValidateExists   bool
+	EnsurePullSecret bool
+
+	// Name is the service account set on the workloads.
+	Name string
+	// Namespaces are the namespaces of the workloads.
+	Namespaces []string
+
+	Client corev1client.ServiceAccountsGetter
+
+	genericclioptions.IOStreams
+}
+
+// withServiceAccountValidation adds the --validate-exists and --ensure-pull-secret flags to the
+// serviceaccount command.
+func withServiceAccountValidation(cmd *cobra.Command, f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &ServiceAccountValidationOptions{IOStreams: streams}
+	cmd.Flags().BoolVar(&o.ValidateExists, "validate-exists", o.ValidateExists, "If true, fail unless the service account exists in the namespace of every resource. Contacts the server, also with --local.")
+	cmd.Flags().BoolVar(&o.EnsurePullSecret, "ensure-pull-secret", o.EnsurePullSecret, "If true, warn when the service account has no image pull secrets. Contacts the server, also with --local.")
+
+	run := cmd.Run
+	cmd.Run = func(c *cobra.Command, args []string) {
+		if !o.ValidateExists && !o.EnsurePullSecret {
+			run(c, args)
+			return
+		}
+		cleanup, err := bufferStdin(c.Flags())
+		kcmdutil.CheckErr(err)
+		defer cleanup()
+		kcmdutil.CheckErr(o.Complete(f, c, args))
+		kcmdutil.CheckErr(o.Run())
+		run(c, args)
+	}
+	return cmd
+}
+
+func (o *ServiceAccountValidationOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("serviceaccount is required")
+	}
+	o.Name = args[len(args)-1]
+	resources := args[:len(args)-1]
+
+	namespace, enforceNamespace, err := f.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return err
+	}
+	namespaces := sets.NewString()
+	if len(resources) > 0 && !kcmdutil.GetFlagBool(cmd, "local") {
+		namespaces.Insert(namespace)
+	}
+
+	// the namespaces of the resources in files are read without contacting the server
+	fileOptions := &resource.FilenameOptions{
+		Filenames: kcmdutil.GetFlagStringSlice(cmd, "filename"),
+		Recursive: kcmdutil.GetFlagBool(cmd, "recursive"),
+		Kustomize: kcmdutil.GetFlagString(cmd, "kustomize"),
+	}
+	if len(fileOptions.Filenames) > 0 || len(fileOptions.Kustomize) > 0 {
+		infos, err := f.NewBuilder().
+			WithScheme(scheme.Scheme, scheme.Scheme.PrioritizedVersionsAllGroups()...).
+			Local().
+			ContinueOnError().
+			NamespaceParam(namespace).DefaultNamespace().
+			FilenameParam(enforceNamespace, fileOptions).
+			Flatten().
+			Do().Infos()
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			namespaces.Insert(info.Namespace)
+		}
+	}
+	o.Namespaces = namespaces.List()
+
+	client, err := f.KubernetesClientSet()
+	if err != nil {
+		return err
+	}
+	o.Client = client.CoreV1()
+	return nil
+}
+
+// Run checks the service account in the namespace of every resource.
+func (o *ServiceAccountValidationOptions) Run() error {
+	var errs []error
+	for _, namespace := range o.Namespaces {
+		sa, err := o.Client.ServiceAccounts(namespace).Get(context.TODO(), o.Name, metav1.GetOptions{})
+		switch {
+		case kapierrors.IsNotFound(err):
+			if o.ValidateExists {
+				errs = append(errs, fmt.Errorf("service account %q does not exist in namespace %q", o.Name, namespace))
+				continue
+			}
+			fmt.Fprintf(o.ErrOut, "warning: service account %q does not exist in namespace %q\n", o.Name, namespace)
+			continue
+		case err != nil:
+			return err
+		}
+		if o.EnsurePullSecret && len(sa.ImagePullSecrets) == 0 {
+			fmt.Fprintf(o.ErrOut, "warning: service account %q in namespace %q has no image pull secrets, pods using it may be unable to pull images\n", o.Name, namespace)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(kcmdutil.MultipleErrors("error: ", errs))
+	}
+	return nil
+}
+
+// bufferStdin copies standard input to a temporary file and replaces the "-" filename with it,
+// so that resources passed on standard input can be read more than once.
+func bufferStdin(flags *pflag.FlagSet) (func(), error) {
+	flag := flags.Lookup("filename")
+	if flag == nil {
+		return func() {}, nil
+	}
+	value, ok := flag.Value.(pflag.SliceValue)
+	if !ok {
+		return func() {}, nil
+	}
+	filenames := value.GetSlice()
+	index := -1
+	for i, filename := range filenames {
+		if filename == "-" {
+			index = i
+		}
+	}
+	if index == -1 {
+		return func() {}, nil
+	}
+
+	f, err := ioutil.TempFile("", "set-serviceaccount")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	_, err = io.Copy(f, os.Stdin)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	filenames[index] = f.Name()
+	if err := value.Replace(filenames); err != nil {
+		cleanup()
+		return nil, err
+	}
+	return cleanup, nil
+}
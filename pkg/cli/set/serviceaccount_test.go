@@ -0,0 +1,126 @@
+package set
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	restfake "k8s.io/client-go/rest/fake"
+	kcmdtesting "k8s.io/kubectl/pkg/cmd/testing"
+)
+
+func TestServiceAccountValidation(t *testing.T) {
+	withPullSecret := &corev1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: "builder", Namespace: "one"},
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "builder-dockercfg"}},
+	}
+	withoutPullSecret := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "two"},
+	}
+
+	tests := []struct {
+		name             string
+		validateExists   bool
+		ensurePullSecret bool
+		namespaces       []string
+		expectErr        string
+		expectWarning    string
+	}{
+		{
+			name:           "exists",
+			validateExists: true,
+			namespaces:     []string{"one", "two"},
+		},
+		{
+			name:           "missing",
+			validateExists: true,
+			namespaces:     []string{"one", "three"},
+			expectErr:      `service account "builder" does not exist in namespace "three"`,
+		},
+		{
+			name:             "missing pull secret",
+			ensurePullSecret: true,
+			namespaces:       []string{"one", "two"},
+			expectWarning:    `service account "builder" in namespace "two" has no image pull secrets`,
+		},
+		{
+			name:             "missing without validation",
+			ensurePullSecret: true,
+			namespaces:       []string{"three"},
+			expectWarning:    `service account "builder" does not exist in namespace "three"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			streams, _, _, errOut := genericclioptions.NewTestIOStreams()
+			o := &ServiceAccountValidationOptions{
+				ValidateExists:   test.validateExists,
+				EnsurePullSecret: test.ensurePullSecret,
+				Name:             "builder",
+				Namespaces:       test.namespaces,
+				Client:           fake.NewSimpleClientset(withPullSecret, withoutPullSecret).CoreV1(),
+				IOStreams:        streams,
+			}
+			err := o.Run()
+			if len(test.expectErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), test.expectErr) {
+					t.Fatalf("expected error %q, got %v", test.expectErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(errOut.String(), test.expectWarning) || (len(test.expectWarning) == 0 && errOut.Len() > 0) {
+				t.Errorf("expected warning %q, got %q", test.expectWarning, errOut.String())
+			}
+		})
+	}
+}
+
+func TestServiceAccountValidationNamespaces(t *testing.T) {
+	dir, err := ioutil.TempDir("", "set-serviceaccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "deployments.yaml")
+	deployments := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: one
+  namespace: one
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: two
+  namespace: two
+`
+	if err := ioutil.WriteFile(filename, []byte(deployments), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tf := kcmdtesting.NewTestFactory()
+	defer tf.Cleanup()
+	tf.Client = &restfake.RESTClient{}
+	cmd := NewCmdServiceAccount(tf, genericclioptions.NewTestIOStreamsDiscard())
+	cmd.Flags().Set("filename", filename)
+	cmd.Flags().Set("local", "true")
+
+	o := &ServiceAccountValidationOptions{}
+	if err := o.Complete(tf, cmd, []string{"builder"}); err != nil {
+		t.Fatal(err)
+	}
+	if o.Name != "builder" {
+		t.Errorf("unexpected service account %q", o.Name)
+	}
+	if !reflect.DeepEqual(o.Namespaces, []string{"one", "two"}) {
+		t.Errorf("unexpected namespaces %v", o.Namespaces)
+	}
+}
@@ -83,38 +83,6 @@ func NewCmdSet(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.C
 	return set
 }
 
-var (
-	setImageLong = ktemplates.LongDesc(`
-Update existing container image(s) of resources.`)
-
-	setImageExample = ktemplates.Examples(`
-	  # Set a deployment configs's nginx container image to 'nginx:1.9.1', and its busybox container image to 'busybox'.
-	  oc set image dc/nginx busybox=busybox nginx=nginx:1.9.1
-
-	  # Set a deployment configs's app container image to the image referenced by the imagestream tag 'openshift/ruby:2.3'.
-	  oc set image dc/myapp app=openshift/ruby:2.3 --source=imagestreamtag
-
-	  # Update all deployments' and rc's nginx container's image to 'nginx:1.9.1'
-	  oc set image deployments,rc nginx=nginx:1.9.1 --all
-
-	  # Update image of all containers of daemonset abc to 'nginx:1.9.1'
-	  oc set image daemonset abc *=nginx:1.9.1
-
-	  # Print result (in yaml format) of updating nginx container image from local file, without hitting the server
-	  oc set image -f path/to/file.yaml nginx=nginx:1.9.1 --local -o yaml`)
-)
-
-// NewCmdImage is a wrapper for the Kubernetes CLI set image command
-func NewCmdImage(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
-	cmd := set.NewCmdImage(f, streams)
-	cmd.Long = setImageLong
-	cmd.Example = setImageExample
-	cmd.Flags().String("source", "docker", "The image source type; valid types are 'imagestreamtag', 'istag', 'imagestreamimage', 'isimage', and 'docker'")
-	set.ImageResolver = resolveImageFactory(f, cmd)
-
-	return cmd
-}
-
 var (
 	setResourcesLong = ktemplates.LongDesc(`
 Specify compute resource requirements (cpu, memory) for any resource that defines a pod template. If a pod is successfully scheduled, it is guaranteed the amount of resource requested, but may burst up to its specified limits.
@@ -174,6 +142,10 @@ func NewCmdSelector(f kcmdutil.Factory, streams genericclioptions.IOStreams) *co
 var (
 	setServiceaccountLong = ktemplates.LongDesc(`
 Update ServiceAccount of pod template resources.
+
+Pass --validate-exists to fail if the service account does not exist in the namespace of the
+resources, and --ensure-pull-secret to warn if it has no image pull secrets. Both check the
+service account on the server before any resource is changed, also when --local is set.
 `)
 
 	setServiceaccountExample = ktemplates.Examples(`
@@ -182,6 +154,9 @@ oc set serviceaccount deployment nginx-deployment serviceaccount1
 
 # Print the result (in YAML format) of updated nginx deployment with service account from a local file, without hitting the API server
 oc set sa -f nginx-deployment.yaml serviceaccount1 --local --dry-run -o yaml
+
+# Set the service account of all deployments, failing if it does not exist and warning if it cannot pull images
+oc set sa deployments --all serviceaccount1 --validate-exists --ensure-pull-secret
 `)
 )
 
@@ -191,7 +166,7 @@ func NewCmdServiceAccount(f kcmdutil.Factory, streams genericclioptions.IOStream
 	cmd.Long = setServiceaccountLong
 	cmd.Example = setServiceaccountExample
 
-	return cmd
+	return withServiceAccountValidation(cmd, f, streams)
 }
 
 var (
@@ -3,6 +3,7 @@ package set
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strings"
 	"text/tabwriter"
@@ -32,10 +33,12 @@ import (
 
 	appsv1 "github.com/openshift/api/apps/v1"
 	buildv1 "github.com/openshift/api/build/v1"
+	buildv1client "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
 	"github.com/openshift/library-go/pkg/build/buildutil"
 	"github.com/openshift/library-go/pkg/image/reference"
 	ometa "github.com/openshift/library-go/pkg/image/referencemutator"
 	triggerutil "github.com/openshift/library-go/pkg/image/trigger"
+	buildhelpers "github.com/openshift/oc/pkg/helpers/build"
 	"github.com/openshift/oc/pkg/helpers/newapp/app"
 )
 
@@ -72,6 +75,12 @@ var (
 		oc set triggers bc/webapp --from-github
 		oc set triggers bc/webapp --from-webhook
 
+		# Add a GitLab webhook to a build that uses the secret 'gitlab-secret', and print its URL
+		oc set triggers bc/webapp --from-gitlab --secret=gitlab-secret
+
+		# Remove the generic webhooks that use the secret 'gitlab-secret'
+		oc set triggers bc/webapp --from-generic --secret=gitlab-secret --remove
+
 		# Remove all triggers
 		oc set triggers bc/webapp --remove-all
 
@@ -105,6 +114,9 @@ type TriggersOptions struct {
 	FromWebHookAllowEnv *bool
 	FromGitLab          *bool
 	FromBitbucket       *bool
+	// Secret is the name of the secret a webhook added with one of the --from-* flags
+	// references, instead of a generated secret value
+	Secret string
 	// FromImageNamespace is the namespace for the FromImage
 	FromImageNamespace string
 
@@ -116,6 +128,9 @@ type TriggersOptions struct {
 	DryRunStrategy    kcmdutil.DryRunStrategy
 	FieldManager      string
 	Args              []string
+	// WebHookURLs returns the URLs of the webhooks of a build config, set when they are printed
+	// after a webhook is added
+	WebHookURLs func(config *buildv1.BuildConfig) []string
 
 	resource.FilenameOptions
 	genericclioptions.IOStreams
@@ -156,9 +171,11 @@ func NewCmdTriggers(f kcmdutil.Factory, streams genericclioptions.IOStreams) *co
 	cmd.Flags().StringVar(&o.FromImage, "from-image", o.FromImage, "An image stream tag to trigger off of")
 	o.FromGitHub = cmd.Flags().Bool("from-github", false, "If true, a GitHub webhook - a secret value will be generated automatically")
 	o.FromWebHook = cmd.Flags().Bool("from-webhook", false, "If true, a generic webhook - a secret value will be generated automatically")
+	cmd.Flags().BoolVar(o.FromWebHook, "from-generic", false, "If true, a generic webhook - an alias of --from-webhook")
 	o.FromWebHookAllowEnv = cmd.Flags().Bool("from-webhook-allow-env", false, "If true, a generic webhook which can provide environment variables - a secret value will be generated automatically")
 	o.FromGitLab = cmd.Flags().Bool("from-gitlab", false, "If true, a GitLab webhook - a secret value will be generated automatically")
 	o.FromBitbucket = cmd.Flags().Bool("from-bitbucket", false, "If true, a Bitbucket webhook - a secret value will be generated automatically")
+	cmd.Flags().StringVar(&o.Secret, "secret", o.Secret, "The name of a secret holding the webhook secret in its WebHookSecretKey key, used by the webhook added with a --from-* flag instead of a generated value. With --remove, only the webhooks referencing this secret are removed.")
 
 	o.PrintFlags.AddFlags(cmd)
 	kcmdutil.AddDryRunFlag(cmd)
@@ -177,7 +194,7 @@ func (o *TriggersOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args
 	if !cmd.Flags().Lookup("from-github").Changed {
 		o.FromGitHub = nil
 	}
-	if !cmd.Flags().Lookup("from-webhook").Changed {
+	if !cmd.Flags().Lookup("from-webhook").Changed && !cmd.Flags().Lookup("from-generic").Changed {
 		o.FromWebHook = nil
 	}
 	if !cmd.Flags().Lookup("from-webhook-allow-env").Changed {
@@ -221,6 +238,20 @@ func (o *TriggersOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args
 	}
 	o.Builder = f.NewBuilder
 
+	if len(o.webHookTriggerType()) > 0 && !o.Remove && !o.Local && o.DryRunStrategy == kcmdutil.DryRunNone {
+		clientConfig, err := f.ToRESTConfig()
+		if err != nil {
+			return err
+		}
+		buildClient, err := buildv1client.NewForConfig(clientConfig)
+		if err != nil {
+			return err
+		}
+		o.WebHookURLs = func(config *buildv1.BuildConfig) []string {
+			return webHookURLs(buildhelpers.NewWebhookURLClient(buildClient.RESTClient(), config.Namespace), config, o.webHookTriggerType())
+		}
+	}
+
 	kcmdutil.PrintFlagsWithDryRunStrategy(o.PrintFlags, o.DryRunStrategy)
 	o.Printer, err = o.PrintFlags.ToPrinter()
 	if err != nil {
@@ -256,6 +287,22 @@ func (o *TriggersOptions) count() int {
 	return count
 }
 
+// webHookTriggerType returns the type of the webhook selected by the --from-* flags, or an
+// empty string when none is.
+func (o *TriggersOptions) webHookTriggerType() buildv1.BuildTriggerType {
+	switch {
+	case o.FromWebHook != nil && *o.FromWebHook, o.FromWebHookAllowEnv != nil && *o.FromWebHookAllowEnv:
+		return buildv1.GenericWebHookBuildTriggerType
+	case o.FromGitHub != nil && *o.FromGitHub:
+		return buildv1.GitHubWebHookBuildTriggerType
+	case o.FromGitLab != nil && *o.FromGitLab:
+		return buildv1.GitLabWebHookBuildTriggerType
+	case o.FromBitbucket != nil && *o.FromBitbucket:
+		return buildv1.BitbucketWebHookBuildTriggerType
+	}
+	return ""
+}
+
 func (o *TriggersOptions) Validate() error {
 	count := o.count()
 	switch {
@@ -272,6 +319,9 @@ func (o *TriggersOptions) Validate() error {
 	case count == 0 && !o.Remove && !o.RemoveAll && !o.Auto && !o.Manual && !o.PrintTable:
 		return fmt.Errorf("specify one of the --from-* flags to add a trigger, --remove to remove, or --auto|--manual to control existing triggers")
 	}
+	if len(o.Secret) > 0 && len(o.webHookTriggerType()) == 0 {
+		return fmt.Errorf("--secret requires one of --from-github, --from-gitlab, --from-bitbucket, --from-generic or --from-webhook-allow-env")
+	}
 	if o.Local && o.DryRunStrategy == kcmdutil.DryRunServer {
 		return fmt.Errorf("cannot specify --local and --dry-run=server - did you mean --dry-run=client?")
 	}
@@ -349,6 +399,11 @@ func (o *TriggersOptions) Run() error {
 		if err := o.Printer.PrintObj(actual, o.Out); err != nil {
 			allErrs = append(allErrs, err)
 		}
+		if config, ok := actual.(*buildv1.BuildConfig); ok && o.WebHookURLs != nil {
+			for _, webHookURL := range o.WebHookURLs(config) {
+				fmt.Fprintf(o.ErrOut, "Webhook URL for %s: %s\n", name, webHookURL)
+			}
+		}
 	}
 	return utilerrors.NewAggregate(allErrs)
 
@@ -427,19 +482,19 @@ func (o *TriggersOptions) updateTriggers(triggers *TriggerDefinition) {
 			triggers.ImageChange = newTriggers
 		}
 		if o.FromWebHook != nil && *o.FromWebHook {
-			triggers.GenericWebHooks = nil
+			triggers.GenericWebHooks = o.removeWebHooks(triggers.GenericWebHooks)
 		}
 		if o.FromWebHookAllowEnv != nil && *o.FromWebHookAllowEnv {
-			triggers.GenericWebHooks = nil
+			triggers.GenericWebHooks = o.removeWebHooks(triggers.GenericWebHooks)
 		}
 		if o.FromGitHub != nil && *o.FromGitHub {
-			triggers.GitHubWebHooks = nil
+			triggers.GitHubWebHooks = o.removeWebHooks(triggers.GitHubWebHooks)
 		}
 		if o.FromGitLab != nil && *o.FromGitLab {
-			triggers.GitLabWebHooks = nil
+			triggers.GitLabWebHooks = o.removeWebHooks(triggers.GitLabWebHooks)
 		}
 		if o.FromBitbucket != nil && *o.FromBitbucket {
-			triggers.BitbucketWebHooks = nil
+			triggers.BitbucketWebHooks = o.removeWebHooks(triggers.BitbucketWebHooks)
 		}
 		return
 	}
@@ -481,47 +536,75 @@ func (o *TriggersOptions) updateTriggers(triggers *TriggerDefinition) {
 		}
 	}
 	if o.FromWebHook != nil && *o.FromWebHook {
-		secret := app.GenerateSecret(20)
-		triggers.GenericWebHooks = append(triggers.GenericWebHooks,
-			buildv1.WebHookTrigger{
-				Secret:   secret,
-				AllowEnv: false,
-			},
-		)
+		triggers.GenericWebHooks = o.addWebHook(triggers.GenericWebHooks, false)
 	}
 	if o.FromWebHookAllowEnv != nil && *o.FromWebHookAllowEnv {
-		secret := app.GenerateSecret(20)
-		triggers.GenericWebHooks = append(triggers.GenericWebHooks,
-			buildv1.WebHookTrigger{
-				Secret:   secret,
-				AllowEnv: true,
-			},
-		)
+		triggers.GenericWebHooks = o.addWebHook(triggers.GenericWebHooks, true)
 	}
 	if o.FromGitHub != nil && *o.FromGitHub {
-		secret := app.GenerateSecret(20)
-		triggers.GitHubWebHooks = append(triggers.GitHubWebHooks,
-			buildv1.WebHookTrigger{
-				Secret: secret,
-			},
-		)
+		triggers.GitHubWebHooks = o.addWebHook(triggers.GitHubWebHooks, false)
 	}
 	if o.FromGitLab != nil && *o.FromGitLab {
-		secret := app.GenerateSecret(20)
-		triggers.GitLabWebHooks = append(triggers.GitLabWebHooks,
-			buildv1.WebHookTrigger{
-				Secret: secret,
-			},
-		)
+		triggers.GitLabWebHooks = o.addWebHook(triggers.GitLabWebHooks, false)
 	}
 	if o.FromBitbucket != nil && *o.FromBitbucket {
-		secret := app.GenerateSecret(20)
-		triggers.BitbucketWebHooks = append(triggers.BitbucketWebHooks,
-			buildv1.WebHookTrigger{
-				Secret: secret,
-			},
-		)
+		triggers.BitbucketWebHooks = o.addWebHook(triggers.BitbucketWebHooks, false)
+	}
+}
+
+// addWebHook appends a webhook that uses a generated secret value, or the secret named by
+// --secret unless a webhook already references it.
+func (o *TriggersOptions) addWebHook(hooks []buildv1.WebHookTrigger, allowEnv bool) []buildv1.WebHookTrigger {
+	if len(o.Secret) == 0 {
+		return append(hooks, buildv1.WebHookTrigger{
+			Secret:   app.GenerateSecret(20),
+			AllowEnv: allowEnv,
+		})
+	}
+	for i, hook := range hooks {
+		if hook.SecretReference != nil && hook.SecretReference.Name == o.Secret {
+			hooks[i].AllowEnv = allowEnv
+			return hooks
+		}
+	}
+	return append(hooks, buildv1.WebHookTrigger{
+		SecretReference: &buildv1.SecretLocalReference{Name: o.Secret},
+		AllowEnv:        allowEnv,
+	})
+}
+
+// removeWebHooks removes the webhooks that reference the secret named by --secret, or all of
+// them when it is not set.
+func (o *TriggersOptions) removeWebHooks(hooks []buildv1.WebHookTrigger) []buildv1.WebHookTrigger {
+	if len(o.Secret) == 0 {
+		return nil
+	}
+	var kept []buildv1.WebHookTrigger
+	for _, hook := range hooks {
+		if hook.SecretReference == nil || hook.SecretReference.Name != o.Secret {
+			kept = append(kept, hook)
+		}
+	}
+	return kept
+}
+
+// webHookURLs returns the URLs of the webhooks of the given type on a build config.
+func webHookURLs(client buildhelpers.WebHookURLInterface, config *buildv1.BuildConfig, triggerType buildv1.BuildTriggerType) []string {
+	var urls []string
+	for i := range config.Spec.Triggers {
+		trigger := &config.Spec.Triggers[i]
+		if trigger.Type != triggerType {
+			continue
+		}
+		u, err := client.WebHookURL(config.Name, trigger)
+		if err != nil {
+			klog.V(2).Infof("Unable to get the webhook URL of %s: %v", config.Name, err)
+			continue
+		}
+		urlStr, _ := url.PathUnescape(u.String())
+		urls = append(urls, urlStr)
 	}
+	return urls
 }
 
 // ImageChangeTrigger represents the capabilities present in deployment config and build
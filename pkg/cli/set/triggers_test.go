@@ -0,0 +1,91 @@
+package set
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+func TestUpdateTriggersWebHookSecret(t *testing.T) {
+	yes := true
+	ref := func(name string) buildv1.WebHookTrigger {
+		return buildv1.WebHookTrigger{SecretReference: &buildv1.SecretLocalReference{Name: name}}
+	}
+	tests := []struct {
+		name     string
+		options  TriggersOptions
+		existing []buildv1.WebHookTrigger
+		expected []buildv1.WebHookTrigger
+	}{
+		{
+			name:     "add",
+			options:  TriggersOptions{FromGitHub: &yes, Secret: "github", Auto: true},
+			existing: []buildv1.WebHookTrigger{ref("other")},
+			expected: []buildv1.WebHookTrigger{ref("other"), ref("github")},
+		},
+		{
+			name:     "add existing",
+			options:  TriggersOptions{FromGitHub: &yes, Secret: "github", Auto: true},
+			existing: []buildv1.WebHookTrigger{ref("github")},
+			expected: []buildv1.WebHookTrigger{ref("github")},
+		},
+		{
+			name:     "remove by secret",
+			options:  TriggersOptions{FromGitHub: &yes, Secret: "github", Remove: true},
+			existing: []buildv1.WebHookTrigger{ref("other"), ref("github")},
+			expected: []buildv1.WebHookTrigger{ref("other")},
+		},
+		{
+			name:     "remove all",
+			options:  TriggersOptions{FromGitHub: &yes, Remove: true},
+			existing: []buildv1.WebHookTrigger{ref("other"), ref("github")},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			triggers := &TriggerDefinition{GitHubWebHooks: test.existing}
+			test.options.updateTriggers(triggers)
+			if !reflect.DeepEqual(triggers.GitHubWebHooks, test.expected) {
+				t.Errorf("expected %#v, got %#v", test.expected, triggers.GitHubWebHooks)
+			}
+		})
+	}
+}
+
+func TestTriggersValidateSecret(t *testing.T) {
+	yes := true
+	if err := (&TriggersOptions{Secret: "github", FromConfig: true}).Validate(); err == nil {
+		t.Errorf("expected --secret without a webhook to be rejected")
+	}
+	if err := (&TriggersOptions{Secret: "github", FromGitLab: &yes}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type fakeWebHookURLs struct{}
+
+func (fakeWebHookURLs) WebHookURL(name string, trigger *buildv1.BuildTriggerPolicy) (*url.URL, error) {
+	return url.Parse("https://api.example.com/apis/build.openshift.io/v1/namespaces/test/buildconfigs/" + name + "/webhooks/<secret>/" + string(trigger.Type))
+}
+
+func TestWebHookURLs(t *testing.T) {
+	config := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "webapp"},
+		Spec: buildv1.BuildConfigSpec{
+			Triggers: []buildv1.BuildTriggerPolicy{
+				{Type: buildv1.ConfigChangeBuildTriggerType},
+				{Type: buildv1.GitHubWebHookBuildTriggerType, GitHubWebHook: &buildv1.WebHookTrigger{}},
+				{Type: buildv1.GitLabWebHookBuildTriggerType, GitLabWebHook: &buildv1.WebHookTrigger{}},
+			},
+		},
+	}
+	urls := webHookURLs(fakeWebHookURLs{}, config, buildv1.GitLabWebHookBuildTriggerType)
+	expected := []string{"https://api.example.com/apis/build.openshift.io/v1/namespaces/test/buildconfigs/webapp/webhooks/<secret>/GitLab"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("expected %v, got %v", expected, urls)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
@@ -22,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -64,6 +66,12 @@ var (
 			if your claim hasn't been bound, your pods will not start.
 		* secret (mounted secret): Secret volumes mount a named secret to the provided
 		  directory.
+		* csi (inline CSI volume): An ephemeral volume provided by the named CSI driver,
+		  configured with driver specific volume attributes.
+		* projected (projected volume): A single directory combining secrets, config maps,
+		  and bound service account tokens, each given with --source. A token source is
+		  written as serviceaccount-token with optional audience, expiration, and path
+		  settings, e.g. serviceaccount-token:audience=vault,expiration=1h.
 
 		For descriptions on other volume types, see https://docs.openshift.com`)
 
@@ -91,6 +99,14 @@ var (
 		# (and by removing the volume "v1" if no other containers have volume mounts that reference it)
 		oc set volume dc/myapp --remove --name=v1 --containers=c1
 
+		# Add an inline CSI volume provided by the secrets store CSI driver to deployment config 'myapp'
+		oc set volume dc/myapp --add -t csi -m /mnt/secrets --csi-driver=secrets-store.csi.k8s.io \
+		  --csi-volume-attribute=secretProviderClass=my-provider --read-only
+
+		# Add a projected volume combining a secret, a config map, and a bound service account token
+		oc set volume dc/myapp --add -t projected -m /var/run/app --source=secret/creds \
+		  --source=configmap/settings --source=serviceaccount-token:audience=vault,expiration=1h
+
 		# Add new volume based on a more complex volume source (AWS EBS, GCE PD,
 		# Ceph, Gluster, NFS, ISCSI, ...)
 		oc set volume dc/myapp --add -m /data --source=<json-string>
@@ -144,6 +160,12 @@ type AddVolumeOptions struct {
 	SecretName    string
 	Source        string
 
+	// ProjectedSources are the secret/NAME, configmap/NAME and serviceaccount-token
+	// sources combined into a projected volume.
+	ProjectedSources []string
+	// sources holds the raw --source values until Complete decides how they are used.
+	sources []string
+
 	ReadOnly    bool
 	CreateClaim bool
 	ClaimName   string
@@ -151,6 +173,9 @@ type AddVolumeOptions struct {
 	ClaimMode   string
 	ClaimClass  string
 
+	CSIDriver           string
+	CSIVolumeAttributes []string
+
 	TypeChanged  bool
 	ClassChanged bool
 }
@@ -192,7 +217,7 @@ func NewCmdVolume(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 	cmd.Flags().StringVarP(&o.Containers, "containers", "c", o.Containers, "The names of containers in the selected pod templates to change - may use wildcards")
 	cmd.Flags().BoolVar(&o.Confirm, "confirm", o.Confirm, "If true, confirm that you really want to remove multiple volumes")
 
-	cmd.Flags().StringVarP(&o.AddOpts.Type, "type", "t", o.AddOpts.Type, "Type of the volume source for add operation. Supported options: emptyDir, hostPath, secret, configmap, persistentVolumeClaim")
+	cmd.Flags().StringVarP(&o.AddOpts.Type, "type", "t", o.AddOpts.Type, "Type of the volume source for add operation. Supported options: emptyDir, hostPath, secret, configmap, persistentVolumeClaim, csi, projected")
 	cmd.Flags().StringVarP(&o.AddOpts.MountPath, "mount-path", "m", o.AddOpts.MountPath, "Mount path inside the container. Optional param for --add or --remove")
 	cmd.Flags().StringVar(&o.AddOpts.SubPath, "sub-path", o.AddOpts.SubPath, "Path within the local volume from which the container's volume should be mounted. Optional param for --add or --remove")
 	cmd.Flags().StringVar(&o.AddOpts.DefaultMode, "default-mode", o.AddOpts.DefaultMode, "The default mode bits to create files with. Can be between 0000 and 0777. Defaults to 0644.")
@@ -205,7 +230,9 @@ func NewCmdVolume(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobr
 	cmd.Flags().StringVar(&o.AddOpts.ClaimClass, "claim-class", o.AddOpts.ClaimClass, "StorageClass to use for the persistent volume claim")
 	cmd.Flags().StringVar(&o.AddOpts.ClaimSize, "claim-size", o.AddOpts.ClaimSize, "If specified along with a persistent volume type, create a new claim with the given size in bytes. Accepts SI notation: 10, 10G, 10Gi")
 	cmd.Flags().StringVar(&o.AddOpts.ClaimMode, "claim-mode", o.AddOpts.ClaimMode, "Set the access mode of the claim to be created. Valid values are ReadWriteOnce (rwo), ReadWriteMany (rwm), or ReadOnlyMany (rom)")
-	cmd.Flags().StringVar(&o.AddOpts.Source, "source", o.AddOpts.Source, "Details of volume source as json string. This can be used if the required volume type is not supported by --type option. (e.g.: '{\"nfs\": {\"path\": \"/tmp\",\"server\":\"172.17.0.2\"}}')")
+	cmd.Flags().StringVar(&o.AddOpts.CSIDriver, "csi-driver", o.AddOpts.CSIDriver, "Name of the CSI driver providing the volume. Must be provided for csi volume type")
+	cmd.Flags().StringArrayVar(&o.AddOpts.CSIVolumeAttributes, "csi-volume-attribute", o.AddOpts.CSIVolumeAttributes, "A driver specific attribute of the CSI volume in the form key=value. May be specified multiple times")
+	cmd.Flags().StringArrayVar(&o.AddOpts.sources, "source", o.AddOpts.sources, "Details of volume source as json string. This can be used if the required volume type is not supported by --type option. (e.g.: '{\"nfs\": {\"path\": \"/tmp\",\"server\":\"172.17.0.2\"}}'). With --type=projected, a source to project (secret/NAME, configmap/NAME, or serviceaccount-token[:audience=AUDIENCE,expiration=DURATION,path=PATH]); may be specified multiple times")
 
 	o.PrintFlags.AddFlags(cmd)
 	kcmdutil.AddDryRunFlag(cmd)
@@ -236,10 +263,10 @@ func (o *VolumeOptions) Validate() error {
 		if err != nil {
 			return err
 		}
-	} else if len(o.AddOpts.Source) > 0 || len(o.AddOpts.Path) > 0 || len(o.AddOpts.SecretName) > 0 ||
+	} else if len(o.AddOpts.Source) > 0 || len(o.AddOpts.ProjectedSources) > 0 || len(o.AddOpts.Path) > 0 || len(o.AddOpts.SecretName) > 0 ||
 		len(o.AddOpts.ConfigMapName) > 0 || len(o.AddOpts.ClaimName) > 0 || len(o.AddOpts.DefaultMode) > 0 ||
-		o.AddOpts.Overwrite {
-		return errors.New("--type|--path|--configmap-name|--secret-name|--claim-name|--source|--default-mode|--overwrite are only valid for --add operation")
+		len(o.AddOpts.CSIDriver) > 0 || len(o.AddOpts.CSIVolumeAttributes) > 0 || o.AddOpts.Overwrite {
+		return errors.New("--type|--path|--configmap-name|--secret-name|--claim-name|--csi-driver|--csi-volume-attribute|--source|--default-mode|--overwrite are only valid for --add operation")
 	}
 	// Removing all volumes for the resource type needs confirmation
 	if o.Remove && len(o.Name) == 0 && !o.Confirm {
@@ -284,11 +311,36 @@ func (a *AddVolumeOptions) Validate() error {
 			if len(a.ClaimName) == 0 && len(a.ClaimSize) == 0 {
 				return errors.New("must provide --claim-name or --claim-size (to create a new claim) for --type=pvc")
 			}
+		case "projected":
+			if len(a.ProjectedSources) == 0 {
+				return errors.New("must provide at least one --source for --type=projected")
+			}
+			if len(a.DefaultMode) > 0 {
+				if ok, _ := regexp.MatchString(`\b0?[0-7]{3}\b`, a.DefaultMode); !ok {
+					return errors.New("--default-mode must be between 0000 and 0777")
+				}
+			}
+			if _, err := parseVolumeProjections(a.ProjectedSources); err != nil {
+				return err
+			}
+		case "csi":
+			if len(a.CSIDriver) == 0 {
+				return errors.New("must provide --csi-driver for --type=csi")
+			}
+			if errs := validation.IsDNS1123Subdomain(strings.ToLower(a.CSIDriver)); len(errs) > 0 || len(a.CSIDriver) > 63 {
+				return fmt.Errorf("--csi-driver %q is not a valid CSI driver name", a.CSIDriver)
+			}
+			if _, err := parseCSIVolumeAttributes(a.CSIVolumeAttributes); err != nil {
+				return err
+			}
 		default:
-			return errors.New("invalid volume type. Supported types: emptyDir, hostPath, secret, persistentVolumeClaim")
+			return errors.New("invalid volume type. Supported types: emptyDir, hostPath, secret, configmap, persistentVolumeClaim, csi, projected")
 		}
-	} else if len(a.Path) > 0 || len(a.SecretName) > 0 || len(a.ClaimName) > 0 {
-		return errors.New("--path|--secret-name|--claim-name are only valid for --type option")
+	} else if len(a.Path) > 0 || len(a.SecretName) > 0 || len(a.ClaimName) > 0 || len(a.CSIDriver) > 0 {
+		return errors.New("--path|--secret-name|--claim-name|--csi-driver are only valid for --type option")
+	}
+	if len(a.CSIVolumeAttributes) > 0 && strings.ToLower(a.Type) != "csi" {
+		return errors.New("--csi-volume-attribute is only valid for --type=csi")
 	}
 
 	if len(a.Source) > 0 {
@@ -361,6 +413,17 @@ func (o *VolumeOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []
 	o.UpdatePodSpecForObject = polymorphichelpers.UpdatePodSpecForObjectFn
 
 	o.AddOpts.TypeChanged = cmd.Flag("type").Changed
+	if strings.ToLower(o.AddOpts.Type) == "projected" {
+		o.AddOpts.ProjectedSources = o.AddOpts.sources
+	} else {
+		switch len(o.AddOpts.sources) {
+		case 0:
+		case 1:
+			o.AddOpts.Source = o.AddOpts.sources[0]
+		default:
+			return errors.New("--source may only be specified more than once with --type=projected")
+		}
+	}
 	o.AddOpts.ClassChanged = cmd.Flag("claim-class").Changed
 
 	o.DryRunStrategy, err = kcmdutil.GetDryRunStrategy(cmd)
@@ -398,17 +461,20 @@ func (a *AddVolumeOptions) Complete() error {
 		case len(a.Path) > 0:
 			a.Type = "hostpath"
 			a.TypeChanged = true
+		case len(a.CSIDriver) > 0:
+			a.Type = "csi"
+			a.TypeChanged = true
 		default:
 			a.Type = "emptydir"
 		}
 	}
-	if a.Type == "configmap" || a.Type == "secret" {
+	if a.Type == "configmap" || a.Type == "secret" || strings.ToLower(a.Type) == "projected" {
 		if len(a.DefaultMode) == 0 {
 			a.DefaultMode = "644"
 		}
 	} else {
 		if len(a.DefaultMode) != 0 {
-			return errors.New("--default-mode is only available for secrets and configmaps")
+			return errors.New("--default-mode is only available for secrets, configmaps, and projected volumes")
 		}
 	}
 
@@ -613,12 +679,128 @@ func setVolumeSourceByType(kv *corev1.Volume, opts *AddVolumeOptions) error {
 		kv.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
 			ClaimName: opts.ClaimName,
 		}
+	case "csi":
+		attributes, err := parseCSIVolumeAttributes(opts.CSIVolumeAttributes)
+		if err != nil {
+			return err
+		}
+		readOnly := opts.ReadOnly
+		kv.CSI = &corev1.CSIVolumeSource{
+			Driver:           opts.CSIDriver,
+			ReadOnly:         &readOnly,
+			VolumeAttributes: attributes,
+		}
+	case "projected":
+		sources, err := parseVolumeProjections(opts.ProjectedSources)
+		if err != nil {
+			return err
+		}
+		kv.Projected = &corev1.ProjectedVolumeSource{
+			Sources: sources,
+		}
+		if len(opts.DefaultMode) > 0 {
+			defaultMode, err := strconv.ParseUint(opts.DefaultMode, 8, 32)
+			if err != nil {
+				return err
+			}
+			defaultMode32 := int32(defaultMode)
+			kv.Projected.DefaultMode = &defaultMode32
+		}
 	default:
 		return fmt.Errorf("invalid volume type: %s", opts.Type)
 	}
 	return nil
 }
 
+// parseVolumeProjections turns --source values into the sources of a projected volume.
+// Secrets and config maps are given as secret/NAME and configmap/NAME, and bound service
+// account tokens as serviceaccount-token with optional comma separated audience, expiration,
+// and path settings.
+func parseVolumeProjections(sources []string) ([]corev1.VolumeProjection, error) {
+	var projections []corev1.VolumeProjection
+	tokenPaths := map[string]bool{}
+	for _, source := range sources {
+		kind, sep, value := source, byte(0), ""
+		if i := strings.IndexAny(source, "/:"); i != -1 {
+			kind, sep, value = source[:i], source[i], source[i+1:]
+		}
+		switch strings.ToLower(kind) {
+		case "secret":
+			if sep != '/' || len(value) == 0 {
+				return nil, fmt.Errorf("--source %q must be of the form secret/NAME", source)
+			}
+			projections = append(projections, corev1.VolumeProjection{
+				Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: value}},
+			})
+		case "configmap":
+			if sep != '/' || len(value) == 0 {
+				return nil, fmt.Errorf("--source %q must be of the form configmap/NAME", source)
+			}
+			projections = append(projections, corev1.VolumeProjection{
+				ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: value}},
+			})
+		case "serviceaccount-token":
+			if sep == '/' {
+				return nil, fmt.Errorf("--source %q must be of the form serviceaccount-token[:audience=AUDIENCE,expiration=DURATION,path=PATH]", source)
+			}
+			token := &corev1.ServiceAccountTokenProjection{Path: "token"}
+			if len(value) > 0 {
+				for _, setting := range strings.Split(value, ",") {
+					parts := strings.SplitN(setting, "=", 2)
+					if len(parts) != 2 || len(parts[1]) == 0 {
+						return nil, fmt.Errorf("--source %q: token settings must be of the form key=value, but is %q", source, setting)
+					}
+					switch parts[0] {
+					case "audience":
+						token.Audience = parts[1]
+					case "expiration":
+						d, err := time.ParseDuration(parts[1])
+						if err != nil {
+							return nil, fmt.Errorf("--source %q: expiration must be a duration such as 1h: %v", source, err)
+						}
+						if d < 10*time.Minute {
+							return nil, fmt.Errorf("--source %q: expiration must be at least 10m", source)
+						}
+						seconds := int64(d / time.Second)
+						token.ExpirationSeconds = &seconds
+					case "path":
+						token.Path = parts[1]
+					default:
+						return nil, fmt.Errorf("--source %q: unknown token setting %q, must be one of audience, expiration, or path", source, parts[0])
+					}
+				}
+			}
+			if tokenPaths[token.Path] {
+				return nil, fmt.Errorf("--source %q: more than one token is projected to path %q", source, token.Path)
+			}
+			tokenPaths[token.Path] = true
+			projections = append(projections, corev1.VolumeProjection{ServiceAccountToken: token})
+		default:
+			return nil, fmt.Errorf("--source %q must be one of secret/NAME, configmap/NAME, or serviceaccount-token for --type=projected", source)
+		}
+	}
+	return projections, nil
+}
+
+// parseCSIVolumeAttributes turns a list of key=value pairs into CSI volume attributes.
+func parseCSIVolumeAttributes(attributes []string) (map[string]string, error) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	result := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		parts := strings.SplitN(attribute, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("--csi-volume-attribute must be of the form key=value, but is %q", attribute)
+		}
+		if _, ok := result[parts[0]]; ok {
+			return nil, fmt.Errorf("--csi-volume-attribute %q may only be specified once", parts[0])
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result, nil
+}
+
 func (o *VolumeOptions) printVolumes(infos []*resource.Info) []error {
 	listingErrors := []error{}
 	for _, info := range infos {
@@ -918,6 +1100,24 @@ func describeVolumeSource(source *corev1.VolumeSource) string {
 		return fmt.Sprintf("secret/%s", source.Secret.SecretName)
 	case source.ConfigMap != nil:
 		return fmt.Sprintf("configMap/%s", source.ConfigMap.Name)
+	case source.CSI != nil:
+		readOnly := source.CSI.ReadOnly != nil && *source.CSI.ReadOnly
+		return fmt.Sprintf("CSI %s%s", source.CSI.Driver, sourceAccessMode(readOnly))
+	case source.Projected != nil:
+		var parts []string
+		for _, p := range source.Projected.Sources {
+			switch {
+			case p.Secret != nil:
+				parts = append(parts, fmt.Sprintf("secret/%s", p.Secret.Name))
+			case p.ConfigMap != nil:
+				parts = append(parts, fmt.Sprintf("configMap/%s", p.ConfigMap.Name))
+			case p.ServiceAccountToken != nil:
+				parts = append(parts, fmt.Sprintf("serviceAccountToken audience=%s", p.ServiceAccountToken.Audience))
+			case p.DownwardAPI != nil:
+				parts = append(parts, "downwardAPI")
+			}
+		}
+		return fmt.Sprintf("projected %s", strings.Join(parts, ", "))
 	default:
 		return "unknown"
 	}
@@ -12,6 +12,7 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kubectl/pkg/polymorphichelpers"
 
+	appsv1 "github.com/openshift/api/apps/v1"
 	"github.com/openshift/oc/pkg/helpers/originpolymorphichelpers"
 )
 
@@ -384,6 +385,56 @@ func TestValidateAddOptions(t *testing.T) {
 			&AddVolumeOptions{Type: "configmap", ConfigMapName: "sandbox-pv", DefaultMode: "07777"},
 			errors.New("--default-mode must be between 0000 and 0777"),
 		},
+		{
+			"creating csi volume with attributes",
+			&AddVolumeOptions{Type: "csi", CSIDriver: "secrets-store.csi.k8s.io", CSIVolumeAttributes: []string{"secretProviderClass=my-provider"}},
+			nil,
+		},
+		{
+			"creating csi volume without driver",
+			&AddVolumeOptions{Type: "csi"},
+			errors.New("must provide --csi-driver for --type=csi"),
+		},
+		{
+			"creating csi volume with invalid driver",
+			&AddVolumeOptions{Type: "csi", CSIDriver: "not_a/driver"},
+			errors.New(`--csi-driver "not_a/driver" is not a valid CSI driver name`),
+		},
+		{
+			"creating csi volume with malformed attribute",
+			&AddVolumeOptions{Type: "csi", CSIDriver: "secrets-store.csi.k8s.io", CSIVolumeAttributes: []string{"secretProviderClass"}},
+			errors.New(`--csi-volume-attribute must be of the form key=value, but is "secretProviderClass"`),
+		},
+		{
+			"creating projected volume",
+			&AddVolumeOptions{Type: "projected", ProjectedSources: []string{"secret/a", "configmap/b", "serviceaccount-token:audience=vault,expiration=1h"}},
+			nil,
+		},
+		{
+			"creating projected volume without sources",
+			&AddVolumeOptions{Type: "projected"},
+			errors.New("must provide at least one --source for --type=projected"),
+		},
+		{
+			"creating projected volume with unknown source",
+			&AddVolumeOptions{Type: "projected", ProjectedSources: []string{"pvc/a"}},
+			errors.New(`--source "pvc/a" must be one of secret/NAME, configmap/NAME, or serviceaccount-token for --type=projected`),
+		},
+		{
+			"creating projected volume with short token expiration",
+			&AddVolumeOptions{Type: "projected", ProjectedSources: []string{"serviceaccount-token:expiration=5m"}},
+			errors.New(`--source "serviceaccount-token:expiration=5m": expiration must be at least 10m`),
+		},
+		{
+			"creating projected volume with two tokens on the same path",
+			&AddVolumeOptions{Type: "projected", ProjectedSources: []string{"serviceaccount-token", "serviceaccount-token:audience=vault"}},
+			errors.New(`--source "serviceaccount-token:audience=vault": more than one token is projected to path "token"`),
+		},
+		{
+			"csi volume attributes without csi type",
+			&AddVolumeOptions{Type: "emptyDir", CSIVolumeAttributes: []string{"a=b"}},
+			errors.New("--csi-volume-attribute is only valid for --type=csi"),
+		},
 	}
 
 	for _, testCase := range tests {
@@ -407,3 +458,111 @@ func TestValidateAddOptions(t *testing.T) {
 
 	}
 }
+
+func TestAddCSIVolumeToDeploymentConfig(t *testing.T) {
+	dc := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "fakedc",
+			Namespace: "default",
+		},
+		Spec: appsv1.DeploymentConfigSpec{
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "fake-container"}},
+				},
+			},
+		},
+	}
+	infos, vOptions := getFakeInfo(nil)
+	infos[0].Object = dc
+	infos[0].Name = dc.Name
+	addOpts := &AddVolumeOptions{
+		Type:                "csi",
+		MountPath:           "/mnt/secrets",
+		ReadOnly:            true,
+		CSIDriver:           "secrets-store.csi.k8s.io",
+		CSIVolumeAttributes: []string{"secretProviderClass=my-provider", "usePodIdentity=false"},
+	}
+	vOptions.AddOpts = addOpts
+	vOptions.Add = true
+
+	if err := addOpts.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	patches, patchError := vOptions.getVolumeUpdatePatches(infos, false)
+	if patchError != nil {
+		t.Fatal(patchError)
+	}
+	if len(patches) != 1 || patches[0].Err != nil {
+		t.Fatalf("Expected a single successful patch, got %#v", patches)
+	}
+
+	spec := patches[0].Info.Object.(*appsv1.DeploymentConfig).Spec.Template.Spec
+	if len(spec.Volumes) != 1 || spec.Volumes[0].CSI == nil {
+		t.Fatalf("Expected a CSI volume to be added, got %#v", spec.Volumes)
+	}
+	csi := spec.Volumes[0].CSI
+	if csi.Driver != "secrets-store.csi.k8s.io" {
+		t.Errorf("Unexpected driver %q", csi.Driver)
+	}
+	if csi.ReadOnly == nil || !*csi.ReadOnly {
+		t.Errorf("Expected CSI volume to be read-only")
+	}
+	if len(csi.VolumeAttributes) != 2 || csi.VolumeAttributes["secretProviderClass"] != "my-provider" || csi.VolumeAttributes["usePodIdentity"] != "false" {
+		t.Errorf("Unexpected volume attributes %v", csi.VolumeAttributes)
+	}
+	mounts := spec.Containers[0].VolumeMounts
+	if len(mounts) != 1 || mounts[0].MountPath != "/mnt/secrets" || !mounts[0].ReadOnly || mounts[0].Name != "fake-mount" {
+		t.Errorf("Unexpected volume mounts %#v", mounts)
+	}
+}
+
+func TestAddProjectedVolume(t *testing.T) {
+	infos, vOptions := getFakeInfo(makeFakePod())
+	vOptions.Name = "app"
+	addOpts := &AddVolumeOptions{
+		Type:             "projected",
+		MountPath:        "/var/run/app",
+		ProjectedSources: []string{"secret/creds", "configmap/settings", "serviceaccount-token:audience=vault,expiration=1h"},
+	}
+	if err := addOpts.Complete(); err != nil {
+		t.Fatal(err)
+	}
+	if err := addOpts.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	vOptions.AddOpts = addOpts
+	vOptions.Add = true
+
+	patches, patchError := vOptions.getVolumeUpdatePatches(infos, false)
+	if patchError != nil {
+		t.Fatal(patchError)
+	}
+	if len(patches) != 1 || patches[0].Err != nil {
+		t.Fatalf("Expected a single successful patch, got %#v", patches)
+	}
+
+	spec := patches[0].Info.Object.(*corev1.Pod).Spec
+	var projected *corev1.ProjectedVolumeSource
+	for _, v := range spec.Volumes {
+		if v.Projected != nil {
+			projected = v.Projected
+		}
+	}
+	if projected == nil || len(projected.Sources) != 3 {
+		t.Fatalf("Expected a projected volume with three sources, got %#v", spec.Volumes)
+	}
+	if projected.DefaultMode == nil || *projected.DefaultMode != 0644 {
+		t.Errorf("Expected default mode 0644, got %v", projected.DefaultMode)
+	}
+	if s := projected.Sources[0].Secret; s == nil || s.Name != "creds" {
+		t.Errorf("Unexpected secret source %#v", projected.Sources[0])
+	}
+	if c := projected.Sources[1].ConfigMap; c == nil || c.Name != "settings" {
+		t.Errorf("Unexpected config map source %#v", projected.Sources[1])
+	}
+	token := projected.Sources[2].ServiceAccountToken
+	if token == nil || token.Audience != "vault" || token.Path != "token" || token.ExpirationSeconds == nil || *token.ExpirationSeconds != 3600 {
+		t.Errorf("Unexpected service account token source %#v", projected.Sources[2])
+	}
+}
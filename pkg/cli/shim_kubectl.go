@@ -14,15 +14,32 @@ import (
 	"github.com/openshift/oc/pkg/helpers/originpolymorphichelpers"
 )
 
+// openShiftSpecialVerbs are the verbs other than the standard resource verbs that OpenShift
+// authorizes on its own resources, such as impersonating an OpenShift user or binding an
+// OpenShift role. kubectl only accepts these verbs for the resources it knows about.
+var openShiftSpecialVerbs = []struct {
+	verb     string
+	resource schema.GroupResource
+}{
+	{verb: "use", resource: schema.GroupResource{Group: "security.openshift.io", Resource: "securitycontextconstraints"}},
+	{verb: "impersonate", resource: schema.GroupResource{Group: "user.openshift.io", Resource: "users"}},
+	{verb: "impersonate", resource: schema.GroupResource{Group: "user.openshift.io", Resource: "groups"}},
+	{verb: "impersonate", resource: schema.GroupResource{Group: "user.openshift.io", Resource: "systemusers"}},
+	{verb: "impersonate", resource: schema.GroupResource{Group: "user.openshift.io", Resource: "systemgroups"}},
+	{verb: "bind", resource: schema.GroupResource{Group: "authorization.openshift.io", Resource: "roles"}},
+	{verb: "bind", resource: schema.GroupResource{Group: "authorization.openshift.io", Resource: "clusterroles"}},
+	{verb: "escalate", resource: schema.GroupResource{Group: "authorization.openshift.io", Resource: "roles"}},
+	{verb: "escalate", resource: schema.GroupResource{Group: "authorization.openshift.io", Resource: "clusterroles"}},
+}
+
 func shimKubectlForOc() {
 	// we only need this change for `oc`.  `kubectl` should behave as close to `kubectl` as we can
 	// if we call this factory construction method, we want the openshift style config loading
 	kclientcmd.ErrEmptyConfig = genericclioptions.ErrEmptyConfig
 	kclientcmd.ClusterDefaults = kclientcmdapi.Cluster{Server: os.Getenv("KUBERNETES_MASTER")}
-	kcmdcreate.AddSpecialVerb("use", schema.GroupResource{
-		Group:    "security.openshift.io",
-		Resource: "securitycontextconstraints",
-	})
+	for _, sv := range openShiftSpecialVerbs {
+		kcmdcreate.AddSpecialVerb(sv.verb, sv.resource)
+	}
 	kclientcmd.UseModifyConfigLock = false
 
 	// update polymorphic helpers
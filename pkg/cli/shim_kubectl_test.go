@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	kcmdcreate "k8s.io/kubectl/pkg/cmd/create"
+)
+
+func TestOpenShiftSpecialVerbs(t *testing.T) {
+	shimKubectlForOc()
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "user.openshift.io", Version: "v1", Kind: "User"},
+		{Group: "user.openshift.io", Version: "v1", Kind: "Group"},
+		{Group: "authorization.openshift.io", Version: "v1", Kind: "Role"},
+		{Group: "authorization.openshift.io", Version: "v1", Kind: "ClusterRole"},
+		{Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+	} {
+		mapper.Add(gvk, meta.RESTScopeRoot)
+	}
+	scc := schema.GroupVersion{Group: "security.openshift.io", Version: "v1"}
+	mapper.AddSpecific(scc.WithKind("SecurityContextConstraints"), scc.WithResource("securitycontextconstraints"), scc.WithResource("securitycontextconstraints"), meta.RESTScopeRoot)
+
+	tests := []struct {
+		verb     string
+		resource string
+		group    string
+		err      string
+	}{
+		{verb: "use", resource: "securitycontextconstraints", group: "security.openshift.io"},
+		{verb: "impersonate", resource: "users", group: "user.openshift.io"},
+		{verb: "impersonate", resource: "groups", group: "user.openshift.io"},
+		{verb: "impersonate", resource: "systemusers", group: "user.openshift.io"},
+		{verb: "bind", resource: "clusterroles", group: "authorization.openshift.io"},
+		{verb: "escalate", resource: "roles", group: "authorization.openshift.io"},
+		{verb: "use", resource: "routes", group: "route.openshift.io", err: "can not perform 'use' on 'routes'"},
+		{verb: "impersonate", resource: "routes", group: "route.openshift.io", err: "can not perform 'impersonate' on 'routes'"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.verb+" "+tc.resource, func(t *testing.T) {
+			o := kcmdcreate.NewCreateRoleOptions(genericclioptions.NewTestIOStreamsDiscard())
+			o.Name = "test"
+			o.Verbs = []string{tc.verb}
+			o.Resources = []kcmdcreate.ResourceOptions{{Resource: tc.resource, Group: tc.group}}
+			o.Mapper = mapper
+
+			err := o.Validate()
+			if len(tc.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
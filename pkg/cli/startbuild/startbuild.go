@@ -1,6 +1,7 @@
 package startbuild
 
 import (
+	archivetar "archive/tar"
 	"bufio"
 	"bytes"
 	"compress/gzip"
@@ -18,6 +19,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
@@ -78,6 +80,9 @@ var (
 		# Use the contents of a directory as build input
 		oc start-build hello-world --from-dir=src/
 
+		# Use the contents of a directory as build input, leaving out dependencies and log files
+		oc start-build hello-world --from-dir=src/ --exclude-pattern=node_modules/ --exclude-pattern='*.log'
+
 		# Send the contents of a Git repository to the server from tag 'v2'
 		oc start-build hello-world --from-repo=../hello-world --commit=v2
 
@@ -111,6 +116,10 @@ type StartBuildOptions struct {
 	FromArchive   string
 	ExcludeRegExp string
 
+	ExcludePatterns []string
+	ExcludeFrom     string
+	excludeMatcher  gitignore.Matcher
+
 	Env  []string
 	Args []string
 
@@ -182,6 +191,8 @@ func NewCmdStartBuild(f kcmdutil.Factory, streams genericclioptions.IOStreams) *
 	cmd.Flags().StringVar(&o.FromRepo, "from-repo", o.FromRepo, "The path to a local source code repository to use as the binary input for a build.")
 	cmd.Flags().StringVar(&o.Commit, "commit", o.Commit, "Specify the source code commit identifier the build should use; requires a build based on a Git repository")
 	cmd.Flags().StringVarP(&o.ExcludeRegExp, "exclude", "", tar.DefaultExclusionPattern.String(), "When using the --from-dir option: regular expression for selecting files from the source tree to exclude from the build; the default excludes the '.git' directory (see https://golang.org/pkg/regexp for syntax, but note that \"\" will be interpreted as allow all files and exclude no files)")
+	cmd.Flags().StringArrayVar(&o.ExcludePatterns, "exclude-pattern", o.ExcludePatterns, "When using the --from-dir or --from-archive options: a gitignore-style pattern, relative to the directory or the root of a tar archive, of files to exclude from the build. May be specified multiple times and takes precedence over --exclude-from.")
+	cmd.Flags().StringVar(&o.ExcludeFrom, "exclude-from", o.ExcludeFrom, "When using the --from-dir or --from-archive options: a file of gitignore-style patterns, one per line, of files to exclude from the build.")
 
 	cmd.Flags().StringVar(&o.ListWebhooks, "list-webhooks", o.ListWebhooks, "List the webhooks for the specified build config or build; accepts 'all', 'generic', or 'github'")
 	cmd.Flags().StringVar(&o.FromWebhook, "from-webhook", o.FromWebhook, "Specify a generic webhook URL for an existing build config to trigger")
@@ -232,6 +243,16 @@ func (o *StartBuildOptions) Complete(ctx context.Context, f kcmdutil.Factory, cm
 	if cmd.Flags().Lookup("exclude").Changed && len(o.FromDir) == 0 {
 		return fmt.Errorf("the --exclude flag is only supported with --from-dir")
 	}
+	if len(o.ExcludePatterns) > 0 || len(o.ExcludeFrom) > 0 {
+		if len(o.FromDir) == 0 {
+			return fmt.Errorf("the --exclude-pattern and --exclude-from flags are only supported with --from-dir or --from-archive")
+		}
+		patterns, err := loadExcludePatterns(o.ExcludeFrom, o.ExcludePatterns)
+		if err != nil {
+			return err
+		}
+		o.excludeMatcher = gitignore.NewMatcher(patterns)
+	}
 
 	o.Printer, err = o.PrintFlags.ToPrinter()
 	if err != nil {
@@ -457,7 +478,7 @@ func (o *StartBuildOptions) Run(ctx context.Context) error {
 		}
 
 		instantiateClient := buildclientmanual.NewBuildInstantiateBinaryClient(o.BuildClient.RESTClient(), o.Namespace)
-		if newBuild, err = streamPathToBuild(o.Git, o.In, o.ErrOut, instantiateClient, o.FromDir, o.FromFile, o.FromRepo, o.ExcludeRegExp, request); err != nil {
+		if newBuild, err = streamPathToBuild(o.Git, o.In, o.ErrOut, instantiateClient, o.FromDir, o.FromFile, o.FromRepo, o.ExcludeRegExp, o.excludeMatcher, request); err != nil {
 			if kerrors.IsAlreadyExists(err) {
 				return transformIsAlreadyExistsError(err, o.Name)
 			}
@@ -675,7 +696,7 @@ func (o *StartBuildOptions) RunListBuildWebHooks(ctx context.Context) error {
 	return nil
 }
 
-func streamPathToBuild(repo git.Repository, in io.Reader, out io.Writer, client buildclientmanual.BuildInstantiateBinaryInterface, fromDir, fromFile, fromRepo string, excludeRegExp string, options *buildv1.BinaryBuildRequestOptions) (*buildv1.Build, error) {
+func streamPathToBuild(repo git.Repository, in io.Reader, out io.Writer, client buildclientmanual.BuildInstantiateBinaryInterface, fromDir, fromFile, fromRepo string, excludeRegExp string, excludeMatcher gitignore.Matcher, options *buildv1.BinaryBuildRequestOptions) (*buildv1.Build, error) {
 	asDir, asFile, asRepo := len(fromDir) > 0, len(fromFile) > 0, len(fromRepo) > 0
 
 	if asRepo && !git.IsGitInstalled() {
@@ -692,6 +713,8 @@ func streamPathToBuild(repo git.Repository, in io.Reader, out io.Writer, client
 		fromPath = fromRepo
 	}
 
+	// excluded is set once excludeMatcher has been applied while creating the archive
+	var excluded bool
 	var r io.Reader
 	switch {
 	case fromFile == "-":
@@ -820,6 +843,7 @@ func streamPathToBuild(repo git.Repository, in io.Reader, out io.Writer, client
 				w := gzip.NewWriter(pw)
 				t := tar.New(s2ifs.NewFileSystem())
 				t.SetExclusionPattern(re)
+				t.SetExclusionMatcher(excludeMatcher)
 				if err := t.CreateTarStream(path, false, w); err != nil {
 					pw.CloseWithError(err)
 				} else {
@@ -832,6 +856,7 @@ func streamPathToBuild(repo git.Repository, in io.Reader, out io.Writer, client
 				}
 			}()
 			r = pr
+			excluded = true
 
 		} else {
 			f, err := os.Open(path)
@@ -857,6 +882,13 @@ func streamPathToBuild(repo git.Repository, in io.Reader, out io.Writer, client
 		if !isArchive(br) {
 			fmt.Fprintf(out, "WARNING: the provided file may not be an archive (tar, tar.gz, or zip), use --from-file to prevent extraction\n")
 		}
+		if excludeMatcher != nil && !excluded {
+			filtered, err := filterArchive(br, excludeMatcher)
+			if err != nil {
+				return nil, err
+			}
+			r = filtered
+		}
 	}
 
 	stopProgress := progress(out)
@@ -864,6 +896,96 @@ func streamPathToBuild(repo git.Repository, in io.Reader, out io.Writer, client
 	return client.InstantiateBinary(options.Name, options, r)
 }
 
+// filterArchive returns a gzip compressed tar archive with the entries of the tar or tar.gz
+// archive read from r that are not matched by excludeMatcher. Entries inside an excluded
+// directory are removed as well, the same as when archiving a directory.
+func filterArchive(r *bufio.Reader, excludeMatcher gitignore.Matcher) (io.Reader, error) {
+	var in io.Reader = r
+	if magic, _ := r.Peek(2); bytes.Equal(magic, []byte{0x1F, 0x8B}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the archive: %v", err)
+		}
+		in = gz
+	} else if magic, _ := r.Peek(0x101 + 5); len(magic) < 0x101+5 || string(magic[0x101:]) != "ustar" {
+		return nil, fmt.Errorf("the --exclude-pattern and --exclude-from flags are only supported for directories and tar or tar.gz archives")
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(copyArchive(archivetar.NewReader(in), pw, excludeMatcher))
+	}()
+	return pr, nil
+}
+
+// copyArchive writes the entries of tr that are not matched by excludeMatcher to w as a
+// gzip compressed tar archive.
+func copyArchive(tr *archivetar.Reader, w io.Writer, excludeMatcher gitignore.Matcher) error {
+	gw := gzip.NewWriter(w)
+	tw := archivetar.NewWriter(gw)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read the archive: %v", err)
+		}
+		name := strings.Trim(filepath.ToSlash(filepath.Clean(header.Name)), "/")
+		if name != "." && excludedArchivePath(excludeMatcher, strings.Split(name, "/"), header.Typeflag == archivetar.TypeDir) {
+			klog.V(5).Infof("Excluding %s from the archive", header.Name)
+			continue
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+// excludedArchivePath returns true if path, or any directory containing it, is matched by excludeMatcher.
+func excludedArchivePath(excludeMatcher gitignore.Matcher, path []string, isDir bool) bool {
+	for i := 1; i < len(path); i++ {
+		if excludeMatcher.Match(path[:i], true) {
+			return true
+		}
+	}
+	return excludeMatcher.Match(path, isDir)
+}
+
+// loadExcludePatterns returns the gitignore-style patterns read from excludeFrom, if set,
+// followed by patterns so that the patterns given on the command line take precedence.
+func loadExcludePatterns(excludeFrom string, patterns []string) ([]gitignore.Pattern, error) {
+	var lines []string
+	if len(excludeFrom) > 0 {
+		data, err := ioutil.ReadFile(excludeFrom)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read --exclude-from: %v", err)
+		}
+		lines = strings.Split(string(data), "\n")
+	}
+	lines = append(lines, patterns...)
+
+	var result []gitignore.Pattern
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		result = append(result, gitignore.ParsePattern(line, nil))
+	}
+	return result, nil
+}
+
 func progress(out io.Writer) func() {
 	stop := make(chan bool)
 	done := make(chan bool)
@@ -1,7 +1,9 @@
 package startbuild
 
 import (
+	archivetar "archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,10 +13,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/apitesting"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -286,7 +292,7 @@ func TestHttpBinary(t *testing.T) {
 
 		defaultExclusionPattern := tar.DefaultExclusionPattern.String()
 
-		build, err := streamPathToBuild(nil, stdin, stdout, &FakeBuildConfigs{t: t, expectAsFile: tc.fromFile}, fromDir, fromFile, "", defaultExclusionPattern, &options)
+		build, err := streamPathToBuild(nil, stdin, stdout, &FakeBuildConfigs{t: t, expectAsFile: tc.fromFile}, fromDir, fromFile, "", defaultExclusionPattern, nil, &options)
 
 		if len(tc.expectedError) > 0 {
 			if err == nil {
@@ -742,3 +748,122 @@ func createConfigMap(cm string, namespace string) *corev1.ConfigMap {
 		},
 	}
 }
+
+func TestLoadExcludePatterns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "exclude")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	excludeFrom := filepath.Join(dir, "excludes")
+	if err := ioutil.WriteFile(excludeFrom, []byte("# dependencies\nnode_modules/\n\n*.log\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	patterns, err := loadExcludePatterns(excludeFrom, []string{"!keep.log"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 3 {
+		t.Fatalf("expected 3 patterns, got %d", len(patterns))
+	}
+	m := gitignore.NewMatcher(patterns)
+	for _, tc := range []struct {
+		path     string
+		isDir    bool
+		excluded bool
+	}{
+		{path: "node_modules", isDir: true, excluded: true},
+		{path: "web/node_modules", isDir: true, excluded: true},
+		{path: "node_modules", excluded: false},
+		{path: "logs/build.log", excluded: true},
+		{path: "logs/keep.log", excluded: false},
+		{path: "main.go", excluded: false},
+	} {
+		if m.Match(strings.Split(tc.path, "/"), tc.isDir) != tc.excluded {
+			t.Errorf("%s: expected excluded=%t", tc.path, tc.excluded)
+		}
+	}
+
+	if _, err := loadExcludePatterns(filepath.Join(dir, "missing"), nil); err == nil {
+		t.Errorf("expected an error for a missing --exclude-from file")
+	}
+}
+
+// archiveBuildConfigs records the names of the entries in the uploaded tar.gz archive.
+type archiveBuildConfigs struct {
+	names []string
+}
+
+func (c *archiveBuildConfigs) InstantiateBinary(name string, options *buildv1.BinaryBuildRequestOptions, r io.Reader) (*buildv1.Build, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := archivetar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return &buildv1.Build{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.names = append(c.names, header.Name)
+	}
+}
+
+func TestStreamArchiveToBuildExcludes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive := filepath.Join(dir, "source.tar.gz")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := gzip.NewWriter(f)
+	tw := archivetar.NewWriter(gw)
+	for _, name := range []string{"./", "./main.go", "./node_modules/", "./node_modules/lib.js", "./web/", "./web/node_modules/", "./web/node_modules/dep.js", "./web/app.js", "./build.log"} {
+		header := &archivetar.Header{Name: name, Mode: 0644, Typeflag: archivetar.TypeReg}
+		if strings.HasSuffix(name, "/") {
+			header.Mode, header.Typeflag = 0755, archivetar.TypeDir
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	patterns, err := loadExcludePatterns("", []string{"node_modules/", "*.log"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &archiveBuildConfigs{}
+	if _, err := streamPathToBuild(nil, nil, ioutil.Discard, client, archive, "", "", tar.DefaultExclusionPattern.String(), gitignore.NewMatcher(patterns), &buildv1.BinaryBuildRequestOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"./", "./main.go", "./web/", "./web/app.js"}; !reflect.DeepEqual(client.names, expected) {
+		t.Errorf("expected the archive to contain %v, got %v", expected, client.names)
+	}
+
+	zip := filepath.Join(dir, "source.zip")
+	if err := ioutil.WriteFile(zip, []byte{0x50, 0x4B, 0x03, 0x04}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = streamPathToBuild(nil, nil, ioutil.Discard, &archiveBuildConfigs{}, zip, "", "", tar.DefaultExclusionPattern.String(), gitignore.NewMatcher(patterns), &buildv1.BinaryBuildRequestOptions{})
+	if err == nil || !strings.Contains(err.Error(), "only supported for directories and tar or tar.gz archives") {
+		t.Errorf("expected excludes to be rejected for a zip archive, got %v", err)
+	}
+}
@@ -59,6 +59,7 @@ type StatusOptions struct {
 	logsCommandName             string
 	securityPolicyCommandFormat string
 	setProbeCommandName         string
+	linkSecretCommandName       string
 	patchCommandName            string
 
 	genericclioptions.IOStreams
@@ -101,6 +102,7 @@ func (o *StatusOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []
 	o.logsCommandName = fmt.Sprintf("%s logs", cmd.Parent().CommandPath())
 	o.securityPolicyCommandFormat = "oc adm policy add-scc-to-user anyuid -n %s -z %s"
 	o.setProbeCommandName = fmt.Sprintf("%s set probe", cmd.Parent().CommandPath())
+	o.linkSecretCommandName = fmt.Sprintf("%s secrets link", cmd.Parent().CommandPath())
 
 	clientConfig, err := f.ToRESTConfig()
 	if err != nil {
@@ -185,6 +187,7 @@ func (o *StatusOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []
 		LogsCommandName:             o.logsCommandName,
 		SecurityPolicyCommandFormat: o.securityPolicyCommandFormat,
 		SetProbeCommandName:         o.setProbeCommandName,
+		LinkSecretCommandName:       o.linkSecretCommandName,
 	}
 
 	return nil
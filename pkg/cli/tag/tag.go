@@ -1,9 +1,12 @@
 package tag
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -43,9 +46,19 @@ type TagOptions struct {
 	destNamespace  []string
 	destNameAndTag []string
 
+	// fromFile is a file with one SOURCE DEST [DEST ...] mapping, or tags to delete, per line
+	fromFile string
+	fileTags []fileTag
+
 	genericclioptions.IOStreams
 }
 
+// fileTag is the tag command for a line of --from-file.
+type fileTag struct {
+	line    int
+	options TagOptions
+}
+
 var (
 	tagLong = templates.LongDesc(`
 		Tag existing images into image streams.
@@ -59,7 +72,13 @@ var (
 		certificate, or is only served over HTTP. Pass --scheduled to have the server
 		regularly check the tag for updates and import the latest version (which can
 		then trigger builds and deployments). Note that --scheduled is only allowed for
-		container images.`)
+		container images.
+
+		Pass --from-file to apply many tags at once. Every line of the file holds the
+		SOURCE and DEST arguments of one tag, or the tags to delete with --delete, and
+		the other flags apply to all lines. Blank lines and lines starting with '#' are
+		ignored. All lines are checked before any tag is changed, and the command fails
+		if any line could not be applied.`)
 
 	tagExample = templates.Examples(`
 		# Tag the current image for the image stream 'openshift/ruby' and tag '2.0' into the image stream 'yourproject/ruby with tag 'tip'
@@ -75,7 +94,10 @@ var (
 		oc tag --source=docker openshift/origin-control-plane:latest yourproject/ruby:tip --reference-policy=local
 
 		# Remove the specified spec tag from an image stream
-		oc tag openshift/origin-control-plane:latest -d`)
+		oc tag openshift/origin-control-plane:latest -d
+
+		# Apply the tags listed in a file, one "SOURCE DEST" pair per line
+		oc tag --from-file=promotions.txt`)
 )
 
 const (
@@ -93,7 +115,7 @@ func NewTagOptions(streams genericclioptions.IOStreams) *TagOptions {
 func NewCmdTag(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
 	o := NewTagOptions(streams)
 	cmd := &cobra.Command{
-		Use:     "tag [--source=SOURCETYPE] SOURCE DEST [DEST ...]",
+		Use:     "tag [--source=SOURCETYPE] (SOURCE DEST [DEST ...] | --from-file=FILE)",
 		Short:   "Tag existing images into image streams",
 		Long:    tagLong,
 		Example: tagExample,
@@ -111,6 +133,8 @@ func NewCmdTag(f kcmdutil.Factory, streams genericclioptions.IOStreams) *cobra.C
 	cmd.Flags().BoolVar(&o.scheduleTag, "scheduled", o.scheduleTag, "Set a container image to be periodically imported from a remote repository. Defaults to false.")
 	cmd.Flags().BoolVar(&o.insecureTag, "insecure", o.insecureTag, "Set to true if importing the specified container image requires HTTP or has a self-signed certificate. Defaults to false.")
 	cmd.Flags().StringVar(&o.referencePolicy, "reference-policy", SourceReferencePolicy, "Allow to request pullthrough for external image when set to 'local'. Defaults to 'source'.")
+	cmd.Flags().StringVar(&o.fromFile, "from-file", o.fromFile, "A file with the SOURCE and DEST arguments of one tag per line, or '-' to read them from standard input.")
+	cmd.MarkFlagFilename("from-file")
 
 	return cmd
 }
@@ -159,7 +183,11 @@ func determineSourceKind(f kcmdutil.Factory, input string) (string, error) {
 
 // Complete completes all the required options for the tag command.
 func (o *TagOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
-	if len(args) < 2 && (len(args) < 1 && !o.deleteTag) {
+	if len(o.fromFile) > 0 {
+		if len(args) > 0 {
+			return kcmdutil.UsageErrorf(cmd, "--from-file may not be specified together with a source or destinations")
+		}
+	} else if len(args) < 2 && (len(args) < 1 && !o.deleteTag) {
 		return kcmdutil.UsageErrorf(cmd, "you must specify a source and at least one destination or one or more tags to delete")
 	}
 
@@ -178,6 +206,60 @@ func (o *TagOptions) Complete(f kcmdutil.Factory, cmd *cobra.Command, args []str
 		return err
 	}
 
+	if len(o.fromFile) > 0 {
+		return o.completeFromFile(f, cmd)
+	}
+	return o.completeTag(f, cmd, args)
+}
+
+// completeFromFile completes the tag command of every line of --from-file, and reports the lines
+// that are invalid together.
+func (o *TagOptions) completeFromFile(f kcmdutil.Factory, cmd *cobra.Command) error {
+	var data []byte
+	var err error
+	if o.fromFile == "-" {
+		data, err = ioutil.ReadAll(o.In)
+	} else {
+		data, err = ioutil.ReadFile(o.fromFile)
+	}
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		args := strings.Fields(scanner.Text())
+		if len(args) == 0 || strings.HasPrefix(args[0], "#") {
+			continue
+		}
+		tag := fileTag{line: line, options: *o}
+		if len(args) < 2 && !o.deleteTag {
+			errs = append(errs, fmt.Errorf("%s:%d: a source and at least one destination are required", o.fromFile, line))
+			continue
+		}
+		if err := tag.options.completeTag(f, cmd, args); err != nil {
+			errs = append(errs, fmt.Errorf("%s:%d: %v", o.fromFile, line, err))
+			continue
+		}
+		o.fileTags = append(o.fileTags, tag)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf(kcmdutil.MultipleErrors("error: ", errs))
+	}
+	if len(o.fileTags) == 0 {
+		return fmt.Errorf("no tags were found in %s", o.fromFile)
+	}
+	return nil
+}
+
+// completeTag populates the source and destinations of a tag from args.
+func (o *TagOptions) completeTag(f kcmdutil.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+
 	// Populate source.
 	if !o.deleteTag {
 		source := args[0]
@@ -303,6 +385,20 @@ func isCrossImageStream(namespace string, srcRef imagev1.DockerImageReference, d
 
 // Validate validates all the required options for the tag command.
 func (o TagOptions) Validate() error {
+	if len(o.fromFile) > 0 {
+		var errs []error
+		for _, tag := range o.fileTags {
+			tag.options.fromFile = ""
+			if err := tag.options.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s:%d: %v", o.fromFile, tag.line, err))
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf(kcmdutil.MultipleErrors("error: ", errs))
+		}
+		return nil
+	}
+
 	if o.deleteTag && o.aliasTag {
 		return errors.New("--alias and --delete may not be both specified")
 	}
@@ -359,6 +455,10 @@ func (o TagOptions) Validate() error {
 
 // Run contains all the necessary functionality for the OpenShift cli tag command.
 func (o TagOptions) Run() error {
+	if len(o.fromFile) > 0 {
+		return o.runFromFile()
+	}
+
 	var tagReferencePolicy imagev1.TagReferencePolicyType
 	switch o.referencePolicy {
 	case SourceReferencePolicy:
@@ -571,3 +671,19 @@ func (o TagOptions) Run() error {
 
 	return nil
 }
+
+// runFromFile applies the tag of every line of --from-file, continuing past the lines that fail.
+func (o TagOptions) runFromFile() error {
+	failed := 0
+	for _, tag := range o.fileTags {
+		tag.options.fromFile = ""
+		if err := tag.options.Run(); err != nil {
+			fmt.Fprintf(o.ErrOut, "error: %s:%d: %v\n", o.fromFile, tag.line, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tags in %s could not be applied", failed, len(o.fileTags), o.fromFile)
+	}
+	return nil
+}
@@ -2,6 +2,10 @@ package tag
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -388,3 +392,69 @@ func TestRunTag_DeleteNew(t *testing.T) {
 		}
 	}
 }
+
+func TestTagFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFile := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	invalid := writeFile("invalid.txt", "quay.io/openshift/ruby:2.7 rails:tip\nquay.io/openshift/ruby:3.0\nquay.io/openshift/ruby:3.1 a/b/c:tip\n")
+	o := NewTagOptions(genericclioptions.NewTestIOStreamsDiscard())
+	o.namespace = "yourproject"
+	o.fromFile = invalid
+	err = o.completeFromFile(nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid.txt:2: a source and at least one destination are required") || !strings.Contains(err.Error(), `invalid.txt:3: invalid image stream "a/b/c:tip"`) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mappings := writeFile("mappings.txt", `# promoted images
+quay.io/openshift/ruby:2.7 rails:tip
+
+quay.io/openshift/ruby:3.0 myproject/rails:latest rails:next
+quay.io/openshift/python:3.9 django:tip
+`)
+	client := fakeimagev1client.NewSimpleClientset()
+	client.PrependReactor("update", "imagestreamtags", func(action clientgotesting.Action) (handled bool, ret runtime.Object, err error) {
+		istag := action.(clientgotesting.UpdateAction).GetObject().(*imagev1.ImageStreamTag)
+		if istag.Name == "django:tip" {
+			return true, nil, kapierrors.NewBadRequest("invalid tag")
+		}
+		return true, istag, nil
+	})
+	streams, _, out, errOut := genericclioptions.NewTestIOStreams()
+	o = NewTagOptions(streams)
+	o.client = client.ImageV1()
+	o.namespace = "yourproject"
+	o.referencePolicy = SourceReferencePolicy
+	o.fromFile = mappings
+	if err := o.completeFromFile(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(o.fileTags) != 3 {
+		t.Fatalf("expected 3 tags, got %d", len(o.fileTags))
+	}
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	err = o.Run()
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 tags") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut.String(), "mappings.txt:5: invalid tag") {
+		t.Errorf("expected the failed line to be reported: %s", errOut.String())
+	}
+	for _, msg := range []string{"Tag rails:tip set to", "Tag myproject/rails:latest set to", "Tag rails:next set to"} {
+		if !strings.Contains(out.String(), msg) {
+			t.Errorf("expected %q in output: %s", msg, out.String())
+		}
+	}
+}
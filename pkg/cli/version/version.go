@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
@@ -29,12 +30,34 @@ type Version struct {
 	kversion.Version
 	ReleaseClientVersion string `json:"releaseClientVersion,omitempty"`
 	OpenShiftVersion     string `json:"openshiftVersion,omitempty"`
+
+	// ClusterVersion and ClusterOperators are only reported with --output, when the user
+	// can read them.
+	ClusterVersion   *ClusterVersionInfo       `json:"clusterVersion,omitempty"`
+	ClusterOperators []ClusterOperatorVersions `json:"clusterOperators,omitempty"`
+}
+
+// ClusterVersionInfo is the release the cluster is moving to and the releases it was updated to.
+type ClusterVersionInfo struct {
+	Desired configv1.Release         `json:"desired"`
+	History []configv1.UpdateHistory `json:"history,omitempty"`
+}
+
+// ClusterOperatorVersions are the versions of the operands reported by a cluster operator.
+type ClusterOperatorVersions struct {
+	Name     string                    `json:"name"`
+	Versions []configv1.OperandVersion `json:"versions,omitempty"`
 }
 
 var (
 	versionLong = templates.LongDesc(`
 		Print the OpenShift client, kube-apiserver, and openshift-apiserver versions for the current context.
 		Pass --client to print only the OpenShift client version.
+
+		With --output=json or --output=yaml, the desired release and the update history of the
+		cluster, and the operand versions reported by each cluster operator, are included if you
+		are allowed to read them. If the server cannot be reached, only the client version is
+		printed and a warning explains why.
 	`)
 	versionExample = templates.Examples(`
 		# Print the OpenShift client, kube-apiserver, and openshift-apiserver version information for the current context
@@ -45,12 +68,15 @@ var (
 
 		# Print the OpenShift client version information for the current context
 		oc version --client
+
+		# Print the client, server, cluster version and cluster operator versions as YAML
+		oc version -o yaml
 	`)
 )
 
 type VersionOptions struct {
 	kversion.Options
-	oClient         configv1client.ClusterVersionsGetter
+	oClient         configv1client.ConfigV1Interface
 	discoveryClient discovery.CachedDiscoveryInterface
 
 	genericclioptions.IOStreams
@@ -154,9 +180,20 @@ func (o *VersionOptions) Run() error {
 						break
 					}
 				}
+				if len(o.Output) > 0 {
+					versionInfo.ClusterVersion = clusterVersionInfo(clusterVersion)
+				}
+			}
+			if len(o.Output) > 0 && serverErr == nil {
+				versionInfo.ClusterOperators, serverErr = o.clusterOperatorVersions()
 			}
 		}
 	}
+	if len(o.Output) > 0 && serverErr != nil {
+		// structured output is collected for support cases, report whatever could be read
+		fmt.Fprintf(o.ErrOut, "warning: unable to read the server version: %v\n", serverErr)
+		serverErr = nil
+	}
 	switch o.Output {
 	case "":
 		if versionInfo.ClientVersion != nil {
@@ -195,3 +232,35 @@ func (o *VersionOptions) Run() error {
 
 	return serverErr
 }
+
+// clusterOperatorVersions returns the operand versions reported by each cluster operator, or
+// nothing if the user is not allowed to list cluster operators.
+func (o *VersionOptions) clusterOperatorVersions() ([]ClusterOperatorVersions, error) {
+	operators, err := o.oClient.ClusterOperators().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		switch {
+		case kerrors.IsForbidden(err), kerrors.IsNotFound(err):
+			klog.V(5).Infof("Cluster operator versions not found (must be logged in to cluster as admin): %v", err)
+			return nil, nil
+		}
+		return nil, err
+	}
+	return clusterOperatorVersionsFor(operators.Items), nil
+}
+
+func clusterVersionInfo(clusterVersion *configv1.ClusterVersion) *ClusterVersionInfo {
+	return &ClusterVersionInfo{
+		Desired: clusterVersion.Status.Desired,
+		History: clusterVersion.Status.History,
+	}
+}
+
+// clusterOperatorVersionsFor returns the versions of operators, sorted by name.
+func clusterOperatorVersionsFor(operators []configv1.ClusterOperator) []ClusterOperatorVersions {
+	var versions []ClusterOperatorVersions
+	for _, operator := range operators {
+		versions = append(versions, ClusterOperatorVersions{Name: operator.Name, Versions: operator.Status.Versions})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Name < versions[j].Name })
+	return versions
+}
@@ -0,0 +1,53 @@
+package version
+
+import (
+	"encoding/json"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func TestClusterVersions(t *testing.T) {
+	clusterVersion := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Status: configv1.ClusterVersionStatus{
+			Desired: configv1.Release{Version: "4.11.2", Image: "quay.io/openshift-release-dev/ocp-release@sha256:2"},
+			History: []configv1.UpdateHistory{
+				{State: configv1.PartialUpdate, Version: "4.11.2"},
+				{State: configv1.CompletedUpdate, Version: "4.11.1"},
+			},
+			AvailableUpdates: []configv1.Release{{Version: "4.11.3"}},
+		},
+	}
+	operators := []configv1.ClusterOperator{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver"},
+			Status:     configv1.ClusterOperatorStatus{Versions: []configv1.OperandVersion{{Name: "operator", Version: "4.11.2"}, {Name: "kube-apiserver", Version: "1.24.0"}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "authentication"},
+			Status:     configv1.ClusterOperatorStatus{Versions: []configv1.OperandVersion{{Name: "operator", Version: "4.11.1"}}},
+		},
+	}
+
+	versionInfo := Version{
+		OpenShiftVersion: "4.11.1",
+		ClusterVersion:   clusterVersionInfo(clusterVersion),
+		ClusterOperators: clusterOperatorVersionsFor(operators),
+	}
+	data, err := json.Marshal(versionInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"openshiftVersion":"4.11.1",` +
+		`"clusterVersion":{"desired":{"version":"4.11.2","image":"quay.io/openshift-release-dev/ocp-release@sha256:2"},` +
+		`"history":[{"state":"Partial","startedTime":null,"completionTime":null,"version":"4.11.2","image":"","verified":false},` +
+		`{"state":"Completed","startedTime":null,"completionTime":null,"version":"4.11.1","image":"","verified":false}]},` +
+		`"clusterOperators":[{"name":"authentication","versions":[{"name":"operator","version":"4.11.1"}]},` +
+		`{"name":"kube-apiserver","versions":[{"name":"operator","version":"4.11.2"},{"name":"kube-apiserver","version":"1.24.0"}]}]}`
+	if string(data) != expected {
+		t.Errorf("unexpected output:\n%s", data)
+	}
+}
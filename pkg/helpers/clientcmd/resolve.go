@@ -11,11 +11,19 @@ import (
 	"github.com/openshift/library-go/pkg/image/reference"
 )
 
+// ParseDockerImageReferenceToStringFunc parses spec and returns its canonical pull spec. A
+// reference with both a tag and a digest keeps both, the digest being what the image is
+// pulled by and the tag being kept for display.
 func ParseDockerImageReferenceToStringFunc(spec string) (string, error) {
 	ret, err := reference.Parse(spec)
 	if err != nil {
 		return "", err
 	}
+	if len(ret.Tag) > 0 && len(ret.ID) > 0 {
+		id := ret.ID
+		ret.ID = ""
+		return ret.String() + "@" + id, nil
+	}
 	return ret.String(), nil
 }
 
@@ -54,11 +62,7 @@ func resolveImagePullSpec(ctx context.Context, imageClient imagev1typedclient.Im
 		return "", fmt.Errorf("unable to resolve %s %q", source, name)
 	}
 
-	ref, err := reference.Parse(dockerImageReference)
-	if err != nil {
-		return "", err
-	}
-	return ref.String(), nil
+	return ParseDockerImageReferenceToStringFunc(dockerImageReference)
 }
 
 func isDockerImageSource(source string) bool {
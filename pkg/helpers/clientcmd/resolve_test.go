@@ -99,3 +99,41 @@ func TestResolveImagePullSpec(t *testing.T) {
 		}
 	}
 }
+
+func TestParseDockerImageReferenceToStringFunc(t *testing.T) {
+	const digest = "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+	testCases := []struct {
+		name      string
+		spec      string
+		expect    string
+		expectErr bool
+	}{
+		{name: "name only", spec: "test", expect: "test"},
+		{name: "tag", spec: "registry.url/image/test:1.1", expect: "registry.url/image/test:1.1"},
+		{name: "digest", spec: "registry.url/image/test@" + digest, expect: "registry.url/image/test@" + digest},
+		{name: "tag and digest", spec: "registry.url/image/test:1.1@" + digest, expect: "registry.url/image/test:1.1@" + digest},
+		{name: "tag and digest without registry", spec: "test:1.1@" + digest, expect: "test:1.1@" + digest},
+		{name: "docker hub defaults", spec: "docker.io/test:1.1@" + digest, expect: "docker.io/library/test:1.1@" + digest},
+		{name: "invalid digest", spec: "test:1.1@sha256:foo", expectErr: true},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := ParseDockerImageReferenceToStringFunc(test.spec)
+			if err != nil {
+				if !test.expectErr {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if test.expectErr {
+				t.Fatalf("expected error but got none and result %q", result)
+			}
+			if result != test.expect {
+				t.Errorf("expected %q, but got %q", test.expect, result)
+			}
+			if again, err := ParseDockerImageReferenceToStringFunc(result); err != nil || again != result {
+				t.Errorf("expected %q to round trip, got %q: %v", result, again, err)
+			}
+		})
+	}
+}
@@ -66,7 +66,8 @@ func PodContainerRunning(containerName string, coreClient corev1client.CoreV1Int
 				return false, krun.ErrPodCompleted
 			}
 
-			for _, s := range append(append([]corev1.ContainerStatus{}, t.Status.InitContainerStatuses...), t.Status.ContainerStatuses...) {
+			statuses := append(append([]corev1.ContainerStatus{}, t.Status.InitContainerStatuses...), t.Status.ContainerStatuses...)
+			for _, s := range append(statuses, t.Status.EphemeralContainerStatuses...) {
 				if s.Name != containerName {
 					continue
 				}
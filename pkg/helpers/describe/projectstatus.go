@@ -83,6 +83,7 @@ type ProjectStatusDescriber struct {
 	LogsCommandName             string
 	SecurityPolicyCommandFormat string
 	SetProbeCommandName         string
+	LinkSecretCommandName       string
 }
 
 func (d *ProjectStatusDescriber) MakeGraph(namespace string) (osgraph.Graph, sets.String, error) {
@@ -150,6 +151,7 @@ func (d *ProjectStatusDescriber) MakeGraph(namespace string) (osgraph.Graph, set
 	kubeedges.AddAllRequestedServiceAccountEdges(g)
 	kubeedges.AddAllMountableSecretEdges(g)
 	kubeedges.AddAllMountedSecretEdges(g)
+	kubeedges.AddAllImagePullSecretEdges(g)
 	kubeedges.AddHPAScaleRefEdges(g, d.RESTMapper)
 	buildedges.AddAllInputOutputEdges(g)
 	buildedges.AddAllBuildEdges(g)
@@ -465,7 +467,7 @@ func (d *ProjectStatusDescriber) Describe(namespace, name string) (string, error
 
 		allMarkers := osgraph.Markers{}
 		allMarkers = append(allMarkers, createForbiddenMarkers(forbiddenResources)...)
-		for _, scanner := range getMarkerScanners(d.LogsCommandName, d.SecurityPolicyCommandFormat, d.SetProbeCommandName, forbiddenResources) {
+		for _, scanner := range getMarkerScanners(d.LogsCommandName, d.SecurityPolicyCommandFormat, d.SetProbeCommandName, d.LinkSecretCommandName, forbiddenResources) {
 			allMarkers = append(allMarkers, scanner(g, f)...)
 		}
 
@@ -598,7 +600,7 @@ func createForbiddenMarkers(forbiddenResources sets.String) []osgraph.Marker {
 	return markers
 }
 
-func getMarkerScanners(logsCommandName, securityPolicyCommandFormat, setProbeCommandName string, forbiddenResources sets.String) []osgraph.MarkerScanner {
+func getMarkerScanners(logsCommandName, securityPolicyCommandFormat, setProbeCommandName, linkSecretCommandName string, forbiddenResources sets.String) []osgraph.MarkerScanner {
 	return []osgraph.MarkerScanner{
 		func(g osgraph.Graph, f osgraph.Namer) []osgraph.Marker {
 			return kubeanalysis.FindRestartingPods(g, f, logsCommandName, securityPolicyCommandFormat)
@@ -611,6 +613,13 @@ func getMarkerScanners(logsCommandName, securityPolicyCommandFormat, setProbeCom
 			}
 			return kubeanalysis.FindMissingSecrets(g, f)
 		},
+		func(g osgraph.Graph, f osgraph.Namer) []osgraph.Marker {
+			// service accounts and secrets that could not be listed would be reported as missing
+			if forbiddenResources.Has("secrets") || forbiddenResources.Has("serviceaccounts") {
+				return []osgraph.Marker{}
+			}
+			return kubeanalysis.FindMissingImagePullSecrets(g, f, linkSecretCommandName)
+		},
 		kubeanalysis.FindHPASpecsMissingCPUTargets,
 		// TODO(directxman12): re-enable FindHPASpecsMissingScaleRefs once the graph library
 		// knows how to deal with arbitrary scale targets
@@ -619,6 +628,7 @@ func getMarkerScanners(logsCommandName, securityPolicyCommandFormat, setProbeCom
 		buildanalysis.FindCircularBuilds,
 		buildanalysis.FindPendingTags,
 		appsanalysis.FindDeploymentConfigTriggerErrors,
+		kubeanalysis.FindDeploymentTriggerErrors,
 		appsanalysis.FindPersistentVolumeClaimWarnings,
 		buildanalysis.FindMissingInputImageStreams,
 		func(g osgraph.Graph, f osgraph.Namer) []osgraph.Marker {
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"reflect"
 
+	kappsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -67,6 +68,9 @@ func init() {
 	if err := RegisterEnsureNode(&corev1.PersistentVolumeClaim{}, kubegraph.EnsurePersistentVolumeClaimNode); err != nil {
 		panic(err)
 	}
+	if err := RegisterEnsureNode(&kappsv1.Deployment{}, kubegraph.EnsureDeploymentNode); err != nil {
+		panic(err)
+	}
 	if err := RegisterEnsureNode(&autoscalingv1.HorizontalPodAutoscaler{}, kubegraph.EnsureHorizontalPodAutoscalerNode); err != nil {
 	}
 }
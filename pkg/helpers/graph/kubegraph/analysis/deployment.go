@@ -0,0 +1,50 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/gonum/graph"
+
+	osgraph "github.com/openshift/oc/pkg/helpers/graph/genericgraph"
+	imageedges "github.com/openshift/oc/pkg/helpers/graph/imagegraph"
+	imagegraph "github.com/openshift/oc/pkg/helpers/graph/imagegraph/nodes"
+	kubeedges "github.com/openshift/oc/pkg/helpers/graph/kubegraph"
+	kubegraph "github.com/openshift/oc/pkg/helpers/graph/kubegraph/nodes"
+)
+
+const (
+	MissingDeploymentImageStreamErr = "MissingDeploymentImageStream"
+)
+
+// FindDeploymentTriggerErrors inspects the image triggers of deployments for image streams that do not exist.
+// Image streams in other namespaces are not loaded into the graph and are not checked.
+func FindDeploymentTriggerErrors(g osgraph.Graph, f osgraph.Namer) []osgraph.Marker {
+	markers := []osgraph.Marker{}
+
+	for _, uncastDeploymentNode := range g.NodesByKind(kubegraph.DeploymentNodeKind) {
+		deploymentNode := uncastDeploymentNode.(*kubegraph.DeploymentNode)
+
+		for _, uncastIstNode := range g.PredecessorNodesByEdgeKind(deploymentNode, kubeedges.TriggersDeploymentEdgeKind) {
+			istNode, ok := uncastIstNode.(*imagegraph.ImageStreamTagNode)
+			if !ok || istNode.Found() || istNode.Namespace != deploymentNode.Deployment.Namespace {
+				continue
+			}
+			for _, uncastIsNode := range g.SuccessorNodesByEdgeKind(istNode, imageedges.ReferencedImageStreamGraphEdgeKind) {
+				if uncastIsNode.(*imagegraph.ImageStreamNode).Found() {
+					continue
+				}
+				markers = append(markers, osgraph.Marker{
+					Node:         deploymentNode,
+					RelatedNodes: []graph.Node{istNode, uncastIsNode},
+
+					Severity: osgraph.ErrorSeverity,
+					Key:      MissingDeploymentImageStreamErr,
+					Message: fmt.Sprintf("The image trigger for %s will have no effect because %s does not exist.",
+						f.ResourceName(deploymentNode), f.ResourceName(uncastIsNode)),
+				})
+			}
+		}
+	}
+
+	return markers
+}
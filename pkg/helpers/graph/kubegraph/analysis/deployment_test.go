@@ -0,0 +1,33 @@
+package analysis
+
+import (
+	"testing"
+
+	osgraph "github.com/openshift/oc/pkg/helpers/graph/genericgraph"
+	osgraphtest "github.com/openshift/oc/pkg/helpers/graph/genericgraph/test"
+	imageedges "github.com/openshift/oc/pkg/helpers/graph/imagegraph"
+	kubeedges "github.com/openshift/oc/pkg/helpers/graph/kubegraph"
+)
+
+func TestDeploymentTriggerErrors(t *testing.T) {
+	g, _, err := osgraphtest.BuildGraph("../../../graph/genericgraph/test/image-pull-secrets.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kubeedges.AddAllTriggerDeploymentsEdges(g)
+	imageedges.AddAllImageStreamRefEdges(g)
+
+	markers := FindDeploymentTriggerErrors(g, osgraph.DefaultNamer)
+	if e, a := 1, len(markers); e != a {
+		t.Fatalf("expected %v, got %v", e, a)
+	}
+
+	expectedDeployment := g.Find(osgraph.UniqueName("Deployment|example/with-pull-secret"))
+	if e, a := expectedDeployment.ID(), markers[0].Node.ID(); e != a {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+	if e, a := MissingDeploymentImageStreamErr, markers[0].Key; e != a {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+}
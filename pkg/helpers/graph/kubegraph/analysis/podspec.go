@@ -6,6 +6,7 @@ import (
 	"github.com/gonum/graph"
 
 	"github.com/openshift/oc/pkg/helpers/graph/appsgraph"
+	appsnodes "github.com/openshift/oc/pkg/helpers/graph/appsgraph/nodes"
 	osgraph "github.com/openshift/oc/pkg/helpers/graph/genericgraph"
 	kubeedges "github.com/openshift/oc/pkg/helpers/graph/kubegraph"
 	kubegraph "github.com/openshift/oc/pkg/helpers/graph/kubegraph/nodes"
@@ -15,6 +16,9 @@ const (
 	UnmountableSecretWarning    = "UnmountableSecret"
 	MissingSecretWarning        = "MissingSecret"
 	MissingLivenessProbeWarning = "MissingLivenessProbe"
+
+	MissingImagePullSecretWarning = "MissingImagePullSecret"
+	NoImagePullSecretWarning      = "NoImagePullSecret"
 )
 
 // FindUnmountableSecrets inspects all PodSpecs for any Secret reference that isn't listed as mountable by the referenced ServiceAccount
@@ -77,6 +81,74 @@ func FindMissingSecrets(g osgraph.Graph, f osgraph.Namer) []osgraph.Marker {
 	return markers
 }
 
+// FindMissingImagePullSecrets inspects the PodSpecs of deployment configs and deployments for image pull secrets
+// that do not exist, and for service accounts that have no image pull secret while the PodSpec does not reference one.
+func FindMissingImagePullSecrets(g osgraph.Graph, f osgraph.Namer, linkSecretCommand string) []osgraph.Marker {
+	markers := []osgraph.Marker{}
+
+	for _, uncastPodSpecNode := range g.NodesByKind(kubegraph.PodSpecNodeKind) {
+		podSpecNode := uncastPodSpecNode.(*kubegraph.PodSpecNode)
+
+		topLevelNode := osgraph.GetTopLevelContainerNode(g, podSpecNode)
+		switch topLevelNode.(type) {
+		case *appsnodes.DeploymentConfigNode, *kubegraph.DeploymentNode:
+		default:
+			continue
+		}
+		topLevelString := f.ResourceName(topLevelNode)
+
+		for _, missingSecret := range CheckMissingImagePullSecrets(g, podSpecNode) {
+			markers = append(markers, osgraph.Marker{
+				Node:         podSpecNode,
+				RelatedNodes: []graph.Node{missingSecret},
+
+				Severity: osgraph.WarningSeverity,
+				Key:      MissingImagePullSecretWarning,
+				Message: fmt.Sprintf("%s is attempting to pull images with a missing secret %s",
+					topLevelString, f.ResourceName(missingSecret)),
+			})
+		}
+
+		// a service account that was not found is not checked, it is either missing or could not be listed
+		saNodes := g.SuccessorNodesByNodeAndEdgeKind(podSpecNode, kubegraph.ServiceAccountNodeKind, kubeedges.ReferencedServiceAccountEdgeKind)
+		if len(saNodes) == 0 || !saNodes[0].(*kubegraph.ServiceAccountNode).Found() {
+			continue
+		}
+		saNode := saNodes[0].(*kubegraph.ServiceAccountNode)
+
+		saPullSecrets := g.SuccessorNodesByNodeAndEdgeKind(saNode, kubegraph.SecretNodeKind, kubeedges.ImagePullSecretEdgeKind)
+		for _, uncastSecretNode := range saPullSecrets {
+			if secretNode := uncastSecretNode.(*kubegraph.SecretNode); !secretNode.Found() {
+				markers = append(markers, osgraph.Marker{
+					Node:         podSpecNode,
+					RelatedNodes: []graph.Node{saNode, secretNode},
+
+					Severity: osgraph.WarningSeverity,
+					Key:      MissingImagePullSecretWarning,
+					Message: fmt.Sprintf("%s runs as %s, which references a missing image pull secret %s",
+						topLevelString, f.ResourceName(saNode), f.ResourceName(secretNode)),
+				})
+			}
+		}
+
+		if len(saPullSecrets) > 0 || len(podSpecNode.ImagePullSecrets) > 0 {
+			continue
+		}
+		markers = append(markers, osgraph.Marker{
+			Node:         podSpecNode,
+			RelatedNodes: []graph.Node{saNode},
+
+			Severity: osgraph.WarningSeverity,
+			Key:      NoImagePullSecretWarning,
+			Message: fmt.Sprintf("%s runs as %s, which has no image pull secret. Pulling images from registries that require authentication will fail.",
+				topLevelString, f.ResourceName(saNode)),
+			Suggestion: osgraph.Suggestion(fmt.Sprintf("%s %s <pull-secret> --for=pull", linkSecretCommand, saNode.ServiceAccount.Name)),
+		})
+	}
+
+	return markers
+}
+
 // FindMissingLivenessProbes inspects all PodSpecs for missing liveness probes and generates a list of non-duplicate markers
 func FindMissingLivenessProbes(g osgraph.Graph, f osgraph.Namer, setProbeCommand string) []osgraph.Marker {
 	markers := []osgraph.Marker{}
@@ -201,3 +273,17 @@ func CheckMissingMountedSecrets(g osgraph.Graph, podSpecNode *kubegraph.PodSpecN
 
 	return missingSecrets
 }
+
+// CheckMissingImagePullSecrets checks to be sure that all the image pull secrets of the PodSpec are present (not synthetic)
+func CheckMissingImagePullSecrets(g osgraph.Graph, podSpecNode *kubegraph.PodSpecNode) []*kubegraph.SecretNode {
+	missingSecrets := []*kubegraph.SecretNode{}
+
+	for _, uncastSecretNode := range g.SuccessorNodesByNodeAndEdgeKind(podSpecNode, kubegraph.SecretNodeKind, kubeedges.ImagePullSecretEdgeKind) {
+		secretNode := uncastSecretNode.(*kubegraph.SecretNode)
+		if !secretNode.Found() {
+			missingSecrets = append(missingSecrets, secretNode)
+		}
+	}
+
+	return missingSecrets
+}
@@ -100,3 +100,46 @@ func TestMissingLivenessProbes(t *testing.T) {
 		t.Errorf("expected %v, got %v", e, a)
 	}
 }
+
+func TestMissingImagePullSecrets(t *testing.T) {
+	g, _, err := osgraphtest.BuildGraph("../../../graph/genericgraph/test/image-pull-secrets.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	kubeedges.AddAllRequestedServiceAccountEdges(g)
+	kubeedges.AddAllImagePullSecretEdges(g)
+
+	markers := FindMissingImagePullSecrets(g, osgraph.DefaultNamer, "oc secrets link")
+	if e, a := 3, len(markers); e != a {
+		t.Fatalf("expected %v, got %v", e, a)
+	}
+
+	expectedDC := g.Find(osgraph.UniqueName("DeploymentConfig|example/no-pull-secret"))
+	expectedDeployment := g.Find(osgraph.UniqueName("Deployment|example/missing-pull-secret"))
+	expectedSA := g.Find(osgraph.UniqueName("ServiceAccount|example/default"))
+	expectedPodSecret := g.Find(osgraph.UniqueName("Secret|example/missing-pod-pull-secret"))
+	expectedSASecret := g.Find(osgraph.UniqueName("Secret|example/missing-sa-pull-secret"))
+
+	foundNoSecret, foundPodSecret, foundSASecret := false, false, false
+	for _, marker := range markers {
+		topLevelNode := osgraph.GetTopLevelContainerNode(g, marker.Node)
+		related := marker.RelatedNodes[len(marker.RelatedNodes)-1]
+		switch {
+		case marker.Key == NoImagePullSecretWarning && topLevelNode.ID() == expectedDC.ID() && related.ID() == expectedSA.ID():
+			foundNoSecret = true
+			if e, a := "oc secrets link default <pull-secret> --for=pull", string(marker.Suggestion); e != a {
+				t.Errorf("expected suggestion %q, got %q", e, a)
+			}
+		case marker.Key == MissingImagePullSecretWarning && topLevelNode.ID() == expectedDeployment.ID() && related.ID() == expectedPodSecret.ID():
+			foundPodSecret = true
+		case marker.Key == MissingImagePullSecretWarning && topLevelNode.ID() == expectedDeployment.ID() && related.ID() == expectedSASecret.ID():
+			foundSASecret = true
+		default:
+			t.Errorf("unexpected marker: %#v", marker)
+		}
+	}
+	if !foundNoSecret || !foundPodSecret || !foundSASecret {
+		t.Errorf("expected markers were not found: %#v", markers)
+	}
+}
@@ -35,6 +35,8 @@ const (
 	MountableSecretEdgeKind = "MountableSecret"
 	// ReferencedServiceAccountEdgeKind goes from PodSpec to ServiceAccount indicating that Pod is or will be running as the SA.
 	ReferencedServiceAccountEdgeKind = "ReferencedServiceAccount"
+	// ImagePullSecretEdgeKind goes from PodSpec or ServiceAccount to Secret indicating that the Secret is used to pull the images of the Pod.
+	ImagePullSecretEdgeKind = "ImagePullSecret"
 	// ScalingEdgeKind goes from HorizontalPodAutoscaler to scaled objects indicating that the HPA scales the object
 	ScalingEdgeKind = "Scaling"
 	// TriggersDeploymentEdgeKind points from DeploymentConfigs to ImageStreamTags that trigger the deployment
@@ -213,6 +215,49 @@ func AddAllMountableSecretEdges(g osgraph.Graph) {
 	}
 }
 
+func AddImagePullSecretEdges(g osgraph.Graph, podSpec *kubegraph.PodSpecNode) {
+	//pod specs are always contained.  We'll get the toplevel container so that we can pull a namespace from it
+	containerNode := osgraph.GetTopLevelContainerNode(g, podSpec)
+	containerObj := g.GraphDescriber.Object(containerNode)
+
+	meta, err := meta.Accessor(containerObj.(runtime.Object))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, pullSecret := range podSpec.ImagePullSecrets {
+		syntheticSecret := &corev1.Secret{}
+		syntheticSecret.Namespace = meta.GetNamespace()
+		syntheticSecret.Name = pullSecret.Name
+
+		secretNode := kubegraph.FindOrCreateSyntheticSecretNode(g, syntheticSecret)
+		g.AddEdge(podSpec, secretNode, ImagePullSecretEdgeKind)
+	}
+}
+
+func AddServiceAccountImagePullSecretEdges(g osgraph.Graph, saNode *kubegraph.ServiceAccountNode) {
+	for _, pullSecret := range saNode.ServiceAccount.ImagePullSecrets {
+		syntheticSecret := &corev1.Secret{}
+		syntheticSecret.Namespace = saNode.ServiceAccount.Namespace
+		syntheticSecret.Name = pullSecret.Name
+
+		secretNode := kubegraph.FindOrCreateSyntheticSecretNode(g, syntheticSecret)
+		g.AddEdge(saNode, secretNode, ImagePullSecretEdgeKind)
+	}
+}
+
+// AddAllImagePullSecretEdges adds the image pull secrets of every PodSpec and ServiceAccount.
+func AddAllImagePullSecretEdges(g osgraph.Graph) {
+	for _, node := range g.Nodes() {
+		switch cast := node.(type) {
+		case *kubegraph.PodSpecNode:
+			AddImagePullSecretEdges(g, cast)
+		case *kubegraph.ServiceAccountNode:
+			AddServiceAccountImagePullSecretEdges(g, cast)
+		}
+	}
+}
+
 func AddRequestedServiceAccountEdges(g osgraph.Graph, podSpecNode *kubegraph.PodSpecNode) {
 	//pod specs are always contained.  We'll get the toplevel container so that we can pull a namespace from it
 	containerNode := osgraph.GetTopLevelContainerNode(g, podSpecNode)
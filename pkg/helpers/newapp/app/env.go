@@ -300,11 +300,10 @@ func parseLine(line string) (key string, value string, err error) {
 	}
 
 	// Parse the key
-	key = splitString[0]
-	if strings.HasPrefix(key, "export") {
-		key = strings.TrimPrefix(key, "export")
+	key = strings.TrimSpace(splitString[0])
+	if fields := strings.Fields(key); len(fields) == 2 && fields[0] == "export" {
+		key = fields[1]
 	}
-	key = strings.Trim(key, " ")
 
 	// Parse the value
 	value = parseValue(splitString[1])
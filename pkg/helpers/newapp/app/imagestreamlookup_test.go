@@ -280,6 +280,36 @@ func TestImageStreamSearcher(t *testing.T) {
 	}
 }
 
+func TestImageStreamSearcherAllowMissingTags(t *testing.T) {
+	streams, images := fakeImageStreams(
+		&fakeImageStreamDesc{
+			name: "ruby",
+			tags: []imagev1.TagReference{
+				{Name: "2.7"},
+			},
+			latest: "2.7",
+		},
+	)
+	client := testImageStreamClient(streams, images)
+
+	searcher := ImageStreamSearcher{Client: client, Namespaces: []string{"default"}}
+	if _, err := (UniqueExactOrInexactMatchResolver{Searcher: searcher}).Resolve("ruby:3.0"); err == nil {
+		t.Fatalf("expected missing tag to fail to resolve")
+	}
+
+	searcher.AllowMissingTags = true
+	result, err := (UniqueExactOrInexactMatchResolver{Searcher: searcher}).Resolve("ruby:3.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.ImageStream == nil || result.ImageStream.Name != "ruby" {
+		t.Fatalf("expected a match against the ruby image stream: %#v", result)
+	}
+	if result.ImageTag != "3.0" || result.DockerImage != nil {
+		t.Errorf("expected a match for the missing tag without an image: %#v", result)
+	}
+}
+
 func TestMatchSupportsAnnotation(t *testing.T) {
 	tests := []struct {
 		name, value, annotation string
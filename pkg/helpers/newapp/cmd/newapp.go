@@ -92,6 +92,10 @@ type GenerationInputs struct {
 	Secrets    []string
 	ConfigMaps []string
 
+	// EnvironmentFrom holds the secret/NAME and configmap/NAME sources the containers of the
+	// generated deployments are populated from.
+	EnvironmentFrom []string
+
 	AllowMissingImageStreamTags bool
 
 	Deploy           bool
@@ -497,6 +501,7 @@ func (c *AppConfig) buildPipelines(components app.ComponentReferences, environme
 						msg := "Could not find an image stream match for %q. Make sure that a container image with that tag is available on the node for the build to succeed."
 						klog.Warningf(msg, from)
 					}
+					c.warnMissingImageStreamTag(refInput)
 					image = inputImage
 				}
 
@@ -513,6 +518,7 @@ func (c *AppConfig) buildPipelines(components app.ComponentReferences, environme
 					msg := "Could not find an image stream match for %q. Make sure that a container image with that tag is available on the node for the deployment to succeed."
 					klog.Warningf(msg, from)
 				}
+				c.warnMissingImageStreamTag(refInput)
 
 				klog.V(4).Infof("will include %q", ref)
 				if pipeline, err = pipelineBuilder.NewImagePipeline(from, inputImage); err != nil {
@@ -551,7 +557,7 @@ func (c *AppConfig) buildPipelines(components app.ComponentReferences, environme
 
 	if len(c.BuildArgs) > 0 {
 		if numDockerBuilds == 0 {
-			return nil, fmt.Errorf("Cannot use '--build-arg' without a Docker build")
+			return nil, fmt.Errorf("Cannot use '--build-arg' without a Docker build, none of the builds use the docker strategy; use --strategy=docker to build from a Dockerfile")
 		}
 		if numDockerBuilds > 1 {
 			fmt.Fprintf(c.ErrOut, "--> WARNING: Applying --build-arg to multiple Docker builds.\n")
@@ -612,6 +618,20 @@ func (c *AppConfig) buildTemplates(components app.ComponentReferences, parameter
 	return name, objects, nil
 }
 
+// warnMissingImageStreamTag reports when an input was resolved to an image stream tag that does
+// not exist yet, which is only allowed when AllowMissingImageStreamTags is set. The generated
+// triggers will react once the tag is populated, for instance by a build created alongside it.
+func (c *AppConfig) warnMissingImageStreamTag(refInput *app.ComponentInput) {
+	if !c.AllowMissingImageStreamTags || c.ErrOut == nil {
+		return
+	}
+	match := refInput.ResolvedMatch
+	if match == nil || match.ImageStream == nil || match.DockerImage != nil || len(match.ImageTag) == 0 {
+		return
+	}
+	fmt.Fprintf(c.ErrOut, "--> WARNING: Image stream tag \"%s/%s:%s\" for %q does not exist yet; the generated triggers will start once it is available.\n", match.ImageStream.Namespace, match.ImageStream.Name, match.ImageTag, refInput)
+}
+
 // fakeSecretAccessor is used during dry runs of installation
 type fakeSecretAccessor struct {
 	token string
@@ -863,6 +883,10 @@ func templateObjectsToAppObjects(objs []runtime.RawExtension) (app.Objects, erro
 func (c *AppConfig) Run() (*AppResult, error) {
 	env, buildenv, parameters, err := c.validate()
 
+	if err != nil {
+		return nil, err
+	}
+	envFrom, err := parseEnvironmentFrom(c.EnvironmentFrom)
 	if err != nil {
 		return nil, err
 	}
@@ -1040,6 +1064,26 @@ func (c *AppConfig) Run() (*AppResult, error) {
 			}
 		}
 	}
+	if len(envFrom) > 0 {
+		c.warnAboutMissingEnvironmentFrom(envFrom)
+		for _, obj := range objects {
+			var podSpec *corev1.PodSpec
+			switch t := obj.(type) {
+			case *appsv1.DeploymentConfig:
+				if t.Spec.Template != nil {
+					podSpec = &t.Spec.Template.Spec
+				}
+			case *v1.Deployment:
+				podSpec = &t.Spec.Template.Spec
+			}
+			if podSpec == nil {
+				continue
+			}
+			for i := range podSpec.Containers {
+				podSpec.Containers[i].EnvFrom = append(podSpec.Containers[i].EnvFrom, envFrom...)
+			}
+		}
+	}
 
 	return &AppResult{
 		List:      &metainternalversion.List{Items: objects},
@@ -1049,6 +1093,51 @@ func (c *AppConfig) Run() (*AppResult, error) {
 	}, nil
 }
 
+// parseEnvironmentFrom converts secret/NAME and configmap/NAME arguments into the sources of
+// the environment of a container.
+func parseEnvironmentFrom(values []string) ([]corev1.EnvFromSource, error) {
+	var sources []corev1.EnvFromSource
+	for _, value := range values {
+		parts := strings.SplitN(value, "/", 2)
+		if len(parts) != 2 || len(apimachineryvalidation.NameIsDNSSubdomain(parts[1], false)) != 0 {
+			return nil, fmt.Errorf("--env-from %q must be of the form secret/NAME or configmap/NAME", value)
+		}
+		name := parts[1]
+		switch strings.ToLower(parts[0]) {
+		case "secret", "secrets":
+			sources = append(sources, corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}})
+		case "configmap", "configmaps", "cm":
+			sources = append(sources, corev1.EnvFromSource{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}})
+		default:
+			return nil, fmt.Errorf("--env-from %q must be of the form secret/NAME or configmap/NAME", value)
+		}
+	}
+	return sources, nil
+}
+
+// warnAboutMissingEnvironmentFrom prints a warning for every source of the environment that
+// does not exist yet. It may still be created along with the application.
+func (c *AppConfig) warnAboutMissingEnvironmentFrom(sources []corev1.EnvFromSource) {
+	if c.KubeClient == nil {
+		return
+	}
+	for _, source := range sources {
+		var err error
+		var kind, name string
+		switch {
+		case source.SecretRef != nil:
+			kind, name = "secret", source.SecretRef.Name
+			_, err = c.KubeClient.CoreV1().Secrets(c.OriginNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		case source.ConfigMapRef != nil:
+			kind, name = "configmap", source.ConfigMapRef.Name
+			_, err = c.KubeClient.CoreV1().ConfigMaps(c.OriginNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		}
+		if kerrors.IsNotFound(err) {
+			fmt.Fprintf(c.ErrOut, "warning: %s %q referenced by --env-from does not exist yet\n", kind, name)
+		}
+	}
+}
+
 func (c *AppConfig) findImageStreamInObjectList(objects app.Objects, name, namespace string) *imagev1.ImageStream {
 	for _, check := range objects {
 		if is, ok := check.(*imagev1.ImageStream); ok {
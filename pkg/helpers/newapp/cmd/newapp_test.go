@@ -457,6 +457,56 @@ func TestBuildPipelinesWithUnresolvedImage(t *testing.T) {
 	}
 }
 
+func TestBuildPipelinesWithMissingImageStreamTag(t *testing.T) {
+	stream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "test"},
+	}
+	refs := app.ComponentReferences{
+		app.ComponentReference(&app.ComponentInput{
+			Value: "frontend:v2",
+			ResolvedMatch: &app.ComponentMatch{
+				Value:       "frontend:v2",
+				ImageStream: stream,
+				ImageTag:    "v2",
+			},
+		}),
+	}
+
+	errOut := &bytes.Buffer{}
+	a := AppConfig{}
+	a.AllowMissingImageStreamTags = true
+	a.Deploy = true
+	a.DeploymentConfig = true
+	a.Out = &bytes.Buffer{}
+	a.ErrOut = errOut
+	group, err := a.buildPipelines(refs, app.Environment{}, app.Environment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(errOut.String(), `Image stream tag "test/frontend:v2"`) {
+		t.Errorf("expected a warning about the missing tag, got: %s", errOut.String())
+	}
+	if len(group) != 1 || group[0].DeploymentConfig == nil {
+		t.Fatalf("expected a deployment config to be generated: %#v", group)
+	}
+	dc, err := group[0].DeploymentConfig.DeploymentConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, trigger := range dc.Spec.Triggers {
+		if trigger.ImageChangeParams == nil {
+			continue
+		}
+		if from := trigger.ImageChangeParams.From; from.Kind == "ImageStreamTag" && from.Name == "frontend:v2" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an image change trigger on the missing tag: %#v", dc.Spec.Triggers)
+	}
+}
+
 func TestBuildPipelinesWithSSHGitURL(t *testing.T) {
 	sshURL := "ssh://git@github.com:22/user/repo.git"
 	sourceRepo, err := app.NewSourceRepository(sshURL, newapp.StrategyDocker)
@@ -502,6 +552,50 @@ func TestBuildPipelinesWithSSHGitURL(t *testing.T) {
 
 }
 
+func TestBuildPipelinesWithBuildArgs(t *testing.T) {
+	for _, strategy := range []newapp.Strategy{newapp.StrategyDocker, newapp.StrategySource} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			sourceRepo, err := app.NewSourceRepository("https://github.com/foo/bar.git", strategy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			refs := app.ComponentReferences{
+				app.ComponentReference(&app.ComponentInput{
+					Value:         "ruby",
+					Uses:          sourceRepo,
+					ExpectToBuild: true,
+					ResolvedMatch: &app.ComponentMatch{
+						Value: "ruby",
+					},
+				}),
+			}
+
+			a := AppConfig{}
+			a.BuildArgs = []string{"HTTP_PROXY=http://10.0.0.1:3128"}
+			a.Out = &bytes.Buffer{}
+			a.ErrOut = &bytes.Buffer{}
+			group, err := a.buildPipelines(refs, app.Environment{}, app.Environment{})
+			if strategy != newapp.StrategyDocker {
+				if err == nil || !strings.Contains(err.Error(), "Cannot use '--build-arg' without a Docker build") {
+					t.Fatalf("expected the build arguments to be rejected, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			bc, err := group[0].Build.BuildConfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := []corev1.EnvVar{{Name: "HTTP_PROXY", Value: "http://10.0.0.1:3128"}}
+			if bc.Spec.Strategy.DockerStrategy == nil || !reflect.DeepEqual(bc.Spec.Strategy.DockerStrategy.BuildArgs, expected) {
+				t.Errorf("expected build args %v, got %#v", expected, bc.Spec.Strategy)
+			}
+		})
+	}
+}
+
 func TestBuildOutputCycleResilience(t *testing.T) {
 
 	config := &AppConfig{}
@@ -706,3 +800,34 @@ func TestBuildOutputCycleWithCircularTag(t *testing.T) {
 		}
 	}
 }
+
+func TestEnvironmentFrom(t *testing.T) {
+	if _, err := parseEnvironmentFrom([]string{"deployment/foo"}); err == nil {
+		t.Errorf("expected an error for an unsupported kind")
+	}
+	if _, err := parseEnvironmentFrom([]string{"secret"}); err == nil {
+		t.Errorf("expected an error for a missing name")
+	}
+	sources, err := parseEnvironmentFrom([]string{"secret/config", "configmap/settings"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []corev1.EnvFromSource{
+		{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}},
+		{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}},
+	}
+	if !reflect.DeepEqual(sources, expected) {
+		t.Errorf("unexpected sources: %#v", sources)
+	}
+
+	errOut := &bytes.Buffer{}
+	c := &AppConfig{
+		ErrOut:          errOut,
+		OriginNamespace: "test",
+		KubeClient:      fakev1.NewSimpleClientset(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "test"}}),
+	}
+	c.warnAboutMissingEnvironmentFrom(sources)
+	if strings.Contains(errOut.String(), `secret "config"`) || !strings.Contains(errOut.String(), `warning: configmap "settings" referenced by --env-from does not exist yet`) {
+		t.Errorf("unexpected warnings: %s", errOut.String())
+	}
+}
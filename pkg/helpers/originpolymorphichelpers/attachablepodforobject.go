@@ -1,10 +1,13 @@
 package originpolymorphichelpers
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -35,8 +38,66 @@ func NewAttachablePodForObjectFn(delegate polymorphichelpers.AttachablePodForObj
 			pod, _, err := polymorphichelpers.GetFirstPod(coreClient.CoreV1(), t.Namespace, selector.String(), 1*time.Minute, f)
 			return pod, err
 
+		case *batchv1.Job:
+			config, err := restClientGetter.ToRESTConfig()
+			if err != nil {
+				return nil, err
+			}
+			coreClient, err := kubernetes.NewForConfig(config)
+			if err != nil {
+				return nil, err
+			}
+
+			selector, err := metav1.LabelSelectorAsSelector(t.Spec.Selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector for job %s: %v", t.Name, err)
+			}
+			f := func(pods []*v1.Pod) sort.Interface {
+				return jobPodsByPreference(pods)
+			}
+			pod, _, err := polymorphichelpers.GetFirstPod(coreClient.CoreV1(), t.Namespace, selector.String(), timeout, f)
+			return pod, err
+
 		default:
 			return delegate(restClientGetter, object, timeout)
 		}
 	}
 }
+
+// jobPodsByPreference sorts the pods of a job so that the pod to attach to comes first. Pods that
+// have not finished are preferred, ordered like the active pods of a replication controller. Once
+// the job has finished, its most recently completed pod comes first, succeeded pods before failed ones.
+type jobPodsByPreference []*v1.Pod
+
+func (s jobPodsByPreference) Len() int      { return len(s) }
+func (s jobPodsByPreference) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+func (s jobPodsByPreference) Less(i, j int) bool {
+	iFinished, jFinished := podFinished(s[i]), podFinished(s[j])
+	switch {
+	case iFinished != jFinished:
+		return !iFinished
+	case !iFinished:
+		return podutils.ActivePods(s).Less(j, i)
+	case s[i].Status.Phase != s[j].Status.Phase:
+		return s[i].Status.Phase == v1.PodSucceeded
+	default:
+		return podCompletionTime(s[j]).Before(podCompletionTime(s[i]))
+	}
+}
+
+func podFinished(pod *v1.Pod) bool {
+	return pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed
+}
+
+// podCompletionTime returns the time the last container of pod terminated, or the creation time
+// of the pod if none of its containers reported termination.
+func podCompletionTime(pod *v1.Pod) time.Time {
+	completed := pod.CreationTimestamp.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.Time.After(completed) {
+			completed = terminated.FinishedAt.Time
+		}
+	}
+	return completed
+}
@@ -0,0 +1,69 @@
+package originpolymorphichelpers
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJobPodsByPreference(t *testing.T) {
+	created := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	finished := func(name string, phase corev1.PodPhase, finishedAt time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+			Status: corev1.PodStatus{
+				Phase: phase,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(finishedAt)}}},
+				},
+			},
+		}
+	}
+	running := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "running", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       corev1.PodSpec{NodeName: "node"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending", CreationTimestamp: metav1.NewTime(created)},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+
+	tests := []struct {
+		name     string
+		pods     []*corev1.Pod
+		expected string
+	}{
+		{
+			name:     "running pod is preferred",
+			pods:     []*corev1.Pod{finished("done", corev1.PodSucceeded, created.Add(time.Hour)), pending, running},
+			expected: "running",
+		},
+		{
+			name: "most recently completed pod",
+			pods: []*corev1.Pod{
+				finished("first", corev1.PodSucceeded, created.Add(time.Minute)),
+				finished("failed", corev1.PodFailed, created.Add(time.Hour)),
+				finished("last", corev1.PodSucceeded, created.Add(10*time.Minute)),
+				finished("second", corev1.PodSucceeded, created.Add(5*time.Minute)),
+			},
+			expected: "last",
+		},
+		{
+			name:     "failed pod when none succeeded",
+			pods:     []*corev1.Pod{finished("failed-1", corev1.PodFailed, created.Add(time.Minute)), finished("failed-2", corev1.PodFailed, created.Add(2*time.Minute))},
+			expected: "failed-2",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sort.Sort(jobPodsByPreference(tc.pods))
+			if tc.pods[0].Name != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, tc.pods[0].Name)
+			}
+		})
+	}
+}
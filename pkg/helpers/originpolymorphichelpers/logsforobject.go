@@ -9,8 +9,10 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/reference"
 	"k8s.io/kubectl/pkg/polymorphichelpers"
@@ -20,6 +22,7 @@ import (
 	buildv1 "github.com/openshift/api/build/v1"
 	appsv1client "github.com/openshift/client-go/apps/clientset/versioned/typed/apps/v1"
 	buildv1client "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
+	"github.com/openshift/library-go/pkg/apps/appsutil"
 	ocbuildapihelpers "github.com/openshift/oc/pkg/helpers/build"
 	buildmanualclientv1 "github.com/openshift/oc/pkg/helpers/build/client/v1"
 	"github.com/openshift/oc/pkg/helpers/originpolymorphichelpers/deploymentconfigs"
@@ -34,9 +37,16 @@ func NewLogsForObjectFn(delegate polymorphichelpers.LogsForObjectFunc) polymorph
 
 		switch t := object.(type) {
 		case *appsv1.DeploymentConfig:
+			if popts, ok := options.(*corev1.PodLogOptions); ok {
+				kubeClient, err := kubernetes.NewForConfig(clientConfig)
+				if err != nil {
+					return nil, err
+				}
+				return logsForDeploymentConfigPods(kubeClient, delegate, restClientGetter, t, popts, timeout, allContainers)
+			}
 			dopts, ok := options.(*appsv1.DeploymentLogOptions)
 			if !ok {
-				return nil, errors.New("provided options object is not a DeploymentLogOptions")
+				return nil, errors.New("provided options object is not a DeploymentLogOptions or PodLogOptions")
 			}
 			appsClient, err := appsv1client.NewForConfig(clientConfig)
 			if err != nil {
@@ -110,3 +120,32 @@ func NewLogsForObjectFn(delegate polymorphichelpers.LogsForObjectFunc) polymorph
 		}
 	}
 }
+
+// logsForDeploymentConfigPods returns a log request for every pod of the latest replication
+// controller of config, so the logs of all the pods of a rollout can be read together.
+func logsForDeploymentConfigPods(kubeClient kubernetes.Interface, delegate polymorphichelpers.LogsForObjectFunc, restClientGetter genericclioptions.RESTClientGetter, config *appsv1.DeploymentConfig, options *corev1.PodLogOptions, timeout time.Duration, allContainers bool) (map[corev1.ObjectReference]rest.ResponseWrapper, error) {
+	rcName := appsutil.LatestDeploymentNameForConfig(config)
+	rc, err := kubeClient.CoreV1().ReplicationControllers(config.Namespace).Get(context.TODO(), rcName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pods, err := kubeClient.CoreV1().Pods(config.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labels.SelectorFromSet(rc.Spec.Selector).String()})
+	if err != nil {
+		return nil, err
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("no pods found for replication controller %s", rcName)
+	}
+
+	ret := make(map[corev1.ObjectReference]rest.ResponseWrapper)
+	for i := range pods.Items {
+		requests, err := delegate(restClientGetter, &pods.Items[i], options, timeout, allContainers)
+		if err != nil {
+			return nil, err
+		}
+		for ref, request := range requests {
+			ret[ref] = request
+		}
+	}
+	return ret, nil
+}
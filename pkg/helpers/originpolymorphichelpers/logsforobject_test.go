@@ -0,0 +1,64 @@
+package originpolymorphichelpers
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+)
+
+func TestLogsForDeploymentConfigPods(t *testing.T) {
+	pod := func(name, deployment string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Labels: map[string]string{"deployment": deployment}}}
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.ReplicationController{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "test"},
+			Spec:       corev1.ReplicationControllerSpec{Selector: map[string]string{"deployment": "web-2"}},
+		},
+		pod("web-1-abcde", "web-1"),
+		pod("web-2-fghij", "web-2"),
+		pod("web-2-klmno", "web-2"),
+	)
+	config := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+		Status:     appsv1.DeploymentConfigStatus{LatestVersion: 2},
+	}
+	tail := int64(10)
+	options := &corev1.PodLogOptions{TailLines: &tail}
+
+	delegate := func(_ genericclioptions.RESTClientGetter, object, opts runtime.Object, _ time.Duration, _ bool) (map[corev1.ObjectReference]rest.ResponseWrapper, error) {
+		if opts != options {
+			t.Errorf("expected the pod log options to be passed through, got %#v", opts)
+		}
+		p := object.(*corev1.Pod)
+		ref := corev1.ObjectReference{Kind: "Pod", Namespace: p.Namespace, Name: p.Name, FieldPath: "spec.containers{web}"}
+		return map[corev1.ObjectReference]rest.ResponseWrapper{ref: nil}, nil
+	}
+
+	requests, err := logsForDeploymentConfigPods(client, delegate, nil, config, options, time.Second, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for ref := range requests {
+		names = append(names, ref.Name)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "web-2-fghij" || names[1] != "web-2-klmno" {
+		t.Errorf("expected the pods of the latest deployment, got %v", names)
+	}
+
+	config.Status.LatestVersion = 3
+	if _, err := logsForDeploymentConfigPods(client, delegate, nil, config, options, time.Second, false); err == nil {
+		t.Errorf("expected an error for a missing replication controller")
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+
 	s2ierr "github.com/openshift/oc/pkg/helpers/source-to-image/errors"
 	"github.com/openshift/oc/pkg/helpers/source-to-image/fs"
 	utillog "github.com/openshift/oc/pkg/helpers/source-to-image/log"
@@ -34,6 +36,10 @@ type Tar interface {
 	// creation
 	SetExclusionPattern(*regexp.Regexp)
 
+	// SetExclusionMatcher sets a gitignore-style matcher that is
+	// applied in addition to the exclusion pattern on tar creation
+	SetExclusionMatcher(gitignore.Matcher)
+
 	// CreateTarFile creates a tar file in the base directory
 	// using the contents of dir directory
 	// The name of the new tar file is returned if successful
@@ -180,6 +186,7 @@ type stiTar struct {
 	fs.FileSystem
 	timeout              time.Duration
 	exclude              *regexp.Regexp
+	excludeMatcher       gitignore.Matcher
 	includeDirInPath     bool
 	disallowOverwrite    bool
 	disallowOutsidePaths bool
@@ -208,10 +215,30 @@ func (t *stiTar) CreateTarFile(base, dir string) (string, error) {
 	return tarFile.Name(), nil
 }
 
+// SetExclusionMatcher sets a gitignore-style matcher for tar creation. The
+// matcher is given paths relative to the directory being archived, and
+// directories it matches are skipped entirely.
+func (t *stiTar) SetExclusionMatcher(m gitignore.Matcher) {
+	t.excludeMatcher = m
+}
+
 func (t *stiTar) shouldExclude(path string) bool {
 	return t.exclude != nil && t.exclude.String() != "" && t.exclude.MatchString(filepath.ToSlash(path))
 }
 
+// matchesExclusionMatcher returns true if path, relative to dir, is matched
+// by the exclusion matcher.
+func (t *stiTar) matchesExclusionMatcher(dir, path string, isDir bool) bool {
+	if t.excludeMatcher == nil || dir == path {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return t.excludeMatcher.Match(strings.Split(filepath.ToSlash(rel), "/"), isDir)
+}
+
 // CreateTarStream calls CreateTarStreamToTarWriter with a nil logger
 func (t *stiTar) CreateTarStream(dir string, includeDirInPath bool, writer io.Writer) error {
 	tarWriter := tar.NewWriter(writer)
@@ -240,6 +267,12 @@ func (t *stiTar) CreateTarStreamToTarWriter(dir string, includeDirInPath bool, t
 		if err != nil {
 			return err
 		}
+		if t.matchesExclusionMatcher(dir, path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 		// on Windows, directory symlinks report as a directory and as a symlink.
 		// They should be treated as symlinks.
 		if !t.shouldExclude(path) {
@@ -12,6 +12,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+
 	s2ierr "github.com/openshift/oc/pkg/helpers/source-to-image/errors"
 	"github.com/openshift/oc/pkg/helpers/source-to-image/fs"
 )
@@ -289,6 +291,48 @@ func TestCreateTarIncludeDotGit(t *testing.T) {
 	verifyTarFile(t, tarFile, testDirs, testFiles, testLinks)
 }
 
+func TestCreateTarExclusionMatcher(t *testing.T) {
+	th := New(fs.NewFileSystem())
+	th.SetExclusionMatcher(gitignore.NewMatcher([]gitignore.Pattern{
+		gitignore.ParsePattern("node_modules/", nil),
+		gitignore.ParsePattern("*.log", nil),
+		gitignore.ParsePattern("!keep.log", nil),
+	}))
+	tempDir, err := ioutil.TempDir("", "testtar")
+	defer os.RemoveAll(tempDir)
+	if err != nil {
+		t.Fatalf("Cannot create temp directory for test: %v", err)
+	}
+	modificationDate := time.Date(2011, time.March, 5, 23, 30, 1, 0, time.UTC)
+	// dir01 is modified when node_modules is added below
+	testDirs := []dirDesc{
+		{"dir01", time.Time{}, 0700},
+		{"dir01/dir02", modificationDate, 0755},
+	}
+	testFiles := []fileDesc{
+		{"dir01/dir02/test1.txt", modificationDate, 0700, "Test1 file content", false, ""},
+		{"dir01/build.log", modificationDate, 0600, "Ignore file content", true, ""},
+		{"dir01/dir02/keep.log", modificationDate, 0600, "Keep file content", false, ""},
+		{"node_modules", modificationDate, 0600, "Not a directory", false, ""},
+	}
+	if err = createTestFiles(tempDir, testDirs, testFiles, nil); err != nil {
+		t.Fatalf("Cannot create test files: %v", err)
+	}
+	if err = os.MkdirAll(filepath.Join(tempDir, "dir01", "node_modules", "pkg"), 0755); err != nil {
+		t.Fatalf("Cannot create test files: %v", err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(tempDir, "dir01", "node_modules", "pkg", "index.js"), []byte("Ignore file content"), 0600); err != nil {
+		t.Fatalf("Cannot create test files: %v", err)
+	}
+
+	tarFile, err := th.CreateTarFile("", tempDir)
+	defer os.Remove(tarFile)
+	if err != nil {
+		t.Fatalf("Unable to create new tar upload file: %v", err)
+	}
+	verifyTarFile(t, tarFile, testDirs, testFiles, nil)
+}
+
 func TestCreateTarEmptyRegexp(t *testing.T) {
 	th := New(fs.NewFileSystem())
 	th.SetExclusionPattern(regexp.MustCompile(""))